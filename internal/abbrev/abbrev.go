@@ -0,0 +1,109 @@
+package abbrev
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Expansion is one meaning of an abbreviation in a domain.
+type Expansion struct {
+	Domain    string
+	Expansion string
+}
+
+// Lookup returns the known expansions of abbr, optionally restricted to a
+// domain. Matching ignores case and surrounding punctuation.
+func Lookup(abbr, domain string) []Expansion {
+	key := normalize(abbr)
+	var matches []Expansion
+	for _, expansion := range table[key] {
+		if domain != "" && expansion.Domain != domain {
+			continue
+		}
+		matches = append(matches, expansion)
+	}
+	return matches
+}
+
+// Domains lists the domains used by the offline table.
+func Domains() []string {
+	seen := make(map[string]bool)
+	for _, expansions := range table {
+		for _, expansion := range expansions {
+			seen[expansion.Domain] = true
+		}
+	}
+	domains := make([]string, 0, len(seen))
+	for domain := range seen {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// Tokenize splits input such as "LGTM, PTAL" into abbreviations.
+func Tokenize(input string) []string {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == ';' || r == '，' || r == '、'
+	})
+	tokens := make([]string, 0, len(fields))
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		field = strings.Trim(field, ".:!?()[]\"'")
+		if field == "" || seen[normalize(field)] {
+			continue
+		}
+		seen[normalize(field)] = true
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+func normalize(abbr string) string {
+	return strings.ToUpper(strings.Trim(strings.TrimSpace(abbr), ".:!?()[]\"'"))
+}
+
+var table = map[string][]Expansion{
+	"AFAIK": {{"chat", "as far as I know"}},
+	"AKA":   {{"general", "also known as"}},
+	"API":   {{"tech", "application programming interface"}},
+	"ASAP":  {{"general", "as soon as possible"}},
+	"BP":    {{"medical", "blood pressure"}, {"finance", "basis point"}},
+	"BTW":   {{"chat", "by the way"}},
+	"CI":    {{"tech", "continuous integration"}, {"science", "confidence interval"}},
+	"CPU":   {{"tech", "central processing unit"}},
+	"CT":    {{"medical", "computed tomography"}},
+	"DM":    {{"chat", "direct message"}, {"medical", "diabetes mellitus"}},
+	"EOD":   {{"general", "end of day"}},
+	"EPS":   {{"finance", "earnings per share"}},
+	"ETA":   {{"general", "estimated time of arrival"}},
+	"ETF":   {{"finance", "exchange-traded fund"}},
+	"FAQ":   {{"general", "frequently asked questions"}},
+	"FYI":   {{"general", "for your information"}},
+	"HR":    {{"general", "human resources"}, {"medical", "heart rate"}},
+	"IIRC":  {{"chat", "if I recall correctly"}},
+	"IMO":   {{"chat", "in my opinion"}},
+	"IPO":   {{"finance", "initial public offering"}},
+	"KPI":   {{"finance", "key performance indicator"}},
+	"LGTM":  {{"tech", "looks good to me"}},
+	"MRI":   {{"medical", "magnetic resonance imaging"}},
+	"NDA":   {{"general", "non-disclosure agreement"}},
+	"NIT":   {{"tech", "nitpick"}},
+	"OOO":   {{"general", "out of office"}},
+	"OTC":   {{"finance", "over-the-counter"}, {"medical", "over-the-counter (medication)"}},
+	"PR":    {{"tech", "pull request"}, {"general", "public relations"}},
+	"PRN":   {{"medical", "pro re nata (as needed)"}},
+	"PTAL":  {{"tech", "please take another look"}},
+	"QA":    {{"tech", "quality assurance"}},
+	"ROI":   {{"finance", "return on investment"}},
+	"RSVP":  {{"general", "répondez s'il vous plaît (please reply)"}},
+	"SLA":   {{"tech", "service-level agreement"}},
+	"TBD":   {{"general", "to be determined"}},
+	"TLDR":  {{"chat", "too long; didn't read"}},
+	"WFH":   {{"general", "work from home"}},
+	"WIP":   {{"tech", "work in progress"}},
+	"YOY":   {{"finance", "year over year"}},
+	"QID":   {{"medical", "quater in die (four times a day)"}},
+	"BID":   {{"medical", "bis in die (twice a day)"}},
+}
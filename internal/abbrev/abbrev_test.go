@@ -0,0 +1,43 @@
+package abbrev
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tokens := Tokenize("LGTM, PTAL; lgtm (BP)")
+	if strings.Join(tokens, "|") != "LGTM|PTAL|BP" {
+		t.Fatalf("unexpected tokens: %q", tokens)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	matches := Lookup("ptal", "")
+	if len(matches) != 1 || matches[0].Expansion != "please take another look" {
+		t.Fatalf("unexpected matches: %+v", matches)
+	}
+	matches = Lookup("BP", "finance")
+	if len(matches) != 1 || matches[0].Expansion != "basis point" {
+		t.Fatalf("unexpected domain matches: %+v", matches)
+	}
+	matches = Lookup("CI", "science")
+	if len(matches) != 1 || matches[0].Expansion != "confidence interval" {
+		t.Fatalf("unexpected science matches: %+v", matches)
+	}
+	if matches := Lookup("CI", "finance"); len(matches) != 0 {
+		t.Fatalf("unexpected finance matches: %+v", matches)
+	}
+	if matches := Lookup("ZZZ", ""); len(matches) != 0 {
+		t.Fatalf("unexpected matches: %+v", matches)
+	}
+}
+
+func TestDomains(t *testing.T) {
+	domains := strings.Join(Domains(), ",")
+	for _, domain := range []string{"finance", "medical", "tech"} {
+		if !strings.Contains(domains, domain) {
+			t.Fatalf("missing domain %s in %s", domain, domains)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/secrets"
+)
+
+// Record is one LLM request and its outcome as written to the audit log.
+type Record struct {
+	Time         time.Time     `json:"time"`
+	Command      string        `json:"command,omitempty"`
+	Provider     string        `json:"provider"`
+	Model        string        `json:"model,omitempty"`
+	Stream       bool          `json:"stream"`
+	Messages     []llm.Message `json:"messages"`
+	Response     string        `json:"response,omitempty"`
+	FinishReason string        `json:"finish_reason,omitempty"`
+	Usage        *llm.Usage    `json:"usage,omitempty"`
+	CostUSD      *float64      `json:"cost_usd,omitempty"`
+	LatencyMS    int64         `json:"latency_ms"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// Price is the USD cost per million prompt and completion tokens of a
+// model.
+type Price struct {
+	Model  string
+	Input  float64
+	Output float64
+}
+
+// Cost prices usage with the entry for model, if there is one.
+func Cost(prices []Price, model string, usage llm.Usage) (float64, bool) {
+	for _, price := range prices {
+		if strings.EqualFold(price.Model, model) {
+			return (float64(usage.PromptTokens)*price.Input + float64(usage.CompletionTokens)*price.Output) / 1e6, true
+		}
+	}
+	return 0, false
+}
+
+// Logger appends records to a JSONL file. The file is opened for each
+// record so it can be rotated externally.
+type Logger struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewLogger(path string) *Logger {
+	return &Logger{path: path}
+}
+
+func (l *Logger) Write(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encode audit record: %w", err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return file.Close()
+}
+
+// Options describes the requests logged by a wrapped client.
+type Options struct {
+	Command  string
+	Provider string
+	Prices   []Price
+	// InternalDomains are redacted along with credentials, see
+	// secrets.Mask.
+	InternalDomains []string
+}
+
+type client struct {
+	client llm.Client
+	logger *Logger
+	opts   Options
+	now    func() time.Time
+}
+
+// Wrap returns a client that logs every request sent through it, with
+// secrets masked in prompts and responses. A failure to write the log is
+// returned as the request error.
+func Wrap(c llm.Client, logger *Logger, opts Options) llm.Client {
+	return &client{client: c, logger: logger, opts: opts, now: time.Now}
+}
+
+func (c *client) Unwrap() llm.Client { return c.client }
+
+func (c *client) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	start := c.now()
+	resp, err := c.client.Chat(ctx, req)
+	return resp, c.log(start, req, resp, false, err)
+}
+
+func (c *client) ChatStream(ctx context.Context, req llm.ChatRequest, handle llm.StreamHandler) (llm.ChatResponse, error) {
+	start := c.now()
+	resp, err := c.client.ChatStream(ctx, req, handle)
+	return resp, c.log(start, req, resp, true, err)
+}
+
+func (c *client) log(start time.Time, req llm.ChatRequest, resp llm.ChatResponse, stream bool, err error) error {
+	record := Record{
+		Time:         start.UTC(),
+		Command:      c.opts.Command,
+		Provider:     c.opts.Provider,
+		Model:        resp.Model,
+		Stream:       stream,
+		Messages:     make([]llm.Message, 0, len(req.Messages)),
+		Response:     secrets.Mask(resp.Content, c.opts.InternalDomains),
+		FinishReason: resp.FinishReason,
+		LatencyMS:    c.now().Sub(start).Milliseconds(),
+	}
+	if record.Model == "" {
+		record.Model = req.Model
+	}
+	for _, message := range req.Messages {
+		message.Content = secrets.Mask(message.Content, c.opts.InternalDomains)
+		record.Messages = append(record.Messages, message)
+	}
+	if !resp.Usage.IsZero() {
+		usage := resp.Usage
+		record.Usage = &usage
+		if cost, ok := Cost(c.opts.Prices, record.Model, usage); ok {
+			record.CostUSD = &cost
+		}
+	}
+	if err != nil {
+		record.Error = secrets.Mask(err.Error(), c.opts.InternalDomains)
+	}
+	if logErr := c.logger.Write(record); logErr != nil && err == nil {
+		return logErr
+	}
+	return err
+}
@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// ReadLog returns the records of the audit log at path. A missing file is
+// an empty log.
+func ReadLog(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+	defer file.Close()
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("read audit log: line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+	return records, nil
+}
+
+// Summary totals the requests of a period. CostUSD only counts requests
+// whose records carry a cost; Unpriced counts the rest.
+type Summary struct {
+	Requests         int           `json:"requests"`
+	Errors           int           `json:"errors"`
+	PromptTokens     int           `json:"prompt_tokens"`
+	CompletionTokens int           `json:"completion_tokens"`
+	CostUSD          float64       `json:"cost_usd"`
+	Unpriced         int           `json:"unpriced"`
+	Models           []UsageByName `json:"models,omitempty"`
+	Commands         []UsageByName `json:"commands,omitempty"`
+}
+
+// UsageByName is the share of a model or command in a Summary.
+type UsageByName struct {
+	Name     string  `json:"name"`
+	Requests int     `json:"requests"`
+	Tokens   int     `json:"tokens"`
+	CostUSD  float64 `json:"cost_usd"`
+}
+
+// Summarize totals the records from since up to, but not including,
+// until. Models and commands are listed by request count, most used
+// first.
+func Summarize(records []Record, since, until time.Time) Summary {
+	var summary Summary
+	models := make(map[string]*UsageByName)
+	commands := make(map[string]*UsageByName)
+	add := func(shares map[string]*UsageByName, name string, tokens int, cost float64) {
+		if name == "" {
+			name = "unknown"
+		}
+		share, ok := shares[name]
+		if !ok {
+			share = &UsageByName{Name: name}
+			shares[name] = share
+		}
+		share.Requests++
+		share.Tokens += tokens
+		share.CostUSD += cost
+	}
+	for _, record := range records {
+		if record.Time.Before(since) || !record.Time.Before(until) {
+			continue
+		}
+		summary.Requests++
+		if record.Error != "" {
+			summary.Errors++
+		}
+		tokens := 0
+		if record.Usage != nil {
+			summary.PromptTokens += record.Usage.PromptTokens
+			summary.CompletionTokens += record.Usage.CompletionTokens
+			tokens = record.Usage.PromptTokens + record.Usage.CompletionTokens
+		}
+		cost := 0.0
+		if record.CostUSD != nil {
+			cost = *record.CostUSD
+			summary.CostUSD += cost
+		} else {
+			summary.Unpriced++
+		}
+		add(models, record.Model, tokens, cost)
+		add(commands, record.Command, tokens, cost)
+	}
+	summary.Models = sortedShares(models)
+	summary.Commands = sortedShares(commands)
+	return summary
+}
+
+func sortedShares(shares map[string]*UsageByName) []UsageByName {
+	sorted := make([]UsageByName, 0, len(shares))
+	for _, share := range shares {
+		sorted = append(sorted, *share)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Requests != sorted[j].Requests {
+			return sorted[i].Requests > sorted[j].Requests
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
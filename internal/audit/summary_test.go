@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dict-be/internal/llm"
+)
+
+func TestReadLogAndSummarize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger := NewLogger(path)
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	cost := 0.25
+	for _, record := range []Record{
+		{Time: day.Add(-48 * time.Hour), Command: "dict-be query", Model: "a"},
+		{Time: day, Command: "dict-be query", Model: "a", Usage: &llm.Usage{PromptTokens: 100, CompletionTokens: 50}, CostUSD: &cost},
+		{Time: day.Add(time.Hour), Command: "dict-be translate", Model: "b", Usage: &llm.Usage{PromptTokens: 10, CompletionTokens: 5}},
+		{Time: day.Add(2 * time.Hour), Command: "dict-be query", Model: "a", Error: "rate limited"},
+	} {
+		if err := logger.Write(record); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	records, err := ReadLog(path)
+	if err != nil || len(records) != 4 {
+		t.Fatalf("read log: %d records, %v", len(records), err)
+	}
+	summary := Summarize(records, day.Add(-time.Hour), day.Add(24*time.Hour))
+	if summary.Requests != 3 || summary.Errors != 1 || summary.PromptTokens != 110 || summary.CompletionTokens != 55 || summary.CostUSD != 0.25 || summary.Unpriced != 2 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if len(summary.Commands) != 2 || summary.Commands[0] != (UsageByName{Name: "dict-be query", Requests: 2, Tokens: 150, CostUSD: 0.25}) {
+		t.Fatalf("unexpected commands: %+v", summary.Commands)
+	}
+
+	if records, err := ReadLog(filepath.Join(t.TempDir(), "missing.jsonl")); err != nil || records != nil {
+		t.Fatalf("missing log: %v, %v", records, err)
+	}
+	if err := os.WriteFile(path, []byte("{\"time\":\"2026-03-02T09:00:00Z\"}\nnot json\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := ReadLog(path); err == nil {
+		t.Fatalf("expected an error for a malformed line")
+	}
+}
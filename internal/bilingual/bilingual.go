@@ -0,0 +1,181 @@
+// Package bilingual writes a translated document as a parallel text:
+// each source paragraph followed by its translation, with aligned
+// sentences tinted alike and a vocabulary appendix linked from the text.
+package bilingual
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+
+	"dict-be/internal/document"
+	"dict-be/internal/known"
+)
+
+// Book is a translated document.
+type Book struct {
+	Title          string
+	SourceLanguage string
+	TargetLanguage string
+	// Sections holds each source paragraph with its translation.
+	Sections []document.Pair
+	// Vocabulary is listed in the appendix; the first use of each word in
+	// the source text links to its entry.
+	Vocabulary []Entry
+}
+
+// Entry is a vocabulary item as written in the source text.
+type Entry struct {
+	Word    string
+	Meaning string
+}
+
+// sentenceTints is how many background tints alternate between aligned
+// sentence pairs.
+const sentenceTints = 3
+
+const stylesheet = `body { max-width: 42em; margin: 0 auto; padding: 1em; line-height: 1.6; }
+section.pair { margin: 0 0 1.5em; }
+p.source { margin: 0; }
+p.target { margin: 0.3em 0 0; color: #444; }
+span.t0 { background: #fff4d6; }
+span.t1 { background: #e3f1ff; }
+span.t2 { background: #e8f7e4; }
+a.vocab { color: inherit; text-decoration: underline dotted; }
+dt { font-weight: bold; }
+dd { margin: 0 0 0.5em 1.5em; }
+`
+
+// WriteHTML writes book as a standalone HTML page.
+func WriteHTML(w io.Writer, book Book) error {
+	text, vocabulary := book.body("", "")
+	_, err := fmt.Fprintf(w, "<!DOCTYPE html>\n<html lang=\"%s\">\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n%s</style>\n</head>\n<body>\n<h1>%s</h1>\n%s%s</body>\n</html>\n",
+		languageTag(book.TargetLanguage), html.EscapeString(book.Title), stylesheet,
+		html.EscapeString(book.Title), text, vocabulary)
+	return err
+}
+
+// body renders the parallel text and the vocabulary appendix as XHTML
+// fragments. textFile and vocabularyFile prefix the links between them
+// when they end up in separate files.
+func (b Book) body(textFile, vocabularyFile string) (string, string) {
+	linked := make([]int, len(b.Vocabulary))
+	var text strings.Builder
+	sourceLang := langAttr(b.SourceLanguage)
+	targetLang := langAttr(b.TargetLanguage)
+	for i, section := range b.Sections {
+		id := i + 1
+		pairs := document.AlignSentences(section.Source, section.Target)
+		fmt.Fprintf(&text, "<section class=\"pair\" id=\"p%d\">\n<p class=\"source\"%s>", id, sourceLang)
+		for n, pair := range pairs {
+			if n > 0 {
+				text.WriteString(" ")
+			}
+			writeSentence(&text, n, len(pairs), b.markVocabulary(pair.Source, id, linked, vocabularyFile))
+		}
+		fmt.Fprintf(&text, "</p>\n<p class=\"target\"%s>", targetLang)
+		for n, pair := range pairs {
+			if n > 0 {
+				text.WriteString(" ")
+			}
+			writeSentence(&text, n, len(pairs), escape(pair.Target))
+		}
+		text.WriteString("</p>\n</section>\n")
+	}
+	if len(b.Vocabulary) == 0 {
+		return text.String(), ""
+	}
+	var vocabulary strings.Builder
+	vocabulary.WriteString("<section id=\"vocabulary\">\n<h2>Vocabulary</h2>\n<dl>\n")
+	for i, entry := range b.Vocabulary {
+		fmt.Fprintf(&vocabulary, "<dt id=\"v%d\"%s>%s</dt>\n<dd>%s", i+1, sourceLang, escape(entry.Word), escape(entry.Meaning))
+		if linked[i] > 0 {
+			fmt.Fprintf(&vocabulary, " <a href=\"%s#p%d\">&#8617;</a>", textFile, linked[i])
+		}
+		vocabulary.WriteString("</dd>\n")
+	}
+	vocabulary.WriteString("</dl>\n</section>\n")
+	return text.String(), vocabulary.String()
+}
+
+// writeSentence tints the n-th of count aligned sentences; a paragraph
+// that could not be aligned by sentence is left plain.
+func writeSentence(out *strings.Builder, n, count int, sentence string) {
+	if count < 2 {
+		out.WriteString(sentence)
+		return
+	}
+	fmt.Fprintf(out, "<span class=\"t%d\">%s</span>", n%sentenceTints, sentence)
+}
+
+type mark struct {
+	start, end, entry int
+}
+
+// markVocabulary escapes sentence and links the vocabulary entries not
+// linked yet that occur in it, recording paragraph as where they were
+// found.
+func (b Book) markVocabulary(sentence string, paragraph int, linked []int, vocabularyFile string) string {
+	var marks []mark
+	for i, entry := range b.Vocabulary {
+		if linked[i] > 0 {
+			continue
+		}
+		if start := known.Index(sentence, entry.Word); start >= 0 {
+			marks = append(marks, mark{start: start, end: start + len(entry.Word), entry: i})
+		}
+	}
+	sort.Slice(marks, func(i, j int) bool { return marks[i].start < marks[j].start })
+	var out strings.Builder
+	last := 0
+	for _, m := range marks {
+		if m.start < last {
+			continue
+		}
+		linked[m.entry] = paragraph
+		out.WriteString(escape(sentence[last:m.start]))
+		fmt.Fprintf(&out, "<a class=\"vocab\" href=\"%s#v%d\">%s</a>", vocabularyFile, m.entry+1, escape(sentence[m.start:m.end]))
+		last = m.end
+	}
+	out.WriteString(escape(sentence[last:]))
+	return out.String()
+}
+
+// escape escapes text for XHTML and keeps its line breaks.
+func escape(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br/>\n")
+}
+
+func langAttr(language string) string {
+	tag := languageTag(language)
+	if tag == "und" {
+		return ""
+	}
+	return fmt.Sprintf(" lang=\"%s\"", tag)
+}
+
+var languageTags = map[string]string{
+	"english":             "en",
+	"chinese":             "zh",
+	"simplified chinese":  "zh-Hans",
+	"traditional chinese": "zh-Hant",
+	"japanese":            "ja",
+	"korean":              "ko",
+	"french":              "fr",
+	"german":              "de",
+	"spanish":             "es",
+	"italian":             "it",
+	"portuguese":          "pt",
+	"russian":             "ru",
+}
+
+// languageTag returns the BCP 47 tag for a language name, or "und" when it
+// is not known.
+func languageTag(language string) string {
+	if tag, ok := languageTags[strings.ToLower(strings.TrimSpace(language))]; ok {
+		return tag
+	}
+	return "und"
+}
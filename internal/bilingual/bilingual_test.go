@@ -0,0 +1,83 @@
+package bilingual
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"dict-be/internal/document"
+)
+
+var testBook = Book{
+	Title:          "Notes <draft>",
+	SourceLanguage: "English",
+	TargetLanguage: "Simplified Chinese",
+	Sections: []document.Pair{
+		{Source: "The ferry left. We waited on the pier.", Target: "渡轮开走了。我们在码头上等着。"},
+		{Source: "A pier & a ferry", Target: "码头和渡轮"},
+	},
+	Vocabulary: []Entry{{Word: "pier", Meaning: "码头"}, {Word: "ferry", Meaning: "渡轮"}, {Word: "harbour", Meaning: "港口"}},
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, testBook); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`<title>Notes &lt;draft&gt;</title>`,
+		`<p class="source" lang="en"><span class="t0">The <a class="vocab" href="#v2">ferry</a> left.</span> <span class="t1">We waited on the <a class="vocab" href="#v1">pier</a>.</span></p>`,
+		`<p class="target" lang="zh-Hans"><span class="t0">渡轮开走了。</span> <span class="t1">我们在码头上等着。</span></p>`,
+		`<p class="source" lang="en">A pier &amp; a ferry</p>`,
+		`<dt id="v1" lang="en">pier</dt>` + "\n" + `<dd>码头 <a href="#p1">&#8617;</a></dd>`,
+		`<dd>港口</dd>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteEPUB(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteEPUB(&buf, testBook); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first := archive.File[0]; first.Name != "mimetype" || first.Method != zip.Store {
+		t.Fatalf("mimetype must be the first, stored entry: %+v", first.FileHeader)
+	}
+	files := make(map[string]string)
+	for _, file := range archive.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, _ := io.ReadAll(r)
+		r.Close()
+		files[file.Name] = string(data)
+		if strings.HasSuffix(file.Name, ".xhtml") || strings.HasSuffix(file.Name, ".opf") || strings.HasSuffix(file.Name, ".xml") {
+			decoder := xml.NewDecoder(strings.NewReader(files[file.Name]))
+			for {
+				if _, err := decoder.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("%s is not well-formed: %v", file.Name, err)
+				}
+			}
+		}
+	}
+	if !strings.Contains(files["OEBPS/text.xhtml"], `href="vocabulary.xhtml#v1"`) || !strings.Contains(files["OEBPS/vocabulary.xhtml"], `href="text.xhtml#p1"`) {
+		t.Fatalf("chapters are not linked:\n%s\n%s", files["OEBPS/text.xhtml"], files["OEBPS/vocabulary.xhtml"])
+	}
+	if !strings.Contains(files["OEBPS/content.opf"], "<dc:language>zh-Hans</dc:language>") {
+		t.Fatalf("unexpected package document:\n%s", files["OEBPS/content.opf"])
+	}
+}
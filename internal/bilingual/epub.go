@@ -0,0 +1,128 @@
+package bilingual
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"fmt"
+	"html"
+	"io"
+	"time"
+)
+
+const (
+	textFile       = "text.xhtml"
+	vocabularyFile = "vocabulary.xhtml"
+)
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles>
+<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+</rootfiles>
+</container>
+`
+
+// WriteEPUB writes book as an EPUB 3 file with the parallel text and the
+// vocabulary appendix as separate chapters.
+func WriteEPUB(w io.Writer, book Book) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	text, vocabulary := book.body(textFile, vocabularyFile)
+	files := []struct {
+		name, content string
+	}{
+		{"META-INF/container.xml", containerXML},
+		{"OEBPS/content.opf", book.packageDocument(now, vocabulary != "")},
+		{"OEBPS/nav.xhtml", book.xhtml("Contents", book.navigation(vocabulary != ""))},
+		{"OEBPS/style.css", stylesheet},
+		{"OEBPS/" + textFile, book.xhtml(book.Title, "<h1>"+html.EscapeString(book.Title)+"</h1>\n"+text)},
+	}
+	if vocabulary != "" {
+		files = append(files, struct{ name, content string }{"OEBPS/" + vocabularyFile, book.xhtml("Vocabulary", vocabulary)})
+	}
+
+	archive := zip.NewWriter(w)
+	// The mimetype entry must come first and be stored uncompressed.
+	mimetype, err := archive.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store, Modified: now})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return err
+	}
+	for _, file := range files {
+		entry, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(entry, file.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func (b Book) xhtml(title, body string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="%[1]s" xml:lang="%[1]s">
+<head>
+<meta charset="utf-8"/>
+<title>%[2]s</title>
+<link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+%[3]s</body>
+</html>
+`, languageTag(b.TargetLanguage), html.EscapeString(title), body)
+}
+
+func (b Book) navigation(vocabulary bool) string {
+	nav := "<nav epub:type=\"toc\" id=\"toc\">\n<ol>\n<li><a href=\"" + textFile + "\">" + html.EscapeString(b.Title) + "</a></li>\n"
+	if vocabulary {
+		nav += "<li><a href=\"" + vocabularyFile + "\">Vocabulary</a></li>\n"
+	}
+	return nav + "</ol>\n</nav>\n"
+}
+
+func (b Book) packageDocument(modified time.Time, vocabulary bool) string {
+	manifest := `<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+<item id="style" href="style.css" media-type="text/css"/>
+<item id="text" href="` + textFile + `" media-type="application/xhtml+xml"/>
+`
+	spine := "<itemref idref=\"text\"/>\n"
+	if vocabulary {
+		manifest += `<item id="vocabulary" href="` + vocabularyFile + `" media-type="application/xhtml+xml"/>
+`
+		spine += "<itemref idref=\"vocabulary\"/>\n"
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="id">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:identifier id="id">%s</dc:identifier>
+<dc:title>%s</dc:title>
+<dc:language>%s</dc:language>
+<meta property="dcterms:modified">%s</meta>
+</metadata>
+<manifest>
+%s</manifest>
+<spine>
+%s</spine>
+</package>
+`, b.identifier(), html.EscapeString(b.Title), languageTag(b.TargetLanguage), modified.Format(time.RFC3339), manifest, spine)
+}
+
+// identifier derives a stable UUID from the source text and target
+// language, so rebuilding a translation replaces the book in a reader's
+// library instead of adding a copy.
+func (b Book) identifier() string {
+	hash := sha1.New()
+	io.WriteString(hash, b.TargetLanguage+"\x00")
+	for _, section := range b.Sections {
+		io.WriteString(hash, section.Source)
+		io.WriteString(hash, "\x00")
+	}
+	sum := hash.Sum(nil)
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
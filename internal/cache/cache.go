@@ -0,0 +1,289 @@
+// Package cache keeps LLM responses on disk, keyed by a hash of the
+// provider settings and the request, so a repeated request is answered
+// without calling the provider.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"dict-be/internal/llm"
+)
+
+// Store is a directory of cached responses, one JSON file per key.
+// Entries older than ttl are ignored and removed; when the files exceed
+// maxBytes the oldest are removed. Zero ttl or maxBytes means no limit.
+type Store struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+	now      func() time.Time
+}
+
+func NewStore(dir string, ttl time.Duration, maxBytes int64) *Store {
+	return &Store{dir: dir, ttl: ttl, maxBytes: maxBytes, now: time.Now}
+}
+
+// DefaultDir returns ~/.dict-be/cache.
+func DefaultDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir: %w", err)
+	}
+	return filepath.Join(homeDir, ".dict-be", "cache"), nil
+}
+
+type entry struct {
+	Created  time.Time        `json:"created"`
+	Response llm.ChatResponse `json:"response"`
+}
+
+// Key hashes namespace, which names the provider settings that shape an
+// answer, together with req.
+func Key(namespace string, req llm.ChatRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("encode cache key: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(namespace+"\x00"), data...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+// Get returns the response cached for key. Unreadable and expired entries
+// are misses.
+func (s *Store) Get(key string) (llm.ChatResponse, bool) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return llm.ChatResponse{}, false
+	}
+	var cached entry
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return llm.ChatResponse{}, false
+	}
+	if s.expired(cached.Created) {
+		os.Remove(s.path(key))
+		return llm.ChatResponse{}, false
+	}
+	return cached.Response, true
+}
+
+func (s *Store) expired(created time.Time) bool {
+	return s.ttl > 0 && s.now().Sub(created) > s.ttl
+}
+
+// Put caches resp under key, then removes expired entries and the oldest
+// ones beyond the size limit.
+func (s *Store) Put(key string, resp llm.ChatResponse) error {
+	data, err := json.Marshal(entry{Created: s.now().UTC(), Response: resp})
+	if err != nil {
+		return fmt.Errorf("encode cache entry: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	return s.prune()
+}
+
+type file struct {
+	path    string
+	size    int64
+	created time.Time
+}
+
+// files lists the cache entries, oldest first. A missing directory is an
+// empty cache.
+func (s *Store) files() ([]file, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache: %w", err)
+	}
+	var files []file
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{path: filepath.Join(s.dir, name), size: info.Size(), created: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].created.Before(files[j].created) })
+	return files, nil
+}
+
+func (s *Store) prune() error {
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+	var total int64
+	kept := files[:0]
+	for _, f := range files {
+		if s.expired(f.created) {
+			os.Remove(f.path)
+			continue
+		}
+		kept = append(kept, f)
+		total += f.size
+	}
+	for i := 0; s.maxBytes > 0 && total > s.maxBytes && i < len(kept); i++ {
+		if err := os.Remove(kept[i].path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("prune cache: %w", err)
+		}
+		total -= kept[i].size
+	}
+	return nil
+}
+
+// Stats describes the cache contents.
+type Stats struct {
+	Entries int
+	Bytes   int64
+	Oldest  time.Time
+	Newest  time.Time
+}
+
+// Stats counts the entries that have not expired.
+func (s *Store) Stats() (Stats, error) {
+	files, err := s.files()
+	if err != nil {
+		return Stats{}, err
+	}
+	var stats Stats
+	for _, f := range files {
+		if s.expired(f.created) {
+			continue
+		}
+		if stats.Entries == 0 {
+			stats.Oldest = f.created
+		}
+		stats.Entries++
+		stats.Bytes += f.size
+		stats.Newest = f.created
+	}
+	return stats, nil
+}
+
+// Clear removes every entry and returns how many there were.
+func (s *Store) Clear() (int, error) {
+	files, err := s.files()
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range files {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return 0, fmt.Errorf("clear cache: %w", err)
+		}
+	}
+	return len(files), nil
+}
+
+// Options configure Wrap.
+type Options struct {
+	// Namespace names the provider settings that shape an answer without
+	// showing in the request, such as the provider and its URL.
+	Namespace string
+	// Refresh sends every request to the provider and replaces the cached
+	// answer.
+	Refresh bool
+	// Warn receives failures to write the cache, which do not fail the
+	// request.
+	Warn func(error)
+}
+
+type client struct {
+	client llm.Client
+	store  *Store
+	opts   Options
+}
+
+// Wrap answers requests from store when it can and caches the complete
+// answers of the others. A cached answer reports no token usage, since
+// none was spent.
+func Wrap(c llm.Client, store *Store, opts Options) llm.Client {
+	return &client{client: c, store: store, opts: opts}
+}
+
+func (c *client) Unwrap() llm.Client { return c.client }
+
+func (c *client) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	key, err := Key(c.opts.Namespace, req)
+	if err != nil {
+		return c.client.Chat(ctx, req)
+	}
+	if resp, ok := c.get(key); ok {
+		return resp, nil
+	}
+	resp, err := c.client.Chat(ctx, req)
+	c.put(key, resp, err)
+	return resp, err
+}
+
+func (c *client) ChatStream(ctx context.Context, req llm.ChatRequest, handle llm.StreamHandler) (llm.ChatResponse, error) {
+	key, err := Key(c.opts.Namespace, req)
+	if err != nil {
+		return c.client.ChatStream(ctx, req, handle)
+	}
+	if resp, ok := c.get(key); ok {
+		if handle != nil && resp.Content != "" {
+			if err := handle(resp.Content); err != nil {
+				return llm.ChatResponse{}, err
+			}
+		}
+		return resp, nil
+	}
+	resp, err := c.client.ChatStream(ctx, req, handle)
+	c.put(key, resp, err)
+	return resp, err
+}
+
+func (c *client) get(key string) (llm.ChatResponse, bool) {
+	if c.opts.Refresh {
+		return llm.ChatResponse{}, false
+	}
+	resp, ok := c.store.Get(key)
+	resp.Usage = llm.Usage{}
+	return resp, ok
+}
+
+func (c *client) put(key string, resp llm.ChatResponse, err error) {
+	if err != nil || resp.Content == "" {
+		return
+	}
+	if err := c.store.Put(key, resp); err != nil && c.opts.Warn != nil {
+		c.opts.Warn(err)
+	}
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dict-be/internal/llm"
+)
+
+type countingClient struct {
+	calls int
+}
+
+func (c *countingClient) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	c.calls++
+	return llm.ChatResponse{Content: "码头", Model: req.Model, Usage: llm.Usage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12}}, nil
+}
+
+func (c *countingClient) ChatStream(ctx context.Context, req llm.ChatRequest, handle llm.StreamHandler) (llm.ChatResponse, error) {
+	resp, err := c.Chat(ctx, req)
+	if err == nil && handle != nil {
+		err = handle(resp.Content)
+	}
+	return resp, err
+}
+
+func request(text string) llm.ChatRequest {
+	return llm.ChatRequest{Model: "m", Messages: []llm.Message{{Role: "user", Content: text}}}
+}
+
+func TestWrap(t *testing.T) {
+	store := NewStore(t.TempDir(), time.Hour, 0)
+	provider := &countingClient{}
+	client := Wrap(provider, store, Options{Namespace: "openai"})
+
+	first, err := client.Chat(context.Background(), request("pier"))
+	if err != nil || first.Usage.TotalTokens != 12 {
+		t.Fatalf("unexpected first response: %+v, %v", first, err)
+	}
+	var streamed string
+	second, err := client.ChatStream(context.Background(), request("pier"), func(delta string) error {
+		streamed += delta
+		return nil
+	})
+	if err != nil || second.Content != "码头" || streamed != "码头" || !second.Usage.IsZero() {
+		t.Fatalf("unexpected cached response: %+v, %q, %v", second, streamed, err)
+	}
+	if provider.calls != 1 {
+		t.Fatalf("expected one provider call, got %d", provider.calls)
+	}
+	if _, err := Wrap(provider, store, Options{Namespace: "anthropic"}).Chat(context.Background(), request("pier")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Chat(context.Background(), request("run")); err != nil {
+		t.Fatal(err)
+	}
+	if provider.calls != 3 {
+		t.Fatalf("expected another provider and input to miss, got %d calls", provider.calls)
+	}
+	refreshed, err := Wrap(provider, store, Options{Namespace: "openai", Refresh: true}).Chat(context.Background(), request("pier"))
+	if err != nil || provider.calls != 4 || refreshed.Usage.IsZero() {
+		t.Fatalf("expected refresh to call the provider, got %+v, %d calls, %v", refreshed, provider.calls, err)
+	}
+}
+
+func TestStoreExpiryAndLimits(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	store := NewStore(dir, time.Hour, 0)
+	store.now = func() time.Time { return now }
+	resp := llm.ChatResponse{Content: "码头"}
+	if err := store.Put("a", resp); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Get("a"); !ok {
+		t.Fatalf("expected a hit")
+	}
+	now = now.Add(2 * time.Hour)
+	if _, ok := store.Get("a"); ok {
+		t.Fatalf("expected the entry to expire")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected the expired entry to be removed, got %v", err)
+	}
+
+	store = NewStore(dir, 0, 0)
+	for i, key := range []string{"b", "c", "d"} {
+		if err := store.Put(key, resp); err != nil {
+			t.Fatal(err)
+		}
+		modified := time.Date(2026, 3, 1, 8, i, 0, 0, time.UTC)
+		if err := os.Chtimes(filepath.Join(dir, key+".json"), modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats, err := store.Stats()
+	if err != nil || stats.Entries != 3 || !stats.Oldest.Before(stats.Newest) {
+		t.Fatalf("unexpected stats: %+v, %v", stats, err)
+	}
+	store.maxBytes = stats.Bytes * 2 / 3
+	if err := store.prune(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Get("b"); ok {
+		t.Fatalf("expected the oldest entry to be pruned")
+	}
+	if _, ok := store.Get("d"); !ok {
+		t.Fatalf("expected the newest entry to be kept")
+	}
+	if n, err := store.Clear(); err != nil || n != 2 {
+		t.Fatalf("unexpected clear: %d, %v", n, err)
+	}
+	if stats, _ := store.Stats(); stats.Entries != 0 {
+		t.Fatalf("expected an empty cache, got %+v", stats)
+	}
+}
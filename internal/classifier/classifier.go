@@ -0,0 +1,126 @@
+package classifier
+
+import "strings"
+
+// Classifier is a Chinese measure word.
+type Classifier struct {
+	Hanzi  string
+	Pinyin string
+}
+
+// Entry lists the measure words used with a noun.
+type Entry struct {
+	Noun        string
+	Pinyin      string
+	English     string
+	Classifiers []Classifier
+}
+
+// Lookup finds a noun by its Chinese form or English gloss.
+func Lookup(noun string) (Entry, bool) {
+	noun = strings.TrimSpace(noun)
+	key := strings.ToLower(noun)
+	for _, entry := range entries {
+		if entry.Noun == noun || strings.ToLower(entry.English) == key {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Examples builds two template phrases with a noun and one of its
+// classifiers, for display when no LLM call is made. Entries only list
+// classifiers that read naturally in them: the polite 位 is listed for
+// 老师 but not for 人, since "一位人" is wrong.
+func Examples(entry Entry, cl Classifier) []string {
+	return []string{
+		"这是一" + cl.Hanzi + entry.Noun + "。",
+		"我有两" + cl.Hanzi + entry.Noun + "。",
+	}
+}
+
+var (
+	ben        = Classifier{"本", "běn"}
+	ge         = Classifier{"个", "gè"}
+	zhiAnimal  = Classifier{"只", "zhī"}
+	zhiStick   = Classifier{"支", "zhī"}
+	tiao       = Classifier{"条", "tiáo"}
+	zhang      = Classifier{"张", "zhāng"}
+	liang      = Classifier{"辆", "liàng"}
+	jian       = Classifier{"件", "jiàn"}
+	bei        = Classifier{"杯", "bēi"}
+	ba         = Classifier{"把", "bǎ"}
+	wei        = Classifier{"位", "wèi"}
+	ke         = Classifier{"棵", "kē"}
+	zuo        = Classifier{"座", "zuò"}
+	jia        = Classifier{"家", "jiā"}
+	tai        = Classifier{"台", "tái"}
+	bu         = Classifier{"部", "bù"}
+	feng       = Classifier{"封", "fēng"}
+	shuang     = Classifier{"双", "shuāng"}
+	pi         = Classifier{"匹", "pǐ"}
+	tou        = Classifier{"头", "tóu"}
+	duo        = Classifier{"朵", "duǒ"}
+	pian       = Classifier{"篇", "piān"}
+	shou       = Classifier{"首", "shǒu"}
+	jiaMachine = Classifier{"架", "jià"}
+	ding       = Classifier{"顶", "dǐng"}
+	kuai       = Classifier{"块", "kuài"}
+	jianRoom   = Classifier{"间", "jiān"}
+	keRound    = Classifier{"颗", "kē"}
+	fen        = Classifier{"份", "fèn"}
+	dao        = Classifier{"道", "dào"}
+)
+
+var entries = []Entry{
+	{"书", "shū", "book", []Classifier{ben}},
+	{"杂志", "zázhì", "magazine", []Classifier{ben}},
+	{"人", "rén", "person", []Classifier{ge}},
+	{"老师", "lǎoshī", "teacher", []Classifier{wei, ge}},
+	{"苹果", "píngguǒ", "apple", []Classifier{ge}},
+	{"猫", "māo", "cat", []Classifier{zhiAnimal}},
+	{"狗", "gǒu", "dog", []Classifier{zhiAnimal, tiao}},
+	{"鸟", "niǎo", "bird", []Classifier{zhiAnimal}},
+	{"笔", "bǐ", "pen", []Classifier{zhiStick}},
+	{"鱼", "yú", "fish", []Classifier{tiao}},
+	{"河", "hé", "river", []Classifier{tiao}},
+	{"裤子", "kùzi", "trousers", []Classifier{tiao}},
+	{"路", "lù", "road", []Classifier{tiao}},
+	{"纸", "zhǐ", "paper", []Classifier{zhang}},
+	{"桌子", "zhuōzi", "table", []Classifier{zhang}},
+	{"票", "piào", "ticket", []Classifier{zhang}},
+	{"床", "chuáng", "bed", []Classifier{zhang}},
+	{"车", "chē", "car", []Classifier{liang}},
+	{"自行车", "zìxíngchē", "bicycle", []Classifier{liang}},
+	{"衣服", "yīfu", "clothes", []Classifier{jian}},
+	{"事", "shì", "matter", []Classifier{jian}},
+	{"咖啡", "kāfēi", "coffee", []Classifier{bei}},
+	{"茶", "chá", "tea", []Classifier{bei}},
+	{"椅子", "yǐzi", "chair", []Classifier{ba}},
+	{"伞", "sǎn", "umbrella", []Classifier{ba}},
+	{"刀", "dāo", "knife", []Classifier{ba}},
+	{"树", "shù", "tree", []Classifier{ke}},
+	{"山", "shān", "mountain", []Classifier{zuo}},
+	{"桥", "qiáo", "bridge", []Classifier{zuo}},
+	{"公司", "gōngsī", "company", []Classifier{jia}},
+	{"饭馆", "fànguǎn", "restaurant", []Classifier{jia}},
+	{"电脑", "diànnǎo", "computer", []Classifier{tai}},
+	{"电视", "diànshì", "television", []Classifier{tai}},
+	{"电影", "diànyǐng", "film", []Classifier{bu}},
+	{"手机", "shǒujī", "mobile phone", []Classifier{bu}},
+	{"信", "xìn", "letter", []Classifier{feng}},
+	{"鞋", "xié", "shoes", []Classifier{shuang}},
+	{"筷子", "kuàizi", "chopsticks", []Classifier{shuang}},
+	{"马", "mǎ", "horse", []Classifier{pi}},
+	{"牛", "niú", "cow", []Classifier{tou}},
+	{"花", "huā", "flower", []Classifier{duo}},
+	{"文章", "wénzhāng", "article", []Classifier{pian}},
+	{"歌", "gē", "song", []Classifier{shou}},
+	{"飞机", "fēijī", "airplane", []Classifier{jiaMachine}},
+	{"帽子", "màozi", "hat", []Classifier{ding}},
+	{"蛋糕", "dàngāo", "cake", []Classifier{kuai}},
+	{"房间", "fángjiān", "room", []Classifier{jianRoom}},
+	{"星星", "xīngxing", "star", []Classifier{keRound}},
+	{"报纸", "bàozhǐ", "newspaper", []Classifier{fen}},
+	{"菜", "cài", "dish", []Classifier{dao}},
+}
@@ -0,0 +1,36 @@
+package classifier
+
+import "testing"
+
+func TestLookupByChineseAndEnglish(t *testing.T) {
+	entry, ok := Lookup("书")
+	if !ok || entry.Classifiers[0].Hanzi != "本" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	entry, ok = Lookup(" Dog ")
+	if !ok || entry.Noun != "狗" || len(entry.Classifiers) != 2 {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	if _, ok := Lookup("spaceship"); ok {
+		t.Fatalf("expected miss")
+	}
+}
+
+func TestExamples(t *testing.T) {
+	entry, _ := Lookup("猫")
+	examples := Examples(entry, entry.Classifiers[0])
+	if examples[0] != "这是一只猫。" || examples[1] != "我有两只猫。" {
+		t.Fatalf("unexpected examples: %q", examples)
+	}
+}
+
+func TestExamplesPerson(t *testing.T) {
+	entry, _ := Lookup("人")
+	var examples []string
+	for _, cl := range entry.Classifiers {
+		examples = append(examples, Examples(entry, cl)...)
+	}
+	if len(examples) != 2 || examples[0] != "这是一个人。" || examples[1] != "我有两个人。" {
+		t.Fatalf("unexpected examples: %q", examples)
+	}
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"dict-be/internal/abbrev"
+
+	"github.com/spf13/cobra"
+)
+
+type acronymOptions struct {
+	InputFile      string
+	OutputLanguage string
+	Domain         string
+	Offline        bool
+	Output         outputOptions
+}
+
+func newAcronymCmd() *cobra.Command {
+	opts := &acronymOptions{}
+	cmd := &cobra.Command{
+		Use:   "acronym [text...]",
+		Short: "Expand abbreviations and acronyms with translations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAcronym(cmd, opts, args)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.InputFile, "file", "F", "", "input file, use -F- for stdin")
+	addOutputLanguageFlag(cmd, &opts.OutputLanguage, "o", "Simplified Chinese", "translation language")
+	cmd.Flags().StringVar(&opts.Domain, "domain", "", "preferred domain, e.g. tech, medical, finance")
+	cmd.Flags().BoolVar(&opts.Offline, "offline", false, "only use the offline abbreviation table")
+	opts.Output.addFlags(cmd)
+	return cmd
+}
+
+func runAcronym(cmd *cobra.Command, opts *acronymOptions, args []string) error {
+	if err := opts.Output.validate(); err != nil {
+		return err
+	}
+	input, err := readInput(args, opts.InputFile, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	tokens := abbrev.Tokenize(input)
+	if len(tokens) == 0 {
+		return fmt.Errorf("input is required")
+	}
+	domain := strings.ToLower(strings.TrimSpace(opts.Domain))
+	hints := acronymHints(tokens, domain)
+
+	if opts.Offline {
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), hints)
+		return err
+	}
+
+	domainInstruction := ""
+	if domain != "" {
+		domainInstruction = fmt.Sprintf(", preferring the %s domain", domain)
+	}
+	return runPromptCommand(cmd, &opts.Output, "acronym", map[string]string{
+		"input":              strings.Join(tokens, ", "),
+		"output_language":    opts.OutputLanguage,
+		"domain_instruction": domainInstruction,
+		"hints":              hints,
+	})
+}
+
+// acronymHints formats offline table matches, one line per abbreviation.
+func acronymHints(tokens []string, domain string) string {
+	lines := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		matches := abbrev.Lookup(token, domain)
+		if len(matches) == 0 && domain != "" {
+			matches = abbrev.Lookup(token, "")
+		}
+		if len(matches) == 0 {
+			lines = append(lines, fmt.Sprintf("%s: (not in offline table)", token))
+			continue
+		}
+		meanings := make([]string, 0, len(matches))
+		for _, match := range matches {
+			meanings = append(meanings, fmt.Sprintf("%s [%s]", match.Expansion, match.Domain))
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", token, strings.Join(meanings, "; ")))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,11 @@
+package cli
+
+import "testing"
+
+func TestAcronymHints(t *testing.T) {
+	hints := acronymHints([]string{"LGTM", "BP", "XYZZY"}, "medical")
+	expected := "LGTM: looks good to me [tech]\nBP: blood pressure [medical]\nXYZZY: (not in offline table)"
+	if hints != expected {
+		t.Fatalf("unexpected hints:\n%s", hints)
+	}
+}
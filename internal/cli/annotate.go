@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type annotateOptions struct {
+	InputFile string
+	Language  string
+	Style     string
+	Gloss     bool
+	NoKnown   bool
+	Output    outputOptions
+}
+
+// annotationScript describes how readings are written for a language.
+type annotationScript struct {
+	Language string
+	Reading  string
+	Target   string
+}
+
+var annotationScripts = map[string]annotationScript{
+	"ja": {Language: "Japanese", Reading: "furigana (hiragana)", Target: "word containing kanji"},
+	"zh": {Language: "Chinese", Reading: "pinyin with tone marks", Target: "Chinese character or word"},
+	"ko": {Language: "Korean", Reading: "Revised Romanization", Target: "word written in Hangul or Hanja"},
+}
+
+func newAnnotateCmd() *cobra.Command {
+	opts := &annotateOptions{}
+	cmd := &cobra.Command{
+		Use:   "annotate [text...]",
+		Short: "Annotate text with pronunciation readings such as furigana",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAnnotate(cmd, opts, args)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.InputFile, "file", "F", "", "input file, use -F- for stdin")
+	cmd.Flags().StringVar(&opts.Language, "lang", "ja", "text language: ja, zh or ko")
+	cmd.Flags().StringVar(&opts.Style, "style", "bracket", "annotation style: bracket or ruby")
+	cmd.Flags().BoolVar(&opts.Gloss, "gloss", false, "append a per-word gloss list")
+	cmd.Flags().BoolVar(&opts.NoKnown, "no-known", false, "also annotate words in the known-words list")
+	opts.Output.addFlags(cmd)
+	return cmd
+}
+
+func runAnnotate(cmd *cobra.Command, opts *annotateOptions, args []string) error {
+	if err := opts.Output.validate(); err != nil {
+		return err
+	}
+	script, ok := annotationScripts[opts.Language]
+	if !ok {
+		return fmt.Errorf("unsupported language: %s (expected ja, zh or ko)", opts.Language)
+	}
+	styleInstruction, err := annotationStyleInstruction(opts.Style)
+	if err != nil {
+		return err
+	}
+	input, err := readInput(args, opts.InputFile, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(input) == "" {
+		return fmt.Errorf("input is required")
+	}
+	knownInstruction, err := knownWordsInstruction(input, !opts.NoKnown)
+	if err != nil {
+		return err
+	}
+	glossInstruction := "Output only the annotated text."
+	if opts.Gloss {
+		glossInstruction = "After the annotated text, add a blank line and a list with one line per distinct word: the word, its reading, and a brief English gloss."
+	}
+	return runPromptCommand(cmd, &opts.Output, "annotate", map[string]string{
+		"input":             input,
+		"language":          script.Language,
+		"reading":           script.Reading,
+		"target":            script.Target,
+		"style_instruction": styleInstruction,
+		"gloss_instruction": glossInstruction,
+		"known_instruction": knownInstruction,
+	})
+}
+
+func annotationStyleInstruction(style string) (string, error) {
+	switch style {
+	case "bracket":
+		return "Write each reading in square brackets right after the annotated word, for example 漢字[かんじ].", nil
+	case "ruby":
+		return "Wrap each annotated word in HTML ruby markup, for example <ruby>漢字<rt>かんじ</rt></ruby>.", nil
+	default:
+		return "", fmt.Errorf("invalid style: %s (expected bracket or ruby)", style)
+	}
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnnotationStyleInstruction(t *testing.T) {
+	instruction, err := annotationStyleInstruction("ruby")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(instruction, "<ruby>") {
+		t.Fatalf("unexpected instruction: %q", instruction)
+	}
+	if _, err := annotationStyleInstruction("inline"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestBuildAnnotatePrompts(t *testing.T) {
+	script := annotationScripts["ja"]
+	systemPrompt, userPrompt, err := buildPrompts("annotate", map[string]string{
+		"input":             "日本語",
+		"language":          script.Language,
+		"reading":           script.Reading,
+		"target":            script.Target,
+		"style_instruction": "style",
+		"gloss_instruction": "gloss",
+		"known_instruction": "",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, "furigana") || strings.Contains(systemPrompt, "{{") {
+		t.Fatalf("unexpected system prompt: %q", systemPrompt)
+	}
+	if !strings.Contains(userPrompt, "<input>日本語</input>") {
+		t.Fatalf("unexpected user prompt: %q", userPrompt)
+	}
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"dict-be/internal/audit"
+	"dict-be/internal/config"
+	"dict-be/internal/llm"
+
+	"github.com/spf13/cobra"
+)
+
+// auditClient logs requests to audit.path when it is configured. It wraps
+// the provider client directly, so input refused by the secret guard is
+// never logged as sent.
+func auditClient(cmd *cobra.Command, cfg config.Config, client llm.Client) llm.Client {
+	if cfg.Audit.Path == "" {
+		return client
+	}
+	return audit.Wrap(client, audit.NewLogger(cfg.Audit.Path), audit.Options{
+		Command:         cmd.CommandPath(),
+		Provider:        cfg.LLM.Type,
+		Prices:          auditPrices(cfg),
+		InternalDomains: cfg.Secrets.InternalDomains,
+	})
+}
+
+// auditPrices converts the configured audit.prices.
+func auditPrices(cfg config.Config) []audit.Price {
+	prices := make([]audit.Price, 0, len(cfg.Audit.Prices))
+	for _, price := range cfg.Audit.Prices {
+		prices = append(prices, audit.Price{Model: price.Model, Input: price.Input, Output: price.Output})
+	}
+	return prices
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"dict-be/internal/config"
+	"dict-be/internal/llm"
+
+	"github.com/spf13/cobra"
+)
+
+func TestAuditClientDisabled(t *testing.T) {
+	inner := &fakeClient{}
+	if client := auditClient(&cobra.Command{}, config.Config{}, inner); client != inner {
+		t.Fatalf("expected client to be unwrapped without audit.path")
+	}
+}
+
+func TestAuditClientSkipsBlockedInput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	cmd, _ := newGuardTestCmd(false)
+	cmd.Use = "query"
+	cfg := config.Config{
+		LLM:     config.LLMConfig{Type: "openai"},
+		Secrets: config.SecretsConfig{Mode: secretsModeBlock},
+		Audit:   config.AuditConfig{Path: path},
+	}
+	client := guardClient(cmd, cfg, auditClient(cmd, cfg, &fakeClient{resp: llm.ChatResponse{Content: "ok"}}))
+
+	blocked := llm.ChatRequest{Messages: buildMessages("system", "token sk-abcdefghijklmnopqrstuvwx")}
+	if _, err := client.Chat(context.Background(), blocked); err == nil {
+		t.Fatalf("expected block error")
+	}
+	if _, err := client.Chat(context.Background(), llm.ChatRequest{Messages: buildMessages("system", "hello")}); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"command":"query"`) || !strings.Contains(lines[0], `"provider":"openai"`) {
+		t.Fatalf("unexpected audit log: %s", data)
+	}
+}
@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"dict-be/internal/config"
+	"dict-be/internal/llm"
+	"dict-be/internal/progress"
+
+	"github.com/spf13/cobra"
+)
+
+// Formats accepted by batch --format.
+const (
+	batchJSONL = "jsonl"
+	batchCSV   = "csv"
+)
+
+type batchOptions struct {
+	InputFile      string
+	InputLanguage  string
+	OutputLanguage string
+	Sections       string
+	Format         string
+	Output         string
+	Concurrency    int
+	Retries        int
+	Progress       string
+}
+
+// batchLine is a non-blank line of the input file and its line number.
+type batchLine struct {
+	Number int
+	Text   string
+}
+
+// batchRow is one line of output: the structured answer, or the error
+// that remained after the retries.
+type batchRow struct {
+	Line int `json:"line"`
+	queryAnswer
+	Error string `json:"error,omitempty"`
+}
+
+// batchRun holds what every request of a batch shares.
+type batchRun struct {
+	Config         config.Config
+	Model          string
+	InputLanguage  string
+	OutputLanguage string
+	Sections       []string
+	Concurrency    int
+	Retries        int
+	Bar            *progress.Reporter
+}
+
+func newBatchCmd() *cobra.Command {
+	opts := &batchOptions{}
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Translate each line of a file concurrently into JSONL or CSV",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBatch(cmd, opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.InputFile, "file", "F", "", "file with one word or sentence per line, use -F- for stdin")
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "o")
+	cmd.Flags().StringVar(&opts.Sections, "sections", "translation", "comma-separated sections: translation,difficulties,mnemonics,examples,frequency")
+	cmd.Flags().StringVar(&opts.Format, "format", batchJSONL, "output format: jsonl or csv")
+	cmd.Flags().StringVar(&opts.Output, "output", "", "output file (default: stdout)")
+	cmd.Flags().IntVarP(&opts.Concurrency, "concurrency", "j", 4, "number of lines translated at the same time")
+	cmd.Flags().IntVar(&opts.Retries, "retries", 1, "times to retry the failed lines after all others are done")
+	addProgressFlag(cmd, &opts.Progress)
+	return cmd
+}
+
+func runBatch(cmd *cobra.Command, opts *batchOptions) error {
+	if opts.InputFile == "" {
+		return fmt.Errorf("--file is required")
+	}
+	if opts.Format != batchJSONL && opts.Format != batchCSV {
+		return fmt.Errorf("invalid format: %s (expected jsonl or csv)", opts.Format)
+	}
+	if opts.Concurrency < 1 {
+		return fmt.Errorf("invalid --concurrency: %d", opts.Concurrency)
+	}
+	if opts.Retries < 0 {
+		return fmt.Errorf("invalid --retries: %d", opts.Retries)
+	}
+	if err := progress.ParseMode(opts.Progress); err != nil {
+		return err
+	}
+	sections, err := parseQuerySections(opts.Sections)
+	if err != nil {
+		return err
+	}
+	if len(sections) == 0 {
+		return fmt.Errorf("--sections is required")
+	}
+	data, err := readSource(opts.InputFile, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	lines := splitBatchLines(string(data))
+	if len(lines) == 0 {
+		return fmt.Errorf("input is required")
+	}
+	client, cfg, err := loadLLMClient(cmd)
+	if err != nil {
+		return err
+	}
+	if client, err = cacheClient(cmd, cfg, client, false); err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if opts.Output != "" {
+		file, err := os.Create(opts.Output)
+		if err != nil {
+			return fmt.Errorf("create output: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	write, flush := batchWriter(out, opts.Format, sections)
+
+	bar := newProgress(cmd, opts.Progress, len(lines), "lines")
+	defer bar.Finish()
+	client = meterCost(client, cfg, func(usd float64) { bar.AddCost(usd) })
+	run := batchRun{
+		Config:         cfg,
+		Model:          cfg.LLM.Model,
+		InputLanguage:  opts.InputLanguage,
+		OutputLanguage: opts.OutputLanguage,
+		Sections:       sections,
+		Concurrency:    opts.Concurrency,
+		Retries:        opts.Retries,
+		Bar:            bar,
+	}
+	failed, err := translateBatch(commandContext(cmd), client, lines, run, func(row batchRow) error {
+		bar.Clear()
+		return write(row)
+	})
+	if flushErr := flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d lines failed", failed, len(lines))
+	}
+	return nil
+}
+
+// splitBatchLines keeps the non-blank lines with their line numbers.
+func splitBatchLines(data string) []batchLine {
+	var lines []batchLine
+	for i, text := range strings.Split(data, "\n") {
+		if text = strings.TrimSpace(text); text != "" {
+			lines = append(lines, batchLine{Number: i + 1, Text: text})
+		}
+	}
+	return lines
+}
+
+// translateBatch answers lines with up to run.Concurrency requests at a
+// time and passes each row to write as soon as it is ready, so rows come
+// out in completion order. Failed lines are tried again after each pass,
+// up to run.Retries more passes; those still failing are written with
+// their error and counted.
+func translateBatch(ctx context.Context, client llm.Client, lines []batchLine, run batchRun, write func(batchRow) error) (int, error) {
+	type result struct {
+		line batchLine
+		row  batchRow
+		err  error
+	}
+	pending := lines
+	for pass := 0; ; pass++ {
+		jobs := make(chan batchLine)
+		// Buffered, so workers never block once the batch is cancelled.
+		results := make(chan result, len(pending))
+		for range min(run.Concurrency, len(pending)) {
+			go func() {
+				for line := range jobs {
+					row, err := answerBatchLine(ctx, client, line, run)
+					results <- result{line: line, row: row, err: err}
+				}
+			}()
+		}
+		go func() {
+			defer close(jobs)
+			for _, line := range pending {
+				select {
+				case jobs <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		var failed []result
+		var writeErr error
+		for range pending {
+			var r result
+			select {
+			case r = <-results:
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+			if r.err != nil {
+				failed = append(failed, r)
+				continue
+			}
+			run.Bar.Done()
+			if writeErr == nil {
+				writeErr = write(r.row)
+			}
+		}
+		if writeErr != nil {
+			return 0, writeErr
+		}
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if len(failed) == 0 {
+			return 0, nil
+		}
+		if pass == run.Retries {
+			for _, r := range failed {
+				run.Bar.Fail()
+				row := batchRow{Line: r.line.Number, Error: r.err.Error()}
+				row.Word = r.line.Text
+				if err := write(row); err != nil {
+					return 0, err
+				}
+			}
+			return len(failed), nil
+		}
+		pending = pending[:0:0]
+		for _, r := range failed {
+			pending = append(pending, r.line)
+		}
+	}
+}
+
+func answerBatchLine(ctx context.Context, client llm.Client, line batchLine, run batchRun) (batchRow, error) {
+	inputLanguage, outputLanguage := resolveConfiguredLanguages(run.Config, line.Text, run.InputLanguage, run.OutputLanguage)
+	systemPrompt, userPrompt, err := buildQueryJSONPrompts(queryPrompt{
+		Input:          line.Text,
+		InputLanguage:  inputLanguage,
+		OutputLanguage: outputLanguage,
+		Sections:       run.Sections,
+	})
+	if err != nil {
+		return batchRow{}, err
+	}
+	resp, err := client.Chat(ctx, llm.ChatRequest{
+		Model:    run.Model,
+		Messages: buildMessages(systemPrompt, userPrompt),
+	})
+	if err != nil {
+		return batchRow{}, err
+	}
+	answer, err := parseQueryAnswer(resp.Content, line.Text, run.Sections)
+	if err != nil {
+		return batchRow{}, err
+	}
+	return batchRow{Line: line.Number, queryAnswer: answer}, nil
+}
+
+// batchWriter returns a function writing one row in format and one
+// flushing what is buffered. CSV has a column per requested section, with
+// list items on separate lines of the cell.
+func batchWriter(out io.Writer, format string, sections []string) (func(batchRow) error, func() error) {
+	if format == batchJSONL {
+		encoder := json.NewEncoder(out)
+		encoder.SetEscapeHTML(false)
+		return func(row batchRow) error { return encoder.Encode(row) }, func() error { return nil }
+	}
+	writer := csv.NewWriter(out)
+	header := append(append([]string{"line", "input"}, sections...), "error")
+	headerErr := writer.Write(header)
+	write := func(row batchRow) error {
+		if headerErr != nil {
+			return headerErr
+		}
+		record := []string{strconv.Itoa(row.Line), row.Word}
+		for _, section := range sections {
+			record = append(record, row.field(section))
+		}
+		if err := writer.Write(append(record, row.Error)); err != nil {
+			return err
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	flush := func() error {
+		writer.Flush()
+		return writer.Error()
+	}
+	return write, flush
+}
+
+func (r batchRow) field(section string) string {
+	switch section {
+	case "translation":
+		return r.Translation
+	case "difficulties":
+		return strings.Join(r.Difficulties, "\n")
+	case "mnemonics":
+		return strings.Join(r.Mnemonics, "\n")
+	case "examples":
+		lines := make([]string, len(r.Examples))
+		for i, example := range r.Examples {
+			lines[i] = example.Sentence + " | " + example.Translation
+		}
+		return strings.Join(lines, "\n")
+	case "frequency":
+		if r.FrequencyRank > 0 {
+			return strings.TrimSpace(fmt.Sprintf("rank %d (%s)\n%s", r.FrequencyRank, r.FrequencyBand, r.Frequency))
+		}
+		return r.Frequency
+	}
+	return ""
+}
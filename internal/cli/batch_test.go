@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"dict-be/internal/llm"
+)
+
+// flakyClient translates by echoing the input in upper case. Inputs in
+// failures fail that many times first; "broken" always fails.
+type flakyClient struct {
+	mu       sync.Mutex
+	failures map[string]int
+	active   int
+	peak     int
+}
+
+func (c *flakyClient) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	c.mu.Lock()
+	c.active++
+	c.peak = max(c.peak, c.active)
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.active--
+		c.mu.Unlock()
+	}()
+	prompt := req.Messages[len(req.Messages)-1].Content
+	input := prompt[strings.LastIndex(prompt, "<input>")+len("<input>") : strings.LastIndex(prompt, "</input>")]
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if input == "broken" {
+		return llm.ChatResponse{}, errors.New("bad gateway")
+	}
+	if c.failures[input] > 0 {
+		c.failures[input]--
+		return llm.ChatResponse{}, errors.New("rate limited")
+	}
+	return llm.ChatResponse{Content: fmt.Sprintf(`{"translation":%q}`, strings.ToUpper(input))}, nil
+}
+
+func (c *flakyClient) ChatStream(ctx context.Context, req llm.ChatRequest, handle llm.StreamHandler) (llm.ChatResponse, error) {
+	return c.Chat(ctx, req)
+}
+
+func TestTranslateBatch(t *testing.T) {
+	lines := splitBatchLines("pier\n\n  run \nbroken\ntide\nferry\n")
+	if len(lines) != 5 || lines[1] != (batchLine{Number: 3, Text: "run"}) {
+		t.Fatalf("unexpected lines: %+v", lines)
+	}
+	client := &flakyClient{failures: map[string]int{"run": 1, "tide": 2}}
+	run := batchRun{InputLanguage: "English", OutputLanguage: "French", Sections: []string{"translation"}, Concurrency: 2, Retries: 1}
+	var rows []batchRow
+	failed, err := translateBatch(context.Background(), client, lines, run, func(row batchRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Line < rows[j].Line })
+	var got []string
+	for _, row := range rows {
+		got = append(got, fmt.Sprintf("%d %s %s %s", row.Line, row.Word, row.Translation, row.Error))
+	}
+	want := []string{"1 pier PIER ", "3 run RUN ", "4 broken  bad gateway", "5 tide  rate limited", "6 ferry FERRY "}
+	if failed != 2 || strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected rows (%d failed):\n%s", failed, strings.Join(got, "\n"))
+	}
+	if client.peak > 2 {
+		t.Fatalf("expected at most 2 requests at a time, got %d", client.peak)
+	}
+}
+
+func TestBatchWriter(t *testing.T) {
+	row := batchRow{Line: 2, queryAnswer: queryAnswer{Word: "pier", Translation: "码头",
+		Examples: []queryExample{{Sentence: "On the pier.", Translation: "在码头上。"}, {Sentence: "A pier.", Translation: "一个码头。"}}}}
+	failedRow := batchRow{Line: 5, Error: "bad gateway"}
+	failedRow.Word = "broken"
+
+	var out bytes.Buffer
+	write, flush := batchWriter(&out, batchCSV, []string{"translation", "examples"})
+	for _, r := range []batchRow{row, failedRow} {
+		if err := write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := "line,input,translation,examples,error\n2,pier,码头,\"On the pier. | 在码头上。\nA pier. | 一个码头。\",\n5,broken,,,bad gateway\n"
+	if out.String() != want {
+		t.Fatalf("unexpected csv:\n%s", out.String())
+	}
+
+	out.Reset()
+	write, _ = batchWriter(&out, batchJSONL, nil)
+	if err := write(failedRow); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != `{"line":5,"word":"broken","error":"bad gateway"}`+"\n" {
+		t.Fatalf("unexpected jsonl: %s", out.String())
+	}
+}
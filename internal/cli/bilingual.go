@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"dict-be/internal/bilingual"
+	"dict-be/internal/document"
+	"dict-be/internal/llm"
+	"dict-be/internal/postprocess"
+)
+
+// Formats accepted by translate --bilingual.
+const (
+	bilingualHTML = "html"
+	bilingualEPUB = "epub"
+)
+
+func validateBilingual(format string) error {
+	switch format {
+	case "", bilingualHTML, bilingualEPUB:
+		return nil
+	}
+	return fmt.Errorf("invalid --bilingual: %s (expected html or epub)", format)
+}
+
+// newBilingualBook pairs each source paragraph with its post-processed
+// translation and adds up to vocab words from the difficulty prompt.
+func newBilingualBook(ctx context.Context, client llm.Client, model string, post postprocess.Pipeline, path, input, inputLanguage, outputLanguage string, sections []document.Pair, vocab int) (bilingual.Book, error) {
+	book := bilingual.Book{
+		Title:          bilingualTitle(path),
+		SourceLanguage: inputLanguage,
+		TargetLanguage: outputLanguage,
+		Sections:       make([]document.Pair, len(sections)),
+	}
+	for i, section := range sections {
+		target, err := post.Apply(ctx, section.Target)
+		if err != nil {
+			return bilingual.Book{}, err
+		}
+		book.Sections[i] = document.Pair{Source: section.Source, Target: target}
+	}
+	if vocab == 0 {
+		return book, nil
+	}
+	vocabulary, err := extractVocabulary(ctx, client, model, input, inputLanguage, outputLanguage, vocab)
+	if err != nil {
+		return bilingual.Book{}, fmt.Errorf("vocabulary: %w", err)
+	}
+	book.Vocabulary = vocabulary
+	return book, nil
+}
+
+// extractVocabulary asks for the words of input a learner should study,
+// leaving out the known-words list.
+func extractVocabulary(ctx context.Context, client llm.Client, model, input, inputLanguage, outputLanguage string, max int) ([]bilingual.Entry, error) {
+	knownInstruction, err := knownWordsInstruction(input, true)
+	if err != nil {
+		return nil, err
+	}
+	systemPrompt, userPrompt, err := buildPrompts("difficulty", map[string]string{
+		"input":             input,
+		"input_language":    inputLanguage,
+		"output_language":   outputLanguage,
+		"max":               strconv.Itoa(max),
+		"known_instruction": knownInstruction,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Chat(ctx, llm.ChatRequest{
+		Model:    model,
+		Messages: buildMessages(systemPrompt, userPrompt),
+	})
+	if err != nil {
+		return nil, err
+	}
+	report, err := parseDifficultyReport(resp.Content, max)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]bilingual.Entry, len(report.Vocabulary))
+	for i, entry := range report.Vocabulary {
+		entries[i] = bilingual.Entry{Word: entry.Word, Meaning: entry.Meaning}
+	}
+	return entries, nil
+}
+
+func writeBilingual(out io.Writer, format string, book bilingual.Book) error {
+	if format == bilingualEPUB {
+		return bilingual.WriteEPUB(out, book)
+	}
+	return bilingual.WriteHTML(out, book)
+}
+
+// bilingualTitle names the book after its input file.
+func bilingualTitle(path string) string {
+	if path == "" || path == "-" {
+		return "Translation"
+	}
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"dict-be/internal/document"
+	"dict-be/internal/llm"
+	"dict-be/internal/postprocess"
+)
+
+func TestNewBilingualBook(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	client := &fakeClient{resp: llm.ChatResponse{Content: `{"level":"B1","reason":"simple","vocabulary":[` +
+		`{"word":"pier","meaning":"码头"},{"word":" ","meaning":"blank"},{"word":"ferry","meaning":"渡轮"}]}`}}
+	post, err := postprocess.New([]postprocess.Spec{{Builtin: "trim"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sections := []document.Pair{{Source: "We waited on the pier.", Target: "  我们在码头等着。  "}}
+	book, err := newBilingualBook(context.Background(), client, "m", post, "docs/trip.md", "We waited on the pier.", "English", "Simplified Chinese", sections, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if book.Title != "trip" || book.Sections[0].Target != "我们在码头等着。" {
+		t.Fatalf("unexpected book: %+v", book)
+	}
+	if len(book.Vocabulary) != 1 || book.Vocabulary[0].Word != "pier" {
+		t.Fatalf("unexpected vocabulary: %+v", book.Vocabulary)
+	}
+	var buf bytes.Buffer
+	if err := writeBilingual(&buf, bilingualHTML, book); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `<a class="vocab" href="#v1">pier</a>`) {
+		t.Fatalf("unexpected html:\n%s", buf.String())
+	}
+	if err := validateBilingual("pdf"); err == nil {
+		t.Fatalf("expected an error for an unknown format")
+	}
+}
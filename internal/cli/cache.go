@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+
+	"dict-be/internal/cache"
+	"dict-be/internal/config"
+	"dict-be/internal/llm"
+
+	"github.com/spf13/cobra"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and clear the query response cache",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "stats",
+		Short: "Show the number, size and age of cached responses",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			store, err := newCacheStore(cfg)
+			if err != nil {
+				return err
+			}
+			stats, err := store.Stats()
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "entries: %d\nsize: %.1f MB\n", stats.Entries, float64(stats.Bytes)/1e6)
+			if stats.Entries > 0 {
+				fmt.Fprintf(out, "oldest: %s\nnewest: %s\n", stats.Oldest.Local().Format("2006-01-02 15:04"), stats.Newest.Local().Format("2006-01-02 15:04"))
+			}
+			if !cfg.Cache.Enabled {
+				fmt.Fprintln(out, "enabled: false")
+			}
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Delete all cached responses",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			store, err := newCacheStore(cfg)
+			if err != nil {
+				return err
+			}
+			cleared, err := store.Clear()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "cleared %d cached responses\n", cleared)
+			return nil
+		},
+	})
+	return cmd
+}
+
+func newCacheStore(cfg config.Config) (*cache.Store, error) {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return cache.NewStore(dir, cfg.Cache.TTL, int64(cfg.Cache.MaxMB)*1_000_000), nil
+}
+
+// cacheClient answers repeated requests from the response cache when
+// cache.enabled is set. With refresh, every request goes to the model and
+// its answer replaces the cached one.
+func cacheClient(cmd *cobra.Command, cfg config.Config, client llm.Client, refresh bool) (llm.Client, error) {
+	if !cfg.Cache.Enabled {
+		return client, nil
+	}
+	store, err := newCacheStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cache.Wrap(client, store, cache.Options{
+		Namespace: cacheNamespace(cfg.LLM),
+		Refresh:   refresh,
+		Warn: func(err error) {
+			fmt.Fprintln(cmd.ErrOrStderr(), "warning: "+err.Error())
+		},
+	}), nil
+}
+
+// cacheNamespace is the provider settings that change answers without
+// showing in the request.
+func cacheNamespace(cfg config.LLMConfig) string {
+	return cfg.Type + "\x00" + cfg.URL + "\x00" + cfg.SystemPrompt
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"dict-be/internal/config"
+	"dict-be/internal/llm"
+)
+
+func TestCacheClientAndCommands(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.Config{
+		LLM:   config.LLMConfig{Type: "openai", URL: "https://api.example.com/v1"},
+		Cache: config.CacheConfig{Enabled: true, TTL: time.Hour},
+	}
+	cmd := newCacheCmd()
+	fake := &fakeClient{resp: llm.ChatResponse{Content: "码头", Usage: llm.Usage{TotalTokens: 12}}}
+	client, err := cacheClient(cmd, cfg, fake, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req := llm.ChatRequest{Model: "m", Messages: []llm.Message{{Role: "user", Content: "pier"}}}
+	if _, err := client.Chat(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	fake.resp.Content = "changed"
+	if resp, err := client.Chat(context.Background(), req); err != nil || resp.Content != "码头" || !resp.Usage.IsZero() {
+		t.Fatalf("expected the cached answer, got %+v, %v", resp, err)
+	}
+	cfg.LLM.URL = "http://localhost:8080/v1"
+	other, _ := cacheClient(cmd, cfg, fake, false)
+	if resp, _ := other.Chat(context.Background(), req); resp.Content != "changed" {
+		t.Fatalf("expected another endpoint to miss, got %+v", resp)
+	}
+	if disabled, _ := cacheClient(cmd, config.Config{}, fake, false); disabled != llm.Client(fake) {
+		t.Fatalf("expected no cache when disabled")
+	}
+
+	run := func(args ...string) string {
+		cmd := newCacheCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v: unexpected error: %v", args, err)
+		}
+		return out.String()
+	}
+	if out := run("stats"); !strings.HasPrefix(out, "entries: 2\nsize: ") || !strings.Contains(out, "oldest: ") {
+		t.Fatalf("unexpected stats:\n%s", out)
+	}
+	if out := run("clear"); out != "cleared 2 cached responses\n" {
+		t.Fatalf("unexpected clear: %q", out)
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"dict-be/internal/classifier"
+	"dict-be/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+type classifierOptions struct {
+	OutputLanguage string
+	LLM            bool
+	Output         outputOptions
+}
+
+func newClassifierCmd() *cobra.Command {
+	opts := &classifierOptions{}
+	cmd := &cobra.Command{
+		Use:   "classifier <noun>",
+		Short: "Show Chinese measure words for a noun",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runClassifier(cmd, opts, args)
+		},
+	}
+	addOutputLanguageFlag(cmd, &opts.OutputLanguage, "o", "English", "explanation language")
+	cmd.Flags().BoolVar(&opts.LLM, "llm", false, "ask the LLM even when the noun is in the offline table")
+	opts.Output.addFlags(cmd)
+	return cmd
+}
+
+func runClassifier(cmd *cobra.Command, opts *classifierOptions, args []string) error {
+	if err := opts.Output.validate(); err != nil {
+		return err
+	}
+	noun := strings.TrimSpace(strings.Join(args, " "))
+	if noun == "" {
+		return fmt.Errorf("noun is required")
+	}
+	if entry, ok := classifier.Lookup(noun); ok && !opts.LLM && opts.Output.Format == render.Text {
+		return writeClassifierEntry(cmd.OutOrStdout(), entry)
+	}
+	return runPromptCommand(cmd, &opts.Output, "classifier", map[string]string{
+		"input":           noun,
+		"output_language": opts.OutputLanguage,
+	})
+}
+
+func writeClassifierEntry(out io.Writer, entry classifier.Entry) error {
+	if _, err := fmt.Fprintf(out, "%s (%s) - %s\n", entry.Noun, entry.Pinyin, entry.English); err != nil {
+		return err
+	}
+	for _, cl := range entry.Classifiers {
+		if _, err := fmt.Fprintf(out, "\n%s (%s)\n", cl.Hanzi, cl.Pinyin); err != nil {
+			return err
+		}
+		for _, example := range classifier.Examples(entry, cl) {
+			if _, err := fmt.Fprintf(out, "  %s\n", example); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"dict-be/internal/classifier"
+)
+
+func TestWriteClassifierEntry(t *testing.T) {
+	entry, ok := classifier.Lookup("book")
+	if !ok {
+		t.Fatalf("expected offline entry")
+	}
+	var out bytes.Buffer
+	if err := writeClassifierEntry(&out, entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "书 (shū) - book\n\n本 (běn)\n  这是一本书。\n  我有两本书。\n"
+	if out.String() != expected {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
@@ -1,8 +1,68 @@
 package cli
 
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// ExitInterrupted is the exit status of a command stopped by Ctrl-C or
+// SIGTERM, following the shell convention of 128 + SIGINT.
+const ExitInterrupted = 130
+
 func Execute() int {
-	if err := NewRootCmd().Execute(); err != nil {
-		return 1
+	root := NewRootCmd()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case <-signals:
+		case <-ctx.Done():
+			return
+		}
+		// A second signal kills the process as usual.
+		signal.Stop(signals)
+		cancel()
+	}()
+	return executeRoot(ctx, root)
+}
+
+// executeRoot runs root with ctx and reports errors the way cobra does,
+// except that a command stopped by cancelling ctx prints no usage: the
+// output already shows where it stopped. Cobra would print the usage
+// before returning, so root prints neither and the decision is made
+// here, once the command has returned.
+func executeRoot(ctx context.Context, root *cobra.Command) int {
+	silenceErrors, silenceUsage := root.SilenceErrors, root.SilenceUsage
+	root.SilenceErrors, root.SilenceUsage = true, true
+	cmd, err := root.ExecuteContextC(ctx)
+	root.SilenceErrors, root.SilenceUsage = silenceErrors, silenceUsage
+	if err == nil {
+		return 0
+	}
+	if !silenceErrors && !cmd.SilenceErrors {
+		root.PrintErrln(root.ErrPrefix(), err.Error())
+	}
+	if ctx.Err() != nil {
+		return ExitInterrupted
+	}
+	if !silenceUsage && !cmd.SilenceUsage {
+		root.Println(cmd.UsageString())
+	}
+	return 1
+}
+
+// commandContext returns the context cmd runs with, which is cancelled on
+// Ctrl-C, or a background context when cmd was not started by Execute.
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
 	}
-	return 0
+	return context.Background()
 }
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newExecuteTestCmd(args []string, run func(cmd *cobra.Command) error) (*cobra.Command, *bytes.Buffer) {
+	root := &cobra.Command{Use: "dict-be"}
+	root.AddCommand(&cobra.Command{
+		Use:  "work",
+		RunE: func(cmd *cobra.Command, args []string) error { return run(cmd) },
+	})
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(args)
+	return root, &out
+}
+
+func TestExecuteRootUsageOnError(t *testing.T) {
+	root, out := newExecuteTestCmd([]string{"work", "--bogus"}, func(cmd *cobra.Command) error { return nil })
+	if code := executeRoot(context.Background(), root); code != 1 {
+		t.Fatalf("unexpected exit code: %d", code)
+	}
+	if !strings.Contains(out.String(), "Error: unknown flag: --bogus") || !strings.Contains(out.String(), "Usage:") {
+		t.Fatalf("expected error and usage:\n%s", out.String())
+	}
+}
+
+func TestExecuteRootInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	root, out := newExecuteTestCmd([]string{"work"}, func(cmd *cobra.Command) error {
+		cancel()
+		return commandContext(cmd).Err()
+	})
+	if code := executeRoot(ctx, root); code != ExitInterrupted {
+		t.Fatalf("unexpected exit code: %d", code)
+	}
+	if strings.Contains(out.String(), "Usage:") || !strings.Contains(out.String(), "context canceled") {
+		t.Fatalf("expected the error without usage:\n%s", out.String())
+	}
+}
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/postprocess"
+	"dict-be/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+// maxCollocations bounds --count per type; past that the model pads the
+// list with rare or invented combinations.
+const maxCollocations = 10
+
+// collocationTypes are the --types names in output order.
+var collocationTypes = []string{"verb", "adjective", "noun"}
+
+var collocationHeadings = map[string]string{
+	"verb":      "Verbs",
+	"adjective": "Adjectives",
+	"noun":      "Nouns",
+}
+
+type collocationsOptions struct {
+	InputLanguage  string
+	OutputLanguage string
+	Types          string
+	Count          int
+	Format         string
+}
+
+// collocationSet is the JSON output of collocations, grouped by type in
+// the order of collocationTypes.
+type collocationSet struct {
+	Word         string        `json:"word"`
+	Collocations []collocation `json:"collocations"`
+}
+
+type collocation struct {
+	Type        string `json:"type"`
+	Phrase      string `json:"phrase"`
+	Meaning     string `json:"meaning"`
+	Example     string `json:"example,omitempty"`
+	Translation string `json:"translation,omitempty"`
+}
+
+func newCollocationsCmd() *cobra.Command {
+	opts := &collocationsOptions{}
+	cmd := &cobra.Command{
+		Use:   "collocations <word>",
+		Short: "List a word's common collocations with translated examples",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCollocations(cmd, opts, strings.Join(args, " "))
+		},
+	}
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "o")
+	cmd.Flags().StringVar(&opts.Types, "types", "verb,adjective,noun", "comma-separated collocation types: verb,adjective,noun")
+	cmd.Flags().IntVarP(&opts.Count, "count", "n", 5, "maximum collocations per type")
+	cmd.Flags().StringVar(&opts.Format, "format", render.Text, formatHelp+" (json prints the collocations as an object)")
+	return cmd
+}
+
+func runCollocations(cmd *cobra.Command, opts *collocationsOptions, word string) error {
+	if err := validateFormat(opts.Format); err != nil {
+		return err
+	}
+	if opts.Count < 1 || opts.Count > maxCollocations {
+		return fmt.Errorf("invalid --count: %d (expected 1 to %d)", opts.Count, maxCollocations)
+	}
+	types, err := parseCollocationTypes(opts.Types)
+	if err != nil {
+		return err
+	}
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return fmt.Errorf("word is required")
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(word, opts.InputLanguage, opts.OutputLanguage)
+	systemPrompt, userPrompt, err := buildPrompts("collocations", map[string]string{
+		"input":           word,
+		"input_language":  inputLanguage,
+		"output_language": outputLanguage,
+		"types":           strings.Join(types, ", "),
+		"count":           strconv.Itoa(opts.Count),
+	})
+	if err != nil {
+		return err
+	}
+	client, cfg, err := loadLLMClient(cmd)
+	if err != nil {
+		return err
+	}
+	post, err := newPostprocessPipeline(cfg)
+	if err != nil {
+		return err
+	}
+	req := llm.ChatRequest{
+		Model:    cfg.LLM.Model,
+		Messages: buildMessages(systemPrompt, userPrompt),
+	}
+	return writeCollocations(commandContext(cmd), cmd.OutOrStdout(), client, req, word, types, opts.Count, opts.Format, post)
+}
+
+// writeCollocations asks for the collocations as JSON and prints them in
+// format: the validated set for JSON, one markdown table per type
+// otherwise.
+func writeCollocations(ctx context.Context, out io.Writer, client llm.Client, req llm.ChatRequest, word string, types []string, count int, format string, post postprocess.Pipeline) error {
+	resp, err := client.Chat(ctx, req)
+	if err != nil {
+		return err
+	}
+	set, err := parseCollocations(resp.Content, word, types, count)
+	if err != nil {
+		return err
+	}
+	if format == render.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(set)
+	}
+	text, err := post.Apply(ctx, strings.TrimSuffix(set.markdown(), "\n"))
+	if err != nil {
+		return err
+	}
+	return renderContent(out, format, firstNonEmpty(resp.Model, req.Model), text)
+}
+
+// parseCollocations decodes the model's collocations, dropping rows
+// without a phrase or of a type not asked for, and keeps at most count per
+// type, grouped in the order of collocationTypes.
+func parseCollocations(content, word string, types []string, count int) (collocationSet, error) {
+	var set collocationSet
+	if err := decodeJSONContent(content, &set); err != nil {
+		return collocationSet{}, err
+	}
+	byType := make(map[string][]collocation)
+	for _, row := range set.Collocations {
+		row.Type = strings.ToLower(strings.TrimSpace(row.Type))
+		row.Phrase = strings.TrimSpace(row.Phrase)
+		row.Meaning = strings.TrimSpace(row.Meaning)
+		row.Example = strings.TrimSpace(row.Example)
+		row.Translation = strings.TrimSpace(row.Translation)
+		if row.Phrase == "" || !slices.Contains(types, row.Type) || len(byType[row.Type]) >= count {
+			continue
+		}
+		byType[row.Type] = append(byType[row.Type], row)
+	}
+	set = collocationSet{Word: word}
+	for _, kind := range collocationTypes {
+		set.Collocations = append(set.Collocations, byType[kind]...)
+	}
+	if len(set.Collocations) == 0 {
+		return collocationSet{}, fmt.Errorf("model returned no collocations for %q", word)
+	}
+	return set, nil
+}
+
+// parseCollocationTypes validates a comma-separated --types list.
+func parseCollocationTypes(value string) ([]string, error) {
+	var types []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || slices.Contains(types, name) {
+			continue
+		}
+		if !slices.Contains(collocationTypes, name) {
+			return nil, fmt.Errorf("invalid collocation type: %s (expected verb, adjective or noun)", name)
+		}
+		types = append(types, name)
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("at least one collocation type is required")
+	}
+	return types, nil
+}
+
+// markdown renders one table per type, with the example and its
+// translation in separate columns.
+func (s collocationSet) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", s.Word)
+	for _, kind := range collocationTypes {
+		var rows []collocation
+		for _, row := range s.Collocations {
+			if row.Type == kind {
+				rows = append(rows, row)
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n| Collocation | Meaning | Example | Translation |\n| --- | --- | --- | --- |\n", collocationHeadings[kind])
+		for _, row := range rows {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", tableCell(row.Phrase), tableCell(row.Meaning), tableCell(row.Example), tableCell(row.Translation))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/render"
+)
+
+const testCollocations = `{"collocations":[` +
+	`{"type":"noun","phrase":"decision maker","meaning":"决策者","example":"She is the decision maker.","translation":"她是决策者。"},` +
+	`{"type":" Verb ","phrase":"make a decision","meaning":"做出决定","example":"We must make a decision | now.","translation":"我们必须现在做决定。"},` +
+	`{"type":"verb","phrase":"reach a decision","meaning":"达成决定"},` +
+	`{"type":"verb","phrase":"dropped over count","meaning":"x"},` +
+	`{"type":"adverb","phrase":"dropped type","meaning":"x"},{"type":"verb","phrase":" ","meaning":"dropped"}]}`
+
+func TestWriteCollocations(t *testing.T) {
+	client := &fakeClient{resp: llm.ChatResponse{Content: testCollocations}}
+	var out bytes.Buffer
+	if err := writeCollocations(context.Background(), &out, client, llm.ChatRequest{}, "decision", collocationTypes, 2, render.Text, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# decision\n\n## Verbs\n\n| Collocation | Meaning | Example | Translation |\n| --- | --- | --- | --- |\n" +
+		"| make a decision | 做出决定 | We must make a decision \\| now. | 我们必须现在做决定。 |\n" +
+		"| reach a decision | 达成决定 |  |  |\n\n" +
+		"## Nouns\n\n| Collocation | Meaning | Example | Translation |\n| --- | --- | --- | --- |\n" +
+		"| decision maker | 决策者 | She is the decision maker. | 她是决策者。 |\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := writeCollocations(context.Background(), &out, client, llm.ChatRequest{}, "decision", []string{"noun"}, 5, render.JSON, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `"phrase": "decision maker"`) || strings.Contains(out.String(), "make a decision") {
+		t.Fatalf("unexpected json:\n%s", out.String())
+	}
+	if _, err := parseCollocations(testCollocations, "decision", []string{"adjective"}, 5); err == nil {
+		t.Fatalf("expected an error for no collocations")
+	}
+}
+
+func TestParseCollocationTypes(t *testing.T) {
+	types, err := parseCollocationTypes(" Noun,verb,noun,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(types, ",") != "noun,verb" {
+		t.Fatalf("unexpected types: %v", types)
+	}
+	if _, err := parseCollocationTypes("adverb"); err == nil {
+		t.Fatalf("expected an error for an unknown type")
+	}
+	if _, err := parseCollocationTypes(" , "); err == nil {
+		t.Fatalf("expected an error for no types")
+	}
+}
+
+func TestBuildCollocationsPrompts(t *testing.T) {
+	systemPrompt, userPrompt, err := buildPrompts("collocations", map[string]string{
+		"input":           "decision",
+		"input_language":  "English",
+		"output_language": "Simplified Chinese",
+		"types":           "verb, noun",
+		"count":           "3",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, "of these types: verb, noun") || !strings.Contains(systemPrompt, "up to 3 collocations per type") {
+		t.Fatalf("unexpected system prompt: %q", systemPrompt)
+	}
+	if !strings.Contains(userPrompt, "<input>decision</input>") {
+		t.Fatalf("unexpected user prompt: %q", userPrompt)
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"dict-be/internal/vocab"
+
+	"github.com/spf13/cobra"
+)
+
+type defineOptions struct {
+	InputLanguage  string
+	OutputLanguage string
+	Examples       int
+	IPA            bool
+	Save           bool
+	Output         outputOptions
+}
+
+func newDefineCmd() *cobra.Command {
+	opts := &defineOptions{}
+	cmd := &cobra.Command{
+		Use:   "define <word>",
+		Short: "Write a full dictionary entry for a word or phrase",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDefine(cmd, opts, strings.Join(args, " "))
+		},
+	}
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "o")
+	cmd.Flags().IntVar(&opts.Examples, "examples", 2, "example sentences per sense (0 for none)")
+	cmd.Flags().BoolVar(&opts.IPA, "ipa", false, "give a detailed pronunciation with syllables and stress, like pronounce")
+	cmd.Flags().BoolVar(&opts.Save, "save", false, "save the entry to the vocabulary notebook")
+	opts.Output.addFlags(cmd)
+	return cmd
+}
+
+func runDefine(cmd *cobra.Command, opts *defineOptions, word string) error {
+	if err := opts.Output.validate(); err != nil {
+		return err
+	}
+	if opts.Examples < 0 {
+		return fmt.Errorf("invalid --examples: %d", opts.Examples)
+	}
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return fmt.Errorf("word is required")
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(word, opts.InputLanguage, opts.OutputLanguage)
+	answer, err := runPromptCommandAnswer(cmd, &opts.Output, "define", map[string]string{
+		"input":                word,
+		"input_language":       inputLanguage,
+		"output_language":      outputLanguage,
+		"examples_instruction": defineExamplesInstruction(opts.Examples, outputLanguage),
+		"pronunciation":        definePronunciation(word, inputLanguage, opts.IPA),
+	})
+	if err != nil || !opts.Save {
+		return err
+	}
+	return saveVocab(cmd, vocab.Entry{
+		Word:           word,
+		Command:        "define",
+		InputLanguage:  inputLanguage,
+		OutputLanguage: outputLanguage,
+		Content:        answer,
+	})
+}
+
+func definePronunciation(word, inputLanguage string, detailed bool) string {
+	if !detailed {
+		return "its pronunciation (IPA for English, pinyin with tone marks for Chinese, the usual reading otherwise)"
+	}
+	return "its pronunciation in " + pronunciationNotation(word, inputLanguage) + ", split into syllables with the stressed syllable marked (or the tone of each syllable for Chinese)"
+}
+
+func defineExamplesInstruction(n int, outputLanguage string) string {
+	if n == 0 {
+		return "Do not give example sentences."
+	}
+	return fmt.Sprintf("Under each sense, give %d natural example sentences using the headword, each followed by its %s translation.", n, outputLanguage)
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildDefinePrompts(t *testing.T) {
+	systemPrompt, userPrompt, err := buildPrompts("define", map[string]string{
+		"input":                "run",
+		"input_language":       "English",
+		"output_language":      "Simplified Chinese",
+		"examples_instruction": defineExamplesInstruction(3, "Simplified Chinese"),
+		"pronunciation":        definePronunciation("run", "English", true),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, "give 3 natural example sentences") || !strings.Contains(systemPrompt, "part of speech") ||
+		!strings.Contains(systemPrompt, "British and American") {
+		t.Fatalf("unexpected system prompt: %q", systemPrompt)
+	}
+	if !strings.Contains(userPrompt, "<input>run</input>") {
+		t.Fatalf("unexpected user prompt: %q", userPrompt)
+	}
+	if got := defineExamplesInstruction(0, "English"); got != "Do not give example sentences." {
+		t.Fatalf("unexpected instruction: %q", got)
+	}
+}
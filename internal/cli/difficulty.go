@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"dict-be/internal/known"
+	"dict-be/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+// Average reading speeds used for the reading time estimate: words per
+// minute for space-delimited text, characters per minute for CJK text.
+const (
+	readingWordsPerMinute = 230
+	readingCJKPerMinute   = 500
+)
+
+type difficultyOptions struct {
+	InputFile      string
+	InputLanguage  string
+	OutputLanguage string
+	Max            int
+	NoKnown        bool
+	Format         string
+}
+
+// difficultyReport combines the model's assessment with local statistics.
+// UnknownDensity is nil when it cannot be computed.
+type difficultyReport struct {
+	Level          string            `json:"level"`
+	Reason         string            `json:"reason"`
+	ReadingMinutes int               `json:"reading_minutes"`
+	Words          int               `json:"words"`
+	UnknownWords   int               `json:"unknown_words"`
+	UnknownDensity *float64          `json:"unknown_density,omitempty"`
+	Vocabulary     []difficultyEntry `json:"vocabulary"`
+}
+
+type difficultyEntry struct {
+	Word    string `json:"word"`
+	Meaning string `json:"meaning"`
+}
+
+func newDifficultyCmd() *cobra.Command {
+	opts := &difficultyOptions{}
+	cmd := &cobra.Command{
+		Use:   "difficulty [text...]",
+		Short: "Score a text's level, reading time and unknown words",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDifficulty(cmd, opts, args)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.InputFile, "file", "F", "", "text file, use -F- for stdin")
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "o")
+	cmd.Flags().IntVar(&opts.Max, "max", 10, "maximum number of pre-study words")
+	cmd.Flags().BoolVar(&opts.NoKnown, "no-known", false, "ignore the known-words list")
+	cmd.Flags().StringVar(&opts.Format, "format", render.Text, "output format: text or json")
+	return cmd
+}
+
+func runDifficulty(cmd *cobra.Command, opts *difficultyOptions, args []string) error {
+	if opts.Format != render.Text && opts.Format != render.JSON {
+		return fmt.Errorf("invalid format: %s (expected text or json)", opts.Format)
+	}
+	if opts.Max <= 0 {
+		return fmt.Errorf("--max must be positive")
+	}
+	input, err := readInput(args, opts.InputFile, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(input) == "" {
+		return fmt.Errorf("input is required")
+	}
+	var knownWords []string
+	if !opts.NoKnown {
+		store, err := newKnownStore()
+		if err != nil {
+			return err
+		}
+		if knownWords, err = store.Load(); err != nil {
+			return err
+		}
+	}
+	knownInstruction, err := knownWordsInstruction(input, !opts.NoKnown)
+	if err != nil {
+		return err
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(input, opts.InputLanguage, opts.OutputLanguage)
+	content, err := completePrompt(commandContext(cmd), cmd, "difficulty", map[string]string{
+		"input":             input,
+		"input_language":    inputLanguage,
+		"output_language":   outputLanguage,
+		"max":               strconv.Itoa(opts.Max),
+		"known_instruction": knownInstruction,
+	})
+	if err != nil {
+		return err
+	}
+	report, err := parseDifficultyReport(content, opts.Max)
+	if err != nil {
+		return err
+	}
+	report.addStats(input, knownWords)
+	if opts.Format == render.JSON {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return writeDifficultyReport(cmd.OutOrStdout(), report)
+}
+
+func parseDifficultyReport(content string, max int) (difficultyReport, error) {
+	var report difficultyReport
+	if err := decodeJSONContent(content, &report); err != nil {
+		return difficultyReport{}, err
+	}
+	report.Level = strings.TrimSpace(report.Level)
+	if report.Level == "" {
+		return difficultyReport{}, fmt.Errorf("model returned no level")
+	}
+	vocabulary := make([]difficultyEntry, 0, len(report.Vocabulary))
+	for _, entry := range report.Vocabulary {
+		entry.Word = strings.TrimSpace(entry.Word)
+		entry.Meaning = strings.TrimSpace(entry.Meaning)
+		if entry.Word == "" {
+			continue
+		}
+		vocabulary = append(vocabulary, entry)
+		if len(vocabulary) == max {
+			break
+		}
+	}
+	report.Vocabulary = vocabulary
+	return report, nil
+}
+
+// addStats fills in the reading time and, when a known-words list exists
+// and the text has space-delimited words, the share of unknown words.
+func (r *difficultyReport) addStats(input string, knownWords []string) {
+	total, unknown := known.Coverage(knownWords, input)
+	cjk := 0
+	for _, char := range input {
+		if unicode.In(char, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+			cjk++
+		}
+	}
+	minutes := float64(total)/readingWordsPerMinute + float64(cjk)/readingCJKPerMinute
+	r.ReadingMinutes = max(1, int(math.Ceil(minutes)))
+	r.Words = total
+	if len(knownWords) > 0 && total > 0 {
+		density := float64(unknown) / float64(total)
+		r.UnknownWords = unknown
+		r.UnknownDensity = &density
+	}
+}
+
+func writeDifficultyReport(out io.Writer, report difficultyReport) error {
+	fmt.Fprintf(out, "Level: %s\n", report.Level)
+	if report.Reason != "" {
+		fmt.Fprintf(out, "Reason: %s\n", report.Reason)
+	}
+	fmt.Fprintf(out, "Reading time: about %d min\n", report.ReadingMinutes)
+	if report.UnknownDensity != nil {
+		fmt.Fprintf(out, "Unknown words: %d of %d (%.0f%%)\n", report.UnknownWords, report.Words, *report.UnknownDensity*100)
+	} else {
+		fmt.Fprintln(out, "Unknown words: n/a (add words with dict-be known add)")
+	}
+	if len(report.Vocabulary) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out, "\nPre-study vocabulary:")
+	for _, entry := range report.Vocabulary {
+		fmt.Fprintf(out, "- %s: %s\n", entry.Word, entry.Meaning)
+	}
+	return nil
+}
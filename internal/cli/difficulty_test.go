@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseDifficultyReport(t *testing.T) {
+	report, err := parseDifficultyReport(`{"level":" B2 ","reason":"long sentences","vocabulary":[{"word":"ubiquitous","meaning":"everywhere"},{"word":" "},{"word":"arcane","meaning":"obscure"}]}`, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Level != "B2" || len(report.Vocabulary) != 1 || report.Vocabulary[0].Word != "ubiquitous" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if _, err := parseDifficultyReport(`{"vocabulary":[]}`, 5); err == nil {
+		t.Fatalf("expected error for missing level")
+	}
+}
+
+func TestDifficultyStats(t *testing.T) {
+	report := difficultyReport{Level: "A2"}
+	report.addStats("The cat sat on the mat.", nil)
+	if report.ReadingMinutes != 1 || report.Words != 6 || report.UnknownDensity != nil {
+		t.Fatalf("unexpected stats: %+v", report)
+	}
+	report.addStats("The cat sat on the mat.", []string{"the", "cat", "on"})
+	if report.UnknownWords != 2 || report.UnknownDensity == nil || *report.UnknownDensity != 2.0/6 {
+		t.Fatalf("unexpected stats: %+v", report)
+	}
+	var out bytes.Buffer
+	if err := writeDifficultyReport(&out, report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Unknown words: 2 of 6 (33%)") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
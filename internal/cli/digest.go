@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"dict-be/internal/audit"
+	"dict-be/internal/config"
+	"dict-be/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+// webhookTimeout bounds a digest delivery.
+const webhookTimeout = 30 * time.Second
+
+type digestOptions struct {
+	Period  string
+	Format  string
+	Webhook string
+}
+
+// digestReport summarizes a period of use from the audit log.
+type digestReport struct {
+	Period     string        `json:"period"`
+	Since      time.Time     `json:"since"`
+	Until      time.Time     `json:"until"`
+	KnownWords int           `json:"known_words"`
+	Usage      audit.Summary `json:"usage"`
+}
+
+var digestPeriods = map[string]time.Duration{
+	"day":  24 * time.Hour,
+	"week": 7 * 24 * time.Hour,
+}
+
+func newDigestCmd() *cobra.Command {
+	opts := &digestOptions{}
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Summarize recent usage and cost, optionally posting it to a webhook",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDigest(cmd, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Period, "period", "day", "period to summarize, ending now: day or week")
+	cmd.Flags().StringVar(&opts.Format, "format", "markdown", "output format: markdown or json")
+	cmd.Flags().StringVar(&opts.Webhook, "webhook", "", "POST the digest to this URL instead of printing it")
+	return cmd
+}
+
+func runDigest(cmd *cobra.Command, opts *digestOptions) error {
+	length, ok := digestPeriods[opts.Period]
+	if !ok {
+		return fmt.Errorf("invalid --period: %s (expected day or week)", opts.Period)
+	}
+	if opts.Format != "markdown" && opts.Format != render.JSON {
+		return fmt.Errorf("invalid format: %s (expected markdown or json)", opts.Format)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Audit.Path == "" {
+		return fmt.Errorf("digest reads the audit log; set audit.path in the config")
+	}
+	records, err := audit.ReadLog(expandHome(cfg.Audit.Path))
+	if err != nil {
+		return err
+	}
+	store, err := newKnownStore()
+	if err != nil {
+		return err
+	}
+	knownWords, err := store.Load()
+	if err != nil {
+		return err
+	}
+	until := time.Now()
+	report := digestReport{
+		Period:     opts.Period,
+		Since:      until.Add(-length),
+		Until:      until,
+		KnownWords: len(knownWords),
+		Usage:      audit.Summarize(records, until.Add(-length), until),
+	}
+	if opts.Webhook == "" {
+		return writeDigest(cmd.OutOrStdout(), opts.Format, report)
+	}
+	if err := postDigest(commandContext(cmd), opts.Webhook, opts.Format, report); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "digest sent to %s\n", redactWebhook(opts.Webhook))
+	return nil
+}
+
+func writeDigest(out io.Writer, format string, report digestReport) error {
+	if format == render.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	_, err := io.WriteString(out, report.markdown())
+	return err
+}
+
+// postDigest sends the JSON report, or markdown as the "text" field that
+// Slack, Mattermost and similar incoming webhooks expect.
+func postDigest(ctx context.Context, url, format string, report digestReport) error {
+	var payload any = report
+	if format != render.JSON {
+		payload = map[string]string{"text": report.markdown()}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode digest: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook: %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// redactWebhook keeps the host of a webhook URL; the path usually holds
+// its secret.
+func redactWebhook(url string) string {
+	scheme, rest, ok := strings.Cut(url, "://")
+	if !ok {
+		return "webhook"
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	return scheme + "://" + host + "/..."
+}
+
+func (r digestReport) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# dict-be digest: %s to %s\n\n", r.Since.Format("2006-01-02 15:04"), r.Until.Format("2006-01-02 15:04"))
+	usage := r.Usage
+	fmt.Fprintf(&b, "- Requests: %d", usage.Requests)
+	if usage.Errors > 0 {
+		fmt.Fprintf(&b, " (%d failed)", usage.Errors)
+	}
+	fmt.Fprintf(&b, "\n- Tokens: %d prompt, %d completion\n", usage.PromptTokens, usage.CompletionTokens)
+	fmt.Fprintf(&b, "- Cost: $%.4f", usage.CostUSD)
+	if usage.Unpriced > 0 {
+		fmt.Fprintf(&b, " (%d requests without a price)", usage.Unpriced)
+	}
+	fmt.Fprintf(&b, "\n- Known words: %d\n", r.KnownWords)
+	writeShares := func(column string, shares []audit.UsageByName) {
+		if len(shares) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n## By %s\n\n| %s | Requests | Tokens | Cost |\n| --- | ---: | ---: | ---: |\n", strings.ToLower(column), column)
+		for _, share := range shares {
+			fmt.Fprintf(&b, "| %s | %d | %d | $%.4f |\n", share.Name, share.Requests, share.Tokens, share.CostUSD)
+		}
+	}
+	writeShares("Model", usage.Models)
+	writeShares("Command", usage.Commands)
+	return b.String()
+}
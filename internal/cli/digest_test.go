@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"dict-be/internal/audit"
+	"dict-be/internal/render"
+)
+
+var testDigest = digestReport{
+	Period:     "day",
+	Since:      time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+	Until:      time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC),
+	KnownWords: 412,
+	Usage: audit.Summary{
+		Requests: 3, Errors: 1, PromptTokens: 110, CompletionTokens: 55, CostUSD: 0.25, Unpriced: 2,
+		Models: []audit.UsageByName{{Name: "gpt-4o-mini", Requests: 3, Tokens: 165, CostUSD: 0.25}},
+	},
+}
+
+func TestDigestMarkdown(t *testing.T) {
+	want := "# dict-be digest: 2026-03-01 08:00 to 2026-03-02 08:00\n\n" +
+		"- Requests: 3 (1 failed)\n- Tokens: 110 prompt, 55 completion\n" +
+		"- Cost: $0.2500 (2 requests without a price)\n- Known words: 412\n\n" +
+		"## By model\n\n| Model | Requests | Tokens | Cost |\n| --- | ---: | ---: | ---: |\n" +
+		"| gpt-4o-mini | 3 | 165 | $0.2500 |\n"
+	if got := testDigest.markdown(); got != want {
+		t.Fatalf("unexpected markdown:\n%s", got)
+	}
+}
+
+func TestPostDigest(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if r.URL.Path == "/fail" {
+			http.Error(w, "no such hook", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	if err := postDigest(context.Background(), server.URL+"/hooks/secret", "markdown", testDigest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text, _ := got["text"].(string); !strings.HasPrefix(text, "# dict-be digest") {
+		t.Fatalf("unexpected payload: %v", got)
+	}
+	if err := postDigest(context.Background(), server.URL+"/hooks/secret", render.JSON, testDigest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["known_words"] != float64(412) {
+		t.Fatalf("unexpected payload: %v", got)
+	}
+	if err := postDigest(context.Background(), server.URL+"/fail", render.JSON, testDigest); err == nil || !strings.Contains(err.Error(), "no such hook") {
+		t.Fatalf("expected the webhook error, got %v", err)
+	}
+	if got := redactWebhook("https://hooks.slack.com/services/T0/B0/XYZ"); got != "https://hooks.slack.com/..." {
+		t.Fatalf("unexpected redaction: %s", got)
+	}
+}
@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"dict-be/internal/document"
+	"dict-be/internal/glossary"
+	"dict-be/internal/llm"
+	"dict-be/internal/progress"
+	"dict-be/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultChunkSize is the --chunk-size default in characters, about 2,000
+// tokens of English: small enough for any model's context with the
+// prompt and the translation, large enough to keep paragraphs together.
+const defaultChunkSize = 6000
+
+// docTermsPerChunk bounds the terms extracted from each chunk for the
+// shared glossary.
+const docTermsPerChunk = 20
+
+type docTranslateOptions struct {
+	Output         string
+	InputLanguage  string
+	OutputLanguage string
+	ChunkSize      int
+	Concurrency    int
+	Glossary       string
+	NoTerms        bool
+	Markdown       bool
+	NoMarkdown     bool
+	Format         string
+	Progress       string
+}
+
+func newDocCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doc",
+		Short: "Work with whole documents",
+	}
+	cmd.AddCommand(newDocTranslateCmd())
+	return cmd
+}
+
+func newDocTranslateCmd() *cobra.Command {
+	opts := &docTranslateOptions{}
+	cmd := &cobra.Command{
+		Use:   "translate <file>",
+		Short: "Translate a large document in chunks with consistent terminology",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDocTranslate(cmd, opts, args[0])
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "output file (default: stdout)")
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "")
+	cmd.Flags().IntVar(&opts.ChunkSize, "chunk-size", defaultChunkSize, "maximum characters per request")
+	cmd.Flags().IntVarP(&opts.Concurrency, "concurrency", "j", 1, "number of chunks translated at the same time")
+	cmd.Flags().StringVar(&opts.Glossary, "glossary", "", "glossary CSV of term translations to use and check")
+	cmd.Flags().BoolVar(&opts.NoTerms, "no-terms", false, "do not extract a shared glossary from the document first")
+	addMarkdownFlags(cmd, &opts.Markdown, &opts.NoMarkdown)
+	cmd.Flags().StringVar(&opts.Format, "format", render.Text, formatHelp)
+	addProgressFlag(cmd, &opts.Progress)
+	return cmd
+}
+
+func runDocTranslate(cmd *cobra.Command, opts *docTranslateOptions, path string) error {
+	if err := validateFormat(opts.Format); err != nil {
+		return err
+	}
+	if opts.ChunkSize < 1 {
+		return fmt.Errorf("invalid --chunk-size: %d", opts.ChunkSize)
+	}
+	if opts.Concurrency < 1 {
+		return fmt.Errorf("invalid --concurrency: %d", opts.Concurrency)
+	}
+	if opts.Markdown && opts.NoMarkdown {
+		return errors.New("only one of --markdown or --no-markdown can be set")
+	}
+	if err := progress.ParseMode(opts.Progress); err != nil {
+		return err
+	}
+	input, err := readInput(nil, path, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(input) == "" {
+		return errors.New("input is required")
+	}
+	markdown := isMarkdownInput(path, opts.Markdown, opts.NoMarkdown)
+	var chunks, separators []string
+	var verbatim []bool
+	if markdown {
+		chunks, separators, verbatim = document.SplitMarkdownChunks(input, opts.ChunkSize)
+	} else {
+		chunks, separators = document.SplitChunks(input, opts.ChunkSize)
+		verbatim = make([]bool, len(chunks))
+	}
+	var prose []string
+	for i, chunk := range chunks {
+		if !verbatim[i] {
+			prose = append(prose, chunk)
+		}
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(input, opts.InputLanguage, opts.OutputLanguage)
+	terms, err := loadGlossary(opts.Glossary)
+	if err != nil {
+		return err
+	}
+
+	client, cfg, err := loadLLMClient(cmd)
+	if err != nil {
+		return err
+	}
+	post, err := newPostprocessPipeline(cfg)
+	if err != nil {
+		return err
+	}
+	// Term extraction sends one request per chunk before the translations,
+	// and the bar counts and prices both.
+	extractTerms := !opts.NoTerms && len(prose) > 1
+	requests := len(prose)
+	if extractTerms {
+		requests *= 2
+	}
+	bar := newProgress(cmd, opts.Progress, requests, "requests")
+	client = meterCost(client, cfg, func(usd float64) { bar.AddCost(usd) })
+	ctx := commandContext(cmd)
+
+	if extractTerms {
+		extracted, err := extractDocTerms(ctx, client, cfg.LLM.Model, prose, inputLanguage, outputLanguage, opts.Concurrency, bar)
+		if err != nil {
+			bar.Finish()
+			return fmt.Errorf("terms: %w", err)
+		}
+		terms = append(terms, glossary.Unknown(terms, extracted)...)
+	}
+	translate := newParagraphTranslator(client, cfg.LLM.Model, inputLanguage, outputLanguage, terms)
+	if markdown {
+		translate = document.ProtectMarkdown(translate)
+	}
+	translatedProse, err := mapChunks(ctx, prose, opts.Concurrency, countProgress(bar, translate))
+	bar.Finish()
+	if err != nil {
+		return err
+	}
+	// Chunks are numbered as sent, code blocks and front matter left out.
+	translated := make([]string, len(chunks))
+	n := 0
+	for i, chunk := range chunks {
+		if verbatim[i] {
+			translated[i] = chunk
+			continue
+		}
+		translated[i] = translatedProse[n]
+		n++
+		warnGlossary(cmd.ErrOrStderr(), terms, fmt.Sprintf("chunk %d", n), chunk, translated[i])
+	}
+	text, err := post.Apply(ctx, document.JoinParagraphs(translated, separators))
+	if err != nil {
+		return err
+	}
+	if opts.Output == "" {
+		return renderContent(cmd.OutOrStdout(), opts.Format, cfg.LLM.Model, text)
+	}
+	var buf bytes.Buffer
+	if err := renderContent(&buf, opts.Format, cfg.LLM.Model, text); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(opts.Output, buf.Bytes()); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "translated %d chunks with %d glossary terms -> %s\n", len(prose), len(terms), opts.Output)
+	return nil
+}
+
+// extractDocTerms asks for the key terms of each chunk and merges them,
+// keeping the first translation proposed for a term, so every chunk is
+// translated with the same glossary even when chunks run concurrently.
+// Each request is counted on bar.
+func extractDocTerms(ctx context.Context, client llm.Client, model string, chunks []string, inputLanguage, outputLanguage string, concurrency int, bar *progress.Reporter) ([]glossary.Term, error) {
+	extracted, err := mapChunks(ctx, chunks, concurrency, countProgress(bar, func(ctx context.Context, chunk string) ([]glossary.Term, error) {
+		systemPrompt, userPrompt, err := buildPrompts("terms", map[string]string{
+			"input":           chunk,
+			"input_language":  inputLanguage,
+			"output_language": outputLanguage,
+			"max":             strconv.Itoa(docTermsPerChunk),
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Chat(ctx, llm.ChatRequest{
+			Model:    model,
+			Messages: buildMessages(systemPrompt, userPrompt),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return parseExtractedTerms(resp.Content, docTermsPerChunk)
+	}))
+	if err != nil {
+		return nil, err
+	}
+	var terms []glossary.Term
+	for _, chunkTerms := range extracted {
+		terms = append(terms, glossary.Unknown(terms, chunkTerms)...)
+	}
+	return terms, nil
+}
+
+// countProgress wraps fn to mark each call done or failed on bar.
+func countProgress[T any](bar *progress.Reporter, fn func(ctx context.Context, chunk string) (T, error)) func(ctx context.Context, chunk string) (T, error) {
+	return func(ctx context.Context, chunk string) (T, error) {
+		result, err := fn(ctx, chunk)
+		if err != nil {
+			bar.Fail()
+		} else {
+			bar.Done()
+		}
+		return result, err
+	}
+}
+
+// mapChunks calls fn on each chunk with up to concurrency calls at a time
+// and returns the results in chunk order. The first error cancels the
+// calls still running and is returned with its chunk number.
+func mapChunks[T any](ctx context.Context, chunks []string, concurrency int, fn func(ctx context.Context, chunk string) (T, error)) ([]T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([]T, len(chunks))
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	slots := make(chan struct{}, concurrency)
+	for i, chunk := range chunks {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			result, err := fn(ctx, chunk)
+			if err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("chunk %d: %w", i+1, err)
+					cancel()
+				})
+				return
+			}
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/progress"
+)
+
+func TestMapChunks(t *testing.T) {
+	chunks := []string{"a", "bb", "ccc", "dddd", "eeeee"}
+	var running, peak atomic.Int32
+	results, err := mapChunks(context.Background(), chunks, 2, func(ctx context.Context, chunk string) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(6-len(chunk)) * time.Millisecond)
+		return len(chunk), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, n := range results {
+		if n != i+1 {
+			t.Fatalf("unexpected results: %v", results)
+		}
+	}
+	if peak.Load() > 2 {
+		t.Fatalf("ran %d chunks at once, want at most 2", peak.Load())
+	}
+
+	_, err = mapChunks(context.Background(), chunks, 1, func(ctx context.Context, chunk string) (string, error) {
+		if chunk == "ccc" {
+			return "", errors.New("boom")
+		}
+		return chunk, nil
+	})
+	if err == nil || err.Error() != "chunk 3: boom" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestExtractDocTerms(t *testing.T) {
+	client := &reviewFakeClient{fakeClient: fakeClient{resp: llm.ChatResponse{
+		Content: `[{"source":"Widget","target":"小部件"},{"source":"widget","target":"部件"},{"source":"API","target":"API"}]`,
+	}}}
+	var out bytes.Buffer
+	bar := progress.New(&out, progress.Bar, true, 2, "requests")
+	terms, err := extractDocTerms(context.Background(), client, "model", []string{"one", "two"}, "English", "Simplified Chinese", 1, bar)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(terms) != 2 || terms[0].Target != "小部件" || terms[1].Source != "API" {
+		t.Fatalf("unexpected terms: %+v", terms)
+	}
+	if len(client.requests) != 2 || !strings.Contains(client.requests[1].Messages[1].Content, "<input>two</input>") {
+		t.Fatalf("unexpected requests: %+v", client.requests)
+	}
+	if !strings.Contains(out.String(), "2/2 requests") {
+		t.Fatalf("expected extraction requests on the progress bar: %q", out.String())
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type draftOptions struct {
+	InputFile      string
+	OutputLanguage string
+	BackLanguage   string
+	Tone           string
+	Output         outputOptions
+}
+
+func newDraftCmd() *cobra.Command {
+	opts := &draftOptions{}
+	cmd := &cobra.Command{
+		Use:   "draft <instructions...>",
+		Short: "Draft a message or reply in the target language",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDraft(cmd, opts, args)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.InputFile, "file", "F", "", "message to reply to, use -F- for stdin")
+	addOutputLanguageFlag(cmd, &opts.OutputLanguage, "o", "English", "language of the drafted message")
+	cmd.Flags().StringVar(&opts.BackLanguage, "back", "auto", "back-translation language (default: language of the instructions)")
+	cmd.Flags().StringVar(&opts.Tone, "tone", "polite", "tone of the message, e.g. polite, casual, formal")
+	opts.Output.addFlags(cmd)
+	return cmd
+}
+
+func runDraft(cmd *cobra.Command, opts *draftOptions, args []string) error {
+	if err := opts.Output.validate(); err != nil {
+		return err
+	}
+	instruction := strings.TrimSpace(strings.Join(args, " "))
+	if instruction == "" {
+		return fmt.Errorf("instructions are required")
+	}
+	var original string
+	if opts.InputFile != "" {
+		text, err := readInput(nil, opts.InputFile, cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+		original = strings.TrimSpace(text)
+	}
+	backLanguage := opts.BackLanguage
+	if backLanguage == "auto" {
+		backLanguage, _ = resolveLanguages(instruction, "auto", "auto")
+	}
+	contextInstruction := "Write a new message."
+	originalBlock := ""
+	if original != "" {
+		contextInstruction = "The user is replying to the message inside the <original> tags; address its points and match its register."
+		originalBlock = "<original>" + original + "</original>"
+	}
+	return runPromptCommand(cmd, &opts.Output, "draft", map[string]string{
+		"instruction":         instruction,
+		"original":            originalBlock,
+		"output_language":     opts.OutputLanguage,
+		"back_language":       backLanguage,
+		"tone":                opts.Tone,
+		"context_instruction": contextInstruction,
+	})
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildDraftPrompts(t *testing.T) {
+	systemPrompt, userPrompt, err := buildPrompts("draft", map[string]string{
+		"instruction":         "decline the meeting but propose next week",
+		"original":            "<original>Can we meet on Friday?</original>",
+		"output_language":     "Japanese",
+		"back_language":       "English",
+		"tone":                "polite",
+		"context_instruction": "reply",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, "back-translation of the message into English") {
+		t.Fatalf("unexpected system prompt: %q", systemPrompt)
+	}
+	if !strings.Contains(userPrompt, "<original>Can we meet on Friday?</original>") {
+		t.Fatalf("unexpected user prompt: %q", userPrompt)
+	}
+}
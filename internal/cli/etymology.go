@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type etymologyOptions struct {
+	InputLanguage  string
+	OutputLanguage string
+	Output         outputOptions
+}
+
+func newEtymologyCmd() *cobra.Command {
+	opts := &etymologyOptions{}
+	cmd := &cobra.Command{
+		Use:   "etymology <word>",
+		Short: "Explain a word's origin, roots and related words",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEtymology(cmd, opts, strings.Join(args, " "))
+		},
+	}
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "o")
+	opts.Output.addFlags(cmd)
+	return cmd
+}
+
+func runEtymology(cmd *cobra.Command, opts *etymologyOptions, word string) error {
+	if err := opts.Output.validate(); err != nil {
+		return err
+	}
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return fmt.Errorf("word is required")
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(word, opts.InputLanguage, opts.OutputLanguage)
+	return runPromptCommand(cmd, &opts.Output, "etymology", map[string]string{
+		"input":           word,
+		"input_language":  inputLanguage,
+		"output_language": outputLanguage,
+		"parts":           etymologyParts(word),
+	})
+}
+
+// etymologyParts names what a word is built from: characters and their
+// components for Chinese, roots and affixes otherwise.
+func etymologyParts(word string) string {
+	if containsChinese(word) {
+		return "each character, its components (radical, semantic and phonetic parts) and what they mean"
+	}
+	return "its roots, prefixes and suffixes, each with its source language and meaning"
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildEtymologyPrompts(t *testing.T) {
+	systemPrompt, userPrompt, err := buildPrompts("etymology", map[string]string{
+		"input":           "telephone",
+		"input_language":  "English",
+		"output_language": "Simplified Chinese",
+		"parts":           etymologyParts("telephone"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, "Break it into its roots, prefixes and suffixes") || !strings.Contains(systemPrompt, "folk etymologies") {
+		t.Fatalf("unexpected system prompt: %q", systemPrompt)
+	}
+	if !strings.Contains(userPrompt, "<input>telephone</input>") {
+		t.Fatalf("unexpected user prompt: %q", userPrompt)
+	}
+	if got := etymologyParts("电话"); !strings.Contains(got, "radical") {
+		t.Fatalf("unexpected parts for Chinese: %q", got)
+	}
+}
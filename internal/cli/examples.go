@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/postprocess"
+	"dict-be/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+// maxExamples bounds --count; longer lists drift off the requested level.
+const maxExamples = 20
+
+type examplesOptions struct {
+	InputLanguage  string
+	OutputLanguage string
+	Count          int
+	Level          string
+	Format         string
+}
+
+// exampleSet is the JSON output of examples.
+type exampleSet struct {
+	Word     string         `json:"word"`
+	Level    string         `json:"level"`
+	Examples []queryExample `json:"examples"`
+}
+
+func newExamplesCmd() *cobra.Command {
+	opts := &examplesOptions{}
+	cmd := &cobra.Command{
+		Use:   "examples <word>",
+		Short: "Write bilingual example sentences for a word at a learner level",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExamples(cmd, opts, strings.Join(args, " "))
+		},
+	}
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "o")
+	cmd.Flags().IntVarP(&opts.Count, "count", "n", 5, "number of example sentences")
+	addLevelFlag(cmd, &opts.Level, "B1")
+	cmd.Flags().StringVar(&opts.Format, "format", render.Text, formatHelp+" (json prints the sentences as an object)")
+	return cmd
+}
+
+func runExamples(cmd *cobra.Command, opts *examplesOptions, word string) error {
+	if err := validateFormat(opts.Format); err != nil {
+		return err
+	}
+	if opts.Count < 1 || opts.Count > maxExamples {
+		return fmt.Errorf("invalid --count: %d (expected 1 to %d)", opts.Count, maxExamples)
+	}
+	level := learnerLevel(opts.Level, "B1")
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return fmt.Errorf("word is required")
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(word, opts.InputLanguage, opts.OutputLanguage)
+	systemPrompt, userPrompt, err := buildPrompts("examples", map[string]string{
+		"input":           word,
+		"input_language":  inputLanguage,
+		"output_language": outputLanguage,
+		"level":           level,
+		"count":           strconv.Itoa(opts.Count),
+	})
+	if err != nil {
+		return err
+	}
+	client, cfg, err := loadLLMClient(cmd)
+	if err != nil {
+		return err
+	}
+	post, err := newPostprocessPipeline(cfg)
+	if err != nil {
+		return err
+	}
+	req := llm.ChatRequest{
+		Model:    cfg.LLM.Model,
+		Messages: buildMessages(systemPrompt, userPrompt),
+	}
+	set := exampleSet{Word: word, Level: level}
+	return writeExamples(commandContext(cmd), cmd.OutOrStdout(), client, req, set, opts.Count, opts.Format, post)
+}
+
+// writeExamples asks for the sentences as JSON and prints them in format.
+// JSON output is the validated set; other formats get a numbered list,
+// post-processed like any other response.
+func writeExamples(ctx context.Context, out io.Writer, client llm.Client, req llm.ChatRequest, set exampleSet, count int, format string, post postprocess.Pipeline) error {
+	resp, err := client.Chat(ctx, req)
+	if err != nil {
+		return err
+	}
+	set.Examples, err = parseExamples(resp.Content, count)
+	if err != nil {
+		return err
+	}
+	if format == render.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(set)
+	}
+	text, err := post.Apply(ctx, strings.TrimSuffix(set.text(), "\n"))
+	if err != nil {
+		return err
+	}
+	return renderContent(out, format, firstNonEmpty(resp.Model, req.Model), text)
+}
+
+// parseExamples decodes the model's sentences, dropping blank ones and any
+// beyond count.
+func parseExamples(content string, count int) ([]queryExample, error) {
+	var answer struct {
+		Examples []queryExample `json:"examples"`
+	}
+	if err := decodeJSONContent(content, &answer); err != nil {
+		return nil, err
+	}
+	var examples []queryExample
+	for _, example := range answer.Examples {
+		example.Sentence = strings.TrimSpace(example.Sentence)
+		example.Translation = strings.TrimSpace(example.Translation)
+		if example.Sentence != "" && len(examples) < count {
+			examples = append(examples, example)
+		}
+	}
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("model returned no example sentences")
+	}
+	return examples, nil
+}
+
+// text numbers the sentences, each followed by its indented translation.
+func (s exampleSet) text() string {
+	var b strings.Builder
+	for i, example := range s.Examples {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, example.Sentence)
+		if example.Translation != "" {
+			fmt.Fprintf(&b, "   %s\n", example.Translation)
+		}
+	}
+	return b.String()
+}
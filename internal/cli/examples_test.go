@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/render"
+)
+
+const testExamples = "```json\n" + `{"examples":[{"sentence":" The ferry left the pier. ","translation":"渡船离开了码头。"},` +
+	`{"sentence":"","translation":"空"},{"sentence":"We fished off the pier.","translation":"我们在码头钓鱼。"},` +
+	`{"sentence":"A third one.","translation":"第三句。"}]}` + "\n```"
+
+func TestWriteExamples(t *testing.T) {
+	client := &fakeClient{resp: llm.ChatResponse{Content: testExamples}}
+	set := exampleSet{Word: "pier", Level: "A2"}
+	cases := map[string]string{
+		render.JSON: `{
+  "word": "pier",
+  "level": "A2",
+  "examples": [
+    {
+      "sentence": "The ferry left the pier.",
+      "translation": "渡船离开了码头。"
+    },
+    {
+      "sentence": "We fished off the pier.",
+      "translation": "我们在码头钓鱼。"
+    }
+  ]
+}
+`,
+		render.Text: "1. The ferry left the pier.\n   渡船离开了码头。\n2. We fished off the pier.\n   我们在码头钓鱼。\n",
+	}
+	for format, want := range cases {
+		var out bytes.Buffer
+		if err := writeExamples(context.Background(), &out, client, llm.ChatRequest{}, set, 2, format, nil); err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if out.String() != want {
+			t.Fatalf("%s: unexpected output:\n%q", format, out.String())
+		}
+	}
+	if _, err := parseExamples(`{"examples":[{"sentence":" "}]}`, 3); err == nil {
+		t.Fatalf("expected an error for no sentences")
+	}
+}
+
+func TestBuildExamplesPrompts(t *testing.T) {
+	systemPrompt, userPrompt, err := buildPrompts("examples", map[string]string{
+		"input":           "pier",
+		"input_language":  "English",
+		"output_language": "Simplified Chinese",
+		"level":           "B1",
+		"count":           "4",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, "Write 4 natural") || !strings.Contains(systemPrompt, "a B1 learner") {
+		t.Fatalf("unexpected system prompt: %q", systemPrompt)
+	}
+	if !strings.Contains(userPrompt, "<input>pier</input>") {
+		t.Fatalf("unexpected user prompt: %q", userPrompt)
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"dict-be/internal/document"
+)
+
+// --on-error values for translate.
+const (
+	onErrorStop        = "stop"
+	onErrorSource      = "source"
+	onErrorPlaceholder = "placeholder"
+)
+
+// failureFill returns how failed paragraphs are filled for mode, or nil
+// when translate should stop at the first failure.
+func failureFill(mode string) (document.FillFunc, error) {
+	switch mode {
+	case onErrorStop:
+		return nil, nil
+	case onErrorSource:
+		return func(n int, source string) string { return source }, nil
+	case onErrorPlaceholder:
+		return func(n int, source string) string {
+			return fmt.Sprintf("[paragraph %d not translated]", n)
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid --on-error: %s (expected stop, source or placeholder)", mode)
+}
+
+type failureReport struct {
+	Input      string          `json:"input"`
+	Job        string          `json:"job,omitempty"`
+	Paragraphs int             `json:"paragraphs"`
+	Failures   []failureRecord `json:"failures"`
+}
+
+type failureRecord struct {
+	Paragraph int    `json:"paragraph"`
+	Error     string `json:"error"`
+	Source    string `json:"source"`
+}
+
+// writeFailureReport saves the failed paragraphs of result as JSON, so they
+// can be inspected and retried with translate --resume <job>.
+func writeFailureReport(path, input, jobID string, result document.Result) error {
+	report := failureReport{
+		Input:      input,
+		Job:        jobID,
+		Paragraphs: result.Paragraphs,
+		Failures:   make([]failureRecord, 0, len(result.Failures)),
+	}
+	for _, failure := range result.Failures {
+		report.Failures = append(report.Failures, failureRecord{
+			Paragraph: failure.Paragraph,
+			Error:     failure.Err.Error(),
+			Source:    failure.Source,
+		})
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("write failure report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dict-be/internal/document"
+)
+
+func TestFailureFill(t *testing.T) {
+	if fill, err := failureFill(onErrorStop); fill != nil || err != nil {
+		t.Fatalf("stop should not fill: %v", err)
+	}
+	fill, err := failureFill(onErrorPlaceholder)
+	if err != nil || fill(3, "text") != "[paragraph 3 not translated]" {
+		t.Fatalf("unexpected placeholder fill: %v", err)
+	}
+	if fill, _ := failureFill(onErrorSource); fill(1, "text") != "text" {
+		t.Fatalf("unexpected source fill")
+	}
+	if _, err := failureFill("skip"); err == nil {
+		t.Fatalf("expected invalid mode error")
+	}
+}
+
+func TestWriteFailureReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "failures.json")
+	result := document.Result{
+		Paragraphs: 4,
+		Failures:   []document.Failure{{Paragraph: 2, Source: "bad", Err: errors.New("rate limited")}},
+	}
+	if err := writeFailureReport(path, "doc.md", "20260101-abcd", result); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	var report failureReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.Job != "20260101-abcd" || len(report.Failures) != 1 || report.Failures[0].Error != "rate limited" {
+		t.Fatalf("unexpected report: %s", data)
+	}
+	if err := failuresError(result); err == nil || err.Error() != "1 of 4 paragraphs failed (paragraph 2: rate limited)" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
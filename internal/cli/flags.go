@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"dict-be/internal/config"
+	"dict-be/internal/llm"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// languageFlagAliases maps long alias names to their canonical flag.
+var languageFlagAliases = map[string]string{
+	"input-language":  "in",
+	"output-language": "out",
+}
+
+// addLanguageFlags registers --in and --out as the canonical language
+// flags, accepting --input-language and --output-language as aliases.
+// Aliases resolve to the same flag, so when a language is given more than
+// once the last occurrence wins. Pass an empty shorthand where -i or -o is
+// already taken.
+func addLanguageFlags(cmd *cobra.Command, in, out *string, inShorthand, outShorthand string) {
+	cmd.Flags().StringVarP(in, "in", inShorthand, "auto", "input language (alias --input-language)")
+	addOutputLanguageFlag(cmd, out, outShorthand, "auto", "output language")
+}
+
+// addOutputLanguageFlag registers only the --out half of addLanguageFlags,
+// with its --output-language alias, for commands whose input language is
+// fixed or chosen by another flag. value is the command's default.
+func addOutputLanguageFlag(cmd *cobra.Command, out *string, shorthand, value, usage string) {
+	cmd.Flags().StringVarP(out, "out", shorthand, value, usage+" (alias --output-language)")
+	cmd.Flags().SetNormalizeFunc(normalizeLanguageFlag)
+}
+
+func normalizeLanguageFlag(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	if canonical, ok := languageFlagAliases[name]; ok {
+		name = canonical
+	}
+	return pflag.NormalizedName(name)
+}
+
+// addLevelFlag registers --level. Its value falls back to the level config
+// key through learnerLevel.
+func addLevelFlag(cmd *cobra.Command, level *string, fallback string) {
+	usage := "learner level, e.g. A2, B1, C1, HSK4 (default: level in config"
+	if fallback != "" {
+		usage += ", or " + fallback
+	}
+	cmd.Flags().StringVar(level, "level", "", usage+")")
+}
+
+// learnerLevel returns the --level value, or else the level config key,
+// or else fallback.
+func learnerLevel(flag, fallback string) string {
+	if level := strings.TrimSpace(flag); level != "" {
+		return level
+	}
+	// An invalid config fails the command when its client is loaded.
+	cfg, _ := config.Load()
+	return firstNonEmpty(strings.TrimSpace(cfg.Level), fallback)
+}
+
+// samplingOptions holds --temperature and --max-tokens. Temperature only
+// applies when the flag is given, so providers keep their own default.
+type samplingOptions struct {
+	Temperature float64
+	MaxTokens   int
+	flags       *pflag.FlagSet
+}
+
+func addSamplingFlags(cmd *cobra.Command, opts *samplingOptions) {
+	cmd.Flags().Float64Var(&opts.Temperature, "temperature", 0, "sampling temperature (default: provider default)")
+	cmd.Flags().IntVar(&opts.MaxTokens, "max-tokens", 0, "maximum number of tokens to generate (default: provider default)")
+	opts.flags = cmd.Flags()
+}
+
+// validate rejects out-of-range values before a request is sent.
+func (o *samplingOptions) validate() error {
+	if o.Temperature < 0 || o.Temperature > 2 {
+		return fmt.Errorf("--temperature must be between 0 and 2")
+	}
+	if o.MaxTokens < 0 {
+		return fmt.Errorf("--max-tokens must not be negative")
+	}
+	return nil
+}
+
+func (o *samplingOptions) apply(req *llm.ChatRequest) {
+	if o.flags != nil && o.flags.Changed("temperature") {
+		temperature := o.Temperature
+		req.Temperature = &temperature
+	}
+	req.MaxTokens = o.MaxTokens
+}
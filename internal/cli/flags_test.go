@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"dict-be/internal/llm"
+
+	"github.com/spf13/cobra"
+)
+
+func newLanguageFlagsCmd(inShorthand, outShorthand string) (*cobra.Command, *string, *string) {
+	var in, out string
+	cmd := &cobra.Command{Use: "test"}
+	addLanguageFlags(cmd, &in, &out, inShorthand, outShorthand)
+	return cmd, &in, &out
+}
+
+func TestLanguageFlagAliases(t *testing.T) {
+	cmd, in, out := newLanguageFlagsCmd("i", "o")
+	if err := cmd.ParseFlags([]string{"--input-language", "German", "--output-language", "French"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if *in != "German" || *out != "French" {
+		t.Fatalf("unexpected languages: %q %q", *in, *out)
+	}
+}
+
+func TestLanguageFlagShorthands(t *testing.T) {
+	cmd, in, out := newLanguageFlagsCmd("i", "o")
+	if err := cmd.ParseFlags([]string{"-i", "Japanese", "-o", "Korean"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if *in != "Japanese" || *out != "Korean" {
+		t.Fatalf("unexpected languages: %q %q", *in, *out)
+	}
+}
+
+func TestLanguageFlagLastOccurrenceWins(t *testing.T) {
+	cmd, in, _ := newLanguageFlagsCmd("i", "o")
+	if err := cmd.ParseFlags([]string{"--in", "German", "--input-language", "Italian"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if *in != "Italian" {
+		t.Fatalf("expected last occurrence to win, got %q", *in)
+	}
+	if !cmd.Flags().Changed("in") {
+		t.Fatalf("expected alias to mark the canonical flag as changed")
+	}
+}
+
+func TestLanguageFlagDefaults(t *testing.T) {
+	cmd, in, out := newLanguageFlagsCmd("", "")
+	if err := cmd.ParseFlags(nil); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if *in != "auto" || *out != "auto" {
+		t.Fatalf("unexpected defaults: %q %q", *in, *out)
+	}
+	if cmd.Flags().ShorthandLookup("o") != nil {
+		t.Fatalf("unexpected -o shorthand")
+	}
+}
+
+func TestLanguageFlagHelpListsCanonicalOnly(t *testing.T) {
+	cmd, _, _ := newLanguageFlagsCmd("i", "o")
+	usage := cmd.Flags().FlagUsages()
+	if strings.Count(usage, "--in ") != 1 || strings.Contains(usage, "--input-language string") {
+		t.Fatalf("unexpected usage:\n%s", usage)
+	}
+}
+
+func TestOutputLanguageFlag(t *testing.T) {
+	var out string
+	cmd := &cobra.Command{Use: "test"}
+	addOutputLanguageFlag(cmd, &out, "o", "English", "gloss language")
+	if err := cmd.ParseFlags(nil); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if out != "English" {
+		t.Fatalf("unexpected default: %q", out)
+	}
+	if err := cmd.ParseFlags([]string{"--output-language", "German"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if out != "German" {
+		t.Fatalf("unexpected language: %q", out)
+	}
+	if cmd.Flags().Lookup("in") != nil {
+		t.Fatalf("unexpected --in flag")
+	}
+}
+
+func TestSamplingFlags(t *testing.T) {
+	var opts samplingOptions
+	cmd := &cobra.Command{Use: "test"}
+	addSamplingFlags(cmd, &opts)
+	if err := cmd.ParseFlags([]string{"--max-tokens", "200"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	var req llm.ChatRequest
+	opts.apply(&req)
+	if req.Temperature != nil || req.MaxTokens != 200 {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	if err := cmd.ParseFlags([]string{"--temperature", "0"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	opts.apply(&req)
+	if req.Temperature == nil || *req.Temperature != 0 {
+		t.Fatalf("expected explicit zero temperature: %+v", req)
+	}
+	opts.Temperature = 3
+	if err := opts.validate(); err == nil {
+		t.Fatalf("expected range error")
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"dict-be/internal/glossary"
+)
+
+// loadGlossary reads the --glossary CSV. Unlike --learn-glossary, the
+// file must exist.
+func loadGlossary(path string) ([]glossary.Term, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read glossary: %w", err)
+	}
+	defer f.Close()
+	return glossary.ReadCSV(f)
+}
+
+// glossaryInstruction lists the glossary terms that occur in input with
+// their required translations, for the {{.glossary}} prompt variable.
+func glossaryInstruction(terms []glossary.Term, input string) string {
+	matched := glossary.Match(terms, input)
+	if len(matched) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Always translate these terms exactly as given, keeping the spelling and capitalization of the translation:")
+	for _, term := range matched {
+		fmt.Fprintf(&b, "\n- %s -> %s", term.Source, term.Target)
+		if term.Note != "" {
+			fmt.Fprintf(&b, " (%s)", term.Note)
+		}
+	}
+	return b.String()
+}
+
+// warnGlossary prints a warning for each glossary term in source whose
+// translation is missing from translation, and returns how many there
+// were. where names the part of the input checked, or is empty.
+func warnGlossary(out io.Writer, terms []glossary.Term, where, source, translation string) int {
+	missing := glossary.Missing(terms, source, translation)
+	prefix := "warning: "
+	if where != "" {
+		prefix += where + ": "
+	}
+	for _, term := range missing {
+		fmt.Fprintf(out, "%sglossary term %q not translated as %q\n", prefix, term.Source, term.Target)
+	}
+	return len(missing)
+}
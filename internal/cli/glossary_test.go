@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"dict-be/internal/document"
+	"dict-be/internal/glossary"
+)
+
+func TestGlossaryInstruction(t *testing.T) {
+	terms := []glossary.Term{
+		{Source: "Acme Cloud", Target: "Acme Cloud", Note: "product"},
+		{Source: "rate limit", Target: "速率限制"},
+	}
+	got := glossaryInstruction(terms, "Acme Cloud is fast.")
+	if got != "Always translate these terms exactly as given, keeping the spelling and capitalization of the translation:\n- Acme Cloud -> Acme Cloud (product)" {
+		t.Fatalf("unexpected prompt:\n%s", got)
+	}
+	if got := glossaryInstruction(terms, "nothing here"); got != "" {
+		t.Fatalf("expected no instruction, got:\n%s", got)
+	}
+	systemPrompt, _, err := buildQueryPrompts(queryPrompt{Input: "Acme Cloud", InputLanguage: "English", OutputLanguage: "German", Sections: []string{"translation"}, Glossary: got})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(systemPrompt, "- Acme Cloud -> Acme Cloud (product)") {
+		t.Fatalf("glossary missing from system prompt:\n%s", systemPrompt)
+	}
+}
+
+func TestCheckGlossary(t *testing.T) {
+	terms := []glossary.Term{{Source: "rate limit", Target: "速率限制"}}
+	result := document.Result{
+		Sections: []document.Pair{
+			{Source: "The rate limit.", Target: "速率限制。"},
+			{Source: "Another rate limit.", Target: "另一个限流。"},
+			{Source: "A failed rate limit.", Target: "A failed rate limit."},
+		},
+		Failures: []document.Failure{{Paragraph: 3}},
+	}
+	var out bytes.Buffer
+	checkGlossary(&out, terms, result)
+	if got := strings.TrimSpace(out.String()); got != `warning: paragraph 2: glossary term "rate limit" not translated as "速率限制"` {
+		t.Fatalf("unexpected warnings:\n%s", out.String())
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"dict-be/internal/config"
+	"dict-be/internal/llm"
+)
+
+const healthCheckTimeout = 30 * time.Second
+
+type healthTarget struct {
+	name string
+	cfg  config.LLMConfig
+}
+
+// healthTargets lists the llm section, as "default", followed by every
+// profile in name order.
+func healthTargets(cfg config.Config) []healthTarget {
+	targets := []healthTarget{{name: "default", cfg: cfg.DefaultLLM}}
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		targets = append(targets, healthTarget{name: name, cfg: cfg.Profiles[name]})
+	}
+	return targets
+}
+
+// runLLMTestAll pings every configured provider concurrently and prints
+// one row per provider. It fails if any check fails. Each check is
+// bounded by healthCheckTimeout and stops early when ctx is cancelled.
+func runLLMTestAll(ctx context.Context, out io.Writer, cfg config.Config) error {
+	targets := healthTargets(cfg)
+	results := make([]llm.Health, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		if target.cfg.Type == "" {
+			targets[i].cfg.Type = "openai"
+		}
+		client, err := newLLMClient(targets[i].cfg)
+		if err != nil {
+			results[i] = llm.Health{Err: err}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, client llm.Client) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			results[i] = llm.HealthCheck(ctx, client)
+		}(i, client)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	failed := 0
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROFILE\tTYPE\tMODEL\tLATENCY\tSTATUS")
+	for i, target := range targets {
+		result := results[i]
+		status, latency := "ok", result.Latency.Round(time.Millisecond).String()
+		if result.Err != nil {
+			failed++
+			status = "error: " + strings.ReplaceAll(result.Err.Error(), "\n", " ")
+			if result.Latency == 0 {
+				latency = "-"
+			}
+		}
+		model := firstNonEmpty(result.Model, target.cfg.Model, "-")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", target.name, target.cfg.Type, model, latency, status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d providers failed", failed, len(targets))
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"dict-be/internal/config"
+)
+
+func TestRunLLMTestAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"model":"gpt-test","choices":[{"message":{"content":"pong"},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+
+	cfg := config.Config{
+		DefaultLLM: config.LLMConfig{URL: server.URL, Token: "t", Model: "gpt-test"},
+		Profiles: map[string]config.LLMConfig{
+			"broken": {Type: "openai", URL: server.URL},
+		},
+	}
+	var out bytes.Buffer
+	err := runLLMTestAll(context.Background(), &out, cfg)
+	if err == nil || err.Error() != "1 of 2 providers failed" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("unexpected table:\n%s", out.String())
+	}
+	if fields := strings.Fields(lines[1]); fields[0] != "default" || fields[1] != "openai" || fields[2] != "gpt-test" || fields[4] != "ok" {
+		t.Fatalf("unexpected default row: %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "broken") || !strings.Contains(lines[2], "error: openai token is required") {
+		t.Fatalf("unexpected broken row: %q", lines[2])
+	}
+}
+
+func TestRunLLMTestAllCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	cfg := config.Config{DefaultLLM: config.LLMConfig{URL: server.URL, Token: "t", Model: "gpt-test"}}
+	start := time.Now()
+	err := runLLMTestAll(ctx, &bytes.Buffer{}, cfg)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("cancellation took %s", elapsed)
+	}
+}
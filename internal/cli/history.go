@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"dict-be/internal/history"
+
+	"github.com/spf13/cobra"
+)
+
+type historyFilterOptions struct {
+	Since string
+	Until string
+	Limit int
+}
+
+func (o *historyFilterOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Since, "since", "", "only entries from this time on: a date (2006-01-02), RFC 3339 time or age such as 36h, 7d or 2w")
+	cmd.Flags().StringVar(&o.Until, "until", "", "only entries before this time, in the same forms as --since")
+	cmd.Flags().IntVarP(&o.Limit, "limit", "n", 20, "show at most this many of the latest matches (0 for all)")
+}
+
+func (o *historyFilterOptions) filter(terms []string, now time.Time) (history.Filter, error) {
+	if o.Limit < 0 {
+		return history.Filter{}, fmt.Errorf("invalid --limit: %d", o.Limit)
+	}
+	since, err := parseTimeFilter("--since", o.Since, now)
+	if err != nil {
+		return history.Filter{}, err
+	}
+	until, err := parseTimeFilter("--until", o.Until, now)
+	if err != nil {
+		return history.Filter{}, err
+	}
+	return history.Filter{Terms: terms, Since: since, Until: until}, nil
+}
+
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List, search and clear past queries",
+	}
+	cmd.AddCommand(newHistoryListCmd())
+	cmd.AddCommand(newHistorySearchCmd())
+	cmd.AddCommand(newHistoryShowCmd())
+	cmd.AddCommand(newHistoryClearCmd())
+	return cmd
+}
+
+func newHistoryListCmd() *cobra.Command {
+	opts := &historyFilterOptions{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recent queries: ID, time, input and answer",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listHistory(cmd, opts, nil)
+		},
+	}
+	opts.addFlags(cmd)
+	return cmd
+}
+
+func newHistorySearchCmd() *cobra.Command {
+	opts := &historyFilterOptions{}
+	cmd := &cobra.Command{
+		Use:   "search <term...>",
+		Short: "List queries whose input or answer contains every term",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listHistory(cmd, opts, args)
+		},
+	}
+	opts.addFlags(cmd)
+	return cmd
+}
+
+func newHistoryShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "Print a past query and its answer",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid id: %s", args[0])
+			}
+			store, err := newHistoryStore()
+			if err != nil {
+				return err
+			}
+			record, err := store.Find(id)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "#%d %s (%s, %s)\n\n%s\n", record.ID, record.Input, record.Command,
+				record.Time.Local().Format("2006-01-02 15:04"), strings.TrimRight(record.Response, "\n"))
+			return nil
+		},
+	}
+}
+
+func newHistoryClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Delete the query history",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := newHistoryStore()
+			if err != nil {
+				return err
+			}
+			cleared, err := store.Clear()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "cleared %d history entries\n", cleared)
+			return nil
+		},
+	}
+}
+
+func listHistory(cmd *cobra.Command, opts *historyFilterOptions, terms []string) error {
+	filter, err := opts.filter(terms, time.Now())
+	if err != nil {
+		return err
+	}
+	store, err := newHistoryStore()
+	if err != nil {
+		return err
+	}
+	records, err := store.Load()
+	if err != nil {
+		return err
+	}
+	var matched []history.Record
+	for _, record := range records {
+		if filter.Match(record) {
+			matched = append(matched, record)
+		}
+	}
+	if opts.Limit > 0 && len(matched) > opts.Limit {
+		matched = matched[len(matched)-opts.Limit:]
+	}
+	for _, record := range matched {
+		fmt.Fprintf(cmd.OutOrStdout(), "%d\t%s\t%s\t%s\n", record.ID, record.Time.Local().Format("2006-01-02 15:04"),
+			summarizeLine(record.Input), summarizeLine(record.Response))
+	}
+	return nil
+}
+
+func newHistoryStore() (*history.Store, error) {
+	path, err := history.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return history.NewStore(path), nil
+}
+
+// recordHistory appends a lookup to the history. It never fails the
+// command, which has already printed its answer; problems are warnings.
+func recordHistory(cmd *cobra.Command, record history.Record) {
+	store, err := newHistoryStore()
+	if err == nil {
+		_, err = store.Append(record)
+	}
+	if err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: "+err.Error())
+	}
+}
+
+// parseTimeFilter reads a --since or --until value: a date, an RFC 3339
+// time, or an age before now in hours, days or weeks. Empty is the zero
+// time.
+func parseTimeFilter(flag, value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	if unit, ok := units[value[len(value)-1]]; ok {
+		if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n >= 0 {
+			return now.Add(-time.Duration(n) * unit), nil
+		}
+	} else if age, err := time.ParseDuration(value); err == nil && age >= 0 {
+		return now.Add(-age), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s: %s (expected a date, RFC 3339 time or age such as 36h, 7d or 2w)", flag, value)
+}
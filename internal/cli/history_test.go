@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"dict-be/internal/history"
+)
+
+func TestHistoryCommands(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	run := func(args ...string) (string, error) {
+		cmd := newHistoryCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	recordCmd := newHistoryCmd()
+	var warnings bytes.Buffer
+	recordCmd.SetErr(&warnings)
+	recordHistory(recordCmd, history.Record{Command: "query", Input: "pier", Response: "## Translation\n\n码头"})
+	recordHistory(recordCmd, history.Record{Command: "query", Input: "run", Response: "跑"})
+	if warnings.Len() != 0 {
+		t.Fatalf("unexpected warnings: %s", warnings.String())
+	}
+
+	out, err := run("list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "1\t") || !strings.HasSuffix(lines[0], "\tpier\tTranslation") {
+		t.Fatalf("unexpected list:\n%s", out)
+	}
+	if out, _ = run("list", "-n", "1"); !strings.HasPrefix(out, "2\t") || strings.Count(out, "\n") != 1 {
+		t.Fatalf("expected only the latest entry, got:\n%s", out)
+	}
+	if out, _ = run("search", "码头"); !strings.HasPrefix(out, "1\t") || strings.Count(out, "\n") != 1 {
+		t.Fatalf("unexpected search:\n%s", out)
+	}
+	if out, _ = run("search", "pier", "--since", "1h"); strings.Count(out, "\n") != 1 {
+		t.Fatalf("unexpected search with --since:\n%s", out)
+	}
+	if out, _ = run("search", "pier", "--until", "1h"); out != "" {
+		t.Fatalf("expected nothing before an hour ago, got:\n%s", out)
+	}
+	if out, err = run("show", "1"); err != nil || !strings.HasPrefix(out, "#1 pier (query, ") || !strings.HasSuffix(out, "码头\n") {
+		t.Fatalf("unexpected show: %q, %v", out, err)
+	}
+	if out, err = run("clear"); err != nil || out != "cleared 2 history entries\n" {
+		t.Fatalf("unexpected clear: %q, %v", out, err)
+	}
+	if out, _ = run("list"); out != "" {
+		t.Fatalf("expected an empty history, got:\n%s", out)
+	}
+}
+
+func TestParseTimeFilter(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"":                     {},
+		"36h":                  now.Add(-36 * time.Hour),
+		"7d":                   now.AddDate(0, 0, -7),
+		"2w":                   now.AddDate(0, 0, -14),
+		"2026-03-01T08:00:00Z": time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+		"2026-03-01":           time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local),
+	}
+	for value, want := range cases {
+		got, err := parseTimeFilter("--since", value, now)
+		if err != nil || !got.Equal(want) {
+			t.Fatalf("%q: got %v, %v, want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"yesterday", "-3d", "5x"} {
+		if _, err := parseTimeFilter("--since", value, now); err == nil {
+			t.Fatalf("%q: expected an error", value)
+		}
+	}
+}
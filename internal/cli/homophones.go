@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"dict-be/internal/homophone"
+
+	"github.com/spf13/cobra"
+)
+
+type homophonesOptions struct {
+	Language       string
+	OutputLanguage string
+	Offline        bool
+	Output         outputOptions
+}
+
+func newHomophonesCmd() *cobra.Command {
+	opts := &homophonesOptions{}
+	cmd := &cobra.Command{
+		Use:   "homophones <word>",
+		Short: "List words that sound alike, with readings and examples",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHomophones(cmd, opts, args)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Language, "lang", "auto", "word language: zh, en or auto")
+	addOutputLanguageFlag(cmd, &opts.OutputLanguage, "o", "English", "explanation language")
+	cmd.Flags().BoolVar(&opts.Offline, "offline", false, "only print matches from the built-in table")
+	opts.Output.addFlags(cmd)
+	return cmd
+}
+
+func runHomophones(cmd *cobra.Command, opts *homophonesOptions, args []string) error {
+	if err := opts.Output.validate(); err != nil {
+		return err
+	}
+	word := strings.TrimSpace(strings.Join(args, " "))
+	if word == "" {
+		return fmt.Errorf("word is required")
+	}
+	language := opts.Language
+	if language == "auto" {
+		language = detectHomophoneLanguage(word)
+	}
+	if language != "zh" && language != "en" {
+		return fmt.Errorf("unsupported language: %s (expected zh or en)", language)
+	}
+	result, found := homophone.Lookup(word, language)
+	if opts.Offline {
+		if !found {
+			return fmt.Errorf("no offline matches for %q", word)
+		}
+		return writeHomophones(cmd.OutOrStdout(), result)
+	}
+	candidates := "none"
+	if found {
+		var b strings.Builder
+		if err := writeHomophones(&b, result); err != nil {
+			return err
+		}
+		candidates = strings.TrimSpace(b.String())
+	}
+	return runPromptCommand(cmd, &opts.Output, "homophones", map[string]string{
+		"input":           word,
+		"language":        homophoneLanguages[language],
+		"candidates":      candidates,
+		"output_language": opts.OutputLanguage,
+	})
+}
+
+var homophoneLanguages = map[string]string{
+	"zh": "Mandarin Chinese",
+	"en": "English",
+}
+
+// detectHomophoneLanguage treats Han characters and pinyin tone marks or
+// numbers as Chinese and defaults to English.
+func detectHomophoneLanguage(word string) string {
+	for _, r := range word {
+		if unicode.Is(unicode.Han, r) || unicode.IsDigit(r) || strings.ContainsRune("āáǎàēéěèīíǐìōóǒòūúǔùǖǘǚǜü", r) {
+			return "zh"
+		}
+	}
+	return "en"
+}
+
+func writeHomophones(out io.Writer, result homophone.Result) error {
+	separator := ""
+	if result.Word.Text != "" {
+		separator = "\n"
+		if _, err := fmt.Fprintf(out, "%s (%s)\n", result.Word.Text, result.Word.Reading); err != nil {
+			return err
+		}
+	}
+	sections := []struct {
+		title string
+		words []homophone.Word
+	}{
+		{"Homophones", result.Homophones},
+		{"Sounds similar", result.Near},
+	}
+	for _, section := range sections {
+		if len(section.words) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "%s%s:\n", separator, section.title); err != nil {
+			return err
+		}
+		separator = "\n"
+		for _, word := range section.words {
+			if _, err := fmt.Fprintf(out, "  %s (%s)\n", word.Text, word.Reading); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"dict-be/internal/homophone"
+)
+
+func TestWriteHomophones(t *testing.T) {
+	result, ok := homophone.Lookup("权利", "zh")
+	if !ok {
+		t.Fatalf("expected offline entry")
+	}
+	var out bytes.Buffer
+	if err := writeHomophones(&out, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "权利 (quánlì)\n\nHomophones:\n  权力 (quánlì)\n"
+	if out.String() != expected {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestWriteHomophonesPinyin(t *testing.T) {
+	result, _ := homophone.Lookup("zai", "zh")
+	var out bytes.Buffer
+	if err := writeHomophones(&out, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Sounds similar:\n  在 (zài)\n  再 (zài)\n"
+	if out.String() != expected {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestDetectHomophoneLanguage(t *testing.T) {
+	cases := map[string]string{"事实": "zh", "shì": "zh", "shi4": "zh", "their": "en"}
+	for word, expected := range cases {
+		if got := detectHomophoneLanguage(word); got != expected {
+			t.Fatalf("%s: expected %s, got %s", word, expected, got)
+		}
+	}
+}
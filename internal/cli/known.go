@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"dict-be/internal/known"
+
+	"github.com/spf13/cobra"
+)
+
+type knownImportOptions struct {
+	Format string
+}
+
+func newKnownCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "known",
+		Short: "Manage the list of words you already know",
+	}
+	cmd.AddCommand(newKnownAddCmd())
+	cmd.AddCommand(newKnownImportCmd())
+	cmd.AddCommand(newKnownListCmd())
+	return cmd
+}
+
+func newKnownAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <word...>",
+		Short: "Add words to the known-words list",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return addKnownWords(cmd, args)
+		},
+	}
+}
+
+func newKnownImportCmd() *cobra.Command {
+	opts := &knownImportOptions{}
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import known words from a text file or Anki export, use - for stdin",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runKnownImport(cmd, opts, args[0])
+		},
+	}
+	cmd.Flags().StringVar(&opts.Format, "format", "auto", "input format: auto, text or anki")
+	return cmd
+}
+
+func newKnownListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Print the known-words list",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := newKnownStore()
+			if err != nil {
+				return err
+			}
+			words, err := store.Load()
+			if err != nil {
+				return err
+			}
+			for _, word := range words {
+				fmt.Fprintln(cmd.OutOrStdout(), word)
+			}
+			return nil
+		},
+	}
+}
+
+func newKnownStore() (*known.Store, error) {
+	path, err := known.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return known.NewStore(path), nil
+}
+
+func runKnownImport(cmd *cobra.Command, opts *knownImportOptions, path string) error {
+	input, err := readInput(nil, path, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	words, err := parseKnownImport(input, opts.Format)
+	if err != nil {
+		return err
+	}
+	return addKnownWords(cmd, words)
+}
+
+// parseKnownImport reads a plain word list or an Anki "Notes in Plain Text"
+// export. Auto-detection treats input with # headers or tab-separated
+// fields as Anki.
+func parseKnownImport(input, format string) ([]string, error) {
+	if format == "auto" {
+		format = "text"
+		if strings.HasPrefix(input, "#separator:") || strings.Contains(input, "\t") {
+			format = "anki"
+		}
+	}
+	switch format {
+	case "text":
+		return known.ReadText(strings.NewReader(input))
+	case "anki":
+		return known.ReadAnki(strings.NewReader(input))
+	default:
+		return nil, fmt.Errorf("invalid format: %s (expected auto, text or anki)", format)
+	}
+}
+
+func addKnownWords(cmd *cobra.Command, words []string) error {
+	store, err := newKnownStore()
+	if err != nil {
+		return err
+	}
+	added, err := store.Add(words)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "added %d known words\n", added)
+	return nil
+}
+
+// knownWordsInstruction lists the known words that occur in input, so the
+// model can skip them. It is empty when disabled or nothing matches.
+func knownWordsInstruction(input string, enabled bool) (string, error) {
+	if !enabled {
+		return "", nil
+	}
+	store, err := newKnownStore()
+	if err != nil {
+		return "", err
+	}
+	words, err := store.Load()
+	if err != nil {
+		return "", err
+	}
+	matched := known.Filter(words, input)
+	if len(matched) == 0 {
+		return "", nil
+	}
+	return "The learner already knows these words; do not annotate or gloss them: " + strings.Join(matched, ", ") + ".", nil
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKnownImport(t *testing.T) {
+	words, err := parseKnownImport("#separator:tab\n#html:true\nHund\tdog\n", "auto")
+	if err != nil || !reflect.DeepEqual(words, []string{"hund"}) {
+		t.Fatalf("unexpected anki words: %v, %v", words, err)
+	}
+	words, err = parseKnownImport("# my list\nApple\n\ntake off\n", "auto")
+	if err != nil || !reflect.DeepEqual(words, []string{"apple", "take off"}) {
+		t.Fatalf("unexpected text words: %v, %v", words, err)
+	}
+	if _, err := parseKnownImport("apple", "csv"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"dict-be/internal/glossary"
+	"dict-be/internal/llm"
+)
+
+// maxLearnedTerms caps the terms proposed after one translation.
+const maxLearnedTerms = 30
+
+// glossaryLearner collects the term translations the model used in a
+// document and offers to append the new ones to a glossary CSV.
+type glossaryLearner struct {
+	path           string
+	yes            bool
+	client         llm.Client
+	model          string
+	inputLanguage  string
+	outputLanguage string
+	in             io.Reader
+	out            io.Writer
+}
+
+func (l *glossaryLearner) learn(ctx context.Context, source, translation string) error {
+	terms, err := readGlossaryFile(l.path)
+	if err != nil {
+		return err
+	}
+	learned, err := l.extract(ctx, source, translation, terms)
+	if err != nil {
+		return fmt.Errorf("learn glossary: %w", err)
+	}
+	learned = glossary.Unknown(terms, usedTerms(learned, source, translation))
+	if len(learned) == 0 {
+		fmt.Fprintf(l.out, "no new terms for %s\n", l.path)
+		return nil
+	}
+	fmt.Fprintf(l.out, "new terms used in this translation:\n")
+	for _, term := range learned {
+		fmt.Fprintf(l.out, "  %s -> %s\n", term.Source, term.Target)
+	}
+	if !l.yes {
+		ok, err := l.confirm(len(learned))
+		if err != nil || !ok {
+			return err
+		}
+	}
+	var buf bytes.Buffer
+	if err := glossary.WriteCSV(&buf, append(terms, learned...)); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(l.path, buf.Bytes()); err != nil {
+		return err
+	}
+	fmt.Fprintf(l.out, "added %d terms to %s\n", len(learned), l.path)
+	return nil
+}
+
+func (l *glossaryLearner) extract(ctx context.Context, source, translation string, terms []glossary.Term) ([]glossary.Term, error) {
+	known := make([]string, 0, len(terms))
+	for _, term := range terms {
+		known = append(known, term.Source)
+	}
+	systemPrompt, userPrompt, err := buildPrompts("learnterms", map[string]string{
+		"input":           source,
+		"translation":     translation,
+		"known":           firstNonEmpty(strings.Join(known, "\n"), "none"),
+		"input_language":  l.inputLanguage,
+		"output_language": l.outputLanguage,
+		"max":             fmt.Sprint(maxLearnedTerms),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.client.Chat(ctx, llm.ChatRequest{
+		Model:    l.model,
+		Messages: buildMessages(systemPrompt, userPrompt),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseExtractedTerms(resp.Content, maxLearnedTerms)
+}
+
+// confirm asks whether to append n terms. Without a terminal to ask on,
+// nothing is appended.
+func (l *glossaryLearner) confirm(n int) (bool, error) {
+	if file, ok := l.in.(*os.File); !ok || !isTerminal(file) {
+		fmt.Fprintf(l.out, "not added to %s; pass --yes to add learned terms without asking\n", l.path)
+		return false, nil
+	}
+	fmt.Fprintf(l.out, "add %d terms to %s? [y/N] ", n, l.path)
+	answer, err := bufio.NewReader(l.in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// usedTerms keeps the terms whose source appears in the source text and
+// whose target appears in the translation, dropping any the model made up.
+func usedTerms(terms []glossary.Term, source, translation string) []glossary.Term {
+	source, translation = strings.ToLower(source), strings.ToLower(translation)
+	used := terms[:0:0]
+	for _, term := range terms {
+		if strings.Contains(source, strings.ToLower(term.Source)) && strings.Contains(translation, strings.ToLower(term.Target)) {
+			used = append(used, term)
+		}
+	}
+	return used
+}
+
+// readGlossaryFile reads a glossary CSV; a missing file is an empty
+// glossary.
+func readGlossaryFile(path string) ([]glossary.Term, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read glossary: %w", err)
+	}
+	defer f.Close()
+	return glossary.ReadCSV(f)
+}
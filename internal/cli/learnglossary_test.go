@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"dict-be/internal/llm"
+)
+
+func TestGlossaryLearner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glossary.csv")
+	if err := os.WriteFile(path, []byte("source,target,note\nrate limit,速率限制,\n"), 0o644); err != nil {
+		t.Fatalf("write glossary: %v", err)
+	}
+	client := &fakeClient{resp: llm.ChatResponse{Content: "```json\n[" +
+		`{"source":"Rate limit","target":"速率限制"},` +
+		`{"source":"token bucket","target":"令牌桶","note":"algorithm"},` +
+		`{"source":"leaky bucket","target":"漏桶"}` +
+		"]\n```"}}
+	var out bytes.Buffer
+	learner := &glossaryLearner{path: path, yes: true, client: client, in: strings.NewReader(""), out: &out}
+	if err := learner.learn(context.Background(), "The rate limit uses a token bucket.", "速率限制使用令牌桶。"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	expected := "source,target,note\nrate limit,速率限制,\ntoken bucket,令牌桶,algorithm\n"
+	if string(data) != expected {
+		t.Fatalf("unexpected glossary:\n%s", data)
+	}
+	if !strings.Contains(out.String(), "added 1 terms") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestGlossaryLearnerAsksWithoutYes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glossary.csv")
+	client := &fakeClient{resp: llm.ChatResponse{Content: `[{"source":"token bucket","target":"令牌桶"}]`}}
+	var out bytes.Buffer
+	learner := &glossaryLearner{path: path, client: client, in: strings.NewReader("y\n"), out: &out}
+	if err := learner.learn(context.Background(), "a token bucket", "一个令牌桶"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected no glossary without a terminal, got %v", err)
+	}
+	if !strings.Contains(out.String(), "--yes") {
+		t.Fatalf("expected hint about --yes:\n%s", out.String())
+	}
+}
@@ -1,14 +1,19 @@
 package cli
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"dict-be/internal/config"
 	"dict-be/internal/llm"
+	"dict-be/internal/postprocess"
+	"dict-be/internal/render"
+	"dict-be/internal/version"
 
 	"github.com/spf13/cobra"
 )
@@ -21,6 +26,8 @@ type llmChatOptions struct {
 	Model    string
 	URL      string
 	Token    string
+	Format   string
+	Sampling samplingOptions
 }
 
 func newLLMCmd() *cobra.Command {
@@ -31,6 +38,7 @@ func newLLMCmd() *cobra.Command {
 
 	cmd.AddCommand(newLLMChatCmd())
 	cmd.AddCommand(newLLMTestCmd())
+	cmd.AddCommand(newLLMUseCmd())
 	return cmd
 }
 
@@ -51,6 +59,8 @@ func newLLMChatCmd() *cobra.Command {
 	cmd.Flags().StringVar(&opts.Model, "model", "", "override model name")
 	cmd.Flags().StringVar(&opts.URL, "url", "", "override base url")
 	cmd.Flags().StringVar(&opts.Token, "token", "", "override access token")
+	cmd.Flags().StringVar(&opts.Format, "format", render.Text, formatHelp)
+	addSamplingFlags(cmd, &opts.Sampling)
 
 	return cmd
 }
@@ -59,6 +69,12 @@ func runLLMChat(cmd *cobra.Command, opts *llmChatOptions) error {
 	if opts.Stream && opts.NoStream {
 		return errors.New("only one of --stream or --no-stream can be set")
 	}
+	if err := validateFormat(opts.Format); err != nil {
+		return err
+	}
+	if err := opts.Sampling.validate(); err != nil {
+		return err
+	}
 	cfg, err := config.Load()
 	if err != nil {
 		return err
@@ -80,11 +96,13 @@ func runLLMChat(cmd *cobra.Command, opts *llmChatOptions) error {
 		return errors.New("prompt is required")
 	}
 
-	model := firstNonEmpty(opts.Model, cfg.LLM.Model)
-	url := firstNonEmpty(opts.URL, cfg.LLM.URL)
-	token := firstNonEmpty(opts.Token, cfg.LLM.Token)
+	llmCfg := cfg.LLM
+	llmCfg.Model = firstNonEmpty(opts.Model, cfg.LLM.Model)
+	llmCfg.URL = firstNonEmpty(opts.URL, cfg.LLM.URL)
+	llmCfg.Token = firstNonEmpty(opts.Token, cfg.LLM.Token)
+	model := llmCfg.Model
 
-	client, err := newLLMClient(cfg.LLM.Type, url, token, model)
+	client, err := newLLMClient(llmCfg, commandMiddleware(cmd, cfg))
 	if err != nil {
 		return err
 	}
@@ -93,25 +111,14 @@ func runLLMChat(cmd *cobra.Command, opts *llmChatOptions) error {
 		Model:    model,
 		Messages: buildMessages(opts.System, prompt),
 	}
+	opts.Sampling.apply(&req)
 
-	if opts.Stream {
-		_, err = client.ChatStream(context.Background(), req, func(delta string) error {
-			_, writeErr := fmt.Fprint(cmd.OutOrStdout(), delta)
-			return writeErr
-		})
-		if err != nil {
-			return err
-		}
-		_, _ = fmt.Fprintln(cmd.OutOrStdout())
-		return nil
-	}
-
-	resp, err := client.Chat(context.Background(), req)
+	post, err := newPostprocessPipeline(cfg)
 	if err != nil {
 		return err
 	}
-	_, err = fmt.Fprintln(cmd.OutOrStdout(), resp.Content)
-	return err
+	stream := streamEnabled(opts.Stream, opts.NoStream, opts.Format)
+	return runChat(commandContext(cmd), cmd.OutOrStdout(), client, req, stream, opts.Format, post)
 }
 
 type llmTestOptions struct {
@@ -120,6 +127,8 @@ type llmTestOptions struct {
 	Model    string
 	URL      string
 	Token    string
+	Format   string
+	All      bool
 }
 
 func newLLMTestCmd() *cobra.Command {
@@ -137,6 +146,8 @@ func newLLMTestCmd() *cobra.Command {
 	cmd.Flags().StringVar(&opts.Model, "model", "", "override model name")
 	cmd.Flags().StringVar(&opts.URL, "url", "", "override base url")
 	cmd.Flags().StringVar(&opts.Token, "token", "", "override access token")
+	cmd.Flags().StringVar(&opts.Format, "format", render.Text, formatHelp)
+	cmd.Flags().BoolVar(&opts.All, "all", false, "ping the llm section and every profile and print a status table")
 
 	return cmd
 }
@@ -145,19 +156,30 @@ func runLLMTest(cmd *cobra.Command, opts *llmTestOptions) error {
 	if opts.Stream && opts.NoStream {
 		return errors.New("only one of --stream or --no-stream can be set")
 	}
+	if err := validateFormat(opts.Format); err != nil {
+		return err
+	}
 	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
+	if opts.All {
+		if opts.Model != "" || opts.URL != "" || opts.Token != "" {
+			return errors.New("--all cannot be combined with --model, --url or --token")
+		}
+		return runLLMTestAll(commandContext(cmd), cmd.OutOrStdout(), cfg)
+	}
 	if cfg.LLM.Type == "" {
 		cfg.LLM.Type = "openai"
 	}
 
-	model := firstNonEmpty(opts.Model, cfg.LLM.Model)
-	url := firstNonEmpty(opts.URL, cfg.LLM.URL)
-	token := firstNonEmpty(opts.Token, cfg.LLM.Token)
+	llmCfg := cfg.LLM
+	llmCfg.Model = firstNonEmpty(opts.Model, cfg.LLM.Model)
+	llmCfg.URL = firstNonEmpty(opts.URL, cfg.LLM.URL)
+	llmCfg.Token = firstNonEmpty(opts.Token, cfg.LLM.Token)
+	model := llmCfg.Model
 
-	client, err := newLLMClient(cfg.LLM.Type, url, token, model)
+	client, err := newLLMClient(llmCfg, commandMiddleware(cmd, cfg))
 	if err != nil {
 		return err
 	}
@@ -172,24 +194,8 @@ func runLLMTest(cmd *cobra.Command, opts *llmTestOptions) error {
 		},
 	}
 
-	if opts.Stream {
-		_, err = client.ChatStream(context.Background(), req, func(delta string) error {
-			_, writeErr := fmt.Fprint(cmd.OutOrStdout(), delta)
-			return writeErr
-		})
-		if err != nil {
-			return err
-		}
-		_, _ = fmt.Fprintln(cmd.OutOrStdout())
-		return nil
-	}
-
-	resp, err := client.Chat(context.Background(), req)
-	if err != nil {
-		return err
-	}
-	_, err = fmt.Fprintln(cmd.OutOrStdout(), resp.Content)
-	return err
+	stream := streamEnabled(opts.Stream, opts.NoStream, opts.Format)
+	return runChat(commandContext(cmd), cmd.OutOrStdout(), client, req, stream, opts.Format, nil)
 }
 
 func buildMessages(system, prompt string) []llm.Message {
@@ -216,27 +222,119 @@ func firstNonEmpty(values ...string) string {
 	return ""
 }
 
-func newLLMClient(provider, url, token, model string) (llm.Client, error) {
-	switch provider {
-	case "openai":
-		return llm.NewOpenAIClient(llm.OpenAIConfig{
-			BaseURL: url,
-			Token:   token,
-			Model:   model,
-		})
-	case "anthropics":
-		return llm.NewAnthropicClient(llm.AnthropicConfig{
-			BaseURL: url,
-			Token:   token,
-			Model:   model,
-		})
-	case "gemini":
-		return llm.NewGeminiClient(llm.GeminiConfig{
-			BaseURL: url,
-			Token:   token,
-			Model:   model,
+// newPostprocessPipeline builds the configured output post-processors.
+func newPostprocessPipeline(cfg config.Config) (postprocess.Pipeline, error) {
+	specs := make([]postprocess.Spec, 0, len(cfg.Postprocess))
+	for _, step := range cfg.Postprocess {
+		specs = append(specs, postprocess.Spec{
+			Builtin: step.Builtin,
+			Command: step.Command,
 		})
-	default:
-		return nil, fmt.Errorf("unsupported llm.type: %s", provider)
 	}
+	return postprocess.New(specs)
+}
+
+// loadLLMClient builds a client from the loaded configuration, defaulting
+// llm.type to openai, with the command middleware.
+func loadLLMClient(cmd *cobra.Command) (llm.Client, config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, cfg, err
+	}
+	if cfg.LLM.Type == "" {
+		cfg.LLM.Type = "openai"
+	}
+	client, err := newLLMClient(cfg.LLM, commandMiddleware(cmd, cfg))
+	if err != nil {
+		return nil, cfg, err
+	}
+	return client, cfg, nil
+}
+
+// commandMiddleware is the stack every command request goes through,
+// outermost first: the secret scanner, so refused input is neither sent
+// nor logged, then the audit log and the debug log.
+func commandMiddleware(cmd *cobra.Command, cfg config.Config) llm.Middleware {
+	return llm.Chain(
+		func(client llm.Client) llm.Client { return guardClient(cmd, cfg, client) },
+		func(client llm.Client) llm.Client { return auditClient(cmd, cfg, client) },
+		func(client llm.Client) llm.Client { return logClient(cmd, cfg, client) },
+	)
+}
+
+// newLLMClient builds the provider client below middleware, applying the
+// config's default system prompt and retrying interrupted streams without
+// streaming. Unless polling is on, streams that look buffered by a
+// gateway print a hint to use --poll.
+func newLLMClient(cfg config.LLMConfig, middleware ...llm.Middleware) (llm.Client, error) {
+	middleware = append(middleware,
+		func(client llm.Client) llm.Client { return withSystemPrompt(client, cfg.SystemPrompt) },
+		llm.StreamFallbackMiddleware(),
+	)
+	if !cfg.Poll {
+		middleware = append(middleware, llm.BufferingMiddleware(llm.DefaultBufferingThreshold, warnBuffering))
+	}
+	return newProviderClient(cfg, middleware...)
+}
+
+var bufferingWarning sync.Once
+
+// warnBuffering suggests --poll once per run.
+func warnBuffering(silence time.Duration) {
+	bufferingWarning.Do(func() {
+		fmt.Fprintf(os.Stderr, "warning: the stream sent nothing for %s; a proxy or gateway may be buffering it, try --poll\n",
+			silence.Round(time.Second))
+	})
+}
+
+// newProviderClient builds a client through the llm provider registry,
+// passing the provider-specific config sections as options.
+func newProviderClient(cfg config.LLMConfig, middleware ...llm.Middleware) (llm.Client, error) {
+	retry := llm.RetryPolicy{Retries: llm.DefaultRetries, Backoff: llm.DefaultRetryBackoff}
+	if cfg.Retries != nil {
+		retry.Retries = *cfg.Retries
+	}
+	if cfg.RetryBackoff > 0 {
+		retry.Backoff = cfg.RetryBackoff
+	}
+	timeout := llm.TimeoutPolicy{Timeout: llm.DefaultTimeout, StreamIdle: llm.DefaultStreamIdleTimeout}
+	if cfg.Timeout > 0 {
+		timeout.Timeout = cfg.Timeout
+	}
+	if cfg.StreamIdleTimeout > 0 {
+		timeout.StreamIdle = cfg.StreamIdleTimeout
+	}
+	reconnects := llm.DefaultStreamReconnects
+	if cfg.StreamReconnects != nil {
+		reconnects = *cfg.StreamReconnects
+	}
+	return llm.NewClient(cfg.Type, llm.Config{
+		BaseURL:          cfg.URL,
+		Token:            cfg.Token,
+		Model:            cfg.Model,
+		UserAgent:        firstNonEmpty(cfg.UserAgent, "dict-be/"+version.Version),
+		Proxy:            cfg.Proxy,
+		Record:           expandHome(cfg.Record),
+		Replay:           expandHome(cfg.Replay),
+		Poll:             cfg.Poll,
+		StreamReconnects: reconnects,
+		Retry:            retry,
+		Timeout:          timeout,
+		RateLimit:        llm.RateLimit{RPM: cfg.RPM, TPM: cfg.TPM},
+		Middleware:       middleware,
+		Options: map[string]string{
+			"resource":    cfg.Azure.Resource,
+			"deployment":  cfg.Azure.Deployment,
+			"api_version": cfg.Azure.APIVersion,
+			"region":      cfg.Bedrock.Region,
+			"profile":     cfg.Bedrock.Profile,
+			"models":      strings.Join(cfg.OpenRouter.Models, ","),
+			"project":     cfg.Vertex.Project,
+			"location":    cfg.Vertex.Location,
+			"credentials": cfg.Vertex.Credentials,
+			"template":    cfg.LlamaCpp.Template,
+			"response":    cfg.Mock.Response,
+			"fixtures":    expandHome(cfg.Mock.Fixtures),
+		},
+	})
 }
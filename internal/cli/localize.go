@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type localizeOptions struct {
+	InputFile      string
+	InputLanguage  string
+	OutputLanguage string
+	KeepOriginal   bool
+	Output         outputOptions
+}
+
+func newLocalizeFormatCmd() *cobra.Command {
+	opts := &localizeOptions{}
+	cmd := &cobra.Command{
+		Use:   "localize-format [text...]",
+		Short: "Translate text and localize numbers, dates and units",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLocalizeFormat(cmd, opts, args)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.InputFile, "file", "F", "", "input file, use -F- for stdin")
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "o")
+	cmd.Flags().BoolVar(&opts.KeepOriginal, "keep-original", false, "keep original values in parentheses after converted ones")
+	opts.Output.addFlags(cmd)
+	return cmd
+}
+
+func runLocalizeFormat(cmd *cobra.Command, opts *localizeOptions, args []string) error {
+	if err := opts.Output.validate(); err != nil {
+		return err
+	}
+	input, err := readInput(args, opts.InputFile, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(input) == "" {
+		return fmt.Errorf("input is required")
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(input, opts.InputLanguage, opts.OutputLanguage)
+	return runPromptCommand(cmd, &opts.Output, "localize", map[string]string{
+		"input":                input,
+		"input_language":       inputLanguage,
+		"output_language":      outputLanguage,
+		"original_instruction": localizeOriginalInstruction(opts.KeepOriginal),
+	})
+}
+
+func localizeOriginalInstruction(keep bool) string {
+	if keep {
+		return "- Keep each original value in parentheses right after its localized form, for example 5,6 km (3.5 miles)."
+	}
+	return "- Replace original values with their localized forms."
+}
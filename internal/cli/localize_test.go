@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildLocalizePrompts(t *testing.T) {
+	systemPrompt, userPrompt, err := buildPrompts("localize", map[string]string{
+		"input":                "3.5 miles on 05/06/2024",
+		"input_language":       "English",
+		"output_language":      "German",
+		"original_instruction": localizeOriginalInstruction(true),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, "(3.5 miles)") || strings.Contains(systemPrompt, "{{") {
+		t.Fatalf("unexpected system prompt: %q", systemPrompt)
+	}
+	if !strings.Contains(userPrompt, "<input>3.5 miles on 05/06/2024</input>") {
+		t.Fatalf("unexpected user prompt: %q", userPrompt)
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"log/slog"
+	"strings"
+
+	"dict-be/internal/config"
+	"dict-be/internal/llm"
+	"dict-be/internal/secrets"
+
+	"github.com/spf13/cobra"
+)
+
+// logClient logs every request to stderr when --verbose is set or
+// logging.level is debug. Credentials found by the secret scanner and the
+// configured tokens are redacted.
+func logClient(cmd *cobra.Command, cfg config.Config, client llm.Client) llm.Client {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	if !verbose && cfg.Logging.Level != "debug" {
+		return client
+	}
+	logger := slog.New(slog.NewTextHandler(cmd.ErrOrStderr(), &slog.HandlerOptions{Level: slog.LevelDebug}))
+	tokens := configuredTokens(cfg)
+	return llm.WithLogging(client, logger, func(text string) string {
+		for _, token := range tokens {
+			text = strings.ReplaceAll(text, token, "[REDACTED token]")
+		}
+		return secrets.Mask(text, cfg.Secrets.InternalDomains)
+	})
+}
+
+// configuredTokens lists the tokens of the llm section and every profile,
+// which may not match any of the scanner's key patterns.
+func configuredTokens(cfg config.Config) []string {
+	var tokens []string
+	add := func(token string) {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	add(cfg.LLM.Token)
+	add(cfg.DefaultLLM.Token)
+	for _, profile := range cfg.Profiles {
+		add(profile.Token)
+	}
+	return tokens
+}
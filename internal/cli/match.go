@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"dict-be/internal/config"
+	"dict-be/internal/headword"
+
+	"github.com/spf13/cobra"
+)
+
+type matchOptions struct {
+	Anagram        bool
+	Dictionaries   []string
+	Limit          int
+	Define         bool
+	OutputLanguage string
+	Output         outputOptions
+}
+
+func newMatchCmd() *cobra.Command {
+	opts := &matchOptions{}
+	cmd := &cobra.Command{
+		Use:   "match <pattern>",
+		Short: "Search offline headword lists by pattern or anagram",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMatch(cmd, opts, args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&opts.Anagram, "anagram", false, "find words using exactly these letters (? is a blank)")
+	cmd.Flags().StringArrayVar(&opts.Dictionaries, "dict", nil, "headword list to search, in addition to dictionaries from the config (repeatable)")
+	cmd.Flags().IntVar(&opts.Limit, "limit", 100, "maximum number of matches to print (0 for all)")
+	cmd.Flags().BoolVar(&opts.Define, "define", false, "ask the LLM for a short definition of each match")
+	addOutputLanguageFlag(cmd, &opts.OutputLanguage, "o", "English", "definition language")
+	opts.Output.addFlags(cmd)
+	return cmd
+}
+
+func runMatch(cmd *cobra.Command, opts *matchOptions, pattern string) error {
+	if err := opts.Output.validate(); err != nil {
+		return err
+	}
+	if opts.Limit < 0 {
+		return fmt.Errorf("invalid --limit: %d", opts.Limit)
+	}
+	match, err := matcher(pattern, opts.Anagram)
+	if err != nil {
+		return err
+	}
+	words, err := loadHeadwords(opts.Dictionaries)
+	if err != nil {
+		return err
+	}
+	found := headword.Search(words, match)
+	if len(found) == 0 {
+		return fmt.Errorf("no matches for %q", pattern)
+	}
+	if opts.Limit > 0 && len(found) > opts.Limit {
+		fmt.Fprintf(cmd.ErrOrStderr(), "showing %d of %d matches; raise --limit to see more\n", opts.Limit, len(found))
+		found = found[:opts.Limit]
+	}
+	if !opts.Define {
+		return writeMatches(cmd.OutOrStdout(), found)
+	}
+	return runPromptCommand(cmd, &opts.Output, "match", map[string]string{
+		"input":           strings.Join(found, "\n"),
+		"output_language": opts.OutputLanguage,
+	})
+}
+
+func matcher(pattern string, anagram bool) (func(string) bool, error) {
+	if anagram {
+		letters, err := headword.NewAnagram(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return letters.Match, nil
+	}
+	compiled, err := headword.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.Match, nil
+}
+
+// loadHeadwords reads the dictionaries from the config followed by extra.
+func loadHeadwords(extra []string) ([]string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	paths := append(append([]string(nil), cfg.Dictionaries...), extra...)
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no headword lists; set dictionaries in the config or pass --dict")
+	}
+	var words []string
+	for _, path := range paths {
+		list, err := headword.Load(expandHome(path))
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, list...)
+	}
+	return words, nil
+}
+
+func writeMatches(out io.Writer, words []string) error {
+	for _, word := range words {
+		if _, err := fmt.Fprintln(out, word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"dict-be/internal/headword"
+)
+
+func TestLoadHeadwordsAndMatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("butter\nbetter\nlisten\nsilent\n"), 0o600); err != nil {
+		t.Fatalf("write words: %v", err)
+	}
+	words, err := loadHeadwords([]string{path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	match, err := matcher("b?tter", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := headword.Search(words, match); !reflect.DeepEqual(got, []string{"better", "butter"}) {
+		t.Fatalf("unexpected matches: %q", got)
+	}
+	match, _ = matcher("tinsel", true)
+	if got := headword.Search(words, match); !reflect.DeepEqual(got, []string{"listen", "silent"}) {
+		t.Fatalf("unexpected anagrams: %q", got)
+	}
+}
+
+func TestLoadHeadwordsRequiresDictionary(t *testing.T) {
+	if _, err := loadHeadwords(nil); err == nil {
+		t.Fatalf("expected error without dictionaries")
+	}
+}
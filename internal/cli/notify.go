@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"fmt"
+
+	"dict-be/internal/notify"
+
+	"github.com/spf13/cobra"
+)
+
+// notifyEnabled prefers an explicit --notify flag over the config default.
+func notifyEnabled(cmd *cobra.Command, flag, configDefault bool) bool {
+	if cmd.Flags().Changed("notify") {
+		return flag
+	}
+	return configDefault
+}
+
+// notifyDone reports the outcome of a long-running job. Notification
+// failures are only printed, never returned.
+func notifyDone(cmd *cobra.Command, job string, jobErr error) {
+	message := job + " finished"
+	if jobErr != nil {
+		message = fmt.Sprintf("%s failed: %v", job, jobErr)
+	}
+	if err := notify.Send("dict-be", message); err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+	}
+}
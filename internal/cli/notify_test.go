@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestNotifyEnabledPrefersFlag(t *testing.T) {
+	var flag bool
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().BoolVar(&flag, "notify", false, "")
+
+	if !notifyEnabled(cmd, flag, true) {
+		t.Fatalf("expected config default when flag is unset")
+	}
+	if err := cmd.Flags().Set("notify", "false"); err != nil {
+		t.Fatalf("set flag: %v", err)
+	}
+	if notifyEnabled(cmd, flag, true) {
+		t.Fatalf("expected explicit --notify=false to win")
+	}
+}
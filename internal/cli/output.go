@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/postprocess"
+	"dict-be/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+var formatHelp = "output format: " + strings.Join(render.Formats, ", ")
+
+// outputOptions holds the streaming and format flags shared by commands
+// that print a single LLM response.
+type outputOptions struct {
+	Stream   bool
+	NoStream bool
+	Format   string
+}
+
+func (o *outputOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.Stream, "stream", false, "stream response")
+	cmd.Flags().BoolVar(&o.NoStream, "no-stream", false, "disable streaming response")
+	cmd.Flags().StringVar(&o.Format, "format", render.Text, formatHelp)
+}
+
+func (o *outputOptions) validate() error {
+	if o.Stream && o.NoStream {
+		return fmt.Errorf("only one of --stream or --no-stream can be set")
+	}
+	return validateFormat(o.Format)
+}
+
+func validateFormat(format string) error {
+	_, err := render.New(format, io.Discard)
+	return err
+}
+
+// streamEnabled reports whether a command should stream. NDJSON output
+// streams by default so consumers see delta events as they arrive.
+func streamEnabled(stream, noStream bool, format string) bool {
+	if stream {
+		return true
+	}
+	return format == render.NDJSON && !noStream
+}
+
+// runChat sends req and renders the response in format. The response is
+// passed through post first; a non-empty pipeline needs the complete text,
+// so streamed deltas are collected instead of printed as they arrive.
+// NDJSON output is never post-processed. When ctx is cancelled mid-stream,
+// the content received so far is rendered unprocessed with the finish
+// reason "interrupted" and the cancellation is returned.
+func runChat(ctx context.Context, out io.Writer, client llm.Client, req llm.ChatRequest, stream bool, format string, post postprocess.Pipeline) error {
+	renderer, err := render.New(format, out)
+	if err != nil {
+		return err
+	}
+	if format == render.NDJSON {
+		post = nil
+	}
+	if err := renderer.Start(req.Model); err != nil {
+		return err
+	}
+
+	incremental := stream && renderer.Incremental() && len(post) == 0
+	var partial strings.Builder
+	var resp llm.ChatResponse
+	switch {
+	case incremental:
+		resp, err = client.ChatStream(ctx, req, func(delta string) error {
+			partial.WriteString(delta)
+			return renderer.Delta(delta)
+		})
+	case stream:
+		resp, err = client.ChatStream(ctx, req, func(delta string) error {
+			partial.WriteString(delta)
+			return nil
+		})
+	default:
+		resp, err = client.Chat(ctx, req)
+	}
+	if err == nil && !incremental {
+		resp.Content, err = post.Apply(ctx, resp.Content)
+	}
+	if err != nil && ctx.Err() != nil && partial.Len() > 0 {
+		interrupted := llm.ChatResponse{Content: partial.String(), Model: req.Model, FinishReason: "interrupted"}
+		if doneErr := renderer.Done(interrupted, incremental); doneErr != nil {
+			return doneErr
+		}
+		return fmt.Errorf("interrupted: %w", ctx.Err())
+	}
+	if err != nil {
+		_ = renderer.Fail(err)
+		return err
+	}
+	return renderer.Done(resp, incremental)
+}
+
+// renderContent renders text that was produced without a single chat
+// request, such as a document translated paragraph by paragraph.
+func renderContent(out io.Writer, format, model, content string) error {
+	renderer, err := render.New(format, out)
+	if err != nil {
+		return err
+	}
+	if err := renderer.Start(model); err != nil {
+		return err
+	}
+	return renderer.Done(llm.ChatResponse{Content: content, Model: model}, false)
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/postprocess"
+	"dict-be/internal/render"
+)
+
+type fakeClient struct {
+	deltas []string
+	resp   llm.ChatResponse
+	err    error
+}
+
+func (f *fakeClient) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	return f.resp, f.err
+}
+
+func (f *fakeClient) ChatStream(ctx context.Context, req llm.ChatRequest, handle llm.StreamHandler) (llm.ChatResponse, error) {
+	for _, delta := range f.deltas {
+		if handle == nil {
+			continue
+		}
+		if err := handle(delta); err != nil {
+			return llm.ChatResponse{}, err
+		}
+	}
+	return f.resp, f.err
+}
+
+func decodeEvents(t *testing.T, data string) []render.Event {
+	t.Helper()
+	var events []render.Event
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		var event render.Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("decode event %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestRunChatNDJSONStream(t *testing.T) {
+	client := &fakeClient{
+		deltas: []string{"he", "llo"},
+		resp: llm.ChatResponse{
+			Content:      "hello",
+			Model:        "gpt-test",
+			FinishReason: "stop",
+			Usage:        llm.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+		},
+	}
+	var out bytes.Buffer
+	err := runChat(context.Background(), &out, client, llm.ChatRequest{Model: "gpt-test"}, true, render.NDJSON, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events := decodeEvents(t, out.String())
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	if got := strings.Join(types, ","); got != "start,delta,delta,usage,done" {
+		t.Fatalf("unexpected events: %s", got)
+	}
+	if events[3].Usage == nil || events[3].Usage.TotalTokens != 5 {
+		t.Fatalf("unexpected usage event: %+v", events[3])
+	}
+	if events[4].FinishReason != "stop" {
+		t.Fatalf("unexpected done event: %+v", events[4])
+	}
+}
+
+func TestRunChatNDJSONOpenAIUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			StreamOptions struct {
+				IncludeUsage bool `json:"include_usage"`
+			} `json:"stream_options"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if !req.StreamOptions.IncludeUsage {
+			t.Errorf("expected stream_options.include_usage")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"model":"gpt-test","choices":[{"delta":{"content":"hi"},"finish_reason":"stop"}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"choices":[],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client, err := llm.NewOpenAIClient(llm.OpenAIConfig{BaseURL: server.URL, Token: "token", Model: "gpt-test"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	var out bytes.Buffer
+	if err := runChat(context.Background(), &out, client, llm.ChatRequest{}, true, render.NDJSON, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events := decodeEvents(t, out.String())
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	if got := strings.Join(types, ","); got != "start,delta,usage,done" {
+		t.Fatalf("unexpected events: %s", got)
+	}
+	if events[2].Usage == nil || events[2].Usage.TotalTokens != 4 {
+		t.Fatalf("unexpected usage event: %+v", events[2])
+	}
+}
+
+func TestRunChatNDJSONError(t *testing.T) {
+	client := &fakeClient{err: errors.New("boom")}
+	var out bytes.Buffer
+	err := runChat(context.Background(), &out, client, llm.ChatRequest{}, false, render.NDJSON, nil)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	events := decodeEvents(t, out.String())
+	if len(events) != 2 || events[1].Type != "error" || events[1].Error != "boom" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestStreamEnabled(t *testing.T) {
+	if !streamEnabled(false, false, render.NDJSON) {
+		t.Fatalf("expected ndjson to stream by default")
+	}
+	if streamEnabled(false, true, render.NDJSON) {
+		t.Fatalf("expected --no-stream to disable ndjson streaming")
+	}
+	if streamEnabled(false, false, render.Text) {
+		t.Fatalf("expected text not to stream by default")
+	}
+}
+
+func TestRunChatPostprocess(t *testing.T) {
+	client := &fakeClient{
+		deltas: []string{"**he", "llo**"},
+		resp:   llm.ChatResponse{Content: "**hello**"},
+	}
+	post, err := postprocess.New([]postprocess.Spec{{Builtin: "strip-markdown"}})
+	if err != nil {
+		t.Fatalf("new pipeline: %v", err)
+	}
+	var out bytes.Buffer
+	if err := runChat(context.Background(), &out, client, llm.ChatRequest{}, true, render.Text, post); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "hello\n" {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+// cancelClient streams deltas, then cancels the request as Ctrl-C would.
+type cancelClient struct {
+	fakeClient
+	cancel context.CancelFunc
+}
+
+func (c *cancelClient) ChatStream(ctx context.Context, req llm.ChatRequest, handle llm.StreamHandler) (llm.ChatResponse, error) {
+	for _, delta := range c.deltas {
+		if err := handle(delta); err != nil {
+			return llm.ChatResponse{}, err
+		}
+	}
+	c.cancel()
+	return llm.ChatResponse{}, ctx.Err()
+}
+
+func TestRunChatInterrupted(t *testing.T) {
+	for _, tc := range []struct {
+		format   string
+		expected string
+	}{
+		{render.Text, "partial ans\n"},
+		{render.JSON, "\"content\": \"partial ans\",\n  \"finish_reason\": \"interrupted\"\n"},
+		{render.NDJSON, `{"type":"done","model":"gpt-test","finish_reason":"interrupted"}`},
+	} {
+		ctx, cancel := context.WithCancel(context.Background())
+		client := &cancelClient{fakeClient: fakeClient{deltas: []string{"partial", " ans"}}, cancel: cancel}
+		var out bytes.Buffer
+		err := runChat(ctx, &out, client, llm.ChatRequest{Model: "gpt-test"}, true, tc.format, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("%s: expected cancellation, got %v", tc.format, err)
+		}
+		if !strings.Contains(out.String(), tc.expected) {
+			t.Fatalf("%s: unexpected output:\n%s", tc.format, out.String())
+		}
+	}
+}
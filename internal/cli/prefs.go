@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"dict-be/internal/config"
+	"dict-be/internal/prefs"
+
+	"github.com/spf13/cobra"
+)
+
+// prefFlags are the flags whose values are learned when
+// preferences.enabled is set.
+var prefFlags = []string{"in", "out", "style", "level", "tone"}
+
+func newPrefsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prefs",
+		Short: "Show or reset the defaults learned from your flags",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Print the learned flag values and how often each was used",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := newPrefsStore()
+			if err != nil {
+				return err
+			}
+			learned, err := store.Load()
+			if err != nil {
+				return err
+			}
+			if cfg, err := config.Load(); err == nil && !cfg.Preferences.Enabled {
+				fmt.Fprintln(cmd.ErrOrStderr(), "preferences are off; set preferences.enabled: true to learn defaults")
+			}
+			return printPrefs(cmd.OutOrStdout(), learned)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "reset",
+		Short: "Forget all learned flag values",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := newPrefsStore()
+			if err != nil {
+				return err
+			}
+			return store.Reset()
+		},
+	})
+	return cmd
+}
+
+func newPrefsStore() (*prefs.Store, error) {
+	path, err := prefs.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return prefs.NewStore(path), nil
+}
+
+// learnPreferences runs before every command when preferences.enabled is
+// set. It never fails the command: problems with the store are printed as
+// warnings.
+func learnPreferences(cmd *cobra.Command) {
+	cfg, err := config.Load()
+	if err != nil || !cfg.Preferences.Enabled || strings.HasPrefix(prefsCommand(cmd), "prefs") {
+		return
+	}
+	store, err := newPrefsStore()
+	if err == nil {
+		var learned *prefs.Prefs
+		if learned, err = store.Load(); err == nil && applyPreferences(cmd, learned) {
+			err = store.Save(learned)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: "+err.Error())
+	}
+}
+
+// applyPreferences records the preference flags given on the command line
+// and fills the others with their learned defaults. It reports whether
+// anything was recorded.
+func applyPreferences(cmd *cobra.Command, learned *prefs.Prefs) bool {
+	command := prefsCommand(cmd)
+	recorded := false
+	for _, name := range prefFlags {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			continue
+		}
+		if flag.Changed {
+			if value := strings.TrimSpace(flag.Value.String()); value != "" && value != "auto" {
+				learned.Record(command, name, value)
+				recorded = true
+			}
+			continue
+		}
+		if value, ok := learned.Default(command, name); ok {
+			_ = cmd.Flags().Set(name, value)
+		}
+	}
+	return recorded
+}
+
+// prefsCommand names cmd without the program name, e.g. "terms extract".
+func prefsCommand(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+func printPrefs(out io.Writer, learned *prefs.Prefs) error {
+	commands := make([]string, 0, len(learned.Commands))
+	for command := range learned.Commands {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMMAND\tFLAG\tVALUE\tUSES\tDEFAULT")
+	for _, command := range commands {
+		for _, name := range prefFlags {
+			def, ok := learned.Default(command, name)
+			for _, use := range learned.Uses(command, name) {
+				mark := ""
+				if ok && use.Value == def {
+					mark = "yes"
+				}
+				fmt.Fprintf(w, "%s\t--%s\t%s\t%d\t%s\n", command, name, use.Value, use.Count, mark)
+			}
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"dict-be/internal/prefs"
+
+	"github.com/spf13/cobra"
+)
+
+func newPrefsTestCmd() (*cobra.Command, *string, *string) {
+	root := &cobra.Command{Use: "dict-be"}
+	cmd := &cobra.Command{Use: "query", Run: func(*cobra.Command, []string) {}}
+	var in, out string
+	addLanguageFlags(cmd, &in, &out, "i", "o")
+	root.AddCommand(cmd)
+	return cmd, &in, &out
+}
+
+func TestApplyPreferences(t *testing.T) {
+	learned := &prefs.Prefs{}
+	for i := 0; i < prefs.MinUses; i++ {
+		cmd, _, _ := newPrefsTestCmd()
+		if err := cmd.ParseFlags([]string{"--output-language", "zh", "--in", "auto"}); err != nil {
+			t.Fatalf("parse flags: %v", err)
+		}
+		if !applyPreferences(cmd, learned) {
+			t.Fatalf("expected --out to be recorded")
+		}
+	}
+	if uses := learned.Uses("query", "in"); len(uses) != 0 {
+		t.Fatalf("auto should not be recorded: %v", uses)
+	}
+
+	cmd, in, out := newPrefsTestCmd()
+	if applyPreferences(cmd, learned) {
+		t.Fatalf("nothing should be recorded without flags")
+	}
+	if *out != "zh" || *in != "auto" {
+		t.Fatalf("unexpected defaults: in %q, out %q", *in, *out)
+	}
+
+	var buf bytes.Buffer
+	if err := printPrefs(&buf, learned); err != nil {
+		t.Fatalf("print: %v", err)
+	}
+	if !strings.Contains(buf.String(), "query    --out  zh     3     yes") {
+		t.Fatalf("unexpected table:\n%s", buf.String())
+	}
+}
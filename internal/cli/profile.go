@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"strings"
+
+	"dict-be/internal/llm"
+)
+
+// systemPromptClient merges a profile's default system prompt under the
+// system prompt of each request, so command instructions come last and
+// take precedence.
+type systemPromptClient struct {
+	client llm.Client
+	prompt string
+}
+
+func withSystemPrompt(client llm.Client, prompt string) llm.Client {
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return client
+	}
+	return &systemPromptClient{client: client, prompt: prompt}
+}
+
+func (c *systemPromptClient) Unwrap() llm.Client { return c.client }
+
+func (c *systemPromptClient) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	return c.client.Chat(ctx, c.apply(req))
+}
+
+func (c *systemPromptClient) ChatStream(ctx context.Context, req llm.ChatRequest, handle llm.StreamHandler) (llm.ChatResponse, error) {
+	return c.client.ChatStream(ctx, c.apply(req), handle)
+}
+
+func (c *systemPromptClient) apply(req llm.ChatRequest) llm.ChatRequest {
+	messages := make([]llm.Message, 0, len(req.Messages)+1)
+	if len(req.Messages) > 0 && req.Messages[0].Role == "system" {
+		messages = append(messages, llm.Message{
+			Role:    "system",
+			Content: c.prompt + "\n\n" + req.Messages[0].Content,
+		})
+		messages = append(messages, req.Messages[1:]...)
+	} else {
+		messages = append(messages, llm.Message{Role: "system", Content: c.prompt})
+		messages = append(messages, req.Messages...)
+	}
+	req.Messages = messages
+	return req
+}
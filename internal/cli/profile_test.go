@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"dict-be/internal/llm"
+)
+
+type recordingClient struct {
+	fakeClient
+	req llm.ChatRequest
+}
+
+func (r *recordingClient) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	r.req = req
+	return r.resp, r.err
+}
+
+func TestWithSystemPromptMergesUnderCommandPrompt(t *testing.T) {
+	inner := &recordingClient{}
+	client := withSystemPrompt(inner, "Answer in plain markdown.")
+	if _, err := client.Chat(context.Background(), llm.ChatRequest{Messages: buildMessages("Translate.", "hi")}); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if got := inner.req.Messages[0].Content; got != "Answer in plain markdown.\n\nTranslate." {
+		t.Fatalf("unexpected system prompt: %q", got)
+	}
+	if len(inner.req.Messages) != 2 || inner.req.Messages[1].Content != "hi" {
+		t.Fatalf("unexpected messages: %+v", inner.req.Messages)
+	}
+
+	if _, err := client.Chat(context.Background(), llm.ChatRequest{Messages: buildMessages("", "hi")}); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if len(inner.req.Messages) != 2 || inner.req.Messages[0].Role != "system" {
+		t.Fatalf("expected a system message to be added: %+v", inner.req.Messages)
+	}
+}
+
+func TestWithSystemPromptEmpty(t *testing.T) {
+	inner := &fakeClient{}
+	if client := withSystemPrompt(inner, " "); client != inner {
+		t.Fatalf("expected client to be unwrapped without a system prompt")
+	}
+}
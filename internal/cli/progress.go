@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"dict-be/internal/audit"
+	"dict-be/internal/config"
+	"dict-be/internal/llm"
+	"dict-be/internal/progress"
+
+	"github.com/spf13/cobra"
+)
+
+func addProgressFlag(cmd *cobra.Command, mode *string) {
+	cmd.Flags().StringVar(mode, "progress", progress.Auto, "progress display: none, plain or bar (default: bar on a terminal, plain otherwise)")
+}
+
+// newProgress reports total segments on stderr. The bar is only drawn
+// when both stdout and stderr are terminals, so redirected output and
+// logs get plain lines.
+func newProgress(cmd *cobra.Command, mode string, total int, unit string) *progress.Reporter {
+	terminal := isTerminal(cmd.OutOrStdout()) && isTerminal(cmd.ErrOrStderr())
+	return progress.New(cmd.ErrOrStderr(), mode, terminal, total, unit)
+}
+
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+type costClient struct {
+	client llm.Client
+	prices []audit.Price
+	add    func(usd float64)
+}
+
+// meterCost passes the cost of every response to add, priced with
+// audit.prices. Responses of unpriced models are not counted.
+func meterCost(client llm.Client, cfg config.Config, add func(usd float64)) llm.Client {
+	if len(cfg.Audit.Prices) == 0 {
+		return client
+	}
+	return &costClient{client: client, prices: auditPrices(cfg), add: add}
+}
+
+func (c *costClient) Unwrap() llm.Client { return c.client }
+
+func (c *costClient) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	resp, err := c.client.Chat(ctx, req)
+	c.record(req, resp)
+	return resp, err
+}
+
+func (c *costClient) ChatStream(ctx context.Context, req llm.ChatRequest, handle llm.StreamHandler) (llm.ChatResponse, error) {
+	resp, err := c.client.ChatStream(ctx, req, handle)
+	c.record(req, resp)
+	return resp, err
+}
+
+func (c *costClient) record(req llm.ChatRequest, resp llm.ChatResponse) {
+	if resp.Usage.IsZero() {
+		return
+	}
+	if cost, ok := audit.Cost(c.prices, firstNonEmpty(resp.Model, req.Model), resp.Usage); ok {
+		c.add(cost)
+	}
+}
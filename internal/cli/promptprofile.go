@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"dict-be/internal/config"
+)
+
+// queryPrompts builds the system and user prompts for one query input.
+type queryPrompts func(p queryPrompt) (string, string, error)
+
+// builtinPromptProfiles are embedded as query_<name>_system.md and
+// query_<name>_user.md.
+var builtinPromptProfiles = []string{"concise", "exam"}
+
+// queryPromptProfile returns the prompts and model of the named prompt
+// profile. prompt_profiles in config take precedence over the built-in
+// profiles; the model is empty when the profile keeps the configured one.
+func queryPromptProfile(cfg config.Config, name string) (queryPrompts, string, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if profile, ok := cfg.PromptProfiles[key]; ok {
+		return configPromptProfile(key, profile), strings.TrimSpace(profile.Model), nil
+	}
+	if slices.Contains(builtinPromptProfiles, key) {
+		return func(p queryPrompt) (string, string, error) {
+			return buildPrompts("query_"+key, p.vars())
+		}, "", nil
+	}
+	return nil, "", fmt.Errorf("unknown prompt profile: %s (expected %s)", name, strings.Join(promptProfileNames(cfg), ", "))
+}
+
+// configPromptProfile renders the templates of a prompt profile from
+// config, falling back to the standard query template for an empty one.
+func configPromptProfile(name string, profile config.PromptProfileConfig) queryPrompts {
+	return func(p queryPrompt) (string, string, error) {
+		vars := p.vars()
+		systemPrompt, err := renderProfileTemplate("prompt_profiles."+name+".system", profile.System, "query_system.md", vars)
+		if err != nil {
+			return "", "", err
+		}
+		userPrompt, err := renderProfileTemplate("prompt_profiles."+name+".user", profile.User, "query_user.md", vars)
+		if err != nil {
+			return "", "", err
+		}
+		return systemPrompt, userPrompt, nil
+	}
+}
+
+func renderProfileTemplate(name, text, fallback string, vars map[string]string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		var err error
+		if text, err = loadPrompt(fallback); err != nil {
+			return "", err
+		}
+		name = fallback
+	}
+	return renderPrompt(name, text, vars)
+}
+
+// promptProfileNames lists the built-in and configured prompt profiles.
+func promptProfileNames(cfg config.Config) []string {
+	names := slices.Clone(builtinPromptProfiles)
+	for name := range cfg.PromptProfiles {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
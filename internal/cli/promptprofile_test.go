@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"dict-be/internal/config"
+)
+
+func TestQueryPromptProfile(t *testing.T) {
+	cfg := config.Config{PromptProfiles: map[string]config.PromptProfileConfig{
+		"exam": {
+			System: "Grade {{.input}} for a {{.output_language}} exam.{{with .glossary}}\n{{.}}{{end}}",
+			Model:  " gpt-4o ",
+		},
+	}}
+	prompts, model, err := queryPromptProfile(cfg, "Exam")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model != "gpt-4o" {
+		t.Fatalf("unexpected model: %q", model)
+	}
+	systemPrompt, userPrompt, err := prompts(queryPrompt{Input: "hello", InputLanguage: "English", OutputLanguage: "German", Sections: []string{"translation"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if systemPrompt != "Grade hello for a German exam." {
+		t.Fatalf("unexpected system prompt: %q", systemPrompt)
+	}
+	if !strings.Contains(userPrompt, "<input>hello</input>") {
+		t.Fatalf("expected the standard user prompt, got %q", userPrompt)
+	}
+
+	prompts, model, err = queryPromptProfile(cfg, "concise")
+	if err != nil || model != "" {
+		t.Fatalf("unexpected model %q, error %v", model, err)
+	}
+	systemPrompt, _, err = prompts(queryPrompt{Input: "hello", InputLanguage: "English", OutputLanguage: "German"})
+	if err != nil || !strings.Contains(systemPrompt, "quick glosses") {
+		t.Fatalf("unexpected system prompt %q, error %v", systemPrompt, err)
+	}
+
+	_, _, err = queryPromptProfile(cfg, "detailed")
+	if err == nil || !strings.Contains(err.Error(), "concise, exam") {
+		t.Fatalf("expected unknown profile error, got %v", err)
+	}
+}
+
+func TestQueryPromptProfileTemplateError(t *testing.T) {
+	cfg := config.Config{PromptProfiles: map[string]config.PromptProfileConfig{
+		"broken": {User: "{{.text}}"},
+	}}
+	prompts, _, err := queryPromptProfile(cfg, "broken")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _, err = prompts(queryPrompt{Input: "hello", InputLanguage: "English", OutputLanguage: "German"})
+	if err == nil || !strings.Contains(err.Error(), "prompt_profiles.broken.user") {
+		t.Fatalf("expected template error naming the profile, got %v", err)
+	}
+}
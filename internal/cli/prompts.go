@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"dict-be/internal/llm"
+
+	"github.com/spf13/cobra"
+)
+
+//go:embed *.md
+var promptFS embed.FS
+
+// buildPrompts loads <name>_system.md and <name>_user.md and renders both
+// as text/template templates over vars and promptDefaults, so {{.input}}
+// is vars["input"].
+func buildPrompts(name string, vars map[string]string) (string, string, error) {
+	systemTemplate, err := loadPrompt(name + "_system.md")
+	if err != nil {
+		return "", "", err
+	}
+	userTemplate, err := loadPrompt(name + "_user.md")
+	if err != nil {
+		return "", "", err
+	}
+	systemPrompt, err := renderPrompt(name+"_system.md", systemTemplate, vars)
+	if err != nil {
+		return "", "", err
+	}
+	userPrompt, err := renderPrompt(name+"_user.md", userTemplate, vars)
+	if err != nil {
+		return "", "", err
+	}
+	return systemPrompt, userPrompt, nil
+}
+
+func loadPrompt(path string) (string, error) {
+	data, err := promptFS.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read prompt template %s: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// promptDefaults are the variables every prompt template can use;
+// commands override them through vars. Empty ones are meant for
+// {{with}} or {{if}}.
+func promptDefaults() map[string]string {
+	return map[string]string{
+		"date":     time.Now().Format(time.DateOnly),
+		"level":    "",
+		"glossary": "",
+	}
+}
+
+// promptFuncs are the functions prompt templates can call, such as
+// {{range lines .known}} or {{if contains .input "x"}}.
+var promptFuncs = template.FuncMap{
+	"lines": func(value string) []string {
+		return nonBlank(strings.Split(value, "\n"))
+	},
+	"contains": strings.Contains,
+}
+
+// renderPrompt executes text as a template named name. A placeholder with
+// no variable is an error rather than an empty string.
+func renderPrompt(name, text string, vars map[string]string) (string, error) {
+	tmpl, err := template.New(name).Funcs(promptFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse prompt template: %w", err)
+	}
+	data := promptDefaults()
+	for key, value := range vars {
+		data[key] = value
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render prompt template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// runPromptCommand renders the named prompt pair, sends it to the configured
+// LLM and prints the response according to out.
+func runPromptCommand(cmd *cobra.Command, out *outputOptions, name string, vars map[string]string) error {
+	_, err := runPromptCommandAnswer(cmd, out, name, vars)
+	return err
+}
+
+// runPromptCommandAnswer is runPromptCommand for commands that keep the
+// answer; it also returns the model's response before post-processing.
+func runPromptCommandAnswer(cmd *cobra.Command, out *outputOptions, name string, vars map[string]string) (string, error) {
+	systemPrompt, userPrompt, err := buildPrompts(name, vars)
+	if err != nil {
+		return "", err
+	}
+	client, cfg, err := loadLLMClient(cmd)
+	if err != nil {
+		return "", err
+	}
+	recorder := &responseRecorder{client: client}
+	req := llm.ChatRequest{
+		Model:    cfg.LLM.Model,
+		Messages: buildMessages(systemPrompt, userPrompt),
+	}
+	post, err := newPostprocessPipeline(cfg)
+	if err != nil {
+		return "", err
+	}
+	stream := streamEnabled(out.Stream, out.NoStream, out.Format)
+	if err := runChat(commandContext(cmd), cmd.OutOrStdout(), recorder, req, stream, out.Format, post); err != nil {
+		return "", err
+	}
+	return recorder.last.Content, nil
+}
+
+// completePrompt renders the named prompt pair and returns the full
+// response text, for commands that post-process the model output.
+func completePrompt(ctx context.Context, cmd *cobra.Command, name string, vars map[string]string) (string, error) {
+	systemPrompt, userPrompt, err := buildPrompts(name, vars)
+	if err != nil {
+		return "", err
+	}
+	client, cfg, err := loadLLMClient(cmd)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Chat(ctx, llm.ChatRequest{
+		Model:    cfg.LLM.Model,
+		Messages: buildMessages(systemPrompt, userPrompt),
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+// decodeJSONContent decodes a JSON value from model output, tolerating a
+// surrounding markdown code fence.
+func decodeJSONContent(content string, out any) error {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "```") {
+		content = strings.TrimPrefix(content, "```")
+		if newline := strings.IndexByte(content, '\n'); newline >= 0 {
+			content = content[newline+1:]
+		}
+		content = strings.TrimSuffix(strings.TrimSpace(content), "```")
+	}
+	if err := json.Unmarshal([]byte(content), out); err != nil {
+		return fmt.Errorf("decode model output as json: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderPrompt(t *testing.T) {
+	text := "Translate to {{.output_language}}.{{with .level}} Level: {{.}}.{{end}}\n{{range lines .words}}- {{.}}\n{{end}}Today is {{.date}}."
+	got, err := renderPrompt("test.md", text, map[string]string{
+		"output_language": "German",
+		"words":           "one\n\ntwo",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Translate to German.\n- one\n- two\nToday is " + time.Now().Format(time.DateOnly) + "."
+	if got != want {
+		t.Fatalf("unexpected prompt:\n%s", got)
+	}
+	got, err = renderPrompt("test.md", "{{with .level}}Level: {{.}}.{{end}}", map[string]string{"level": "B2"})
+	if err != nil || got != "Level: B2." {
+		t.Fatalf("unexpected prompt %q, error %v", got, err)
+	}
+}
+
+func TestRenderPromptErrors(t *testing.T) {
+	_, err := renderPrompt("test.md", "Translate {{.inptu}}", map[string]string{"input": "hello"})
+	if err == nil || !strings.Contains(err.Error(), `"inptu"`) || !strings.Contains(err.Error(), "test.md") {
+		t.Fatalf("expected unknown placeholder error, got %v", err)
+	}
+	if _, err := renderPrompt("test.md", "{{if .input}}", nil); err == nil {
+		t.Fatalf("expected parse error")
+	}
+}
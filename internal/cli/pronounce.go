@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"dict-be/internal/llm"
+
+	"github.com/spf13/cobra"
+)
+
+type pronounceOptions struct {
+	InputLanguage  string
+	OutputLanguage string
+	Audio          string
+	Voice          string
+	SpeechModel    string
+	Output         outputOptions
+}
+
+func newPronounceCmd() *cobra.Command {
+	opts := &pronounceOptions{}
+	cmd := &cobra.Command{
+		Use:   "pronounce <word>",
+		Short: "Show how a word is pronounced, optionally saving audio",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPronounce(cmd, opts, strings.Join(args, " "))
+		},
+	}
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "o")
+	cmd.Flags().StringVar(&opts.Audio, "audio", "", "also save spoken audio of the word to this mp3 file")
+	cmd.Flags().StringVar(&opts.Voice, "voice", llm.DefaultSpeechVoice, "voice for --audio")
+	cmd.Flags().StringVar(&opts.SpeechModel, "speech-model", llm.DefaultSpeechModel, "text-to-speech model for --audio")
+	opts.Output.addFlags(cmd)
+	return cmd
+}
+
+func runPronounce(cmd *cobra.Command, opts *pronounceOptions, word string) error {
+	if err := opts.Output.validate(); err != nil {
+		return err
+	}
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return fmt.Errorf("word is required")
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(word, opts.InputLanguage, opts.OutputLanguage)
+	err := runPromptCommand(cmd, &opts.Output, "pronounce", map[string]string{
+		"input":           word,
+		"input_language":  inputLanguage,
+		"output_language": outputLanguage,
+		"notation":        pronunciationNotation(word, inputLanguage),
+	})
+	if err != nil || opts.Audio == "" {
+		return err
+	}
+	return savePronunciation(cmd, word, opts)
+}
+
+// pronunciationNotation names the transcription to use for word.
+func pronunciationNotation(word, inputLanguage string) string {
+	language := strings.ToLower(inputLanguage)
+	switch {
+	case strings.Contains(language, "chinese") || containsChinese(word):
+		return "Hanyu Pinyin with tone marks"
+	case strings.Contains(language, "english"):
+		return "IPA, giving British and American pronunciations when they differ"
+	}
+	return "IPA"
+}
+
+func savePronunciation(cmd *cobra.Command, word string, opts *pronounceOptions) error {
+	client, _, err := loadLLMClient(cmd)
+	if err != nil {
+		return err
+	}
+	audio, err := llm.Synthesize(commandContext(cmd), client, llm.SpeechRequest{
+		Model:  opts.SpeechModel,
+		Voice:  opts.Voice,
+		Input:  word,
+		Format: "mp3",
+	})
+	if err != nil {
+		return fmt.Errorf("audio: %w", err)
+	}
+	path := expandHome(opts.Audio)
+	if err := writeFileAtomic(path, audio); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "saved audio to %s\n", path)
+	return nil
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPronunciationNotation(t *testing.T) {
+	cases := []struct {
+		word, language, want string
+	}{
+		{"行", "the detected source language", "Hanyu Pinyin"},
+		{"xing", "Simplified Chinese", "Hanyu Pinyin"},
+		{"schedule", "English", "British and American"},
+		{"château", "French", "IPA"},
+	}
+	for _, tc := range cases {
+		if got := pronunciationNotation(tc.word, tc.language); !strings.Contains(got, tc.want) {
+			t.Fatalf("%s (%s): unexpected notation %q", tc.word, tc.language, got)
+		}
+	}
+	systemPrompt, userPrompt, err := buildPrompts("pronounce", map[string]string{
+		"input":           "schedule",
+		"input_language":  "English",
+		"output_language": "Simplified Chinese",
+		"notation":        pronunciationNotation("schedule", "English"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, "primary stress") || !strings.Contains(userPrompt, "<input>schedule</input>") {
+		t.Fatalf("unexpected prompts:\n%s\n%s", systemPrompt, userPrompt)
+	}
+}
@@ -1,35 +1,64 @@
 package cli
 
 import (
-	"context"
-	"embed"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"unicode"
 
 	"dict-be/internal/config"
+	"dict-be/internal/history"
+	"dict-be/internal/langdetect"
 	"dict-be/internal/llm"
+	"dict-be/internal/progress"
+	"dict-be/internal/render"
+	"dict-be/internal/wordfreq"
 
 	"github.com/spf13/cobra"
 )
 
-//go:embed query_system.md
-//go:embed query_user.md
-var queryPromptFS embed.FS
-
-const (
-	querySystemPromptPath = "query_system.md"
-	queryUserPromptPath   = "query_user.md"
-)
-
 type queryOptions struct {
 	InputFile      string
+	InputsFile     string
 	InputLanguage  string
 	OutputLanguage string
 	Stream         bool
 	NoStream       bool
+	Format         string
+	Sections       string
+	Glossary       string
+	PromptProfile  string
+	Level          string
+	Tone           string
+	Frequency      bool
+	ShowReasoning  bool
+	Save           bool
+	NoCache        bool
+	Progress       string
+	Sampling       samplingOptions
+}
+
+// querySections maps --sections names to the instructions that request
+// them from the model.
+var querySections = map[string]string{
+	"translation":  "Translation: the translation of the input.",
+	"difficulties": "Language difficulties: point out the most error-prone or important grammar/semantic points.",
+	"mnemonics":    "Vocabulary memory tips: give memory techniques for key words or phrases.",
+	"examples":     "Examples: two or three example sentences using the key words or phrases, each followed by its translation.",
+	"frequency":    "Frequency: how common the input is in everyday speech and writing, and whether a learner should prioritize memorizing it.",
+}
+
+const defaultQuerySections = "translation,difficulties,mnemonics"
+
+// queryTones maps --tone names to the register instruction they add to
+// the query prompt.
+var queryTones = map[string]string{
+	"formal":    "Translate in a formal register, as in business email or official documents.",
+	"casual":    "Translate in a casual, conversational register, as in chat messages between friends.",
+	"technical": "Translate in a precise technical register, using the established terminology of the field.",
+	"literary":  "Translate in a literary register, keeping the imagery, rhythm and style of the original.",
 }
 
 func newQueryCmd() *cobra.Command {
@@ -42,12 +71,22 @@ func newQueryCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&opts.InputFile, "file", "F", "", "query file, use -F- for stdin")
-	cmd.Flags().StringVar(&opts.InputLanguage, "input-language", "auto", "input language")
-	cmd.Flags().StringVar(&opts.InputLanguage, "in", "auto", "input language")
-	cmd.Flags().StringVar(&opts.OutputLanguage, "output-language", "auto", "output language")
-	cmd.Flags().StringVar(&opts.OutputLanguage, "out", "auto", "output language")
+	cmd.Flags().StringVar(&opts.InputsFile, "file0", "", "file of NUL-delimited queries, each answered in turn, use - for stdin")
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "o")
 	cmd.Flags().BoolVar(&opts.Stream, "stream", false, "stream response")
 	cmd.Flags().BoolVar(&opts.NoStream, "no-stream", false, "disable streaming response")
+	cmd.Flags().StringVar(&opts.Format, "format", render.Text, queryFormatHelp)
+	cmd.Flags().StringVar(&opts.Sections, "sections", "", "comma-separated sections: translation,difficulties,mnemonics,examples,frequency")
+	cmd.Flags().StringVar(&opts.PromptProfile, "prompt-profile", "", "query prompt set: concise, exam or one from prompt_profiles in config")
+	addLevelFlag(cmd, &opts.Level, "")
+	cmd.Flags().BoolVar(&opts.Frequency, "frequency", false, "add how common the word is, with its rank in the bundled English word list")
+	cmd.Flags().StringVar(&opts.Tone, "tone", "", "register of the translation: formal, casual, technical or literary")
+	cmd.Flags().StringVar(&opts.Glossary, "glossary", "", "glossary CSV of term translations to use and check")
+	cmd.Flags().BoolVar(&opts.ShowReasoning, "show-reasoning", false, "print the model's reasoning to stderr when the provider returns it")
+	cmd.Flags().BoolVar(&opts.Save, "save", false, "save each answer to the vocabulary notebook")
+	cmd.Flags().BoolVar(&opts.NoCache, "no-cache", false, "ask the model even when the answer is cached, and refresh the cache")
+	addProgressFlag(cmd, &opts.Progress)
+	addSamplingFlags(cmd, &opts.Sampling)
 	return cmd
 }
 
@@ -55,55 +94,157 @@ func runQuery(cmd *cobra.Command, opts *queryOptions, args []string) error {
 	if opts.Stream && opts.NoStream {
 		return fmt.Errorf("only one of --stream or --no-stream can be set")
 	}
-	input, err := readInput(args, opts.InputFile, cmd.InOrStdin())
-	if err != nil {
+	if err := validateQueryFormat(opts.Format); err != nil {
+		return err
+	}
+	if err := validateQueryTone(opts.Tone); err != nil {
+		return err
+	}
+	if opts.PromptProfile != "" && isStructuredQueryFormat(opts.Format) {
+		return fmt.Errorf("--prompt-profile cannot be combined with --format %s", opts.Format)
+	}
+	if err := opts.Sampling.validate(); err != nil {
+		return err
+	}
+	if err := progress.ParseMode(opts.Progress); err != nil {
 		return err
 	}
-	if strings.TrimSpace(input) == "" {
+	var inputs []string
+	if opts.InputsFile != "" {
+		if opts.InputFile != "" || len(args) > 0 {
+			return fmt.Errorf("--file0 cannot be combined with input args or -F")
+		}
+		var err error
+		if inputs, err = readInputs(opts.InputsFile, cmd.InOrStdin()); err != nil {
+			return err
+		}
+	} else {
+		input, err := readInput(args, opts.InputFile, cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(input) != "" {
+			inputs = []string{input}
+		}
+	}
+	if len(inputs) == 0 {
 		return fmt.Errorf("input is required")
 	}
-	inputLanguage, outputLanguage := resolveLanguages(input, opts.InputLanguage, opts.OutputLanguage)
-	systemPrompt, userPrompt, err := buildQueryPrompts(input, inputLanguage, outputLanguage)
+	terms, err := loadGlossary(opts.Glossary)
 	if err != nil {
 		return err
 	}
-
-	cfg, err := config.Load()
+	level := learnerLevel(opts.Level, "")
+	client, cfg, err := loadLLMClient(cmd)
 	if err != nil {
 		return err
 	}
-	if cfg.LLM.Type == "" {
-		cfg.LLM.Type = "openai"
-	}
 
-	client, err := newLLMClient(cfg.LLM.Type, cfg.LLM.URL, cfg.LLM.Token, cfg.LLM.Model)
+	sections, err := parseQuerySections(firstNonEmpty(opts.Sections, strings.Join(cfg.Query.Sections, ","), defaultQuerySections))
 	if err != nil {
 		return err
 	}
-
-	req := llm.ChatRequest{
-		Model:    cfg.LLM.Model,
-		Messages: buildMessages(systemPrompt, userPrompt),
+	if opts.Frequency && !slices.Contains(sections, "frequency") {
+		sections = append(sections, "frequency")
 	}
-
-	if opts.Stream {
-		_, err = client.ChatStream(context.Background(), req, func(delta string) error {
-			_, writeErr := fmt.Fprint(cmd.OutOrStdout(), delta)
-			return writeErr
-		})
-		if err != nil {
+	prompts, model := queryPrompts(buildQueryPrompts), cfg.LLM.Model
+	if opts.PromptProfile != "" {
+		var profileModel string
+		if prompts, profileModel, err = queryPromptProfile(cfg, opts.PromptProfile); err != nil {
 			return err
 		}
-		_, _ = fmt.Fprintln(cmd.OutOrStdout())
-		return nil
+		model = firstNonEmpty(profileModel, model)
 	}
-
-	resp, err := client.Chat(context.Background(), req)
+	post, err := newPostprocessPipeline(cfg)
 	if err != nil {
 		return err
 	}
-	_, err = fmt.Fprintln(cmd.OutOrStdout(), resp.Content)
-	return err
+	client, err = cacheClient(cmd, cfg, client, opts.NoCache)
+	if err != nil {
+		return err
+	}
+	if opts.ShowReasoning {
+		client = showReasoning(client, cmd.ErrOrStderr())
+	}
+	var bar *progress.Reporter
+	if len(inputs) > 1 {
+		bar = newProgress(cmd, opts.Progress, len(inputs), "inputs")
+		client = meterCost(client, cfg, func(usd float64) { bar.AddCost(usd) })
+	}
+	defer bar.Finish()
+	var recorder *responseRecorder
+	if opts.Save || cfg.History.Enabled || len(terms) > 0 {
+		recorder = &responseRecorder{client: client}
+		client = recorder
+	}
+	stream := streamEnabled(opts.Stream, opts.NoStream, opts.Format)
+	for i, input := range inputs {
+		bar.Clear()
+		if i > 0 && (opts.Format == render.Text || opts.Format == render.ANSI || opts.Format == queryMarkdown || opts.Format == queryPlain) {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+		inputLanguage, outputLanguage := resolveLanguages(input, opts.InputLanguage, opts.OutputLanguage)
+		structured := isStructuredQueryFormat(opts.Format)
+		build := prompts
+		if structured {
+			build = buildQueryJSONPrompts
+		}
+		systemPrompt, userPrompt, err := build(queryPrompt{
+			Input:          input,
+			InputLanguage:  inputLanguage,
+			OutputLanguage: outputLanguage,
+			Sections:       sections,
+			Glossary:       glossaryInstruction(terms, input),
+			Level:          level,
+			Tone:           opts.Tone,
+		})
+		if err != nil {
+			return err
+		}
+		req := llm.ChatRequest{
+			Model:    model,
+			Messages: buildMessages(systemPrompt, userPrompt),
+		}
+		opts.Sampling.apply(&req)
+		if structured {
+			err = runStructuredQuery(commandContext(cmd), cmd.OutOrStdout(), client, req, input, sections, opts.Format, post)
+		} else {
+			err = runChat(commandContext(cmd), cmd.OutOrStdout(), client, req, stream, opts.Format, post)
+		}
+		if err != nil {
+			if len(inputs) > 1 {
+				bar.Fail()
+				return fmt.Errorf("input %d: %w", i+1, err)
+			}
+			return err
+		}
+		if recorder != nil {
+			bar.Clear()
+			where := ""
+			if len(inputs) > 1 {
+				where = fmt.Sprintf("input %d", i+1)
+			}
+			warnGlossary(cmd.ErrOrStderr(), terms, where, input, recorder.last.Content)
+			entry := queryVocabEntry(input, inputLanguage, outputLanguage, recorder.last.Content, structured, sections)
+			if cfg.History.Enabled {
+				recordHistory(cmd, history.Record{
+					Command:        "query",
+					Input:          entry.Word,
+					InputLanguage:  inputLanguage,
+					OutputLanguage: outputLanguage,
+					Model:          firstNonEmpty(recorder.last.Model, model),
+					Response:       entry.Content,
+				})
+			}
+			if opts.Save {
+				if err := saveVocab(cmd, entry); err != nil {
+					return err
+				}
+			}
+		}
+		bar.Done()
+	}
+	return nil
 }
 
 func readInput(args []string, inputFile string, stdin io.Reader) (string, error) {
@@ -116,66 +257,227 @@ func readInput(args []string, inputFile string, stdin io.Reader) (string, error)
 		}
 		return strings.Join(args, " "), nil
 	}
-	if inputFile == "-" {
+	data, err := readSource(inputFile, stdin)
+	if err != nil {
+		return "", err
+	}
+	return trimTrailingNewline(string(data)), nil
+}
+
+// readInputs reads NUL-delimited inputs, as written by find -print0 or
+// printf '%s\0', skipping blank records.
+func readInputs(path string, stdin io.Reader) ([]string, error) {
+	data, err := readSource(path, stdin)
+	if err != nil {
+		return nil, err
+	}
+	var inputs []string
+	for _, record := range strings.Split(string(data), "\x00") {
+		record = trimTrailingNewline(record)
+		if strings.TrimSpace(record) != "" {
+			inputs = append(inputs, record)
+		}
+	}
+	return inputs, nil
+}
+
+// readSource reads all of path, or stdin for "-". It only opens and reads
+// the file, so named pipes and /dev/fd/N paths from process substitution
+// work like regular files.
+func readSource(path string, stdin io.Reader) ([]byte, error) {
+	if path == "-" {
 		data, err := io.ReadAll(stdin)
 		if err != nil {
-			return "", fmt.Errorf("read stdin: %w", err)
+			return nil, fmt.Errorf("read stdin: %w", err)
 		}
-		return trimTrailingNewline(string(data)), nil
+		return data, nil
 	}
-	data, err := os.ReadFile(inputFile)
+	file, err := os.Open(path)
 	if err != nil {
-		return "", fmt.Errorf("read file: %w", err)
+		return nil, fmt.Errorf("read file: %w", err)
 	}
-	return trimTrailingNewline(string(data)), nil
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	return data, nil
+}
+
+// isRegularFile reports whether path is a regular file that can be read
+// again later, as opposed to stdin, a pipe or a device.
+func isRegularFile(path string) bool {
+	if path == "-" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
 }
 
 func trimTrailingNewline(value string) string {
 	return strings.TrimRight(value, "\r\n")
 }
 
-func buildQueryPrompts(input, inputLanguage, outputLanguage string) (string, string, error) {
-	systemTemplate, err := loadQueryPrompt(querySystemPromptPath)
-	if err != nil {
-		return "", "", err
+// queryPrompt is what the query prompt templates are rendered from.
+// Glossary is the rendered glossary instruction, Level the learner level
+// and Tone a queryTones name; all may be empty.
+type queryPrompt struct {
+	Input          string
+	InputLanguage  string
+	OutputLanguage string
+	Sections       []string
+	Glossary       string
+	Level          string
+	Tone           string
+}
+
+func buildQueryPrompts(p queryPrompt) (string, string, error) {
+	return buildPrompts("query", p.vars())
+}
+
+// vars returns the variables of the query prompt templates, including
+// those of prompt profiles.
+func (p queryPrompt) vars() map[string]string {
+	lines := make([]string, 0, len(p.Sections))
+	for _, section := range p.Sections {
+		lines = append(lines, "- "+querySections[section])
 	}
-	userTemplate, err := loadQueryPrompt(queryUserPromptPath)
-	if err != nil {
-		return "", "", err
+	return map[string]string{
+		"input":            p.Input,
+		"input_language":   p.InputLanguage,
+		"output_language":  p.OutputLanguage,
+		"sections":         strings.Join(lines, "\n"),
+		"glossary":         p.Glossary,
+		"level":            p.Level,
+		"tone":             p.Tone,
+		"tone_instruction": queryTones[p.Tone],
+		"frequency":        frequencyNote(p.Input, p.Sections),
 	}
+}
 
-	systemPrompt := renderQueryPrompt(systemTemplate, input, inputLanguage, outputLanguage)
-	userPrompt := renderQueryPrompt(userTemplate, input, inputLanguage, outputLanguage)
-	return systemPrompt, userPrompt, nil
+// frequencyNote states the rank of a single English word in the bundled
+// word list, when the frequency section is requested, so the model can
+// comment on it.
+func frequencyNote(input string, sections []string) string {
+	word := strings.TrimSpace(input)
+	if !slices.Contains(sections, "frequency") || !isEnglishWord(word) {
+		return ""
+	}
+	if rank, ok := wordfreq.Rank(word); ok {
+		return fmt.Sprintf("%q ranks %d among the %d most common English words (%s).", word, rank, wordfreq.Size(), wordfreq.Band(rank))
+	}
+	return fmt.Sprintf("%q is not among the %d most common English words.", word, wordfreq.Size())
 }
 
-func loadQueryPrompt(path string) (string, error) {
-	data, err := queryPromptFS.ReadFile(path)
-	if err != nil {
-		return "", fmt.Errorf("read prompt template %s: %w", path, err)
+// isEnglishWord reports whether word is a single word of ASCII letters,
+// apostrophes and hyphens.
+func isEnglishWord(word string) bool {
+	if word == "" {
+		return false
+	}
+	for _, r := range word {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '\'' || r == '-') {
+			return false
+		}
 	}
-	return strings.TrimSpace(string(data)), nil
+	return true
 }
 
-func renderQueryPrompt(template, input, inputLanguage, outputLanguage string) string {
-	replacer := strings.NewReplacer(
-		"{{input}}", input,
-		"{{input_language}}", inputLanguage,
-		"{{output_language}}", outputLanguage,
-	)
-	return replacer.Replace(template)
+func validateQueryTone(tone string) error {
+	if _, ok := queryTones[tone]; ok || tone == "" {
+		return nil
+	}
+	return fmt.Errorf("invalid tone: %s (expected formal, casual, technical or literary)", tone)
 }
 
+// parseQuerySections validates a comma-separated section list, keeping
+// the given order and dropping duplicates.
+func parseQuerySections(value string) ([]string, error) {
+	var sections []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := querySections[name]; !ok {
+			return nil, fmt.Errorf("invalid section: %s (expected translation, difficulties, mnemonics, examples or frequency)", name)
+		}
+		seen[name] = true
+		sections = append(sections, name)
+	}
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("at least one section is required")
+	}
+	return sections, nil
+}
+
+// resolveLanguages expands the language names from the languages config
+// and replaces --in auto and --out auto, given together, with
+// autoLanguages.
 func resolveLanguages(input, inputLanguage, outputLanguage string) (string, string) {
+	// An invalid config fails the command when its client is loaded;
+	// until then the built-in defaults apply.
+	cfg, _ := config.Load()
+	return resolveConfiguredLanguages(cfg, input, inputLanguage, outputLanguage)
+}
+
+func resolveConfiguredLanguages(cfg config.Config, input, inputLanguage, outputLanguage string) (string, string) {
+	inputLanguage = languageName(cfg.Languages, inputLanguage)
+	outputLanguage = languageName(cfg.Languages, outputLanguage)
 	if inputLanguage == "auto" && outputLanguage == "auto" {
-		if containsChinese(input) {
-			return "Simplified Chinese", "English"
-		}
-		return "English", "Simplified Chinese"
+		return autoLanguages(cfg, input)
 	}
 	return inputLanguage, outputLanguage
 }
 
+// autoLanguages detects the language of input and looks up its output
+// language in query.auto_out. Without an entry, input in
+// query.default_out goes to query.default_in and anything else to
+// query.default_out.
+func autoLanguages(cfg config.Config, input string) (string, string) {
+	detected := firstNonEmpty(langdetect.Detect(input), langdetect.English)
+	if target := strings.TrimSpace(cfg.Query.AutoOut[strings.ToLower(detected)]); target != "" {
+		return detected, languageName(cfg.Languages, target)
+	}
+	defaultIn := languageName(cfg.Languages, firstNonEmpty(cfg.Query.DefaultIn, langdetect.English))
+	defaultOut := languageName(cfg.Languages, firstNonEmpty(cfg.Query.DefaultOut, langdetect.Chinese))
+	if strings.EqualFold(detected, defaultOut) {
+		return defaultOut, defaultIn
+	}
+	return detected, defaultOut
+}
+
+// languageAliases are the short names known without a languages config.
+var languageAliases = map[string]string{
+	"en":      langdetect.English,
+	"zh":      langdetect.Chinese,
+	"zh-hans": langdetect.Chinese,
+	"zh-hant": "Traditional Chinese",
+	"ja":      langdetect.Japanese,
+	"ko":      langdetect.Korean,
+	"fr":      langdetect.French,
+	"de":      langdetect.German,
+	"es":      langdetect.Spanish,
+	"it":      langdetect.Italian,
+	"pt":      langdetect.Portuguese,
+	"ru":      langdetect.Russian,
+}
+
+// languageName returns the language that value names in languages, which
+// viper keys in lower case, or in languageAliases, or value itself.
+func languageName(languages map[string]string, value string) string {
+	value = strings.TrimSpace(value)
+	key := strings.ToLower(value)
+	if name := strings.TrimSpace(languages[key]); name != "" {
+		return name
+	}
+	if name, ok := languageAliases[key]; ok {
+		return name
+	}
+	return value
+}
+
 func containsChinese(value string) bool {
 	for _, r := range value {
 		if unicode.Is(unicode.Han, r) {
@@ -1,10 +1,13 @@
 package cli
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"dict-be/internal/config"
 )
 
 func TestReadQueryFromArgs(t *testing.T) {
@@ -86,15 +89,151 @@ func TestResolveLanguagesNoAuto(t *testing.T) {
 	}
 }
 
+func TestAutoLanguages(t *testing.T) {
+	cfg := config.Config{Query: config.QueryConfig{
+		AutoOut: map[string]string{"japanese": "zh", "french": " German "},
+	}}
+	tests := []struct {
+		input, in, out string
+	}{
+		{"今日はいい天気ですね", "Japanese", "Simplified Chinese"},
+		{"Je ne sais pas ce que c'est.", "French", "German"},
+		{"Как дела?", "Russian", "Simplified Chinese"},
+		{"你好", "Simplified Chinese", "English"},
+		{"42", "English", "Simplified Chinese"},
+	}
+	for _, test := range tests {
+		in, out := autoLanguages(cfg, test.input)
+		if in != test.in || out != test.out {
+			t.Errorf("autoLanguages(%q) = %q, %q, want %q, %q", test.input, in, out, test.in, test.out)
+		}
+	}
+}
+
+func TestResolveConfiguredLanguages(t *testing.T) {
+	cfg := config.Config{
+		Query:     config.QueryConfig{DefaultIn: "jp", DefaultOut: "zh"},
+		Languages: map[string]string{"jp": "Japanese"},
+	}
+	tests := []struct {
+		input, in, out, wantIn, wantOut string
+	}{
+		{"今日はいい天気ですね", "auto", "auto", "Japanese", "Simplified Chinese"},
+		{"你好", "auto", "auto", "Simplified Chinese", "Japanese"},
+		{"hello", "auto", "auto", "English", "Simplified Chinese"},
+		{"hello", "en", "jp", "English", "Japanese"},
+		{"hello", "auto", "ko", "auto", "Korean"},
+	}
+	for _, test := range tests {
+		in, out := resolveConfiguredLanguages(cfg, test.input, test.in, test.out)
+		if in != test.wantIn || out != test.wantOut {
+			t.Errorf("resolve(%q, %q, %q) = %q, %q, want %q, %q", test.input, test.in, test.out, in, out, test.wantIn, test.wantOut)
+		}
+	}
+}
+
+func TestBuildQueryPromptsLevel(t *testing.T) {
+	prompt := queryPrompt{Input: "hello", InputLanguage: "English", OutputLanguage: "Simplified Chinese", Sections: []string{"examples"}}
+	for _, build := range []queryPrompts{buildQueryPrompts, buildQueryJSONPrompts} {
+		systemPrompt, _, err := build(prompt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(systemPrompt, "level") {
+			t.Fatalf("expected no level without --level: %q", systemPrompt)
+		}
+		prompt.Level = "HSK4"
+		systemPrompt, _, err = build(prompt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(systemPrompt, "at level HSK4") || !strings.Contains(systemPrompt, "a HSK4 learner") {
+			t.Fatalf("expected the level in the system prompt: %q", systemPrompt)
+		}
+		prompt.Level = ""
+	}
+}
+
+func TestBuildQueryPromptsTone(t *testing.T) {
+	systemPrompt, _, err := buildQueryPrompts(queryPrompt{Input: "see you", InputLanguage: "English", OutputLanguage: "German", Sections: []string{"translation"}, Tone: "formal"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, queryTones["formal"]) {
+		t.Fatalf("expected the formal register in the system prompt: %q", systemPrompt)
+	}
+	if err := validateQueryTone("polite"); err == nil {
+		t.Fatalf("expected invalid tone error")
+	}
+	if err := validateQueryTone(""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestBuildQueryPrompts(t *testing.T) {
-	systemPrompt, userPrompt, err := buildQueryPrompts("hello", "English", "Simplified Chinese")
+	systemPrompt, userPrompt, err := buildQueryPrompts(queryPrompt{Input: "hello", InputLanguage: "English", OutputLanguage: "Simplified Chinese", Sections: []string{"translation", "examples"}})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if !strings.Contains(systemPrompt, "English") || !strings.Contains(systemPrompt, "Simplified Chinese") {
 		t.Fatalf("unexpected system prompt: %q", systemPrompt)
 	}
+	if !strings.Contains(systemPrompt, "- Examples:") || strings.Contains(systemPrompt, "memory tips") {
+		t.Fatalf("unexpected sections in system prompt: %q", systemPrompt)
+	}
 	if !strings.Contains(userPrompt, "<input>hello</input>") {
 		t.Fatalf("unexpected user prompt: %q", userPrompt)
 	}
 }
+
+func TestParseQuerySections(t *testing.T) {
+	sections, err := parseQuerySections(" Examples, translation,examples ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(sections, ",") != "examples,translation" {
+		t.Fatalf("unexpected sections: %q", sections)
+	}
+	if _, err := parseQuerySections("translation,history"); err == nil {
+		t.Fatalf("expected error for unknown section")
+	}
+	if _, err := parseQuerySections(" , "); err == nil {
+		t.Fatalf("expected error for empty sections")
+	}
+}
+
+func TestReadInputsNULDelimited(t *testing.T) {
+	inputs, err := readInputs("-", strings.NewReader("hello\x00 \x00good night\n\x00"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(inputs) != 2 || inputs[0] != "hello" || inputs[1] != "good night" {
+		t.Fatalf("unexpected inputs: %q", inputs)
+	}
+}
+
+func TestReadQueryFromFileDescriptor(t *testing.T) {
+	if _, err := os.Stat("/dev/fd"); err != nil {
+		t.Skip("no /dev/fd on this platform")
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	defer r.Close()
+	go func() {
+		_, _ = w.WriteString("from a pipe\n")
+		w.Close()
+	}()
+	path := fmt.Sprintf("/dev/fd/%d", r.Fd())
+	input, err := readInput(nil, path, strings.NewReader(""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if input != "from a pipe" {
+		t.Fatalf("unexpected input: %q", input)
+	}
+	if isRegularFile(path) {
+		t.Fatalf("expected pipe not to be a regular file")
+	}
+}
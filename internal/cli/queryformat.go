@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/postprocess"
+	"dict-be/internal/render"
+	"dict-be/internal/wordfreq"
+)
+
+// Formats only query accepts. With these and render.JSON, query asks the
+// model for a JSON object and renders it itself.
+const (
+	queryMarkdown = "markdown"
+	queryPlain    = "plain"
+)
+
+var queryFormatHelp = formatHelp + ", " + queryMarkdown + ", " + queryPlain + " (json, markdown and plain request structured output)"
+
+// queryFields maps --sections names to the JSON keys that request them in
+// structured output.
+var queryFields = map[string]string{
+	"translation":  `"translation": the translation of the input, as a string.`,
+	"difficulties": `"difficulties": an array of strings, each pointing out an error-prone or important grammar or semantic point.`,
+	"mnemonics":    `"mnemonics": an array of strings, each a memory technique for a key word or phrase.`,
+	"examples":     `"examples": an array of two or three objects with the keys "sentence" (in the input language, using a key word or phrase) and "translation".`,
+	"frequency":    `"frequency": a string on how common the input is in everyday speech and writing, and whether a learner should prioritize memorizing it.`,
+}
+
+// queryAnswer is the structured answer to a query. Word is the input as
+// given, and FrequencyRank and FrequencyBand come from the bundled word
+// list, not from the model.
+type queryAnswer struct {
+	Word          string         `json:"word"`
+	Translation   string         `json:"translation,omitempty"`
+	Difficulties  []string       `json:"difficulties,omitempty"`
+	Mnemonics     []string       `json:"mnemonics,omitempty"`
+	Examples      []queryExample `json:"examples,omitempty"`
+	Frequency     string         `json:"frequency,omitempty"`
+	FrequencyRank int            `json:"frequency_rank,omitempty"`
+	FrequencyBand string         `json:"frequency_band,omitempty"`
+}
+
+type queryExample struct {
+	Sentence    string `json:"sentence"`
+	Translation string `json:"translation"`
+}
+
+func validateQueryFormat(format string) error {
+	if isStructuredQueryFormat(format) {
+		return nil
+	}
+	if err := validateFormat(format); err != nil {
+		return fmt.Errorf("invalid format: %s (expected %s, %s or %s)", format, strings.Join(render.Formats, ", "), queryMarkdown, queryPlain)
+	}
+	return nil
+}
+
+func isStructuredQueryFormat(format string) bool {
+	return format == render.JSON || format == queryMarkdown || format == queryPlain
+}
+
+func buildQueryJSONPrompts(p queryPrompt) (string, string, error) {
+	lines := make([]string, 0, len(p.Sections))
+	for _, section := range p.Sections {
+		lines = append(lines, "- "+queryFields[section])
+	}
+	vars := p.vars()
+	vars["fields"] = strings.Join(lines, "\n")
+	return buildPrompts("queryjson", vars)
+}
+
+// runStructuredQuery asks for a JSON answer, validates it and prints it in
+// format. Post-processing applies to markdown and plain output.
+func runStructuredQuery(ctx context.Context, out io.Writer, client llm.Client, req llm.ChatRequest, input string, sections []string, format string, post postprocess.Pipeline) error {
+	resp, err := client.Chat(ctx, req)
+	if err != nil {
+		return err
+	}
+	answer, err := parseQueryAnswer(resp.Content, input, sections)
+	if err != nil {
+		return err
+	}
+	if format == render.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(answer)
+	}
+	text := answer.plain()
+	if format == queryMarkdown {
+		text = answer.markdown()
+	}
+	text, err = post.Apply(ctx, text)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, strings.TrimRight(text, "\n"))
+	return err
+}
+
+// parseQueryAnswer decodes the model's JSON, keeping only the requested
+// sections and dropping blank entries.
+func parseQueryAnswer(content, input string, sections []string) (queryAnswer, error) {
+	var answer queryAnswer
+	if err := decodeJSONContent(content, &answer); err != nil {
+		return queryAnswer{}, err
+	}
+	answer.Word = strings.TrimSpace(input)
+	answer.Translation = strings.TrimSpace(answer.Translation)
+	answer.Frequency = strings.TrimSpace(answer.Frequency)
+	answer.FrequencyRank, answer.FrequencyBand = 0, ""
+	answer.Difficulties = nonBlank(answer.Difficulties)
+	answer.Mnemonics = nonBlank(answer.Mnemonics)
+	examples := answer.Examples[:0]
+	for _, example := range answer.Examples {
+		example.Sentence = strings.TrimSpace(example.Sentence)
+		example.Translation = strings.TrimSpace(example.Translation)
+		if example.Sentence != "" {
+			examples = append(examples, example)
+		}
+	}
+	answer.Examples = examples
+	requested := func(section string) bool { return slices.Contains(sections, section) }
+	if !requested("translation") {
+		answer.Translation = ""
+	} else if answer.Translation == "" {
+		return queryAnswer{}, fmt.Errorf("model returned no translation")
+	}
+	if !requested("difficulties") {
+		answer.Difficulties = nil
+	}
+	if !requested("mnemonics") {
+		answer.Mnemonics = nil
+	}
+	if !requested("examples") || len(answer.Examples) == 0 {
+		answer.Examples = nil
+	}
+	if !requested("frequency") {
+		answer.Frequency = ""
+	} else if isEnglishWord(answer.Word) {
+		if rank, ok := wordfreq.Rank(answer.Word); ok {
+			answer.FrequencyRank, answer.FrequencyBand = rank, wordfreq.Band(rank)
+		}
+	}
+	return answer, nil
+}
+
+func nonBlank(values []string) []string {
+	var kept []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
+
+// markdown renders the answer for reading, one heading per section.
+func (a queryAnswer) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", a.Word)
+	if a.Translation != "" {
+		fmt.Fprintf(&b, "\n## Translation\n\n%s\n", a.Translation)
+	}
+	writeList := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	}
+	writeList("Difficulties", a.Difficulties)
+	writeList("Mnemonics", a.Mnemonics)
+	if len(a.Examples) > 0 {
+		b.WriteString("\n## Examples\n\n")
+		for _, example := range a.Examples {
+			fmt.Fprintf(&b, "- %s\n  %s\n", example.Sentence, example.Translation)
+		}
+	}
+	if a.Frequency != "" || a.FrequencyRank > 0 {
+		b.WriteString("\n## Frequency\n\n")
+		if a.FrequencyRank > 0 {
+			fmt.Fprintf(&b, "Rank %d (%s) in the bundled English word list.\n", a.FrequencyRank, a.FrequencyBand)
+		}
+		if a.Frequency != "" {
+			if a.FrequencyRank > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "%s\n", a.Frequency)
+		}
+	}
+	return b.String()
+}
+
+// plain renders the answer for piping: one "key: value" line per item, so
+// grep and cut can pick fields. Examples put a tab between sentence and
+// translation.
+func (a queryAnswer) plain() string {
+	var b strings.Builder
+	line := func(key, value string) {
+		fmt.Fprintf(&b, "%s: %s\n", key, strings.Join(strings.Fields(value), " "))
+	}
+	line("word", a.Word)
+	if a.Translation != "" {
+		line("translation", a.Translation)
+	}
+	for _, item := range a.Difficulties {
+		line("difficulty", item)
+	}
+	for _, item := range a.Mnemonics {
+		line("mnemonic", item)
+	}
+	for _, example := range a.Examples {
+		fmt.Fprintf(&b, "example: %s\t%s\n", strings.Join(strings.Fields(example.Sentence), " "), strings.Join(strings.Fields(example.Translation), " "))
+	}
+	if a.FrequencyRank > 0 {
+		line("frequency_rank", fmt.Sprintf("%d (%s)", a.FrequencyRank, a.FrequencyBand))
+	}
+	if a.Frequency != "" {
+		line("frequency", a.Frequency)
+	}
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/render"
+)
+
+const testQueryAnswer = "```json\n" + `{"word":"ignored","translation":" 码头 ","difficulties":["Countable noun.",""],` +
+	`"mnemonics":["Pier sounds like peer: peer out from the pier."],` +
+	`"examples":[{"sentence":"We met on the pier.","translation":"我们在码头见面。"}]}` + "\n```"
+
+func TestRunStructuredQuery(t *testing.T) {
+	client := &fakeClient{resp: llm.ChatResponse{Content: testQueryAnswer}}
+	sections := []string{"translation", "difficulties", "mnemonics"}
+	cases := map[string]string{
+		render.JSON: `{
+  "word": "pier",
+  "translation": "码头",
+  "difficulties": [
+    "Countable noun."
+  ],
+  "mnemonics": [
+    "Pier sounds like peer: peer out from the pier."
+  ]
+}
+`,
+		queryMarkdown: "# pier\n\n## Translation\n\n码头\n\n## Difficulties\n\n- Countable noun.\n\n## Mnemonics\n\n- Pier sounds like peer: peer out from the pier.\n",
+		queryPlain:    "word: pier\ntranslation: 码头\ndifficulty: Countable noun.\nmnemonic: Pier sounds like peer: peer out from the pier.\n",
+	}
+	for format, want := range cases {
+		var out bytes.Buffer
+		if err := runStructuredQuery(context.Background(), &out, client, llm.ChatRequest{}, " pier\n", sections, format, nil); err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if out.String() != want {
+			t.Fatalf("%s: unexpected output:\n%s", format, out.String())
+		}
+	}
+}
+
+func TestParseQueryAnswer(t *testing.T) {
+	answer, err := parseQueryAnswer(testQueryAnswer, "pier", []string{"examples"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if answer.Translation != "" || len(answer.Examples) != 1 || answer.plain() != "word: pier\nexample: We met on the pier.\t我们在码头见面。\n" {
+		t.Fatalf("unexpected answer: %+v", answer)
+	}
+	if _, err := parseQueryAnswer(`{"difficulties":["x"]}`, "pier", []string{"translation"}); err == nil {
+		t.Fatalf("expected an error for a missing translation")
+	}
+	if _, err := parseQueryAnswer("not json", "pier", []string{"translation"}); err == nil {
+		t.Fatalf("expected an error for invalid JSON")
+	}
+}
+
+func TestBuildQueryJSONPrompts(t *testing.T) {
+	systemPrompt, userPrompt, err := buildQueryJSONPrompts(queryPrompt{Input: "hello", InputLanguage: "English", OutputLanguage: "Simplified Chinese", Sections: []string{"translation", "examples"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, `- "examples":`) || strings.Contains(systemPrompt, `"mnemonics"`) {
+		t.Fatalf("unexpected fields in system prompt: %q", systemPrompt)
+	}
+	if !strings.Contains(userPrompt, "<input>hello</input>") {
+		t.Fatalf("unexpected user prompt: %q", userPrompt)
+	}
+	if err := validateQueryFormat(queryPlain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := validateQueryFormat("yaml"); err == nil {
+		t.Fatalf("expected an error for an unknown format")
+	}
+}
+
+func TestQueryFrequency(t *testing.T) {
+	sections := []string{"translation", "frequency"}
+	answer, err := parseQueryAnswer(`{"translation":"拿","frequency":" Very common; learn it first. ","frequency_rank":999}`, "took", sections)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if answer.FrequencyRank == 0 || answer.FrequencyRank > 100 || answer.FrequencyBand != "top 100" || answer.Frequency != "Very common; learn it first." {
+		t.Fatalf("unexpected frequency: %+v", answer)
+	}
+	if !strings.Contains(answer.markdown(), "## Frequency\n\nRank ") || !strings.Contains(answer.plain(), "frequency: Very common; learn it first.\n") {
+		t.Fatalf("unexpected rendering:\n%s\n%s", answer.markdown(), answer.plain())
+	}
+
+	answer, err = parseQueryAnswer(`{"translation":"拿","frequency":"Common."}`, "take", sections[:1])
+	if err != nil || answer.Frequency != "" || answer.FrequencyRank != 0 {
+		t.Fatalf("expected no frequency without the section: %+v, %v", answer, err)
+	}
+
+	if note := frequencyNote("serendipity", sections); !strings.Contains(note, "is not among") {
+		t.Fatalf("unexpected note: %q", note)
+	}
+	if note := frequencyNote("take", sections); !strings.Contains(note, `"take" ranks`) {
+		t.Fatalf("unexpected note: %q", note)
+	}
+	for _, input := range []string{"take off", "拿"} {
+		if note := frequencyNote(input, sections); note != "" {
+			t.Fatalf("expected no note for %q, got %q", input, note)
+		}
+	}
+	if note := frequencyNote("take", sections[:1]); note != "" {
+		t.Fatalf("expected no note without the section, got %q", note)
+	}
+}
@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+
+	"dict-be/internal/known"
+	"dict-be/internal/vocab"
+
+	"github.com/spf13/cobra"
+)
+
+// Quiz modes.
+const (
+	quizChoice    = "choice"
+	quizCloze     = "cloze"
+	quizTranslate = "translate"
+)
+
+type quizOptions struct {
+	Mode  string
+	Count int
+}
+
+// quizQuestion is what the model wrote about one saved word.
+type quizQuestion struct {
+	ID          int      `json:"-"`
+	Word        string   `json:"word"`
+	Meaning     string   `json:"meaning"`
+	Distractors []string `json:"distractors"`
+	Cloze       string   `json:"cloze"`
+	Answer      string   `json:"answer"`
+}
+
+func newQuizCmd() *cobra.Command {
+	opts := &quizOptions{}
+	cmd := &cobra.Command{
+		Use:   "quiz",
+		Short: "Quiz yourself on saved words",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runQuiz(cmd, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Mode, "mode", quizChoice, "question type: choice (pick the meaning), cloze (fill in the blank) or translate (type the word for a meaning)")
+	cmd.Flags().IntVarP(&opts.Count, "count", "n", 10, "number of questions")
+	return cmd
+}
+
+func runQuiz(cmd *cobra.Command, opts *quizOptions) error {
+	switch opts.Mode {
+	case quizChoice, quizCloze, quizTranslate:
+	default:
+		return fmt.Errorf("invalid --mode: %s (expected choice, cloze or translate)", opts.Mode)
+	}
+	if opts.Count < 1 {
+		return fmt.Errorf("invalid --count: %d", opts.Count)
+	}
+	store, err := newVocabStore()
+	if err != nil {
+		return err
+	}
+	notebook, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if len(notebook.Entries) == 0 {
+		return fmt.Errorf("no saved words; save some with query --save or define --save")
+	}
+	entries := pickQuizEntries(notebook.Entries, opts.Count)
+	content, err := completePrompt(commandContext(cmd), cmd, "quiz", map[string]string{
+		"words": quizWordList(entries),
+	})
+	if err != nil {
+		return err
+	}
+	questions, err := parseQuizQuestions(content, entries, opts.Mode)
+	if err != nil {
+		return err
+	}
+	answers, err := askQuiz(cmd.InOrStdin(), cmd.OutOrStdout(), questions, opts.Mode, rand.Shuffle)
+	if err != nil {
+		return err
+	}
+	if len(answers) == 0 {
+		return nil
+	}
+	if err := store.RecordAnswers(answers); err != nil {
+		return err
+	}
+	correct := 0
+	for _, answer := range answers {
+		if answer.Correct {
+			correct++
+		}
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "\n%d of %d correct\n", correct, len(answers))
+	return nil
+}
+
+// pickQuizEntries picks the count words answered worst so far, those
+// quizzed longest ago first among equals.
+func pickQuizEntries(entries []vocab.Entry, count int) []vocab.Entry {
+	picked := append([]vocab.Entry(nil), entries...)
+	sort.SliceStable(picked, func(i, j int) bool {
+		if a, b := picked[i].Accuracy(), picked[j].Accuracy(); a != b {
+			return a < b
+		}
+		a, b := picked[i].LastQuizzed, picked[j].LastQuizzed
+		return a == nil && b != nil || a != nil && b != nil && a.Before(*b)
+	})
+	if len(picked) > count {
+		picked = picked[:count]
+	}
+	return picked
+}
+
+// quizWordList lists the words for the prompt, one per line.
+func quizWordList(entries []vocab.Entry) string {
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		language := firstNonEmpty(entry.OutputLanguage, "English")
+		line := fmt.Sprintf("- %s (explain in %s", entry.Word, language)
+		if entry.Translation != "" {
+			line += "; saved translation: " + strings.Join(strings.Fields(entry.Translation), " ")
+		}
+		lines[i] = line + ")"
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseQuizQuestions matches the model's questions to the picked entries,
+// keeping those complete enough for mode in the order of entries.
+func parseQuizQuestions(content string, entries []vocab.Entry, mode string) ([]quizQuestion, error) {
+	var answer struct {
+		Questions []quizQuestion `json:"questions"`
+	}
+	if err := decodeJSONContent(content, &answer); err != nil {
+		return nil, err
+	}
+	byWord := make(map[string]quizQuestion, len(answer.Questions))
+	for _, question := range answer.Questions {
+		byWord[known.Normalize(question.Word)] = question
+	}
+	var questions []quizQuestion
+	for _, entry := range entries {
+		question, ok := byWord[known.Normalize(entry.Word)]
+		if !ok {
+			continue
+		}
+		question.ID = entry.ID
+		question.Word = entry.Word
+		question.Meaning = strings.TrimSpace(question.Meaning)
+		question.Distractors = nonBlank(question.Distractors)
+		question.Cloze = strings.TrimSpace(question.Cloze)
+		question.Answer = strings.TrimSpace(question.Answer)
+		if question.complete(mode) {
+			questions = append(questions, question)
+		}
+	}
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("model returned no usable %s questions", mode)
+	}
+	return questions, nil
+}
+
+func (q quizQuestion) complete(mode string) bool {
+	switch mode {
+	case quizChoice:
+		return q.Meaning != "" && len(q.Distractors) > 0
+	case quizCloze:
+		return strings.Contains(q.Cloze, "____") && q.Answer != ""
+	default:
+		return q.Meaning != ""
+	}
+}
+
+// askQuiz asks each question on out and reads the answers from in, one per
+// line. It stops early, without error, when in ends.
+func askQuiz(in io.Reader, out io.Writer, questions []quizQuestion, mode string, shuffle func(n int, swap func(i, j int))) ([]vocab.Answer, error) {
+	scanner := bufio.NewScanner(in)
+	var answers []vocab.Answer
+	for i, question := range questions {
+		var options []string
+		fmt.Fprintf(out, "\n%d/%d ", i+1, len(questions))
+		switch mode {
+		case quizChoice:
+			options = append([]string{question.Meaning}, question.Distractors...)
+			shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+			fmt.Fprintf(out, "%s\n", question.Word)
+			for n, option := range options {
+				fmt.Fprintf(out, "  %d) %s\n", n+1, option)
+			}
+		case quizCloze:
+			fmt.Fprintf(out, "%s\n", question.Cloze)
+		default:
+			fmt.Fprintf(out, "%s\n", question.Meaning)
+		}
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return answers, scanner.Err()
+		}
+		reply := strings.TrimSpace(scanner.Text())
+		var correct bool
+		var solution string
+		switch mode {
+		case quizChoice:
+			n, err := strconv.Atoi(reply)
+			correct = err == nil && n >= 1 && n <= len(options) && options[n-1] == question.Meaning
+			solution = question.Meaning
+		case quizCloze:
+			correct = known.Normalize(reply) == known.Normalize(question.Answer) || known.Normalize(reply) == known.Normalize(question.Word)
+			solution = question.Answer
+		default:
+			correct = known.Normalize(reply) == known.Normalize(question.Word)
+			solution = question.Word
+		}
+		if correct {
+			fmt.Fprintln(out, "Correct.")
+		} else {
+			fmt.Fprintf(out, "Wrong: %s\n", solution)
+		}
+		answers = append(answers, vocab.Answer{ID: question.ID, Correct: correct})
+	}
+	return answers, nil
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"dict-be/internal/vocab"
+)
+
+const testQuizQuestions = `{"questions":[` +
+	`{"word":"Pier","meaning":"码头","distractors":["桥墩"," ","海滩"],"cloze":"The ferry left the ____.","answer":"pier"},` +
+	`{"word":"run","meaning":"跑","distractors":[],"cloze":"She ____ home yesterday.","answer":"ran"},` +
+	`{"word":"unlisted","meaning":"x","distractors":["y"]}]}`
+
+func TestPickQuizEntries(t *testing.T) {
+	earlier := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	entries := []vocab.Entry{
+		{ID: 1, Quizzed: 2, Correct: 2, LastQuizzed: &earlier},
+		{ID: 2, Quizzed: 2, Correct: 1, LastQuizzed: &later},
+		{ID: 3, Quizzed: 1, Correct: 0, LastQuizzed: &later},
+		{ID: 4},
+		{ID: 5, Quizzed: 2, Correct: 1, LastQuizzed: &earlier},
+	}
+	var ids []int
+	for _, entry := range pickQuizEntries(entries, 4) {
+		ids = append(ids, entry.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{4, 3, 5, 2}) {
+		t.Fatalf("unexpected pick order: %v", ids)
+	}
+}
+
+func TestParseQuizQuestions(t *testing.T) {
+	entries := []vocab.Entry{{ID: 3, Word: "run"}, {ID: 1, Word: "pier"}}
+	questions, err := parseQuizQuestions(testQuizQuestions, entries, quizChoice)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(questions) != 1 || questions[0].ID != 1 || questions[0].Word != "pier" || !reflect.DeepEqual(questions[0].Distractors, []string{"桥墩", "海滩"}) {
+		t.Fatalf("expected only pier to have choices, got %+v", questions)
+	}
+	if questions, err = parseQuizQuestions(testQuizQuestions, entries, quizCloze); err != nil || len(questions) != 2 || questions[0].Answer != "ran" {
+		t.Fatalf("unexpected cloze questions: %+v, %v", questions, err)
+	}
+	if _, err := parseQuizQuestions(`{"questions":[]}`, entries, quizTranslate); err == nil {
+		t.Fatalf("expected an error for no questions")
+	}
+	if got := quizWordList([]vocab.Entry{{Word: "pier", OutputLanguage: "Chinese", Translation: "码头\n"}, {Word: "run"}}); got !=
+		"- pier (explain in Chinese; saved translation: 码头)\n- run (explain in English)" {
+		t.Fatalf("unexpected word list: %q", got)
+	}
+}
+
+func TestAskQuiz(t *testing.T) {
+	entries := []vocab.Entry{{ID: 1, Word: "pier"}, {ID: 3, Word: "run"}}
+	reverse := func(n int, swap func(i, j int)) {
+		for i := 0; i < n/2; i++ {
+			swap(i, n-1-i)
+		}
+	}
+	choice, _ := parseQuizQuestions(testQuizQuestions, entries[:1], quizChoice)
+	var out bytes.Buffer
+	answers, err := askQuiz(strings.NewReader("2\n"), &out, choice, quizChoice, reverse)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Reversed, the options are 海滩, 桥墩, 码头, so 2 is wrong.
+	if !reflect.DeepEqual(answers, []vocab.Answer{{ID: 1}}) || !strings.Contains(out.String(), "  3) 码头\n> Wrong: 码头\n") {
+		t.Fatalf("unexpected choice quiz: %+v\n%s", answers, out.String())
+	}
+
+	cloze, _ := parseQuizQuestions(testQuizQuestions, entries, quizCloze)
+	out.Reset()
+	answers, err = askQuiz(strings.NewReader(" Pier\nRAN\n"), &out, cloze, quizCloze, reverse)
+	if err != nil || !reflect.DeepEqual(answers, []vocab.Answer{{ID: 1, Correct: true}, {ID: 3, Correct: true}}) {
+		t.Fatalf("unexpected cloze answers: %+v, %v", answers, err)
+	}
+
+	translate, _ := parseQuizQuestions(testQuizQuestions, entries, quizTranslate)
+	out.Reset()
+	answers, err = askQuiz(strings.NewReader("pier\n"), &out, translate, quizTranslate, reverse)
+	if err != nil || !reflect.DeepEqual(answers, []vocab.Answer{{ID: 1, Correct: true}}) {
+		t.Fatalf("expected the quiz to stop when input ends, got %+v, %v", answers, err)
+	}
+	if !strings.HasPrefix(out.String(), "\n1/2 码头\n> Correct.\n\n2/2 跑\n> ") {
+		t.Fatalf("unexpected translate quiz:\n%q", out.String())
+	}
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"dict-be/internal/vocab"
+
+	"github.com/spf13/cobra"
+)
+
+type readOptions struct {
+	InputFile      string
+	InputLanguage  string
+	OutputLanguage string
+	Level          string
+	NoKnown        bool
+	Save           bool
+	Output         outputOptions
+}
+
+func newReadCmd() *cobra.Command {
+	opts := &readOptions{}
+	cmd := &cobra.Command{
+		Use:   "read [text...]",
+		Short: "Annotate difficult words in an article for a learner level",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRead(cmd, opts, args)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.InputFile, "file", "F", "", "article file, use -F- for stdin")
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "o")
+	addLevelFlag(cmd, &opts.Level, "B1")
+	cmd.Flags().BoolVar(&opts.NoKnown, "no-known", false, "also gloss words in the known-words list")
+	cmd.Flags().BoolVar(&opts.Save, "save", false, "save the vocabulary list to the vocabulary notebook")
+	opts.Output.addFlags(cmd)
+	return cmd
+}
+
+func runRead(cmd *cobra.Command, opts *readOptions, args []string) error {
+	if err := opts.Output.validate(); err != nil {
+		return err
+	}
+	level := learnerLevel(opts.Level, "B1")
+	input, err := readInput(args, opts.InputFile, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(input) == "" {
+		return fmt.Errorf("input is required")
+	}
+	knownInstruction, err := knownWordsInstruction(input, !opts.NoKnown)
+	if err != nil {
+		return err
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(input, opts.InputLanguage, opts.OutputLanguage)
+	answer, err := runPromptCommandAnswer(cmd, &opts.Output, "read", map[string]string{
+		"input":             input,
+		"input_language":    inputLanguage,
+		"output_language":   outputLanguage,
+		"level":             level,
+		"known_instruction": knownInstruction,
+	})
+	if err != nil || !opts.Save {
+		return err
+	}
+	entries := readVocabEntries(answer, inputLanguage, outputLanguage)
+	if len(entries) == 0 {
+		fmt.Fprintln(cmd.ErrOrStderr(), "no vocabulary list to save")
+		return nil
+	}
+	store, err := newVocabStore()
+	if err != nil {
+		return err
+	}
+	if _, err := store.SaveAll(entries); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "saved %d words to the vocabulary notebook\n", len(entries))
+	return nil
+}
+
+// readVocabEntries turns the vocabulary list after the last "---" line of
+// a read answer, one "item - part of speech - meaning" per line, into
+// notebook entries. Lines without a meaning are skipped.
+func readVocabEntries(answer, inputLanguage, outputLanguage string) []vocab.Entry {
+	lines := strings.Split(answer, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) == "---" {
+			lines = lines[i+1:]
+			break
+		}
+		if i == 0 {
+			return nil
+		}
+	}
+	var entries []vocab.Entry
+	for _, line := range lines {
+		line = trimListMarker(strings.TrimSpace(line))
+		parts := strings.Split(line, " - ")
+		if len(parts) < 2 {
+			continue
+		}
+		word, meaning := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[len(parts)-1])
+		if word == "" || meaning == "" {
+			continue
+		}
+		entries = append(entries, vocab.Entry{
+			Word:           word,
+			Command:        "read",
+			InputLanguage:  inputLanguage,
+			OutputLanguage: outputLanguage,
+			Translation:    meaning,
+			Content:        line,
+		})
+	}
+	return entries
+}
+
+// trimListMarker drops a leading "-", "*", "•" or "1." list marker.
+func trimListMarker(line string) string {
+	for _, marker := range []string{"- ", "* ", "• "} {
+		if rest, ok := strings.CutPrefix(line, marker); ok {
+			return strings.TrimSpace(rest)
+		}
+	}
+	if number, rest, ok := strings.Cut(line, ". "); ok && number != "" && strings.Trim(number, "0123456789") == "" {
+		return strings.TrimSpace(rest)
+	}
+	return line
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildReadPrompts(t *testing.T) {
+	systemPrompt, _, err := buildPrompts("read", map[string]string{
+		"input":             "text",
+		"input_language":    "English",
+		"output_language":   "Simplified Chinese",
+		"level":             "B1",
+		"known_instruction": "",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, "level B1") || strings.Contains(systemPrompt, "{{") {
+		t.Fatalf("unexpected system prompt: %q", systemPrompt)
+	}
+}
+
+func TestReadVocabEntries(t *testing.T) {
+	answer := "The pier (码头) was crowded - again.\n\n---\n- pier - noun - 码头\n2. crowded - adj. - 拥挤的\nnote without meaning\n"
+	entries := readVocabEntries(answer, "English", "Simplified Chinese")
+	if len(entries) != 2 {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if entries[0].Word != "pier" || entries[0].Translation != "码头" || entries[0].Command != "read" ||
+		entries[0].Content != "pier - noun - 码头" || entries[0].OutputLanguage != "Simplified Chinese" {
+		t.Fatalf("unexpected entry: %+v", entries[0])
+	}
+	if entries[1].Word != "crowded" || entries[1].Translation != "拥挤的" {
+		t.Fatalf("unexpected entry: %+v", entries[1])
+	}
+	if entries := readVocabEntries("no list - here", "English", "Simplified Chinese"); len(entries) != 0 {
+		t.Fatalf("expected no entries without a list: %+v", entries)
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"dict-be/internal/llm"
+)
+
+// reasoningClient prints the model's reasoning to out, ahead of and
+// separated from the answer, for --show-reasoning.
+type reasoningClient struct {
+	client  llm.Client
+	out     io.Writer
+	printed bool
+	closed  bool
+}
+
+func showReasoning(client llm.Client, out io.Writer) llm.Client {
+	return &reasoningClient{client: client, out: out}
+}
+
+func (c *reasoningClient) Unwrap() llm.Client { return c.client }
+
+func (c *reasoningClient) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	req.ReasoningHandler = c.print
+	resp, err := c.client.Chat(ctx, req)
+	c.close()
+	return resp, err
+}
+
+func (c *reasoningClient) ChatStream(ctx context.Context, req llm.ChatRequest, handle llm.StreamHandler) (llm.ChatResponse, error) {
+	req.ReasoningHandler = c.print
+	wrapped := handle
+	if handle != nil {
+		wrapped = func(delta string) error {
+			c.close()
+			return handle(delta)
+		}
+	}
+	resp, err := c.client.ChatStream(ctx, req, wrapped)
+	c.close()
+	return resp, err
+}
+
+func (c *reasoningClient) print(delta string) error {
+	c.printed = true
+	_, err := fmt.Fprint(c.out, delta)
+	return err
+}
+
+// close ends the reasoning block once, before the first answer delta.
+func (c *reasoningClient) close() {
+	if c.printed && !c.closed {
+		fmt.Fprint(c.out, "\n\n")
+		c.closed = true
+	}
+}
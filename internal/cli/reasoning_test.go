@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"dict-be/internal/llm"
+)
+
+type reasoningFakeClient struct{}
+
+func (reasoningFakeClient) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	return llm.ChatResponse{}, nil
+}
+
+func (reasoningFakeClient) ChatStream(ctx context.Context, req llm.ChatRequest, handle llm.StreamHandler) (llm.ChatResponse, error) {
+	_ = req.ReasoningHandler("thinking")
+	_ = handle("answer")
+	return llm.ChatResponse{Content: "answer", Reasoning: "thinking"}, nil
+}
+
+func TestShowReasoningSeparatesAnswer(t *testing.T) {
+	var stderr, stdout bytes.Buffer
+	client := showReasoning(reasoningFakeClient{}, &stderr)
+	_, err := client.ChatStream(context.Background(), llm.ChatRequest{}, func(delta string) error {
+		if stderr.String() != "thinking\n\n" {
+			t.Fatalf("reasoning not closed before answer: %q", stderr.String())
+		}
+		stdout.WriteString(delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.String() != "answer" || stderr.String() != "thinking\n\n" {
+		t.Fatalf("unexpected output: %q %q", stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"dict-be/internal/document"
+	"dict-be/internal/jobs"
+)
+
+func newJobStore() (*jobs.Store, error) {
+	dir, err := jobs.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return jobs.NewStore(dir), nil
+}
+
+// newTranslateJob lists the paragraphs of input as job chunks, keeping the
+// translations that previous already completed. In markdown mode, front
+// matter and code blocks are left out, since they are never translated.
+func newTranslateJob(previous *jobs.Job, path, output, inputLanguage, outputLanguage, input string, markdown bool) *jobs.Job {
+	now := time.Now()
+	job := &jobs.Job{
+		ID:             jobs.NewID(now),
+		Command:        "translate",
+		Input:          path,
+		Output:         output,
+		InputLanguage:  inputLanguage,
+		OutputLanguage: outputLanguage,
+		Created:        now,
+	}
+	if previous != nil {
+		job.ID = previous.ID
+		job.Created = previous.Created
+	}
+	translator := document.NewTranslator(nil)
+	if markdown {
+		translator.Markdown()
+	}
+	for _, paragraph := range translator.Paragraphs(input) {
+		job.Chunks = append(job.Chunks, jobs.Chunk{Source: paragraph, Status: jobs.StatusPending})
+	}
+	if previous != nil {
+		for _, chunk := range previous.Chunks {
+			if chunk.Status == jobs.StatusDone {
+				job.Complete(chunk.Source, chunk.Target)
+			}
+		}
+	}
+	return job
+}
+
+// recordJobProgress saves each completed paragraph to the job before the
+// next one is requested.
+func recordJobProgress(store *jobs.Store, job *jobs.Job, translate document.TranslateFunc) document.TranslateFunc {
+	return func(ctx context.Context, text string) (string, error) {
+		output, err := translate(ctx, text)
+		if err != nil {
+			return "", err
+		}
+		job.Complete(text, output)
+		if err := store.Save(job); err != nil {
+			return "", err
+		}
+		return output, nil
+	}
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"dict-be/internal/jobs"
+)
+
+func TestRecordJobProgressResumes(t *testing.T) {
+	store := jobs.NewStore(t.TempDir())
+	input := "one\n\ntwo\n\nthree"
+	job := newTranslateJob(nil, "doc.md", "out.md", "en", "zh", input, false)
+
+	calls := 0
+	failing := recordJobProgress(store, job, func(ctx context.Context, text string) (string, error) {
+		if text == "three" {
+			return "", errors.New("interrupted")
+		}
+		calls++
+		return strings.ToUpper(text), nil
+	})
+	for _, paragraph := range []string{"one", "two", "three"} {
+		_, _ = failing(context.Background(), paragraph)
+	}
+
+	saved, err := store.Load(job.ID)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if saved.Done() != 2 || calls != 2 {
+		t.Fatalf("unexpected progress: %+v", saved)
+	}
+
+	resumed := newTranslateJob(saved, "doc.md", "out.md", "en", "zh", input+"\n\nfour", false)
+	if resumed.ID != job.ID || resumed.Done() != 2 || len(resumed.Chunks) != 4 {
+		t.Fatalf("unexpected resumed job: %+v", resumed)
+	}
+	if resumed.Chunks[1].Target != "TWO" || resumed.Chunks[3].Status != jobs.StatusPending {
+		t.Fatalf("unexpected chunks: %+v", resumed.Chunks)
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"dict-be/internal/document"
+	"dict-be/internal/llm"
+)
+
+// reviewResult is the editor pass output for one paragraph.
+type reviewResult struct {
+	Translation string   `json:"translation"`
+	Changes     []string `json:"changes"`
+}
+
+// reviewLog collects the fixes made by the editor pass during one run.
+type reviewLog struct {
+	changes []string
+}
+
+// withReview runs a second pass over each draft translation in which the
+// model acts as an editor, optionally with a different model, and records
+// the changes it lists.
+func withReview(client llm.Client, model, inputLanguage, outputLanguage string, translate document.TranslateFunc, log *reviewLog) document.TranslateFunc {
+	return func(ctx context.Context, text string) (string, error) {
+		draft, err := translate(ctx, text)
+		if err != nil {
+			return "", err
+		}
+		systemPrompt, userPrompt, err := buildPrompts("review", map[string]string{
+			"input":           text,
+			"draft":           draft,
+			"input_language":  inputLanguage,
+			"output_language": outputLanguage,
+		})
+		if err != nil {
+			return "", err
+		}
+		resp, err := client.Chat(ctx, llm.ChatRequest{
+			Model:    model,
+			Messages: buildMessages(systemPrompt, userPrompt),
+		})
+		if err != nil {
+			return "", fmt.Errorf("review: %w", err)
+		}
+		var result reviewResult
+		if err := decodeJSONContent(resp.Content, &result); err != nil {
+			return "", fmt.Errorf("review: %w", err)
+		}
+		translation := strings.TrimSpace(result.Translation)
+		if translation == "" {
+			return "", fmt.Errorf("review: model returned an empty translation")
+		}
+		for _, change := range result.Changes {
+			if change = strings.TrimSpace(change); change != "" {
+				log.changes = append(log.changes, change)
+			}
+		}
+		return translation, nil
+	}
+}
+
+// report prints the collected changes and clears them for the next run.
+func (l *reviewLog) report(out io.Writer) {
+	if len(l.changes) == 0 {
+		fmt.Fprintln(out, "review: no changes")
+		return
+	}
+	fmt.Fprintf(out, "review: %d changes\n", len(l.changes))
+	for _, change := range l.changes {
+		fmt.Fprintf(out, "- %s\n", change)
+	}
+	l.changes = nil
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"dict-be/internal/llm"
+)
+
+type reviewFakeClient struct {
+	fakeClient
+	requests []llm.ChatRequest
+}
+
+func (f *reviewFakeClient) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	f.requests = append(f.requests, req)
+	return f.resp, f.err
+}
+
+func TestWithReview(t *testing.T) {
+	client := &reviewFakeClient{fakeClient: fakeClient{resp: llm.ChatResponse{
+		Content: "```json\n{\"translation\": \"Bonjour le monde\", \"changes\": [\"fixed word order\", \" \"]}\n```",
+	}}}
+	draft := func(ctx context.Context, text string) (string, error) {
+		return "Monde bonjour", nil
+	}
+	log := &reviewLog{}
+	translate := withReview(client, "editor-model", "English", "French", draft, log)
+	output, err := translate(context.Background(), "Hello world")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output != "Bonjour le monde" {
+		t.Fatalf("unexpected output: %q", output)
+	}
+	req := client.requests[0]
+	if req.Model != "editor-model" || !strings.Contains(req.Messages[1].Content, "<draft>Monde bonjour</draft>") {
+		t.Fatalf("unexpected review request: %+v", req)
+	}
+
+	var out bytes.Buffer
+	log.report(&out)
+	if out.String() != "review: 1 changes\n- fixed word order\n" {
+		t.Fatalf("unexpected report: %q", out.String())
+	}
+	out.Reset()
+	log.report(&out)
+	if out.String() != "review: no changes\n" {
+		t.Fatalf("unexpected report after reset: %q", out.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"dict-be/internal/config"
 
@@ -13,7 +14,15 @@ import (
 )
 
 type Options struct {
-	Config string
+	Config  string
+	Profile string
+	Force   bool
+	Timeout time.Duration
+	Proxy   string
+	Record  string
+	Replay  string
+	Poll    bool
+	Verbose bool
 }
 
 func NewRootCmd() *cobra.Command {
@@ -25,6 +34,9 @@ func NewRootCmd() *cobra.Command {
 	root := &cobra.Command{
 		Use:   "dict-be",
 		Short: "dict-be - CLI starter",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			learnPreferences(cmd)
+		},
 	}
 
 	cobra.OnInitialize(func() {
@@ -38,8 +50,92 @@ func NewRootCmd() *cobra.Command {
 		"config file (default: ~/.dict-be.yml)",
 	)
 	_ = viper.BindPFlag("config", root.PersistentFlags().Lookup("config"))
+	root.PersistentFlags().StringVar(
+		&opts.Profile,
+		"profile",
+		"",
+		"provider profile from the profiles config section",
+	)
+	_ = viper.BindPFlag("profile", root.PersistentFlags().Lookup("profile"))
+	root.PersistentFlags().BoolVar(
+		&opts.Force,
+		"force",
+		false,
+		"send input even if the secret scanner flags it",
+	)
+	root.PersistentFlags().BoolVarP(
+		&opts.Verbose,
+		"verbose",
+		"v",
+		false,
+		"log LLM requests to stderr, same as logging.level: debug",
+	)
+	root.PersistentFlags().DurationVar(
+		&opts.Timeout,
+		"timeout",
+		0,
+		"limit each LLM request, overriding llm.timeout (e.g. 30s)",
+	)
+	_ = viper.BindPFlag("timeout", root.PersistentFlags().Lookup("timeout"))
+	root.PersistentFlags().StringVar(
+		&opts.Proxy,
+		"proxy",
+		"",
+		"send LLM requests through this http or socks5 proxy, overriding llm.proxy",
+	)
+	_ = viper.BindPFlag("proxy", root.PersistentFlags().Lookup("proxy"))
+	root.PersistentFlags().StringVar(
+		&opts.Record,
+		"record",
+		"",
+		"save every LLM HTTP exchange to this cassette file",
+	)
+	_ = viper.BindPFlag("record", root.PersistentFlags().Lookup("record"))
+	root.PersistentFlags().StringVar(
+		&opts.Replay,
+		"replay",
+		"",
+		"answer LLM requests from this cassette file instead of the network",
+	)
+	_ = viper.BindPFlag("replay", root.PersistentFlags().Lookup("replay"))
+	root.PersistentFlags().BoolVar(
+		&opts.Poll,
+		"poll",
+		false,
+		"send streamed requests without streaming and print the answer word by word, for gateways that buffer streams",
+	)
+	_ = viper.BindPFlag("poll", root.PersistentFlags().Lookup("poll"))
 
 	root.AddCommand(newQueryCmd())
+	root.AddCommand(newDefineCmd())
+	root.AddCommand(newPronounceCmd())
+	root.AddCommand(newExamplesCmd())
+	root.AddCommand(newSynonymsCmd())
+	root.AddCommand(newCollocationsCmd())
+	root.AddCommand(newEtymologyCmd())
+	root.AddCommand(newTranslateCmd())
+	root.AddCommand(newDocCmd())
+	root.AddCommand(newSubsCmd())
+	root.AddCommand(newBatchCmd())
+	root.AddCommand(newAnnotateCmd())
+	root.AddCommand(newReadCmd())
+	root.AddCommand(newSummarizeCmd())
+	root.AddCommand(newLocalizeFormatCmd())
+	root.AddCommand(newTermsCmd())
+	root.AddCommand(newAcronymCmd())
+	root.AddCommand(newClassifierCmd())
+	root.AddCommand(newHomophonesCmd())
+	root.AddCommand(newMatchCmd())
+	root.AddCommand(newSegmentCmd())
+	root.AddCommand(newDraftCmd())
+	root.AddCommand(newKnownCmd())
+	root.AddCommand(newVocabCmd())
+	root.AddCommand(newQuizCmd())
+	root.AddCommand(newHistoryCmd())
+	root.AddCommand(newCacheCmd())
+	root.AddCommand(newDifficultyCmd())
+	root.AddCommand(newDigestCmd())
+	root.AddCommand(newPrefsCmd())
 	root.AddCommand(newVersionCmd())
 	root.AddCommand(newLLMCmd())
 	return root
@@ -61,6 +157,14 @@ func initConfig(configFile string) {
 	viper.SetDefault("llm.model", "")
 	viper.SetDefault("llm.token", "")
 	viper.SetDefault("llm.type", "")
+	viper.SetDefault("notify", false)
+	viper.SetDefault("secrets.mode", "warn")
+	viper.SetDefault("history.enabled", true)
+	viper.SetDefault("cache.enabled", true)
+	viper.SetDefault("cache.ttl", "168h")
+	viper.SetDefault("cache.max_mb", 100)
+	viper.SetDefault("query.default_in", "English")
+	viper.SetDefault("query.default_out", "Simplified Chinese")
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"dict-be/internal/config"
+	"dict-be/internal/llm"
+	"dict-be/internal/secrets"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	secretsModeOff   = "off"
+	secretsModeWarn  = "warn"
+	secretsModeBlock = "block"
+)
+
+// secretGuard scans user messages for credentials and internal host names
+// before they reach a third-party provider.
+type secretGuard struct {
+	client  llm.Client
+	domains []string
+	block   bool
+	warn    io.Writer
+}
+
+// guardClient wraps client according to secrets.mode. --force sends
+// flagged input anyway in block mode; warnings are still printed.
+func guardClient(cmd *cobra.Command, cfg config.Config, client llm.Client) llm.Client {
+	mode := firstNonEmpty(cfg.Secrets.Mode, secretsModeWarn)
+	if mode == secretsModeOff {
+		return client
+	}
+	force, _ := cmd.Flags().GetBool("force")
+	return &secretGuard{
+		client:  client,
+		domains: cfg.Secrets.InternalDomains,
+		block:   mode == secretsModeBlock && !force,
+		warn:    cmd.ErrOrStderr(),
+	}
+}
+
+func (g *secretGuard) Unwrap() llm.Client { return g.client }
+
+func (g *secretGuard) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	if err := g.check(req); err != nil {
+		return llm.ChatResponse{}, err
+	}
+	return g.client.Chat(ctx, req)
+}
+
+func (g *secretGuard) ChatStream(ctx context.Context, req llm.ChatRequest, handle llm.StreamHandler) (llm.ChatResponse, error) {
+	if err := g.check(req); err != nil {
+		return llm.ChatResponse{}, err
+	}
+	return g.client.ChatStream(ctx, req, handle)
+}
+
+func (g *secretGuard) check(req llm.ChatRequest) error {
+	var findings []secrets.Finding
+	for _, message := range req.Messages {
+		if message.Role == "user" {
+			findings = append(findings, secrets.Scan(message.Content, g.domains)...)
+		}
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	for _, finding := range findings {
+		fmt.Fprintf(g.warn, "warning: possible %s in input (%s)\n", finding.Kind, secrets.Redact(finding.Match))
+	}
+	if g.block {
+		return fmt.Errorf("input appears to contain secrets; re-run with --force to send it anyway")
+	}
+	return nil
+}
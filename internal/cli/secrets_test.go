@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"dict-be/internal/config"
+	"dict-be/internal/llm"
+
+	"github.com/spf13/cobra"
+)
+
+func newGuardTestCmd(force bool) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("force", false, "")
+	if force {
+		_ = cmd.Flags().Set("force", "true")
+	}
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	return cmd, &stderr
+}
+
+func TestGuardClientBlocks(t *testing.T) {
+	cmd, stderr := newGuardTestCmd(false)
+	cfg := config.Config{Secrets: config.SecretsConfig{Mode: secretsModeBlock}}
+	client := guardClient(cmd, cfg, &fakeClient{resp: llm.ChatResponse{Content: "ok"}})
+	req := llm.ChatRequest{Messages: buildMessages("system", "token sk-abcdefghijklmnopqrstuvwx")}
+	if _, err := client.Chat(context.Background(), req); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected block error, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "OpenAI API key in input (sk-a…wx)") {
+		t.Fatalf("unexpected warning: %q", stderr.String())
+	}
+}
+
+func TestGuardClientForce(t *testing.T) {
+	cmd, stderr := newGuardTestCmd(true)
+	cfg := config.Config{Secrets: config.SecretsConfig{Mode: secretsModeBlock}}
+	client := guardClient(cmd, cfg, &fakeClient{resp: llm.ChatResponse{Content: "ok"}})
+	req := llm.ChatRequest{Messages: buildMessages("", "ssh build01.corp")}
+	resp, err := client.ChatStream(context.Background(), req, nil)
+	if err != nil || resp.Content != "ok" {
+		t.Fatalf("unexpected result: %+v %v", resp, err)
+	}
+	if !strings.Contains(stderr.String(), "internal hostname") {
+		t.Fatalf("expected warning, got %q", stderr.String())
+	}
+}
+
+func TestGuardClientOff(t *testing.T) {
+	cmd, _ := newGuardTestCmd(false)
+	inner := &fakeClient{}
+	cfg := config.Config{Secrets: config.SecretsConfig{Mode: secretsModeOff}}
+	if client := guardClient(cmd, cfg, inner); client != inner {
+		t.Fatalf("expected unwrapped client")
+	}
+}
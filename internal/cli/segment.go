@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"dict-be/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+type segmentOptions struct {
+	InputFile      string
+	Language       string
+	OutputLanguage string
+	Format         string
+}
+
+// segment is one word of segmented CJK text.
+type segment struct {
+	Word    string `json:"word"`
+	Reading string `json:"reading"`
+	Gloss   string `json:"gloss"`
+}
+
+var segmentReadings = map[string]struct {
+	Language string
+	Reading  string
+}{
+	"zh": {"Chinese", "pinyin with tone marks"},
+	"ja": {"Japanese", "reading in hiragana"},
+}
+
+func newSegmentCmd() *cobra.Command {
+	opts := &segmentOptions{}
+	cmd := &cobra.Command{
+		Use:   "segment [text...]",
+		Short: "Split Chinese or Japanese text into words with readings and glosses",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSegment(cmd, opts, args)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.InputFile, "file", "F", "", "input file, use -F- for stdin")
+	cmd.Flags().StringVar(&opts.Language, "lang", "auto", "text language: zh, ja or auto")
+	addOutputLanguageFlag(cmd, &opts.OutputLanguage, "o", "English", "gloss language")
+	cmd.Flags().StringVar(&opts.Format, "format", render.Text, "output format: text or json")
+	return cmd
+}
+
+func runSegment(cmd *cobra.Command, opts *segmentOptions, args []string) error {
+	if opts.Format != render.Text && opts.Format != render.JSON {
+		return fmt.Errorf("invalid format: %s (expected text or json)", opts.Format)
+	}
+	input, err := readInput(args, opts.InputFile, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(input) == "" {
+		return fmt.Errorf("input is required")
+	}
+	segments, err := segmentText(commandContext(cmd), cmd, input, opts.Language, opts.OutputLanguage)
+	if err != nil {
+		return err
+	}
+	if opts.Format == "json" {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(segments)
+	}
+	return writeSegments(cmd.OutOrStdout(), segments)
+}
+
+// segmentText asks the model to segment input and verifies that the words
+// cover the input exactly.
+func segmentText(ctx context.Context, cmd *cobra.Command, input, language, outputLanguage string) ([]segment, error) {
+	if language == "auto" {
+		language = detectCJKLanguage(input)
+	}
+	reading, ok := segmentReadings[language]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language: %s (expected zh or ja)", language)
+	}
+	content, err := completePrompt(ctx, cmd, "segment", map[string]string{
+		"input":           input,
+		"language":        reading.Language,
+		"reading":         reading.Reading,
+		"output_language": outputLanguage,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var segments []segment
+	if err := decodeJSONContent(content, &segments); err != nil {
+		return nil, err
+	}
+	if err := checkSegmentation(input, segments); err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
+
+func checkSegmentation(input string, segments []segment) error {
+	var joined strings.Builder
+	for _, seg := range segments {
+		joined.WriteString(seg.Word)
+	}
+	if stripSpace(joined.String()) != stripSpace(input) {
+		return fmt.Errorf("model segmentation does not match the input text")
+	}
+	return nil
+}
+
+func stripSpace(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// detectCJKLanguage treats any kana as Japanese and defaults to Chinese.
+func detectCJKLanguage(input string) string {
+	for _, r := range input {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana) {
+			return "ja"
+		}
+	}
+	return "zh"
+}
+
+func writeSegments(out io.Writer, segments []segment) error {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, seg := range segments {
+		if strings.TrimSpace(seg.Reading) == "" && strings.TrimSpace(seg.Gloss) == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(writer, "%s\t%s\t%s\n", seg.Word, seg.Reading, seg.Gloss); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCheckSegmentation(t *testing.T) {
+	segments := []segment{{Word: "我"}, {Word: "喜欢"}, {Word: "学习"}, {Word: "。"}}
+	if err := checkSegmentation("我喜欢 学习。", segments); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkSegmentation("我喜欢学中文。", segments); err == nil {
+		t.Fatalf("expected mismatch error")
+	}
+}
+
+func TestDetectCJKLanguage(t *testing.T) {
+	if got := detectCJKLanguage("日本語を勉強する"); got != "ja" {
+		t.Fatalf("unexpected language: %s", got)
+	}
+	if got := detectCJKLanguage("我喜欢学习"); got != "zh" {
+		t.Fatalf("unexpected language: %s", got)
+	}
+}
+
+func TestWriteSegmentsSkipsPunctuation(t *testing.T) {
+	var out bytes.Buffer
+	err := writeSegments(&out, []segment{
+		{Word: "学习", Reading: "xuéxí", Gloss: "to study"},
+		{Word: "。"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "学习  xuéxí  to study\n" {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
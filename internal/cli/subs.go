@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"dict-be/internal/glossary"
+	"dict-be/internal/llm"
+	"dict-be/internal/progress"
+	"dict-be/internal/subtitle"
+
+	"github.com/spf13/cobra"
+)
+
+// errSubtitleCount marks an answer with a different number of cues than
+// asked for, which is retried cue by cue.
+var errSubtitleCount = errors.New("model returned a different number of subtitles")
+
+type subsTranslateOptions struct {
+	Output         string
+	InputLanguage  string
+	OutputLanguage string
+	BatchSize      int
+	Bilingual      bool
+	Glossary       string
+	Progress       string
+}
+
+func newSubsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "subs",
+		Short: "Work with subtitle files",
+	}
+	cmd.AddCommand(newSubsTranslateCmd())
+	return cmd
+}
+
+func newSubsTranslateCmd() *cobra.Command {
+	opts := &subsTranslateOptions{}
+	cmd := &cobra.Command{
+		Use:   "translate <file.srt>",
+		Short: "Translate SRT subtitles, keeping indices and timestamps",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSubsTranslate(cmd, opts, args[0])
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "output file (default: stdout)")
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "")
+	cmd.Flags().IntVar(&opts.BatchSize, "batch-size", 20, "number of cues sent per request")
+	cmd.Flags().BoolVar(&opts.Bilingual, "bilingual", false, "keep the original text under each translation")
+	cmd.Flags().StringVar(&opts.Glossary, "glossary", "", "glossary CSV of term translations to use and check")
+	addProgressFlag(cmd, &opts.Progress)
+	return cmd
+}
+
+func runSubsTranslate(cmd *cobra.Command, opts *subsTranslateOptions, path string) error {
+	if opts.BatchSize < 1 {
+		return fmt.Errorf("invalid --batch-size: %d", opts.BatchSize)
+	}
+	if err := progress.ParseMode(opts.Progress); err != nil {
+		return err
+	}
+	input, err := readInput(nil, path, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	cues, err := subtitle.ParseSRT(input)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	var texts []string
+	var textCues []int
+	for i, cue := range cues {
+		if len(cue.Lines) > 0 {
+			texts = append(texts, cue.Text())
+			textCues = append(textCues, i)
+		}
+	}
+	if len(texts) == 0 {
+		return errors.New("no subtitle text to translate")
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(strings.Join(texts, "\n"), opts.InputLanguage, opts.OutputLanguage)
+	terms, err := loadGlossary(opts.Glossary)
+	if err != nil {
+		return err
+	}
+
+	client, cfg, err := loadLLMClient(cmd)
+	if err != nil {
+		return err
+	}
+	post, err := newPostprocessPipeline(cfg)
+	if err != nil {
+		return err
+	}
+	ctx := commandContext(cmd)
+	batches := (len(texts) + opts.BatchSize - 1) / opts.BatchSize
+	bar := newProgress(cmd, opts.Progress, batches, "batches")
+	client = meterCost(client, cfg, func(usd float64) { bar.AddCost(usd) })
+	translator := subtitleTranslator{
+		client:         client,
+		model:          cfg.LLM.Model,
+		inputLanguage:  inputLanguage,
+		outputLanguage: outputLanguage,
+		terms:          terms,
+	}
+	translations := make([]string, 0, len(texts))
+	for start := 0; start < len(texts); start += opts.BatchSize {
+		batch := texts[start:min(start+opts.BatchSize, len(texts))]
+		translated, err := translator.translate(ctx, batch)
+		if err != nil {
+			bar.Fail()
+			bar.Finish()
+			return fmt.Errorf("cues %s to %s: %w", cues[textCues[start]].Index, cues[textCues[start+len(batch)-1]].Index, err)
+		}
+		bar.Done()
+		translations = append(translations, translated...)
+	}
+	bar.Finish()
+
+	for i, translation := range translations {
+		cue := &cues[textCues[i]]
+		warnGlossary(cmd.ErrOrStderr(), terms, "cue "+cue.Index, texts[i], translation)
+		if translation, err = post.Apply(ctx, translation); err != nil {
+			return err
+		}
+		lines := nonBlank(strings.Split(translation, "\n"))
+		if opts.Bilingual {
+			lines = append(lines, cue.Lines...)
+		}
+		cue.Lines = lines
+	}
+	var buf bytes.Buffer
+	if err := subtitle.WriteSRT(&buf, cues); err != nil {
+		return err
+	}
+	output := buf.Bytes()
+	if strings.Contains(input, "\r\n") {
+		output = bytes.ReplaceAll(output, []byte("\n"), []byte("\r\n"))
+	}
+	if opts.Output == "" {
+		_, err = cmd.OutOrStdout().Write(output)
+		return err
+	}
+	if err := writeFileAtomic(opts.Output, output); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "translated %d subtitles -> %s\n", len(translations), opts.Output)
+	return nil
+}
+
+// subtitleTranslator translates batches of cue texts, one request per
+// batch, so each cue is translated with its neighbours as context.
+type subtitleTranslator struct {
+	client         llm.Client
+	model          string
+	inputLanguage  string
+	outputLanguage string
+	terms          []glossary.Term
+}
+
+// translate returns one translation per text. When the model merges or
+// splits cues, the batch is sent again one cue at a time, which loses the
+// context but keeps every timestamp with its own text.
+func (t subtitleTranslator) translate(ctx context.Context, texts []string) ([]string, error) {
+	translations, err := t.request(ctx, texts)
+	if !errors.Is(err, errSubtitleCount) || len(texts) == 1 {
+		return translations, err
+	}
+	translations = make([]string, len(texts))
+	for i, text := range texts {
+		translated, err := t.request(ctx, []string{text})
+		if err != nil {
+			return nil, err
+		}
+		translations[i] = translated[0]
+	}
+	return translations, nil
+}
+
+func (t subtitleTranslator) request(ctx context.Context, texts []string) ([]string, error) {
+	var input bytes.Buffer
+	encoder := json.NewEncoder(&input)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(texts); err != nil {
+		return nil, err
+	}
+	systemPrompt, userPrompt, err := buildPrompts("subs", map[string]string{
+		"input":           strings.TrimSpace(input.String()),
+		"input_language":  t.inputLanguage,
+		"output_language": t.outputLanguage,
+		"count":           strconv.Itoa(len(texts)),
+		"glossary":        glossaryInstruction(t.terms, strings.Join(texts, "\n")),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.client.Chat(ctx, llm.ChatRequest{
+		Model:    t.model,
+		Messages: buildMessages(systemPrompt, userPrompt),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var translations []string
+	if err := decodeJSONContent(resp.Content, &translations); err != nil {
+		return nil, err
+	}
+	if len(translations) != len(texts) {
+		return nil, fmt.Errorf("%w: got %d, want %d", errSubtitleCount, len(translations), len(texts))
+	}
+	for i, translation := range translations {
+		translations[i] = strings.TrimSpace(translation)
+	}
+	return translations, nil
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"dict-be/internal/llm"
+)
+
+// sequenceClient answers each Chat call with the next of contents.
+type sequenceClient struct {
+	fakeClient
+	contents []string
+	requests []llm.ChatRequest
+}
+
+func (s *sequenceClient) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	s.requests = append(s.requests, req)
+	content := s.contents[0]
+	s.contents = s.contents[1:]
+	return llm.ChatResponse{Content: content}, nil
+}
+
+func TestSubtitleTranslator(t *testing.T) {
+	client := &sequenceClient{contents: []string{"[\" 你好。 \", \"<i>很好。</i>\\n谢谢\"]"}}
+	translator := subtitleTranslator{client: client, inputLanguage: "English", outputLanguage: "Simplified Chinese"}
+	got, err := translator.translate(context.Background(), []string{"Hello.", "<i>Fine.</i>\nThanks"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "你好。" || got[1] != "<i>很好。</i>\n谢谢" {
+		t.Fatalf("unexpected translations: %q", got)
+	}
+	prompt := client.requests[0].Messages[1].Content
+	if !strings.Contains(prompt, "following 2 subtitle cues") || !strings.Contains(prompt, `<input>["Hello.","<i>Fine.</i>\nThanks"]</input>`) {
+		t.Fatalf("unexpected prompt: %q", prompt)
+	}
+}
+
+func TestSubtitleTranslatorRetriesCueByCue(t *testing.T) {
+	client := &sequenceClient{contents: []string{`["你好。谢谢。"]`, `["你好。"]`, `["谢谢。"]`}}
+	translator := subtitleTranslator{client: client, inputLanguage: "English", outputLanguage: "Simplified Chinese"}
+	got, err := translator.translate(context.Background(), []string{"Hello.", "Thanks."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(got, "|") != "你好。|谢谢。" || len(client.requests) != 3 {
+		t.Fatalf("unexpected translations: %q after %d requests", got, len(client.requests))
+	}
+
+	client = &sequenceClient{contents: []string{`[]`}}
+	translator.client = client
+	if _, err := translator.translate(context.Background(), []string{"Hello."}); err == nil {
+		t.Fatalf("expected an error for a missing translation")
+	}
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/postprocess"
+	"dict-be/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+// summaryLengths maps --length to the length the prompt asks for.
+var summaryLengths = map[string]string{
+	"short":  "in two or three sentences",
+	"medium": "in one paragraph of about five to eight sentences",
+	"long":   "in several paragraphs, one for each main part of the text",
+}
+
+type summarizeOptions struct {
+	InputFile      string
+	InputLanguage  string
+	OutputLanguage string
+	Length         string
+	Vocab          int
+	NoKnown        bool
+	Format         string
+}
+
+// summary is the JSON output of summarize.
+type summary struct {
+	Summary    string            `json:"summary"`
+	Vocabulary []difficultyEntry `json:"vocabulary,omitempty"`
+}
+
+func newSummarizeCmd() *cobra.Command {
+	opts := &summarizeOptions{}
+	cmd := &cobra.Command{
+		Use:   "summarize [text...]",
+		Short: "Summarize a foreign-language text and list its key vocabulary",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSummarize(cmd, opts, args)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.InputFile, "file", "F", "", "text file, use -F- for stdin")
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "o")
+	cmd.Flags().StringVar(&opts.Length, "length", "medium", "summary length: short, medium or long")
+	cmd.Flags().IntVar(&opts.Vocab, "vocab", 10, "maximum number of key vocabulary words, 0 for none")
+	cmd.Flags().BoolVar(&opts.NoKnown, "no-known", false, "also list words in the known-words list")
+	cmd.Flags().StringVar(&opts.Format, "format", render.Text, formatHelp+" (json prints the summary and vocabulary as an object)")
+	return cmd
+}
+
+func runSummarize(cmd *cobra.Command, opts *summarizeOptions, args []string) error {
+	if err := validateFormat(opts.Format); err != nil {
+		return err
+	}
+	length, ok := summaryLengths[opts.Length]
+	if !ok {
+		return fmt.Errorf("invalid --length: %s (expected short, medium or long)", opts.Length)
+	}
+	if opts.Vocab < 0 {
+		return fmt.Errorf("--vocab must not be negative")
+	}
+	input, err := readInput(args, opts.InputFile, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(input) == "" {
+		return fmt.Errorf("input is required")
+	}
+	knownInstruction, err := knownWordsInstruction(input, opts.Vocab > 0 && !opts.NoKnown)
+	if err != nil {
+		return err
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(input, opts.InputLanguage, opts.OutputLanguage)
+	systemPrompt, userPrompt, err := buildPrompts("summarize", map[string]string{
+		"input":             input,
+		"input_language":    inputLanguage,
+		"output_language":   outputLanguage,
+		"length":            length,
+		"vocab":             strconv.Itoa(opts.Vocab),
+		"known_instruction": knownInstruction,
+	})
+	if err != nil {
+		return err
+	}
+	client, cfg, err := loadLLMClient(cmd)
+	if err != nil {
+		return err
+	}
+	post, err := newPostprocessPipeline(cfg)
+	if err != nil {
+		return err
+	}
+	req := llm.ChatRequest{
+		Model:    cfg.LLM.Model,
+		Messages: buildMessages(systemPrompt, userPrompt),
+	}
+	return writeSummary(commandContext(cmd), cmd.OutOrStdout(), client, req, opts.Vocab, opts.Format, post)
+}
+
+// writeSummary asks for the summary as JSON and prints it in format: the
+// validated summary for JSON, markdown with a vocabulary list otherwise.
+func writeSummary(ctx context.Context, out io.Writer, client llm.Client, req llm.ChatRequest, vocab int, format string, post postprocess.Pipeline) error {
+	resp, err := client.Chat(ctx, req)
+	if err != nil {
+		return err
+	}
+	result, err := parseSummary(resp.Content, vocab)
+	if err != nil {
+		return err
+	}
+	if format == render.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+	text, err := post.Apply(ctx, strings.TrimSuffix(result.markdown(), "\n"))
+	if err != nil {
+		return err
+	}
+	return renderContent(out, format, firstNonEmpty(resp.Model, req.Model), text)
+}
+
+// parseSummary decodes the model's summary, dropping vocabulary without a
+// word and keeping at most vocab entries. An empty summary is an error.
+func parseSummary(content string, vocab int) (summary, error) {
+	var result summary
+	if err := decodeJSONContent(content, &result); err != nil {
+		return summary{}, err
+	}
+	result.Summary = strings.TrimSpace(result.Summary)
+	if result.Summary == "" {
+		return summary{}, fmt.Errorf("model returned no summary")
+	}
+	var vocabulary []difficultyEntry
+	for _, entry := range result.Vocabulary {
+		entry.Word = strings.TrimSpace(entry.Word)
+		entry.Meaning = strings.TrimSpace(entry.Meaning)
+		if entry.Word == "" || len(vocabulary) == vocab {
+			continue
+		}
+		vocabulary = append(vocabulary, entry)
+	}
+	result.Vocabulary = vocabulary
+	return result, nil
+}
+
+func (s summary) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Summary\n\n%s\n", s.Summary)
+	if len(s.Vocabulary) > 0 {
+		b.WriteString("\n## Key vocabulary\n\n")
+		for _, entry := range s.Vocabulary {
+			if entry.Meaning == "" {
+				fmt.Fprintf(&b, "- %s\n", entry.Word)
+				continue
+			}
+			fmt.Fprintf(&b, "- %s: %s\n", entry.Word, entry.Meaning)
+		}
+	}
+	return b.String()
+}
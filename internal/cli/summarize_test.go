@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/render"
+)
+
+const testSummary = `{"summary":" 文章介绍了城市骑行的好处。\n\n作者建议增加自行车道。 ",` +
+	`"vocabulary":[{"word":"commute","meaning":"通勤"},{"word":" "},{"word":"bike lane"},{"word":"congestion","meaning":"拥堵"}]}`
+
+func TestWriteSummary(t *testing.T) {
+	client := &fakeClient{resp: llm.ChatResponse{Content: testSummary}}
+	var out bytes.Buffer
+	if err := writeSummary(context.Background(), &out, client, llm.ChatRequest{}, 2, render.Text, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "## Summary\n\n文章介绍了城市骑行的好处。\n\n作者建议增加自行车道。\n\n## Key vocabulary\n\n- commute: 通勤\n- bike lane\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := writeSummary(context.Background(), &out, client, llm.ChatRequest{}, 0, render.JSON, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `"summary": "文章`) || strings.Contains(out.String(), "vocabulary") {
+		t.Fatalf("unexpected json:\n%s", out.String())
+	}
+	if _, err := parseSummary(`{"summary":" ","vocabulary":[{"word":"commute"}]}`, 5); err == nil {
+		t.Fatalf("expected an error for an empty summary")
+	}
+}
+
+func TestBuildSummarizePrompts(t *testing.T) {
+	vars := map[string]string{
+		"input":             "Cycling to work is good for cities.",
+		"input_language":    "English",
+		"output_language":   "Simplified Chinese",
+		"length":            summaryLengths["short"],
+		"vocab":             "5",
+		"known_instruction": "",
+	}
+	systemPrompt, userPrompt, err := buildPrompts("summarize", vars)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, "in Simplified Chinese, in two or three sentences") || !strings.Contains(systemPrompt, "at most 5 key words") {
+		t.Fatalf("unexpected system prompt: %q", systemPrompt)
+	}
+	if !strings.Contains(userPrompt, "<input>Cycling to work is good for cities.</input>") {
+		t.Fatalf("unexpected user prompt: %q", userPrompt)
+	}
+
+	vars["vocab"] = "0"
+	systemPrompt, _, err = buildPrompts("summarize", vars)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(systemPrompt, "key words") || !strings.Contains(systemPrompt, `"meaning", left empty)`) {
+		t.Fatalf("unexpected system prompt without vocabulary: %q", systemPrompt)
+	}
+}
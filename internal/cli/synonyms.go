@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/postprocess"
+	"dict-be/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+type synonymsOptions struct {
+	InputLanguage  string
+	OutputLanguage string
+	Format         string
+}
+
+// synonymSet is the JSON output of synonyms. The headword's own row comes
+// first in Synonyms so the table compares it with the others.
+type synonymSet struct {
+	Word     string    `json:"word"`
+	Synonyms []synonym `json:"synonyms"`
+	Antonyms []synonym `json:"antonyms,omitempty"`
+}
+
+type synonym struct {
+	Word   string `json:"word"`
+	Nuance string `json:"nuance"`
+	Usage  string `json:"usage,omitempty"`
+}
+
+func newSynonymsCmd() *cobra.Command {
+	opts := &synonymsOptions{}
+	cmd := &cobra.Command{
+		Use:   "synonyms <word>",
+		Short: "Compare a word's synonyms and list its antonyms",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSynonyms(cmd, opts, strings.Join(args, " "))
+		},
+	}
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "o")
+	cmd.Flags().StringVar(&opts.Format, "format", render.Text, formatHelp+" (json prints the comparison as an object)")
+	return cmd
+}
+
+func runSynonyms(cmd *cobra.Command, opts *synonymsOptions, word string) error {
+	if err := validateFormat(opts.Format); err != nil {
+		return err
+	}
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return fmt.Errorf("word is required")
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(word, opts.InputLanguage, opts.OutputLanguage)
+	systemPrompt, userPrompt, err := buildPrompts("synonyms", map[string]string{
+		"input":           word,
+		"input_language":  inputLanguage,
+		"output_language": outputLanguage,
+	})
+	if err != nil {
+		return err
+	}
+	client, cfg, err := loadLLMClient(cmd)
+	if err != nil {
+		return err
+	}
+	post, err := newPostprocessPipeline(cfg)
+	if err != nil {
+		return err
+	}
+	req := llm.ChatRequest{
+		Model:    cfg.LLM.Model,
+		Messages: buildMessages(systemPrompt, userPrompt),
+	}
+	return writeSynonyms(commandContext(cmd), cmd.OutOrStdout(), client, req, word, opts.Format, post)
+}
+
+// writeSynonyms asks for the comparison as JSON and prints it in format:
+// the validated set for JSON, a markdown table otherwise.
+func writeSynonyms(ctx context.Context, out io.Writer, client llm.Client, req llm.ChatRequest, word, format string, post postprocess.Pipeline) error {
+	resp, err := client.Chat(ctx, req)
+	if err != nil {
+		return err
+	}
+	set, err := parseSynonyms(resp.Content, word)
+	if err != nil {
+		return err
+	}
+	if format == render.JSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(set)
+	}
+	text, err := post.Apply(ctx, strings.TrimSuffix(set.markdown(), "\n"))
+	if err != nil {
+		return err
+	}
+	return renderContent(out, format, firstNonEmpty(resp.Model, req.Model), text)
+}
+
+// parseSynonyms decodes the model's comparison, dropping rows without a
+// word. A word with no synonyms is reported as an error rather than an
+// empty table.
+func parseSynonyms(content, word string) (synonymSet, error) {
+	var set synonymSet
+	if err := decodeJSONContent(content, &set); err != nil {
+		return synonymSet{}, err
+	}
+	set.Word = word
+	set.Synonyms = cleanSynonyms(set.Synonyms)
+	set.Antonyms = cleanSynonyms(set.Antonyms)
+	if len(set.Synonyms) == 0 {
+		return synonymSet{}, fmt.Errorf("model returned no synonyms for %q", word)
+	}
+	return set, nil
+}
+
+func cleanSynonyms(rows []synonym) []synonym {
+	var kept []synonym
+	for _, row := range rows {
+		row.Word = strings.TrimSpace(row.Word)
+		row.Nuance = strings.TrimSpace(row.Nuance)
+		row.Usage = strings.TrimSpace(row.Usage)
+		if row.Word != "" {
+			kept = append(kept, row)
+		}
+	}
+	return kept
+}
+
+// markdown renders the synonyms as a comparison table and the antonyms as
+// a list.
+func (s synonymSet) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n## Synonyms\n\n| Word | Nuance | Usage |\n| --- | --- | --- |\n", s.Word)
+	for _, row := range s.Synonyms {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", tableCell(row.Word), tableCell(row.Nuance), tableCell(row.Usage))
+	}
+	if len(s.Antonyms) > 0 {
+		b.WriteString("\n## Antonyms\n\n")
+		for _, row := range s.Antonyms {
+			if row.Nuance == "" {
+				fmt.Fprintf(&b, "- %s\n", row.Word)
+				continue
+			}
+			fmt.Fprintf(&b, "- %s: %s\n", row.Word, row.Nuance)
+		}
+	}
+	return b.String()
+}
+
+// tableCell keeps text on one markdown table row.
+func tableCell(text string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(text), " "), "|", `\|`)
+}
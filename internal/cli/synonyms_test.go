@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/render"
+)
+
+const testSynonyms = `{"synonyms":[{"word":"big","nuance":"General size.","usage":"a big house"},` +
+	`{"word":" huge ","nuance":"Much stronger | informal.","usage":"a huge\nmistake"},{"word":"","nuance":"dropped"}],` +
+	`"antonyms":[{"word":"small","nuance":"Little in size."},{"word":"tiny"}]}`
+
+func TestWriteSynonyms(t *testing.T) {
+	client := &fakeClient{resp: llm.ChatResponse{Content: testSynonyms}}
+	var out bytes.Buffer
+	if err := writeSynonyms(context.Background(), &out, client, llm.ChatRequest{}, "big", render.Text, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# big\n\n## Synonyms\n\n| Word | Nuance | Usage |\n| --- | --- | --- |\n" +
+		"| big | General size. | a big house |\n| huge | Much stronger \\| informal. | a huge mistake |\n\n" +
+		"## Antonyms\n\n- small: Little in size.\n- tiny\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := writeSynonyms(context.Background(), &out, client, llm.ChatRequest{}, "big", render.JSON, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `"word": "huge"`) || !strings.Contains(out.String(), `"antonyms": [`) {
+		t.Fatalf("unexpected json:\n%s", out.String())
+	}
+	if _, err := parseSynonyms(`{"synonyms":[],"antonyms":[{"word":"small"}]}`, "big"); err == nil {
+		t.Fatalf("expected an error for no synonyms")
+	}
+}
+
+func TestBuildSynonymsPrompts(t *testing.T) {
+	systemPrompt, userPrompt, err := buildPrompts("synonyms", map[string]string{
+		"input":           "big",
+		"input_language":  "English",
+		"output_language": "Simplified Chinese",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, "explain in Simplified Chinese the nuance") || !strings.Contains(systemPrompt, `"antonyms"`) {
+		t.Fatalf("unexpected system prompt: %q", systemPrompt)
+	}
+	if !strings.Contains(userPrompt, "<input>big</input>") {
+		t.Fatalf("unexpected user prompt: %q", userPrompt)
+	}
+}
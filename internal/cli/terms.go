@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"dict-be/internal/glossary"
+
+	"github.com/spf13/cobra"
+)
+
+type termsExtractOptions struct {
+	InputFile      string
+	InputLanguage  string
+	OutputLanguage string
+	Output         string
+	Max            int
+}
+
+func newTermsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "terms",
+		Short: "Manage translation terminology",
+	}
+	cmd.AddCommand(newTermsExtractCmd())
+	return cmd
+}
+
+func newTermsExtractCmd() *cobra.Command {
+	opts := &termsExtractOptions{}
+	cmd := &cobra.Command{
+		Use:   "extract [text...]",
+		Short: "Extract key terms from a document into a glossary CSV",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTermsExtract(cmd, opts, args)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.InputFile, "file", "F", "", "document file, use -F- for stdin")
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "glossary CSV file (default: stdout)")
+	cmd.Flags().IntVar(&opts.Max, "max", 50, "maximum number of terms")
+	return cmd
+}
+
+func runTermsExtract(cmd *cobra.Command, opts *termsExtractOptions, args []string) error {
+	if opts.Max <= 0 {
+		return fmt.Errorf("--max must be positive")
+	}
+	input, err := readInput(args, opts.InputFile, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(input) == "" {
+		return fmt.Errorf("input is required")
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(input, opts.InputLanguage, opts.OutputLanguage)
+	content, err := completePrompt(commandContext(cmd), cmd, "terms", map[string]string{
+		"input":           input,
+		"input_language":  inputLanguage,
+		"output_language": outputLanguage,
+		"max":             strconv.Itoa(opts.Max),
+	})
+	if err != nil {
+		return err
+	}
+	terms, err := parseExtractedTerms(content, opts.Max)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := glossary.WriteCSV(&buf, terms); err != nil {
+		return err
+	}
+	if opts.Output == "" {
+		_, err = cmd.OutOrStdout().Write(buf.Bytes())
+		return err
+	}
+	if err := writeFileAtomic(opts.Output, buf.Bytes()); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "wrote %d terms to %s\n", len(terms), opts.Output)
+	return nil
+}
+
+// parseExtractedTerms decodes the model's JSON term list, dropping empty
+// and duplicate source terms.
+func parseExtractedTerms(content string, max int) ([]glossary.Term, error) {
+	var raw []glossary.Term
+	if err := decodeJSONContent(content, &raw); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(raw))
+	terms := make([]glossary.Term, 0, len(raw))
+	for _, term := range raw {
+		term.Source = strings.TrimSpace(term.Source)
+		term.Target = strings.TrimSpace(term.Target)
+		term.Note = strings.TrimSpace(term.Note)
+		key := strings.ToLower(term.Source)
+		if term.Source == "" || term.Target == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		terms = append(terms, term)
+		if len(terms) == max {
+			break
+		}
+	}
+	return terms, nil
+}
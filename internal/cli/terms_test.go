@@ -0,0 +1,29 @@
+package cli
+
+import "testing"
+
+func TestParseExtractedTerms(t *testing.T) {
+	content := "```json\n" + `[
+  {"source": "rate limit", "target": "速率限制", "note": ""},
+  {"source": "Rate Limit", "target": "限流"},
+  {"source": "", "target": "空"},
+  {"source": "token bucket", "target": "令牌桶", "note": "algorithm"},
+  {"source": "retry", "target": "重试"}
+]` + "\n```"
+	terms, err := parseExtractedTerms(content, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(terms) != 2 {
+		t.Fatalf("unexpected terms: %+v", terms)
+	}
+	if terms[0].Target != "速率限制" || terms[1].Source != "token bucket" {
+		t.Fatalf("unexpected terms: %+v", terms)
+	}
+}
+
+func TestParseExtractedTermsInvalid(t *testing.T) {
+	if _, err := parseExtractedTerms("not json", 10); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,491 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"dict-be/internal/document"
+	"dict-be/internal/glossary"
+	"dict-be/internal/jobs"
+	"dict-be/internal/llm"
+	"dict-be/internal/progress"
+	"dict-be/internal/render"
+
+	"github.com/spf13/cobra"
+)
+
+type translateOptions struct {
+	Output         string
+	InputLanguage  string
+	OutputLanguage string
+	Watch          bool
+	Interval       time.Duration
+	ExportTSV      string
+	Notify         bool
+	Format         string
+	Resume         string
+	Review         bool
+	ReviewModel    string
+	Progress       string
+	OnError        string
+	FailureReport  string
+	Glossary       string
+	LearnGlossary  string
+	Yes            bool
+	Bilingual      string
+	Vocab          int
+	Markdown       bool
+	NoMarkdown     bool
+}
+
+func newTranslateCmd() *cobra.Command {
+	opts := &translateOptions{}
+	cmd := &cobra.Command{
+		Use:   "translate <file>",
+		Short: "Translate a document paragraph by paragraph",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return runTranslate(cmd, opts, path)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "output file (default: stdout)")
+	addLanguageFlags(cmd, &opts.InputLanguage, &opts.OutputLanguage, "i", "")
+	cmd.Flags().BoolVar(&opts.Watch, "watch", false, "watch the file and re-translate changed paragraphs")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", time.Second, "polling interval for --watch")
+	cmd.Flags().StringVar(&opts.ExportTSV, "export-tsv", "", "write aligned source/target sentence pairs to a TSV file")
+	cmd.Flags().BoolVar(&opts.Notify, "notify", false, "show a desktop notification when the translation finishes")
+	cmd.Flags().StringVar(&opts.Format, "format", render.Text, formatHelp)
+	cmd.Flags().StringVar(&opts.Resume, "resume", "", "resume an interrupted translation job by ID")
+	cmd.Flags().BoolVar(&opts.Review, "review", false, "review each translated paragraph in a second editor pass")
+	cmd.Flags().StringVar(&opts.ReviewModel, "review-model", "", "model for the review pass (implies --review)")
+	addProgressFlag(cmd, &opts.Progress)
+	cmd.Flags().StringVar(&opts.OnError, "on-error", onErrorStop, "on a failed paragraph: stop, or keep going and write its source or a placeholder")
+	cmd.Flags().StringVar(&opts.FailureReport, "failure-report", "", "write failed paragraphs and their errors to this JSON file")
+	cmd.Flags().StringVar(&opts.Glossary, "glossary", "", "glossary CSV of term translations to use and check")
+	cmd.Flags().StringVar(&opts.LearnGlossary, "learn-glossary", "", "offer to append the term translations used to this glossary CSV")
+	cmd.Flags().BoolVar(&opts.Yes, "yes", false, "append learned glossary terms without asking")
+	cmd.Flags().StringVar(&opts.Bilingual, "bilingual", "", "write source and translation paragraph by paragraph as html or epub")
+	cmd.Flags().IntVar(&opts.Vocab, "vocab", 30, "vocabulary appendix size for --bilingual (0 to leave it out)")
+	addMarkdownFlags(cmd, &opts.Markdown, &opts.NoMarkdown)
+	return cmd
+}
+
+func runTranslate(cmd *cobra.Command, opts *translateOptions, path string) error {
+	if opts.Watch && opts.Output == "" {
+		return errors.New("--watch requires -o")
+	}
+	if opts.Watch && path == "-" {
+		return errors.New("--watch cannot read from stdin")
+	}
+	if opts.Watch && opts.LearnGlossary != "" {
+		return errors.New("--learn-glossary cannot be combined with --watch")
+	}
+	if opts.Interval <= 0 {
+		return errors.New("--interval must be positive")
+	}
+	if err := validateFormat(opts.Format); err != nil {
+		return err
+	}
+	if err := validateBilingual(opts.Bilingual); err != nil {
+		return err
+	}
+	if opts.Bilingual != "" && cmd.Flags().Changed("format") {
+		return errors.New("--bilingual cannot be combined with --format")
+	}
+	if opts.Bilingual == bilingualEPUB && opts.Output == "" {
+		return errors.New("--bilingual epub requires -o")
+	}
+	if opts.Vocab < 0 {
+		return fmt.Errorf("invalid --vocab: %d", opts.Vocab)
+	}
+	if opts.Markdown && opts.NoMarkdown {
+		return errors.New("only one of --markdown or --no-markdown can be set")
+	}
+	if err := progress.ParseMode(opts.Progress); err != nil {
+		return err
+	}
+	fill, err := failureFill(opts.OnError)
+	if err != nil {
+		return err
+	}
+
+	var store *jobs.Store
+	var job *jobs.Job
+	if opts.Resume != "" {
+		if opts.Watch {
+			return errors.New("--resume cannot be combined with --watch")
+		}
+		if store, err = newJobStore(); err != nil {
+			return err
+		}
+		if job, err = store.Load(opts.Resume); err != nil {
+			return err
+		}
+		if path != "" && path != job.Input {
+			return fmt.Errorf("job %s translates %s, not %s", job.ID, job.Input, path)
+		}
+		path = job.Input
+		opts.Output = firstNonEmpty(opts.Output, job.Output)
+	}
+	if path == "" {
+		return errors.New("file is required")
+	}
+	markdown := isMarkdownInput(path, opts.Markdown, opts.NoMarkdown)
+
+	input, err := readInput(nil, path, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	if opts.Watch && !isRegularFile(path) {
+		return errors.New("--watch requires a regular file, not a pipe")
+	}
+	inputLanguage, outputLanguage := resolveTranslateLanguages(input, opts.InputLanguage, opts.OutputLanguage)
+	if job != nil {
+		inputLanguage, outputLanguage = job.InputLanguage, job.OutputLanguage
+	}
+	terms, err := loadGlossary(opts.Glossary)
+	if err != nil {
+		return err
+	}
+
+	client, cfg, err := loadLLMClient(cmd)
+	if err != nil {
+		return err
+	}
+	post, err := newPostprocessPipeline(cfg)
+	if err != nil {
+		return err
+	}
+	var bar *progress.Reporter
+	client = meterCost(client, cfg, func(usd float64) { bar.AddCost(usd) })
+	translate := newParagraphTranslator(client, cfg.LLM.Model, inputLanguage, outputLanguage, terms)
+	var review *reviewLog
+	if opts.Review || opts.ReviewModel != "" {
+		review = &reviewLog{}
+		model := firstNonEmpty(opts.ReviewModel, cfg.LLM.Model)
+		translate = withReview(client, model, inputLanguage, outputLanguage, translate, review)
+	}
+	if markdown {
+		translate = document.ProtectMarkdown(translate)
+	}
+	if !opts.Watch && isRegularFile(path) {
+		if store == nil {
+			if store, err = newJobStore(); err != nil {
+				return err
+			}
+		}
+		job = newTranslateJob(job, path, opts.Output, inputLanguage, outputLanguage, input, markdown)
+		if err := store.Save(job); err != nil {
+			return err
+		}
+		translate = recordJobProgress(store, job, translate)
+	}
+	translate = reportProgress(&bar, translate)
+	var learner *glossaryLearner
+	if opts.LearnGlossary != "" {
+		learner = &glossaryLearner{
+			path:           opts.LearnGlossary,
+			yes:            opts.Yes,
+			client:         client,
+			model:          cfg.LLM.Model,
+			inputLanguage:  inputLanguage,
+			outputLanguage: outputLanguage,
+			in:             cmd.InOrStdin(),
+			out:            cmd.ErrOrStderr(),
+		}
+	}
+	translator := document.NewTranslator(translate)
+	if markdown {
+		translator.Markdown()
+	}
+	if fill != nil {
+		translator.ContinueOnError(fill)
+	}
+	if job != nil {
+		for _, chunk := range job.Chunks {
+			if chunk.Status == jobs.StatusDone {
+				translator.Seed(chunk.Source, chunk.Target)
+			}
+		}
+	}
+
+	ctx := commandContext(cmd)
+
+	translateOnce := func() error {
+		bar = newProgress(cmd, opts.Progress, translator.Pending(input), "paragraphs")
+		result, err := translator.Translate(ctx, input)
+		bar.Finish()
+		if err != nil {
+			return err
+		}
+		if opts.FailureReport != "" && len(result.Failures) > 0 {
+			jobID := ""
+			if job != nil {
+				jobID = job.ID
+			}
+			if err := writeFailureReport(opts.FailureReport, path, jobID, result); err != nil {
+				return err
+			}
+		}
+		if review != nil {
+			review.report(cmd.ErrOrStderr())
+		}
+		checkGlossary(cmd.ErrOrStderr(), terms, result)
+		translation := result.Text
+		write := func(out io.Writer) error {
+			return renderContent(out, opts.Format, cfg.LLM.Model, result.Text)
+		}
+		if opts.Bilingual != "" {
+			book, err := newBilingualBook(ctx, client, cfg.LLM.Model, post, path, input, inputLanguage, outputLanguage, result.Sections, opts.Vocab)
+			if err != nil {
+				return err
+			}
+			write = func(out io.Writer) error {
+				return writeBilingual(out, opts.Bilingual, book)
+			}
+		} else if result.Text, err = post.Apply(ctx, result.Text); err != nil {
+			return err
+		}
+		learn := func() error {
+			if learner == nil || result.Translated == 0 {
+				return nil
+			}
+			return learner.learn(ctx, input, translation)
+		}
+		if opts.ExportTSV != "" {
+			if err := exportTSV(opts.ExportTSV, result.Pairs); err != nil {
+				return err
+			}
+		}
+		if opts.Output == "" {
+			if err := write(cmd.OutOrStdout()); err != nil {
+				return err
+			}
+			reportDuplicates(cmd.ErrOrStderr(), result)
+			if err := learn(); err != nil {
+				return err
+			}
+			return failuresError(result)
+		}
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			return err
+		}
+		if err := writeFileAtomic(opts.Output, buf.Bytes()); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "translated %d of %d paragraphs -> %s\n",
+			result.Translated, result.Paragraphs, opts.Output)
+		reportDuplicates(cmd.ErrOrStderr(), result)
+		if err := learn(); err != nil {
+			return err
+		}
+		return failuresError(result)
+	}
+
+	if !opts.Watch {
+		err := translateOnce()
+		if job != nil {
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%d of %d paragraphs saved; resume with: dict-be translate --resume %s\n",
+					job.Done(), len(job.Chunks), job.ID)
+			} else if removeErr := store.Remove(job.ID); removeErr != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), removeErr.Error())
+			}
+		}
+		if notifyEnabled(cmd, opts.Notify, cfg.Notify) {
+			notifyDone(cmd, "Translation of "+filepath.Base(path), err)
+		}
+		return err
+	}
+	if err := translateOnce(); err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "watching %s, press Ctrl-C to stop\n", path)
+	return watchFile(ctx, path, opts.Interval, func() {
+		input, err = readInput(nil, path, cmd.InOrStdin())
+		if err == nil {
+			err = translateOnce()
+		}
+		if err != nil {
+			fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+		}
+	})
+}
+
+func newParagraphTranslator(client llm.Client, model, inputLanguage, outputLanguage string, terms []glossary.Term) document.TranslateFunc {
+	return func(ctx context.Context, text string) (string, error) {
+		systemPrompt, userPrompt, err := buildPrompts("translate", map[string]string{
+			"input":           text,
+			"input_language":  inputLanguage,
+			"output_language": outputLanguage,
+			"glossary":        glossaryInstruction(terms, text),
+		})
+		if err != nil {
+			return "", err
+		}
+		resp, err := client.Chat(ctx, llm.ChatRequest{
+			Model:    model,
+			Messages: buildMessages(systemPrompt, userPrompt),
+		})
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(resp.Content), nil
+	}
+}
+
+// reportProgress counts finished and failed paragraphs on the reporter
+// *bar points to, which each run replaces.
+func reportProgress(bar **progress.Reporter, translate document.TranslateFunc) document.TranslateFunc {
+	return func(ctx context.Context, text string) (string, error) {
+		output, err := translate(ctx, text)
+		if err != nil {
+			(*bar).Fail()
+		} else {
+			(*bar).Done()
+		}
+		return output, err
+	}
+}
+
+// resolveTranslateLanguages applies query's auto detection, and otherwise
+// lets the model detect the source language when only --out is given.
+func resolveTranslateLanguages(input, inputLanguage, outputLanguage string) (string, string) {
+	inputLanguage, outputLanguage = resolveLanguages(input, inputLanguage, outputLanguage)
+	if inputLanguage == "auto" {
+		inputLanguage = "the detected source language"
+	}
+	return inputLanguage, outputLanguage
+}
+
+// markdownExtensions are the file extensions translated in markdown mode
+// unless --no-markdown is given.
+var markdownExtensions = []string{".md", ".markdown", ".mdx"}
+
+func addMarkdownFlags(cmd *cobra.Command, markdown, noMarkdown *bool) {
+	cmd.Flags().BoolVar(markdown, "markdown", false, "translate only markdown prose, keeping code, URLs and front matter (default: on for .md files)")
+	cmd.Flags().BoolVar(noMarkdown, "no-markdown", false, "translate .md files as plain text")
+}
+
+// isMarkdownInput reports whether path is translated in markdown mode:
+// markdown files are unless noMarkdown is set, other files and stdin only
+// with markdown.
+func isMarkdownInput(path string, markdown, noMarkdown bool) bool {
+	if markdown || noMarkdown {
+		return markdown
+	}
+	return slices.Contains(markdownExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// failuresError reports the paragraphs that failed and were filled in, so
+// the command exits non-zero although it wrote its output.
+func failuresError(result document.Result) error {
+	if len(result.Failures) == 0 {
+		return nil
+	}
+	first := result.Failures[0]
+	return fmt.Errorf("%d of %d paragraphs failed (paragraph %d: %w)",
+		len(result.Failures), result.Paragraphs, first.Paragraph, first.Err)
+}
+
+// checkGlossary warns about the glossary terms each translated paragraph
+// left out; paragraphs filled in after a failure and those kept as they
+// are, such as markdown code blocks, are skipped.
+func checkGlossary(out io.Writer, terms []glossary.Term, result document.Result) {
+	if len(terms) == 0 {
+		return
+	}
+	failed := make(map[int]bool, len(result.Failures))
+	for _, failure := range result.Failures {
+		failed[failure.Paragraph] = true
+	}
+	for i, section := range result.Sections {
+		if !failed[i+1] && section.Source != section.Target {
+			warnGlossary(out, terms, fmt.Sprintf("paragraph %d", i+1), section.Source, section.Target)
+		}
+	}
+}
+
+// reportDuplicates prints how much translation work repeated paragraphs
+// saved, if any.
+func reportDuplicates(out io.Writer, result document.Result) {
+	if result.Duplicates == 0 {
+		return
+	}
+	fmt.Fprintf(out, "reused translations for %d duplicate paragraphs (%d characters not sent)\n",
+		result.Duplicates, result.DuplicateChars)
+}
+
+// watchFile polls path and calls onChange whenever its size or
+// modification time changes, until ctx is cancelled.
+func watchFile(ctx context.Context, path string, interval time.Duration, onChange func()) error {
+	last, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat file: %w", err)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+			continue
+		}
+		last = info
+		onChange()
+	}
+}
+
+func exportTSV(path string, pairs []document.Pair) error {
+	var buf bytes.Buffer
+	if err := document.WriteTSV(&buf, pairs); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("export tsv: %w", err)
+	}
+	return nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("chmod temp file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveTranslateLanguages(t *testing.T) {
+	inputLang, outputLang := resolveTranslateLanguages("hello", "auto", "Japanese")
+	if inputLang != "the detected source language" || outputLang != "Japanese" {
+		t.Fatalf("unexpected languages: %q %q", inputLang, outputLang)
+	}
+	inputLang, outputLang = resolveTranslateLanguages("你好", "auto", "auto")
+	if inputLang != "Simplified Chinese" || outputLang != "English" {
+		t.Fatalf("unexpected languages: %q %q", inputLang, outputLang)
+	}
+}
+
+func TestIsMarkdownInput(t *testing.T) {
+	cases := []struct {
+		path                 string
+		markdown, noMarkdown bool
+		want                 bool
+	}{
+		{"README.md", false, false, true},
+		{"notes.Markdown", false, false, true},
+		{"README.md", false, true, false},
+		{"notes.txt", false, false, false},
+		{"-", true, false, true},
+	}
+	for _, c := range cases {
+		if got := isMarkdownInput(c.path, c.markdown, c.noMarkdown); got != c.want {
+			t.Fatalf("isMarkdownInput(%q, %v, %v) = %v", c.path, c.markdown, c.noMarkdown, got)
+		}
+	}
+}
+
+func TestTranslatePromptPlaceholders(t *testing.T) {
+	vars := map[string]string{"input": "Run ⟦1⟧ first.", "input_language": "English", "output_language": "French"}
+	systemPrompt, _, err := buildPrompts("translate", vars)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(systemPrompt, "Placeholders such as ⟦1⟧") {
+		t.Fatalf("expected the placeholder instruction: %q", systemPrompt)
+	}
+	vars["input"] = "Run it first."
+	if systemPrompt, _, _ = buildPrompts("translate", vars); strings.Contains(systemPrompt, "Placeholders") {
+		t.Fatalf("unexpected placeholder instruction: %q", systemPrompt)
+	}
+}
+
+func TestWatchFileDetectsChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "draft.md")
+	if err := os.WriteFile(path, []byte("one"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	changed := make(chan struct{}, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = os.WriteFile(path, []byte("one two"), 0o600)
+	}()
+	err := watchFile(ctx, path, 10*time.Millisecond, func() {
+		changed <- struct{}{}
+		cancel()
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-changed:
+	default:
+		t.Fatalf("expected change callback")
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.md")
+	if err := writeFileAtomic(path, []byte("hello")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Fatalf("unexpected content: %q", data)
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"dict-be/internal/config"
+	"dict-be/internal/llm"
+
+	"github.com/spf13/cobra"
+)
+
+func newLLMUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use [model]",
+		Short: "Pick the model to use and save it to the config file",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLLMUse(cmd, args)
+		},
+	}
+}
+
+func runLLMUse(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.LLM.Type == "" {
+		cfg.LLM.Type = "openai"
+	}
+
+	var model string
+	if len(args) > 0 {
+		model = strings.TrimSpace(args[0])
+	} else {
+		// Listing does not need a model, but the clients require one.
+		llmCfg := cfg.LLM
+		llmCfg.Model = firstNonEmpty(llmCfg.Model, "-")
+		client, err := newProviderClient(llmCfg)
+		if err != nil {
+			return err
+		}
+		models, err := llm.ListModels(commandContext(cmd), client)
+		if err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			return errors.New("provider returned no models")
+		}
+		if model, err = pickModel(cmd.InOrStdin(), cmd.OutOrStdout(), models, cfg.LLM.Model); err != nil {
+			return err
+		}
+	}
+	if model == "" {
+		return errors.New("model is required")
+	}
+
+	key := "llm.model"
+	if cfg.Profile != "" {
+		key = "profiles." + cfg.Profile + ".model"
+	}
+	if err := config.SetModel(config.File(), cfg.Profile, model); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s set to %s in %s\n", key, model, config.File())
+	return nil
+}
+
+// pickModel prints a numbered list of models, marking current, and reads a
+// number or model ID from in.
+func pickModel(in io.Reader, out io.Writer, models []string, current string) (string, error) {
+	for i, model := range models {
+		marker := " "
+		if model == current {
+			marker = "*"
+		}
+		fmt.Fprintf(out, "%s %3d) %s\n", marker, i+1, model)
+	}
+	fmt.Fprintf(out, "Select a model [1-%d]: ", len(models))
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read selection: %w", err)
+	}
+	choice := strings.TrimSpace(line)
+	if choice == "" {
+		return "", errors.New("no model selected")
+	}
+	if n, err := strconv.Atoi(choice); err == nil {
+		if n < 1 || n > len(models) {
+			return "", fmt.Errorf("selection out of range: %d", n)
+		}
+		return models[n-1], nil
+	}
+	for _, model := range models {
+		if model == choice {
+			return model, nil
+		}
+	}
+	return "", fmt.Errorf("unknown model: %s", choice)
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPickModel(t *testing.T) {
+	models := []string{"gpt-4o", "gpt-4o-mini", "o3-mini"}
+	var out bytes.Buffer
+	model, err := pickModel(strings.NewReader("2\n"), &out, models, "gpt-4o")
+	if err != nil {
+		t.Fatalf("pick model: %v", err)
+	}
+	if model != "gpt-4o-mini" {
+		t.Fatalf("unexpected model: %s", model)
+	}
+	if !strings.Contains(out.String(), "*   1) gpt-4o\n") {
+		t.Fatalf("expected current model to be marked:\n%s", out.String())
+	}
+
+	if model, err := pickModel(strings.NewReader("o3-mini"), &out, models, ""); err != nil || model != "o3-mini" {
+		t.Fatalf("expected pick by id, got %q, %v", model, err)
+	}
+	for _, input := range []string{"", "4\n", "gpt-5\n"} {
+		if _, err := pickModel(strings.NewReader(input), &out, models, ""); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+}
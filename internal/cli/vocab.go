@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"dict-be/internal/llm"
+	"dict-be/internal/vocab"
+
+	"github.com/spf13/cobra"
+)
+
+func newVocabCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vocab",
+		Short: "Manage the words saved with query --save and define --save",
+	}
+	cmd.AddCommand(newVocabListCmd())
+	cmd.AddCommand(newVocabShowCmd())
+	cmd.AddCommand(newVocabDeleteCmd())
+	cmd.AddCommand(newVocabExportCmd())
+	return cmd
+}
+
+type vocabExportOptions struct {
+	Format string
+	Output string
+}
+
+func newVocabListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List saved words: ID, word, date saved and a summary",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := newVocabStore()
+			if err != nil {
+				return err
+			}
+			notebook, err := store.Load()
+			if err != nil {
+				return err
+			}
+			for _, entry := range notebook.Entries {
+				fmt.Fprintf(cmd.OutOrStdout(), "%d\t%s\t%s\t%s\n", entry.ID, entry.Word, entry.Added.Local().Format("2006-01-02"), vocabSummary(entry))
+			}
+			return nil
+		},
+	}
+}
+
+func newVocabShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id|word>",
+		Short: "Print a saved word's answer",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := newVocabStore()
+			if err != nil {
+				return err
+			}
+			notebook, err := store.Load()
+			if err != nil {
+				return err
+			}
+			entry, ok := notebook.Find(args[0])
+			if !ok {
+				return fmt.Errorf("no saved word %q", args[0])
+			}
+			quizzes := ""
+			if entry.Quizzed > 0 {
+				quizzes = fmt.Sprintf(", %d of %d quiz answers correct", entry.Correct, entry.Quizzed)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "#%d %s (%s, saved %s%s)\n\n%s\n", entry.ID, entry.Word, entry.Command,
+				entry.Added.Local().Format("2006-01-02"), quizzes, strings.TrimRight(entry.Content, "\n"))
+			return nil
+		},
+	}
+}
+
+func newVocabDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <id|word...>",
+		Short: "Delete saved words",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := newVocabStore()
+			if err != nil {
+				return err
+			}
+			deleted, err := store.Delete(args)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "deleted %d saved words\n", len(deleted))
+			return nil
+		},
+	}
+}
+
+func newVocabExportCmd() *cobra.Command {
+	opts := &vocabExportOptions{}
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export saved words for import into a flashcard app",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVocabExport(cmd, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Format, "format", "anki", "export format: anki")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "export file (default: stdout)")
+	return cmd
+}
+
+func runVocabExport(cmd *cobra.Command, opts *vocabExportOptions) error {
+	if opts.Format != "anki" {
+		return fmt.Errorf("invalid format: %s (expected anki)", opts.Format)
+	}
+	store, err := newVocabStore()
+	if err != nil {
+		return err
+	}
+	notebook, err := store.Load()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := vocab.WriteAnki(&buf, notebook.Entries); err != nil {
+		return err
+	}
+	if opts.Output == "" {
+		_, err = cmd.OutOrStdout().Write(buf.Bytes())
+		return err
+	}
+	if err := writeFileAtomic(opts.Output, buf.Bytes()); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "wrote %d saved words to %s\n", len(notebook.Entries), opts.Output)
+	return nil
+}
+
+func newVocabStore() (*vocab.Store, error) {
+	path, err := vocab.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return vocab.NewStore(path), nil
+}
+
+// saveVocab stores entry in the notebook and reports its ID on stderr.
+func saveVocab(cmd *cobra.Command, entry vocab.Entry) error {
+	store, err := newVocabStore()
+	if err != nil {
+		return err
+	}
+	saved, err := store.Save(entry)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "saved %q as #%d\n", saved.Word, saved.ID)
+	return nil
+}
+
+// vocabSummary is the translation, or the first line of the answer, cut
+// to fit a list line.
+func vocabSummary(entry vocab.Entry) string {
+	return summarizeLine(firstNonEmpty(entry.Translation, entry.Content))
+}
+
+// summarizeLine is the first non-blank line of text without markdown
+// heading and list markers, cut to fit a list line.
+func summarizeLine(text string) string {
+	summary := ""
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(strings.TrimLeft(line, "#*- ")); line != "" {
+			summary = line
+			break
+		}
+	}
+	summary = strings.Join(strings.Fields(summary), " ")
+	if runes := []rune(summary); len(runes) > 60 {
+		summary = string(runes[:59]) + "…"
+	}
+	return summary
+}
+
+// queryVocabEntry builds the entry saved by query --save. A structured
+// answer keeps its fields for later export; free text is kept as is.
+func queryVocabEntry(input, inputLanguage, outputLanguage, content string, structured bool, sections []string) vocab.Entry {
+	entry := vocab.Entry{
+		Word:           strings.TrimSpace(input),
+		Command:        "query",
+		InputLanguage:  inputLanguage,
+		OutputLanguage: outputLanguage,
+		Content:        content,
+	}
+	if !structured {
+		return entry
+	}
+	answer, err := parseQueryAnswer(content, input, sections)
+	if err != nil {
+		return entry
+	}
+	entry.Translation = answer.Translation
+	entry.Mnemonics = answer.Mnemonics
+	for _, example := range answer.Examples {
+		entry.Examples = append(entry.Examples, vocab.Example{Sentence: example.Sentence, Translation: example.Translation})
+	}
+	entry.Content = answer.markdown()
+	return entry
+}
+
+// responseRecorder keeps the last successful response, for commands that
+// save their answer after printing it.
+type responseRecorder struct {
+	client llm.Client
+	last   llm.ChatResponse
+}
+
+func (r *responseRecorder) Unwrap() llm.Client { return r.client }
+
+func (r *responseRecorder) Chat(ctx context.Context, req llm.ChatRequest) (llm.ChatResponse, error) {
+	resp, err := r.client.Chat(ctx, req)
+	if err == nil {
+		r.last = resp
+	}
+	return resp, err
+}
+
+func (r *responseRecorder) ChatStream(ctx context.Context, req llm.ChatRequest, handle llm.StreamHandler) (llm.ChatResponse, error) {
+	resp, err := r.client.ChatStream(ctx, req, handle)
+	if err == nil {
+		r.last = resp
+	}
+	return resp, err
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"dict-be/internal/vocab"
+)
+
+func TestVocabCommands(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	run := func(args ...string) (string, error) {
+		cmd := newVocabCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	saveCmd := newVocabCmd()
+	saveCmd.SetErr(&bytes.Buffer{})
+	entries := []vocab.Entry{
+		queryVocabEntry("pier", "English", "Simplified Chinese", testQueryAnswer, true, []string{"translation", "mnemonics", "examples"}),
+		{Word: "run", Command: "define", Content: "## run /rʌn/\n\n1. (v.) move fast on foot"},
+	}
+	for _, entry := range entries {
+		if err := saveVocab(saveCmd, entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	out, err := run("list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "1\tpier\t") || !strings.HasSuffix(lines[0], "\t码头") ||
+		!strings.HasSuffix(lines[1], "\trun /rʌn/") {
+		t.Fatalf("unexpected list:\n%s", out)
+	}
+	if out, err = run("show", "pier"); err != nil || !strings.HasPrefix(out, "#1 pier (query, saved ") ||
+		!strings.Contains(out, "## Examples\n\n- We met on the pier.") {
+		t.Fatalf("unexpected show: %q, %v", out, err)
+	}
+	if _, err := run("show", "9"); err == nil {
+		t.Fatalf("expected an error for an unknown ID")
+	}
+	if out, err = run("export"); err != nil || !strings.Contains(out, "\npier\t码头<br><br>") {
+		t.Fatalf("unexpected export: %q, %v", out, err)
+	}
+	if _, err := run("export", "--format", "apkg"); err == nil {
+		t.Fatalf("expected an error for an unknown format")
+	}
+	if out, err = run("delete", "1"); err != nil || out != "deleted 1 saved words\n" {
+		t.Fatalf("unexpected delete: %q, %v", out, err)
+	}
+	if out, _ = run("list"); !strings.HasPrefix(out, "2\trun\t") {
+		t.Fatalf("unexpected list after delete:\n%s", out)
+	}
+}
+
+func TestQueryVocabEntry(t *testing.T) {
+	entry := queryVocabEntry(" pier\n", "English", "Chinese", testQueryAnswer, true, []string{"translation", "examples"})
+	if entry.Word != "pier" || entry.Translation != "码头" || len(entry.Examples) != 1 || entry.Mnemonics != nil {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	entry = queryVocabEntry("pier", "English", "Chinese", "Translation: 码头", false, nil)
+	if entry.Translation != "" || entry.Content != "Translation: 码头" {
+		t.Fatalf("unexpected free-text entry: %+v", entry)
+	}
+}
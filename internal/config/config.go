@@ -2,19 +2,211 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
+
+	"dict-be/internal/llm"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	LLM LLMConfig `mapstructure:"llm"`
+	// DefaultLLM is the llm section as configured, before an active
+	// profile replaced LLM.
+	DefaultLLM LLMConfig `mapstructure:"-"`
+	// Profile names the entry of Profiles that replaces LLM, if any.
+	Profile     string               `mapstructure:"profile"`
+	Profiles    map[string]LLMConfig `mapstructure:"profiles"`
+	Query       QueryConfig          `mapstructure:"query"`
+	Notify      bool                 `mapstructure:"notify"`
+	Postprocess []PostprocessConfig  `mapstructure:"postprocess"`
+	Secrets     SecretsConfig        `mapstructure:"secrets"`
+	Audit       AuditConfig          `mapstructure:"audit"`
+	Logging     LoggingConfig        `mapstructure:"logging"`
+	Preferences PreferencesConfig    `mapstructure:"preferences"`
+	History     HistoryConfig        `mapstructure:"history"`
+	Cache       CacheConfig          `mapstructure:"cache"`
+	// Languages maps short names such as "ja" to the language names used
+	// in prompts, for --in, --out and the query language settings.
+	Languages map[string]string `mapstructure:"languages"`
+	// Level is the learner level, such as B1 or HSK4, used when --level
+	// is not given.
+	Level string `mapstructure:"level"`
+	// PromptProfiles are the query prompt sets selected with
+	// query --prompt-profile, in addition to the built-in ones.
+	PromptProfiles map[string]PromptProfileConfig `mapstructure:"prompt_profiles"`
+	// Dictionaries are offline headword lists (ECDICT CSV, WordNet index,
+	// StarDict .idx or plain text) searched by the match command.
+	Dictionaries []string `mapstructure:"dictionaries"`
+	// Timeout and Proxy are set by the --timeout and --proxy flags and
+	// replace the timeout and proxy of the llm section and every profile.
+	Timeout time.Duration `mapstructure:"timeout"`
+	Proxy   string        `mapstructure:"proxy"`
+	// Record and Replay are set by the --record and --replay flags and
+	// apply to the llm section and every profile.
+	Record string `mapstructure:"record"`
+	Replay string `mapstructure:"replay"`
+	// Poll is set by the --poll flag and turns on polling for the llm
+	// section and every profile.
+	Poll bool `mapstructure:"poll"`
+}
+
+// PreferencesConfig opts in to learning defaults for --in, --out, --style,
+// --level and --tone from the values passed most often.
+type PreferencesConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// HistoryConfig turns the query history on or off; it is on by default.
+type HistoryConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// CacheConfig controls the response cache used by query. Entries older
+// than TTL are not used; MaxMB caps the cache size. Zero means no limit.
+type CacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`
+	MaxMB   int           `mapstructure:"max_mb"`
+}
+
+// LoggingConfig sets the level of diagnostic logs written to stderr; debug
+// logs every LLM request.
+type LoggingConfig struct {
+	Level string `mapstructure:"level"`
+}
+
+// AuditConfig enables the JSONL log of every LLM request when Path is set.
+// Prices, in USD per million tokens, add a cost to logged requests.
+type AuditConfig struct {
+	Path   string        `mapstructure:"path"`
+	Prices []PriceConfig `mapstructure:"prices"`
+}
+
+type PriceConfig struct {
+	Model  string  `mapstructure:"model"`
+	Input  float64 `mapstructure:"input"`
+	Output float64 `mapstructure:"output"`
+}
+
+// SecretsConfig controls the pre-flight scan for credentials and internal
+// host names in input sent to the LLM.
+type SecretsConfig struct {
+	Mode            string   `mapstructure:"mode"`
+	InternalDomains []string `mapstructure:"internal_domains"`
+}
+
+// QueryConfig holds query defaults. When --in and --out are both auto,
+// input in DefaultOut is translated to DefaultIn and any other input to
+// DefaultOut, unless AutoOut maps the detected input language to an
+// output language.
+type QueryConfig struct {
+	Sections   []string          `mapstructure:"sections"`
+	DefaultIn  string            `mapstructure:"default_in"`
+	DefaultOut string            `mapstructure:"default_out"`
+	AutoOut    map[string]string `mapstructure:"auto_out"`
+}
+
+// PromptProfileConfig is a named set of query prompt templates. System
+// and User are text/template sources over the query prompt variables; an
+// empty one keeps the standard query template. Model, if set, replaces
+// the configured model.
+type PromptProfileConfig struct {
+	System string `mapstructure:"system"`
+	User   string `mapstructure:"user"`
+	Model  string `mapstructure:"model"`
+}
+
+// PostprocessConfig is one output post-processing step: a built-in name
+// or a shell command that reads the output on stdin.
+type PostprocessConfig struct {
+	Builtin string `mapstructure:"builtin"`
+	Command string `mapstructure:"command"`
 }
 
 type LLMConfig struct {
-	URL   string `mapstructure:"url"`
-	Model string `mapstructure:"model"`
-	Token string `mapstructure:"token"`
-	Type  string `mapstructure:"type"`
+	URL        string           `mapstructure:"url"`
+	Model      string           `mapstructure:"model"`
+	Token      string           `mapstructure:"token"`
+	Type       string           `mapstructure:"type"`
+	Azure      AzureConfig      `mapstructure:"azure"`
+	Bedrock    BedrockConfig    `mapstructure:"bedrock"`
+	OpenRouter OpenRouterConfig `mapstructure:"openrouter"`
+	Vertex     VertexConfig     `mapstructure:"vertex"`
+	LlamaCpp   LlamaCppConfig   `mapstructure:"llamacpp"`
+	Mock       MockConfig       `mapstructure:"mock"`
+	// SystemPrompt is merged under the system prompt of every request
+	// sent with this config.
+	SystemPrompt string `mapstructure:"system_prompt"`
+	// UserAgent replaces the default dict-be/<version> User-Agent header.
+	UserAgent string `mapstructure:"user_agent"`
+	// Proxy is an http, https, socks5 or socks5h URL that requests go
+	// through instead of the proxy from the environment.
+	Proxy string `mapstructure:"proxy"`
+	// Retries is how often transient failures are retried; nil means the
+	// llm package default. RetryBackoff is the base of the exponential
+	// backoff between retries.
+	Retries      *int          `mapstructure:"retries"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	// Timeout bounds each request; StreamIdleTimeout bounds the wait for
+	// each streamed delta. Zero means the llm package default.
+	Timeout           time.Duration `mapstructure:"timeout"`
+	StreamIdleTimeout time.Duration `mapstructure:"stream_idle_timeout"`
+	// StreamReconnects is how often a broken stream is re-issued to
+	// continue the answer; nil means the llm package default.
+	StreamReconnects *int `mapstructure:"stream_reconnects"`
+	// RPM and TPM cap requests and tokens per minute; zero means no limit.
+	RPM int `mapstructure:"rpm"`
+	TPM int `mapstructure:"tpm"`
+	// Poll sends streamed requests without streaming and prints the
+	// answer word by word, for gateways that buffer streams.
+	Poll bool `mapstructure:"poll"`
+	// Record and Replay are cassette paths taken from the --record and
+	// --replay flags; they cannot be set in the config file.
+	Record string `mapstructure:"-"`
+	Replay string `mapstructure:"-"`
+}
+
+// AzureConfig holds the routing fields used when llm.type is azure-openai.
+type AzureConfig struct {
+	Resource   string `mapstructure:"resource"`
+	Deployment string `mapstructure:"deployment"`
+	APIVersion string `mapstructure:"api_version"`
+}
+
+// BedrockConfig selects the AWS region and shared credentials profile used
+// when llm.type is bedrock.
+type BedrockConfig struct {
+	Region  string `mapstructure:"region"`
+	Profile string `mapstructure:"profile"`
+}
+
+// OpenRouterConfig lists fallback models tried in order after llm.model
+// when llm.type is openrouter.
+type OpenRouterConfig struct {
+	Models []string `mapstructure:"models"`
+}
+
+// VertexConfig switches llm.type gemini to Vertex AI when Project is set.
+type VertexConfig struct {
+	Project     string `mapstructure:"project"`
+	Location    string `mapstructure:"location"`
+	Credentials string `mapstructure:"credentials"`
+}
+
+// LlamaCppConfig selects the chat template used to build prompts when
+// llm.type is llamacpp.
+type LlamaCppConfig struct {
+	Template string `mapstructure:"template"`
+}
+
+// MockConfig sets the canned answers used when llm.type is mock: Response
+// is a template for every request, Fixtures a directory of per-prompt
+// responses.
+type MockConfig struct {
+	Response string `mapstructure:"response"`
+	Fixtures string `mapstructure:"fixtures"`
 }
 
 func Load() (Config, error) {
@@ -22,20 +214,118 @@ func Load() (Config, error) {
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return cfg, err
 	}
+	// Viper lowercases map keys, so profile names match case-insensitively.
+	cfg.Profile = strings.ToLower(strings.TrimSpace(cfg.Profile))
 	if err := cfg.Validate(); err != nil {
 		return cfg, err
 	}
+	cfg.LLM = cfg.applyOverrides(cfg.LLM)
+	for name, profile := range cfg.Profiles {
+		cfg.Profiles[name] = cfg.applyOverrides(profile)
+	}
+	cfg.DefaultLLM = cfg.LLM
+	if cfg.Profile != "" {
+		cfg.LLM = cfg.Profiles[cfg.Profile]
+	}
 	return cfg, nil
 }
 
+// applyOverrides returns llm with the settings given as global flags.
+func (c Config) applyOverrides(llm LLMConfig) LLMConfig {
+	if c.Timeout > 0 {
+		llm.Timeout = c.Timeout
+	}
+	if c.Proxy != "" {
+		llm.Proxy = c.Proxy
+	}
+	if c.Poll {
+		llm.Poll = true
+	}
+	llm.Record = c.Record
+	llm.Replay = c.Replay
+	return llm
+}
+
 func (c Config) Validate() error {
-	if c.LLM.Type == "" {
-		return nil
+	switch c.Secrets.Mode {
+	case "", "off", "warn", "block":
+	default:
+		return fmt.Errorf("invalid secrets.mode: %s", c.Secrets.Mode)
 	}
-	switch c.LLM.Type {
-	case "openai", "anthropics", "gemini":
-		return nil
+	switch c.Logging.Level {
+	case "", "debug", "info", "warn", "error":
 	default:
-		return fmt.Errorf("invalid llm.type: %s", c.LLM.Type)
+		return fmt.Errorf("invalid logging.level: %s", c.Logging.Level)
+	}
+	if c.Profile != "" {
+		if _, ok := c.Profiles[c.Profile]; !ok {
+			return fmt.Errorf("unknown profile: %s", c.Profile)
+		}
+	}
+	if err := validateLLM("llm", c.LLM); err != nil {
+		return err
+	}
+	for name, profile := range c.Profiles {
+		if err := validateLLM("profiles."+name, profile); err != nil {
+			return err
+		}
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("invalid timeout: %s", c.Timeout)
+	}
+	if c.Cache.TTL < 0 {
+		return fmt.Errorf("invalid cache.ttl: %s", c.Cache.TTL)
+	}
+	if c.Cache.MaxMB < 0 {
+		return fmt.Errorf("invalid cache.max_mb: %d", c.Cache.MaxMB)
+	}
+	for key, value := range map[string]string{"query.default_in": c.Query.DefaultIn, "query.default_out": c.Query.DefaultOut} {
+		if strings.EqualFold(strings.TrimSpace(value), "auto") {
+			return fmt.Errorf("invalid %s: auto", key)
+		}
+	}
+	if c.Record != "" && c.Replay != "" {
+		return fmt.Errorf("--record and --replay cannot be combined")
+	}
+	return nil
+}
+
+func validateLLM(prefix string, cfg LLMConfig) error {
+	if err := validateLLMType(prefix+".type", cfg.Type); err != nil {
+		return err
+	}
+	if cfg.Retries != nil && *cfg.Retries < 0 {
+		return fmt.Errorf("invalid %s.retries: %d", prefix, *cfg.Retries)
+	}
+	if cfg.RetryBackoff < 0 {
+		return fmt.Errorf("invalid %s.retry_backoff: %s", prefix, cfg.RetryBackoff)
+	}
+	if cfg.Timeout < 0 {
+		return fmt.Errorf("invalid %s.timeout: %s", prefix, cfg.Timeout)
+	}
+	if cfg.StreamIdleTimeout < 0 {
+		return fmt.Errorf("invalid %s.stream_idle_timeout: %s", prefix, cfg.StreamIdleTimeout)
+	}
+	if cfg.StreamReconnects != nil && *cfg.StreamReconnects < 0 {
+		return fmt.Errorf("invalid %s.stream_reconnects: %d", prefix, *cfg.StreamReconnects)
+	}
+	if cfg.RPM < 0 {
+		return fmt.Errorf("invalid %s.rpm: %d", prefix, cfg.RPM)
+	}
+	if cfg.TPM < 0 {
+		return fmt.Errorf("invalid %s.tpm: %d", prefix, cfg.TPM)
+	}
+	return nil
+}
+
+func validateLLMType(key, value string) error {
+	if value == "" {
+		return nil
+	}
+	for _, provider := range llm.Providers() {
+		if value == provider {
+			return nil
+		}
 	}
+	return fmt.Errorf("invalid %s: %s", key, value)
 }
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// File returns the path of the config file in use.
+func File() string {
+	return viper.ConfigFileUsed()
+}
+
+// SetModel writes model as llm.model, or as profiles.<profile>.model when
+// profile is set, to the YAML config file at path. Other keys, comments and
+// ${VAR} references are kept; the file is created if it does not exist.
+func SetModel(path, profile, model string) error {
+	if path == "" {
+		return errors.New("no config file to write")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read config: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parse config: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("parse config: %s is not a mapping", path)
+	}
+
+	keys := []string{"llm"}
+	if profile != "" {
+		keys = []string{"profiles", profile}
+	}
+	section := root
+	for _, key := range keys {
+		if section, err = mappingValue(section, key); err != nil {
+			return err
+		}
+	}
+	value := lookupKey(section, "model")
+	if value == nil {
+		section.Content = append(section.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "model"},
+			&yaml.Node{Kind: yaml.ScalarNode})
+		value = section.Content[len(section.Content)-1]
+	}
+	*value = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: model, LineComment: value.LineComment}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, buf.Bytes(), mode); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// mappingValue returns the mapping stored under key, adding an empty one
+// when the key is missing. Keys match case-insensitively, as viper does.
+func mappingValue(node *yaml.Node, key string) (*yaml.Node, error) {
+	value := lookupKey(node, key)
+	if value == nil {
+		value = &yaml.Node{Kind: yaml.MappingNode}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	}
+	if value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+		*value = yaml.Node{Kind: yaml.MappingNode}
+	}
+	if value.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config key %s is not a mapping", key)
+	}
+	return value, nil
+}
+
+func lookupKey(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
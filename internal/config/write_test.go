@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetModel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	original := "# dict-be\nllm:\n  type: openai\n  model: gpt-4o-mini # cheap\n  token: ${OPENAI_API_KEY}\nprofiles:\n  Local:\n    type: openai\n"
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	if err := SetModel(path, "", "gpt-4o"); err != nil {
+		t.Fatalf("set model: %v", err)
+	}
+	if err := SetModel(path, "local", "qwen2.5:7b"); err != nil {
+		t.Fatalf("set profile model: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{"# dict-be", "model: gpt-4o # cheap", "token: ${OPENAI_API_KEY}", "  Local:\n    type: openai\n    model: qwen2.5:7b"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o644 {
+		t.Fatalf("unexpected mode: %v", info.Mode().Perm())
+	}
+}
+
+func TestSetModelCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := SetModel(path, "", "gpt-4o"); err != nil {
+		t.Fatalf("set model: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if string(data) != "llm:\n  model: gpt-4o\n" {
+		t.Fatalf("unexpected config: %q", data)
+	}
+}
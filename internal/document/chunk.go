@@ -0,0 +1,111 @@
+package document
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// SplitChunks groups the paragraphs of text into chunks of at most limit
+// characters, so a long document can be sent in requests that fit the
+// model's context. Chunks break only between paragraphs, except that a
+// paragraph longer than limit is broken between sentences; a single
+// sentence longer than limit becomes a chunk of its own. As with
+// SplitParagraphs, JoinParagraphs(chunks, separators) reproduces text.
+func SplitChunks(text string, limit int) (chunks []string, separators []string) {
+	chunks, separators, _ = splitChunks(text, limit, false)
+	return chunks, separators
+}
+
+// SplitMarkdownChunks is SplitChunks over the paragraphs of SplitMarkdown.
+// Front matter and each fenced code block make a chunk of their own,
+// whatever their length, and verbatim reports which chunks they are.
+func SplitMarkdownChunks(text string, limit int) (chunks []string, separators []string, verbatim []bool) {
+	return splitChunks(text, limit, true)
+}
+
+func splitChunks(text string, limit int, markdown bool) (chunks []string, separators []string, verbatim []bool) {
+	paragraphs, paragraphSeparators, keep := splitParagraphs(text, markdown)
+	emit := func(chunk, separator string, kept bool) {
+		if len(chunks) > 0 {
+			separators = append(separators, separator)
+		}
+		chunks = append(chunks, chunk)
+		verbatim = append(verbatim, kept)
+	}
+	var current strings.Builder
+	currentSeparator := ""
+	size := 0
+	flush := func() {
+		if size > 0 {
+			emit(current.String(), currentSeparator, false)
+			current.Reset()
+			size = 0
+		}
+	}
+	add := func(piece, separator string) {
+		n := utf8.RuneCountInString(piece)
+		separatorSize := utf8.RuneCountInString(separator)
+		if size > 0 && size+separatorSize+n > limit {
+			flush()
+		}
+		if size == 0 {
+			currentSeparator = separator
+		} else {
+			current.WriteString(separator)
+			size += separatorSize
+		}
+		current.WriteString(piece)
+		size += n
+	}
+	for i, paragraph := range paragraphs {
+		separator := ""
+		if i > 0 {
+			separator = paragraphSeparators[i-1]
+		}
+		switch {
+		case keep[i]:
+			flush()
+			emit(paragraph, separator, true)
+		case utf8.RuneCountInString(paragraph) <= limit:
+			add(paragraph, separator)
+		default:
+			pieces, pieceSeparators := splitSentencePieces(paragraph)
+			for j, piece := range pieces {
+				if j > 0 {
+					separator = pieceSeparators[j-1]
+				}
+				add(piece, separator)
+			}
+		}
+	}
+	flush()
+	return chunks, separators, verbatim
+}
+
+// splitSentencePieces splits paragraph into its sentences and the
+// whitespace between them, so that joining them back gives paragraph.
+func splitSentencePieces(paragraph string) (pieces []string, separators []string) {
+	rest := paragraph
+	for _, sentence := range SplitSentences(paragraph) {
+		start := strings.Index(rest, sentence)
+		if start < 0 {
+			break
+		}
+		end := start + len(sentence)
+		if len(pieces) == 0 {
+			// Leading indentation stays with the first sentence.
+			pieces = append(pieces, rest[:end])
+		} else {
+			separators = append(separators, rest[:start])
+			pieces = append(pieces, sentence)
+		}
+		rest = rest[end:]
+	}
+	if len(pieces) == 0 {
+		return []string{paragraph}, nil
+	}
+	// Keep anything SplitSentences trimmed off the end, such as trailing
+	// spaces, with the last sentence.
+	pieces[len(pieces)-1] += rest
+	return pieces, separators
+}
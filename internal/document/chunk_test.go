@@ -0,0 +1,51 @@
+package document
+
+import "testing"
+
+func TestSplitChunks(t *testing.T) {
+	text := "# Title\n\nshort one\n\n\nshort two\n\n  First sentence here. Second sentence here.  Third one!\n\nlast"
+	chunks, separators := SplitChunks(text, 25)
+	want := []string{
+		"# Title\n\nshort one",
+		"short two",
+		"  First sentence here.",
+		"Second sentence here.",
+		"Third one!\n\nlast",
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("unexpected chunks: %q", chunks)
+	}
+	for i := range want {
+		if chunks[i] != want[i] {
+			t.Fatalf("chunk %d: got %q, want %q", i, chunks[i], want[i])
+		}
+	}
+	if separators[1] != "\n\n" || separators[2] != " " || separators[3] != "  " {
+		t.Fatalf("unexpected separators: %q", separators)
+	}
+	if got := JoinParagraphs(chunks, separators); got != text {
+		t.Fatalf("unexpected join: %q", got)
+	}
+
+	chunks, _ = SplitChunks(text, 1000)
+	if len(chunks) != 1 || chunks[0] != text {
+		t.Fatalf("unexpected single chunk: %q", chunks)
+	}
+	chunks, _ = SplitChunks("one very long sentence without a stop", 10)
+	if len(chunks) != 1 {
+		t.Fatalf("unexpected chunks for an unsplittable sentence: %q", chunks)
+	}
+}
+
+func TestSplitMarkdownChunks(t *testing.T) {
+	chunks, separators, verbatim := SplitMarkdownChunks(testMarkdown, 1000)
+	if len(chunks) != 5 || !verbatim[0] || verbatim[1] || !verbatim[2] || verbatim[3] || !verbatim[4] {
+		t.Fatalf("unexpected chunks: %q %v", chunks, verbatim)
+	}
+	if chunks[1] != "# Install\n\nRun this:" || chunks[3] != "Then read the docs." {
+		t.Fatalf("unexpected prose chunks: %q", chunks)
+	}
+	if got := JoinParagraphs(chunks, separators); got != testMarkdown {
+		t.Fatalf("unexpected join: %q", got)
+	}
+}
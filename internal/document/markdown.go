@@ -0,0 +1,95 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// inlinePatterns match the parts of a markdown paragraph that must not be
+// translated. When a pattern has a group, only the group is protected, so
+// the link text around a destination stays translatable. Earlier patterns
+// win: a URL inside inline code is protected as code.
+var inlinePatterns = []*regexp.Regexp{
+	regexp.MustCompile("``.+?``|`[^`\n]+`"),
+	regexp.MustCompile(`<(?:https?|ftp|mailto):[^>\s]+>`),
+	regexp.MustCompile(`\]\(\s*(<[^>\n]*>|[^)\s]+)`),
+	regexp.MustCompile(`(?m)^ {0,3}\[[^\]\n]+\]:[ \t]*(\S+)`),
+	regexp.MustCompile("(?:https?|ftp)://[^\\s<>()\\[\\]\"'`]*[^\\s<>()\\[\\]\"'`.,;:!?]"),
+}
+
+// Placeholders stand for protected text while a paragraph is translated.
+// The translate prompt asks the model to keep them as they are.
+const (
+	placeholderOpen  = "⟦"
+	placeholderClose = "⟧"
+)
+
+var placeholderPattern = regexp.MustCompile(placeholderOpen + `\d+` + placeholderClose)
+
+// ProtectMarkdown wraps translate so that inline code, URLs and link
+// destinations in a paragraph are replaced by numbered placeholders
+// before it is sent and put back afterwards. A paragraph with nothing but
+// protected text and punctuation is returned without calling translate.
+// A translation that drops a placeholder is an error rather than a
+// paragraph with its code or link missing.
+func ProtectMarkdown(translate TranslateFunc) TranslateFunc {
+	return func(ctx context.Context, text string) (string, error) {
+		masked, protected := maskInline(text)
+		if len(protected) == 0 {
+			return translate(ctx, text)
+		}
+		if !strings.ContainsFunc(placeholderPattern.ReplaceAllString(masked, ""), unicode.IsLetter) {
+			return text, nil
+		}
+		output, err := translate(ctx, masked)
+		if err != nil {
+			return "", err
+		}
+		return unmaskInline(output, protected)
+	}
+}
+
+func placeholder(n int) string {
+	return placeholderOpen + strconv.Itoa(n) + placeholderClose
+}
+
+// maskInline replaces each protected span of text with placeholder(n),
+// numbering from 1, and returns the spans in that order.
+func maskInline(text string) (string, []string) {
+	var protected []string
+	for _, pattern := range inlinePatterns {
+		var b strings.Builder
+		last := 0
+		for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := match[0], match[1]
+			if len(match) > 2 && match[2] >= 0 {
+				start, end = match[2], match[3]
+			}
+			b.WriteString(text[last:start])
+			protected = append(protected, text[start:end])
+			b.WriteString(placeholder(len(protected)))
+			last = end
+		}
+		b.WriteString(text[last:])
+		text = b.String()
+	}
+	return text, protected
+}
+
+// unmaskInline puts the protected spans back in place of their
+// placeholders.
+func unmaskInline(text string, protected []string) (string, error) {
+	replacements := make([]string, 0, 2*len(protected))
+	for i, span := range protected {
+		name := placeholder(i + 1)
+		if !strings.Contains(text, name) {
+			return "", fmt.Errorf("translation dropped %s (%s)", name, span)
+		}
+		replacements = append(replacements, name, span)
+	}
+	return strings.NewReplacer(replacements...).Replace(text), nil
+}
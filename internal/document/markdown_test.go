@@ -0,0 +1,97 @@
+package document
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const testMarkdown = "---\ntitle: Setup\n\ntags: [go]\n---\n\n# Install\n\nRun this:\n```sh\ngo install ./...\n\n# then\ndict-be version\n```\nThen read the docs.\n\n~~~\nunclosed"
+
+func TestSplitMarkdown(t *testing.T) {
+	paragraphs, separators, verbatim := SplitMarkdown(testMarkdown)
+	want := []string{
+		"---\ntitle: Setup\n\ntags: [go]\n---",
+		"# Install",
+		"Run this:",
+		"```sh\ngo install ./...\n\n# then\ndict-be version\n```",
+		"Then read the docs.",
+		"~~~\nunclosed",
+	}
+	if len(paragraphs) != len(want) {
+		t.Fatalf("unexpected paragraphs: %q", paragraphs)
+	}
+	for i := range want {
+		if paragraphs[i] != want[i] {
+			t.Fatalf("paragraph %d: got %q, want %q", i, paragraphs[i], want[i])
+		}
+	}
+	for i, keep := range []bool{true, false, false, true, false, true} {
+		if verbatim[i] != keep {
+			t.Fatalf("unexpected verbatim flags: %v", verbatim)
+		}
+	}
+	if separators[2] != "\n" || separators[3] != "\n" {
+		t.Fatalf("unexpected separators: %q", separators)
+	}
+	if got := JoinParagraphs(paragraphs, separators); got != testMarkdown {
+		t.Fatalf("unexpected join: %q", got)
+	}
+	if paragraphs, _ := SplitParagraphs(testMarkdown); len(paragraphs) != 6 {
+		t.Fatalf("plain split should ignore markdown, got %q", paragraphs)
+	}
+}
+
+func TestTranslatorMarkdown(t *testing.T) {
+	var calls []string
+	translator := NewTranslator(func(ctx context.Context, text string) (string, error) {
+		calls = append(calls, text)
+		return strings.ToUpper(text), nil
+	})
+	translator.Markdown()
+	result, err := translator.Translate(context.Background(), testMarkdown)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "---\ntitle: Setup\n\ntags: [go]\n---\n\n# INSTALL\n\nRUN THIS:\n```sh\ngo install ./...\n\n# then\ndict-be version\n```\nTHEN READ THE DOCS.\n\n~~~\nunclosed"
+	if result.Text != want || len(calls) != 3 || result.Verbatim != 3 || result.Translated != 3 {
+		t.Fatalf("unexpected result: %+v (calls %q)", result, calls)
+	}
+	if pending := NewTranslator(nil).Pending(testMarkdown); pending != 6 {
+		t.Fatalf("unexpected pending count without markdown: %d", pending)
+	}
+	if got := translator.Paragraphs(testMarkdown); len(got) != 3 || got[2] != "Then read the docs." {
+		t.Fatalf("unexpected paragraphs: %q", got)
+	}
+}
+
+func TestProtectMarkdown(t *testing.T) {
+	var sent string
+	translate := ProtectMarkdown(func(ctx context.Context, text string) (string, error) {
+		sent = text
+		return strings.NewReplacer("Run", "Lancez", "see", "voir", "the guide", "le guide").Replace(text), nil
+	})
+	text := "Run `go test ./...` and see [the guide](https://example.com/guide \"Guide\") or <https://example.com>, also https://go.dev/doc."
+	got, err := translate(context.Background(), text)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent != "Run ⟦1⟧ and see [the guide](⟦3⟧ \"Guide\") or ⟦2⟧, also ⟦4⟧." {
+		t.Fatalf("unexpected masked text: %q", sent)
+	}
+	if got != "Lancez `go test ./...` and voir [le guide](https://example.com/guide \"Guide\") or <https://example.com>, also https://go.dev/doc." {
+		t.Fatalf("unexpected translation: %q", got)
+	}
+
+	sent = ""
+	if got, err := translate(context.Background(), "`make build` — https://example.com"); err != nil || got != "`make build` — https://example.com" || sent != "" {
+		t.Fatalf("code-only paragraph should not be sent: %q, %v (sent %q)", got, err, sent)
+	}
+
+	drop := ProtectMarkdown(func(ctx context.Context, text string) (string, error) {
+		return "Lancez la commande.", nil
+	})
+	if _, err := drop(context.Background(), "Run `make`."); err == nil || !strings.Contains(err.Error(), "⟦1⟧") {
+		t.Fatalf("expected an error for a dropped placeholder, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package document
+
+import "strings"
+
+// SplitParagraphs splits text on blank lines. Separators are kept so that
+// JoinParagraphs reproduces the original spacing.
+func SplitParagraphs(text string) (paragraphs []string, separators []string) {
+	paragraphs, separators, _ = splitParagraphs(text, false)
+	return paragraphs, separators
+}
+
+// SplitMarkdown is SplitParagraphs for markdown. Front matter at the top
+// of text and each fenced code block stay whole, blank lines included,
+// and verbatim reports which paragraphs they are, so they can be kept
+// untranslated. A code fence also ends the paragraph before it.
+func SplitMarkdown(text string) (paragraphs []string, separators []string, verbatim []bool) {
+	return splitParagraphs(text, true)
+}
+
+func splitParagraphs(text string, markdown bool) (paragraphs []string, separators []string, verbatim []bool) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+	var current []string
+	var blank []string
+	flush := func(keep bool) {
+		if len(current) == 0 {
+			return
+		}
+		if len(paragraphs) > 0 {
+			separator := "\n"
+			for _, line := range blank {
+				separator += line + "\n"
+			}
+			separators = append(separators, separator)
+		}
+		paragraphs = append(paragraphs, strings.Join(current, "\n"))
+		verbatim = append(verbatim, keep)
+		current = nil
+		blank = nil
+	}
+	if markdown {
+		if end := frontMatterEnd(lines); end > 0 {
+			current = lines[:end]
+			flush(true)
+			lines = lines[end:]
+		}
+	}
+	fence := ""
+	for _, line := range lines {
+		if fence != "" {
+			current = append(current, line)
+			if isClosingFence(line, fence) {
+				flush(true)
+				fence = ""
+			}
+			continue
+		}
+		if markdown {
+			if fence = openingFence(line); fence != "" {
+				flush(false)
+				current = append(current, line)
+				continue
+			}
+		}
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				flush(false)
+			}
+			if len(paragraphs) > 0 {
+				blank = append(blank, line)
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	// An unclosed fence runs to the end of the document.
+	flush(fence != "")
+	return paragraphs, separators, verbatim
+}
+
+// frontMatterEnd returns the number of lines of the YAML or TOML front
+// matter lines start with, or 0 when there is none.
+func frontMatterEnd(lines []string) int {
+	if len(lines) == 0 {
+		return 0
+	}
+	delimiter := strings.TrimRight(lines[0], " \t")
+	if delimiter != "---" && delimiter != "+++" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if line := strings.TrimRight(lines[i], " \t"); line == delimiter || (delimiter == "---" && line == "...") {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// openingFence returns the backtick or tilde run that opens a fenced code
+// block on line, indented by at most three spaces, or "".
+func openingFence(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return ""
+	}
+	for _, marker := range []byte{'`', '~'} {
+		n := 0
+		for n < len(trimmed) && trimmed[n] == marker {
+			n++
+		}
+		if n < 3 {
+			continue
+		}
+		// A backtick fence's info string cannot contain backticks.
+		if marker == '`' && strings.Contains(trimmed[n:], "`") {
+			return ""
+		}
+		return trimmed[:n]
+	}
+	return ""
+}
+
+// isClosingFence reports whether line closes a block opened by fence: a
+// run of the same character at least as long, with nothing after it.
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return false
+	}
+	trimmed = strings.TrimRight(trimmed, " \t")
+	return len(trimmed) >= len(fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// JoinParagraphs is the inverse of SplitParagraphs. Missing separators
+// default to a single blank line.
+func JoinParagraphs(paragraphs []string, separators []string) string {
+	var builder strings.Builder
+	for i, paragraph := range paragraphs {
+		if i > 0 {
+			if i-1 < len(separators) {
+				builder.WriteString(separators[i-1])
+			} else {
+				builder.WriteString("\n\n")
+			}
+		}
+		builder.WriteString(paragraph)
+	}
+	return builder.String()
+}
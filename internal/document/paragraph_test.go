@@ -0,0 +1,114 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSplitParagraphs(t *testing.T) {
+	paragraphs, separators := SplitParagraphs("# Title\n\nfirst line\nsecond line\n\n\nlast\n")
+	if len(paragraphs) != 3 {
+		t.Fatalf("unexpected paragraphs: %q", paragraphs)
+	}
+	if paragraphs[1] != "first line\nsecond line" {
+		t.Fatalf("unexpected paragraph: %q", paragraphs[1])
+	}
+	if separators[1] != "\n\n\n" {
+		t.Fatalf("unexpected separator: %q", separators[1])
+	}
+	if got := JoinParagraphs(paragraphs, separators); got != "# Title\n\nfirst line\nsecond line\n\n\nlast" {
+		t.Fatalf("unexpected join: %q", got)
+	}
+}
+
+func TestTranslatorReusesUnchangedParagraphs(t *testing.T) {
+	var calls []string
+	translator := NewTranslator(func(ctx context.Context, text string) (string, error) {
+		calls = append(calls, text)
+		return strings.ToUpper(text), nil
+	})
+
+	result, err := translator.Translate(context.Background(), "one\n\ntwo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Text != "ONE\n\nTWO" || result.Translated != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	if pending := translator.Pending("one\n\ntwo changed\n\nthree\n\nthree"); pending != 2 {
+		t.Fatalf("unexpected pending count: %d", pending)
+	}
+	result, err = translator.Translate(context.Background(), "one\n\ntwo changed\n\nthree")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Text != "ONE\n\nTWO CHANGED\n\nTHREE" {
+		t.Fatalf("unexpected text: %q", result.Text)
+	}
+	if result.Translated != 2 || result.Paragraphs != 3 {
+		t.Fatalf("unexpected counts: %+v", result)
+	}
+	if len(calls) != 4 {
+		t.Fatalf("unexpected calls: %q", calls)
+	}
+}
+
+func TestTranslatorDeduplicatesRepeatedParagraphs(t *testing.T) {
+	calls := 0
+	translator := NewTranslator(func(ctx context.Context, text string) (string, error) {
+		calls++
+		return strings.ToUpper(text), nil
+	})
+
+	result, err := translator.Translate(context.Background(), "header\n\nbody\n\nheader\n\nheader")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Text != "HEADER\n\nBODY\n\nHEADER\n\nHEADER" || calls != 2 {
+		t.Fatalf("unexpected result: %+v (calls %d)", result, calls)
+	}
+	if result.Duplicates != 2 || result.DuplicateChars != 12 || result.Translated != 2 {
+		t.Fatalf("unexpected counts: %+v", result)
+	}
+}
+
+func TestTranslatorContinueOnError(t *testing.T) {
+	calls := 0
+	translator := NewTranslator(func(ctx context.Context, text string) (string, error) {
+		calls++
+		if text == "bad" {
+			return "", errors.New("rate limited")
+		}
+		return strings.ToUpper(text), nil
+	})
+	translator.ContinueOnError(func(n int, source string) string {
+		return fmt.Sprintf("[%d: %s]", n, source)
+	})
+
+	result, err := translator.Translate(context.Background(), "one\n\nbad\n\ntwo\n\nbad")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Text != "ONE\n\n[2: bad]\n\nTWO\n\n[4: bad]" || calls != 3 {
+		t.Fatalf("unexpected result: %q (calls %d)", result.Text, calls)
+	}
+	if len(result.Failures) != 2 || result.Failures[1].Paragraph != 4 || result.Translated != 2 {
+		t.Fatalf("unexpected failures: %+v", result)
+	}
+	if len(result.Sections) != 4 || result.Sections[1] != (Pair{Source: "bad", Target: "[2: bad]"}) {
+		t.Fatalf("unexpected sections: %+v", result.Sections)
+	}
+	if pending := translator.Pending("one\n\nbad"); pending != 1 {
+		t.Fatalf("failed paragraph should stay pending, got %d", pending)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := translator.Translate(ctx, "bad"); err == nil {
+		t.Fatalf("expected cancellation to stop the run")
+	}
+}
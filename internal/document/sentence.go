@@ -0,0 +1,66 @@
+package document
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Pair is an aligned source/target segment.
+type Pair struct {
+	Source string
+	Target string
+}
+
+// SplitSentences splits text after sentence-ending punctuation. Latin
+// terminators need trailing whitespace; CJK terminators split immediately.
+func SplitSentences(text string) []string {
+	runes := []rune(strings.TrimSpace(text))
+	var sentences []string
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r != '。' && r != '！' && r != '？' && r != '.' && r != '!' && r != '?' {
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && isClosingPunct(runes[end]) {
+			end++
+		}
+		if (r == '.' || r == '!' || r == '?') && end < len(runes) && !unicode.IsSpace(runes[end]) {
+			continue
+		}
+		if sentence := strings.TrimSpace(string(runes[start:end])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = end
+		i = end - 1
+	}
+	if rest := strings.TrimSpace(string(runes[start:])); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
+
+func isClosingPunct(r rune) bool {
+	switch r {
+	case '"', '\'', ')', ']', '”', '’', '」', '』', '）':
+		return true
+	default:
+		return false
+	}
+}
+
+// AlignSentences pairs the sentences of a translated paragraph. When the
+// sentence counts differ the whole paragraph is kept as a single pair.
+func AlignSentences(source, target string) []Pair {
+	sourceSentences := SplitSentences(source)
+	targetSentences := SplitSentences(target)
+	if len(sourceSentences) == 0 || len(sourceSentences) != len(targetSentences) {
+		return []Pair{{Source: strings.TrimSpace(source), Target: strings.TrimSpace(target)}}
+	}
+	pairs := make([]Pair, len(sourceSentences))
+	for i := range sourceSentences {
+		pairs[i] = Pair{Source: sourceSentences[i], Target: targetSentences[i]}
+	}
+	return pairs
+}
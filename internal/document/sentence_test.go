@@ -0,0 +1,40 @@
+package document
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitSentences(t *testing.T) {
+	sentences := SplitSentences(`He said "hi." Then he left! e.g.3.5 is fine。你好吗？好。`)
+	expected := []string{`He said "hi."`, "Then he left!", "e.g.3.5 is fine。", "你好吗？", "好。"}
+	if len(sentences) != len(expected) {
+		t.Fatalf("unexpected sentences: %q", sentences)
+	}
+	for i := range expected {
+		if sentences[i] != expected[i] {
+			t.Fatalf("sentence %d: got %q, want %q", i, sentences[i], expected[i])
+		}
+	}
+}
+
+func TestAlignSentences(t *testing.T) {
+	pairs := AlignSentences("One. Two.", "一。二。")
+	if len(pairs) != 2 || pairs[1].Source != "Two." || pairs[1].Target != "二。" {
+		t.Fatalf("unexpected pairs: %+v", pairs)
+	}
+	pairs = AlignSentences("One. Two.", "一和二。")
+	if len(pairs) != 1 || pairs[0].Target != "一和二。" {
+		t.Fatalf("expected paragraph fallback: %+v", pairs)
+	}
+}
+
+func TestWriteTSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTSV(&buf, []Pair{{Source: "a\tb", Target: "c\nd"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "a b\tc d\n" {
+		t.Fatalf("unexpected tsv: %q", buf.String())
+	}
+}
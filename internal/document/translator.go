@@ -0,0 +1,163 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+)
+
+// TranslateFunc translates a single paragraph.
+type TranslateFunc func(ctx context.Context, text string) (string, error)
+
+// Translator translates documents paragraph by paragraph and remembers
+// previous results, so re-running it on an edited document only sends the
+// paragraphs that changed. Repeated paragraphs within a document, such as
+// boilerplate and headers, are translated once.
+type Translator struct {
+	translate TranslateFunc
+	cache     map[string]string
+	fill      FillFunc
+	markdown  bool
+}
+
+// FillFunc returns the text written in place of paragraph n (1-based)
+// when its translation failed.
+type FillFunc func(n int, source string) string
+
+// Failure is a paragraph whose translation failed while the translator
+// continued past errors.
+type Failure struct {
+	Paragraph int
+	Source    string
+	Err       error
+}
+
+// Result describes a single Translate run.
+type Result struct {
+	Text       string
+	Paragraphs int
+	Translated int
+	// Duplicates counts paragraphs that repeat an earlier paragraph of the
+	// same document and reused its translation; DuplicateChars is their
+	// total length in characters.
+	Duplicates     int
+	DuplicateChars int
+	Pairs          []Pair
+	// Sections holds each paragraph with its translation, or with its
+	// fill when the translation failed, in document order.
+	Sections []Pair
+	// Failures lists the paragraphs filled in by ContinueOnError.
+	Failures []Failure
+	// Verbatim counts the front matter and code blocks kept as they are
+	// in markdown mode.
+	Verbatim int
+}
+
+func NewTranslator(translate TranslateFunc) *Translator {
+	return &Translator{
+		translate: translate,
+		cache:     make(map[string]string),
+	}
+}
+
+// Seed records a known translation, such as one saved by an interrupted
+// run, so Translate reuses it instead of calling translate.
+func (t *Translator) Seed(source, target string) {
+	t.cache[source] = target
+}
+
+// ContinueOnError makes Translate fill failed paragraphs with fill and go
+// on with the rest instead of returning the first error. Failed paragraphs
+// are not remembered, so the next run tries them again. Cancelling the
+// context still stops the run.
+func (t *Translator) ContinueOnError(fill FillFunc) {
+	t.fill = fill
+}
+
+// Markdown makes Translate split text with SplitMarkdown and copy front
+// matter and fenced code blocks to the output instead of translating
+// them.
+func (t *Translator) Markdown() {
+	t.markdown = true
+}
+
+// Paragraphs returns the paragraphs of text that Translate sends to
+// translate, in order, repeats included.
+func (t *Translator) Paragraphs(text string) []string {
+	paragraphs, _, verbatim := t.split(text)
+	var prose []string
+	for i, paragraph := range paragraphs {
+		if !verbatim[i] {
+			prose = append(prose, paragraph)
+		}
+	}
+	return prose
+}
+
+func (t *Translator) split(text string) (paragraphs []string, separators []string, verbatim []bool) {
+	return splitParagraphs(text, t.markdown)
+}
+
+// Pending returns how many paragraphs of text Translate would send to
+// translate, leaving out remembered and repeated paragraphs.
+func (t *Translator) Pending(text string) int {
+	paragraphs := t.Paragraphs(text)
+	seen := make(map[string]bool, len(paragraphs))
+	pending := 0
+	for _, paragraph := range paragraphs {
+		if _, ok := t.cache[paragraph]; !ok && !seen[paragraph] {
+			pending++
+		}
+		seen[paragraph] = true
+	}
+	return pending
+}
+
+func (t *Translator) Translate(ctx context.Context, text string) (Result, error) {
+	paragraphs, separators, verbatim := t.split(text)
+	translated := make([]string, len(paragraphs))
+	result := Result{Paragraphs: len(paragraphs)}
+	seen := make(map[string]bool, len(paragraphs))
+	failed := make(map[string]error)
+	for i, paragraph := range paragraphs {
+		if verbatim[i] {
+			translated[i] = paragraph
+			result.Verbatim++
+			continue
+		}
+		if seen[paragraph] {
+			result.Duplicates++
+			result.DuplicateChars += utf8.RuneCountInString(paragraph)
+		}
+		seen[paragraph] = true
+		if cached, ok := t.cache[paragraph]; ok {
+			translated[i] = cached
+			result.Pairs = append(result.Pairs, AlignSentences(paragraph, cached)...)
+			continue
+		}
+		err, repeated := failed[paragraph]
+		var output string
+		if !repeated {
+			output, err = t.translate(ctx, paragraph)
+		}
+		if err != nil {
+			if t.fill == nil || ctx.Err() != nil {
+				return Result{}, fmt.Errorf("translate paragraph %d: %w", i+1, err)
+			}
+			failed[paragraph] = err
+			translated[i] = t.fill(i+1, paragraph)
+			result.Failures = append(result.Failures, Failure{Paragraph: i + 1, Source: paragraph, Err: err})
+			continue
+		}
+		t.cache[paragraph] = output
+		translated[i] = output
+		result.Translated++
+		result.Pairs = append(result.Pairs, AlignSentences(paragraph, output)...)
+	}
+	result.Text = JoinParagraphs(translated, separators)
+	result.Sections = make([]Pair, len(paragraphs))
+	for i, paragraph := range paragraphs {
+		result.Sections[i] = Pair{Source: paragraph, Target: translated[i]}
+	}
+	return result, nil
+}
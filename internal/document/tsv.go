@@ -0,0 +1,20 @@
+package document
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+var tsvReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+// WriteTSV writes one source<TAB>target line per pair. Tabs and line
+// breaks inside segments are replaced with spaces.
+func WriteTSV(w io.Writer, pairs []Pair) error {
+	for _, pair := range pairs {
+		if _, err := fmt.Fprintf(w, "%s\t%s\n", tsvReplacer.Replace(pair.Source), tsvReplacer.Replace(pair.Target)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
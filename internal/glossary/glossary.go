@@ -0,0 +1,120 @@
+package glossary
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"dict-be/internal/known"
+)
+
+// Term is a source term and its required translation.
+type Term struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Note   string `json:"note,omitempty"`
+}
+
+var header = []string{"source", "target", "note"}
+
+// WriteCSV writes terms with a source,target,note header.
+func WriteCSV(w io.Writer, terms []Term) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, term := range terms {
+		if err := writer.Write([]string{term.Source, term.Target, term.Note}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCSV reads a glossary written by WriteCSV. The header row is optional
+// and the note column may be omitted.
+func ReadCSV(r io.Reader) ([]Term, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	var terms []Term
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read glossary: %w", err)
+		}
+		if line == 1 && len(record) >= 2 && strings.EqualFold(record[0], "source") && strings.EqualFold(record[1], "target") {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("glossary line %d: expected source and target", line)
+		}
+		term := Term{
+			Source: strings.TrimSpace(record[0]),
+			Target: strings.TrimSpace(record[1]),
+		}
+		if len(record) > 2 {
+			term.Note = strings.TrimSpace(record[2])
+		}
+		if term.Source == "" {
+			continue
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// Unknown returns the candidates whose source term is not in terms,
+// comparing case-insensitively.
+func Unknown(terms, candidates []Term) []Term {
+	known := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		known[strings.ToLower(term.Source)] = true
+	}
+	var unknown []Term
+	for _, term := range candidates {
+		if key := strings.ToLower(term.Source); !known[key] {
+			known[key] = true
+			unknown = append(unknown, term)
+		}
+	}
+	return unknown
+}
+
+// Match returns the terms with a target whose source term occurs in text
+// as a whole word, comparing case-insensitively.
+func Match(terms []Term, text string) []Term {
+	text = strings.ToLower(text)
+	var matched []Term
+	for _, term := range terms {
+		if term.Target != "" && known.Index(text, strings.ToLower(term.Source)) >= 0 {
+			matched = append(matched, term)
+		}
+	}
+	return matched
+}
+
+// Missing returns the terms of Match(terms, source) whose target does not
+// appear in translation. A target with capital letters must match
+// exactly, so "iphone" for "iPhone" is reported; an all-lowercase target
+// matches regardless of case, since it may start a sentence.
+func Missing(terms []Term, source, translation string) []Term {
+	lower := strings.ToLower(translation)
+	var missing []Term
+	for _, term := range Match(terms, source) {
+		found := strings.Contains(translation, term.Target)
+		if term.Target == strings.ToLower(term.Target) {
+			found = strings.Contains(lower, term.Target)
+		}
+		if !found {
+			missing = append(missing, term)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,87 @@
+package glossary
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	terms := []Term{
+		{Source: "rate limit", Target: "速率限制", Note: "API"},
+		{Source: "token, bucket", Target: "令牌桶"},
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, terms); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := ReadCSV(&buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(got) != 2 || got[1].Source != "token, bucket" || got[0].Note != "API" {
+		t.Fatalf("unexpected terms: %+v", got)
+	}
+}
+
+func TestReadCSVWithoutHeader(t *testing.T) {
+	got, err := ReadCSV(strings.NewReader("dict-be,dict-be\nwordbook,单词本\n"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(got) != 2 || got[1].Target != "单词本" {
+		t.Fatalf("unexpected terms: %+v", got)
+	}
+}
+
+func TestReadCSVMissingTarget(t *testing.T) {
+	if _, err := ReadCSV(strings.NewReader("source,target\nonly\n")); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestUnknown(t *testing.T) {
+	terms := []Term{{Source: "Rate limit", Target: "速率限制"}}
+	got := Unknown(terms, []Term{
+		{Source: "rate limit", Target: "限流"},
+		{Source: "token bucket", Target: "令牌桶"},
+		{Source: "Token Bucket", Target: "令牌桶"},
+	})
+	if len(got) != 1 || got[0].Source != "token bucket" {
+		t.Fatalf("unexpected terms: %+v", got)
+	}
+}
+
+func TestMissing(t *testing.T) {
+	terms := []Term{
+		{Source: "Acme Cloud", Target: "Acme Cloud"},
+		{Source: "rate limit", Target: "速率限制"},
+		{Source: "bucket", Target: "桶"},
+		{Source: "draft", Target: ""},
+	}
+	source := "The acme cloud rate limit applies to every draft."
+	if got := Match(terms, source); len(got) != 2 {
+		t.Fatalf("unexpected matches: %+v", got)
+	}
+	got := Missing(terms, source, "Acme Cloud 的限流适用于每份草稿。")
+	if len(got) != 1 || got[0].Source != "rate limit" {
+		t.Fatalf("unexpected missing terms: %+v", got)
+	}
+}
+
+func TestMissingSentenceStart(t *testing.T) {
+	terms := []Term{{Source: "Ratenlimit", Target: "rate limit"}}
+	if got := Missing(terms, "Das Ratenlimit gilt.", "Rate limit applies."); len(got) != 0 {
+		t.Fatalf("expected a capitalized lowercase target to count: %+v", got)
+	}
+}
+
+func TestMissingCapitalization(t *testing.T) {
+	terms := []Term{{Source: "iPhone", Target: "iPhone"}}
+	if got := Missing(terms, "Charge the IPHONE overnight.", "iphone 要充一夜电。"); len(got) != 1 {
+		t.Fatalf("expected a miscapitalized target to be reported: %+v", got)
+	}
+	if got := Missing(terms, "Charge the IPHONE overnight.", "iPhone 要充一夜电。"); len(got) != 0 {
+		t.Fatalf("unexpected missing terms: %+v", got)
+	}
+}
@@ -0,0 +1,241 @@
+// Package headword reads the headword lists of offline dictionaries and
+// searches them by crossword pattern or anagram.
+package headword
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Load reads the headwords of the dictionary at path. The format follows
+// the file name:
+//   - *.idx or *.idx.gz: a StarDict index;
+//   - *.csv: an ECDICT table, using its "word" column;
+//   - index.noun, index.verb, index.adj, index.adv: a WordNet index;
+//   - anything else: a plain list with one headword per line, where
+//     lines starting with # are comments.
+func Load(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read headwords: %w", err)
+	}
+	defer f.Close()
+	var r io.Reader = f
+	name := strings.ToLower(filepath.Base(path))
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("read headwords %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+		name = strings.TrimSuffix(name, ".gz")
+	}
+	var words []string
+	switch {
+	case strings.HasSuffix(name, ".idx"):
+		words, err = readStarDict(r)
+	case strings.HasSuffix(name, ".csv"):
+		words, err = readECDICT(r)
+	case strings.HasPrefix(name, "index.") && !strings.Contains(name[len("index."):], "."):
+		words, err = readWordNet(r)
+	default:
+		words, err = readPlain(r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read headwords %s: %w", path, err)
+	}
+	return words, nil
+}
+
+// readStarDict reads the NUL-terminated words of a StarDict .idx file,
+// each followed by a 32-bit offset and size.
+func readStarDict(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var words []string
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, 0)
+		if end < 0 || len(data) < end+9 {
+			return nil, errors.New("truncated StarDict index")
+		}
+		words = append(words, string(data[:end]))
+		data = data[end+9:]
+	}
+	return words, nil
+}
+
+func readECDICT(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	column := 0
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), "word") {
+			column = i
+		}
+	}
+	var words []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return words, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if column < len(record) {
+			words = append(words, record[column])
+		}
+	}
+}
+
+// readWordNet reads the lemmas of a WordNet index file. License lines
+// start with a space; underscores in lemmas stand for spaces.
+func readWordNet(r io.Reader) ([]string, error) {
+	var words []string
+	err := scanLines(r, func(line string) {
+		if line == "" || line[0] == ' ' {
+			return
+		}
+		lemma, _, _ := strings.Cut(line, " ")
+		words = append(words, strings.ReplaceAll(lemma, "_", " "))
+	})
+	return words, err
+}
+
+func readPlain(r io.Reader) ([]string, error) {
+	var words []string
+	err := scanLines(r, func(line string) {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	})
+	return words, err
+}
+
+func scanLines(r io.Reader, handle func(string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		handle(scanner.Text())
+	}
+	return scanner.Err()
+}
+
+// Pattern matches headwords against a crossword pattern, where ? stands for
+// one letter and * for any number of letters. Matching ignores case.
+type Pattern struct {
+	re *regexp.Regexp
+}
+
+func Compile(pattern string) (*Pattern, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return nil, errors.New("pattern is required")
+	}
+	var expr strings.Builder
+	expr.WriteString("(?i)^")
+	for _, r := range pattern {
+		switch r {
+		case '?':
+			expr.WriteString(`\pL`)
+		case '*':
+			expr.WriteString(`\pL*`)
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return &Pattern{re: regexp.MustCompile(expr.String())}, nil
+}
+
+func (p *Pattern) Match(word string) bool {
+	return p.re.MatchString(word)
+}
+
+// Anagram matches headwords that use exactly the given letters, ignoring
+// case, spaces and punctuation. Each ? in the letters is a blank that
+// stands for any one letter.
+type Anagram struct {
+	letters map[rune]int
+	blanks  int
+	size    int
+}
+
+func NewAnagram(letters string) (*Anagram, error) {
+	a := &Anagram{letters: make(map[rune]int)}
+	for _, r := range strings.ToLower(letters) {
+		switch {
+		case r == '?':
+			a.blanks++
+		case unicode.IsLetter(r):
+			a.letters[r]++
+		default:
+			continue
+		}
+		a.size++
+	}
+	if a.size == 0 {
+		return nil, errors.New("letters are required")
+	}
+	return a, nil
+}
+
+func (a *Anagram) Match(word string) bool {
+	counts := make(map[rune]int, len(a.letters))
+	size := 0
+	for _, r := range strings.ToLower(word) {
+		if unicode.IsLetter(r) {
+			counts[r]++
+			size++
+		}
+	}
+	if size != a.size {
+		return false
+	}
+	missing := 0
+	for r, n := range counts {
+		if extra := n - a.letters[r]; extra > 0 {
+			missing += extra
+		}
+	}
+	return missing <= a.blanks
+}
+
+// Search returns the distinct words accepted by match, sorted
+// case-insensitively.
+func Search(words []string, match func(string) bool) []string {
+	seen := make(map[string]bool)
+	var found []string
+	for _, word := range words {
+		if !seen[word] && match(word) {
+			seen[word] = true
+			found = append(found, word)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		a, b := strings.ToLower(found[i]), strings.ToLower(found[j])
+		if a != b {
+			return a < b
+		}
+		return found[i] < found[j]
+	})
+	return found
+}
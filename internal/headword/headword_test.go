@@ -0,0 +1,95 @@
+package headword
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadFormats(t *testing.T) {
+	var idx bytes.Buffer
+	for _, word := range []string{"apple", "butter"} {
+		idx.WriteString(word)
+		idx.Write(make([]byte, 9))
+	}
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(idx.Bytes())
+	zw.Close()
+
+	cases := []struct {
+		name string
+		data []byte
+		want []string
+	}{
+		{"dict.idx", idx.Bytes(), []string{"apple", "butter"}},
+		{"dict.idx.gz", gz.Bytes(), []string{"apple", "butter"}},
+		{"ecdict.csv", []byte("word,phonetic,definition\nbetter,,adj. good\n\"bitter\",,\"adj. sour, sharp\"\n"), []string{"better", "bitter"}},
+		{"index.noun", []byte("  1 This software and database\nice_cream n 1 1 @ 1 0 07614500\nbutton n 9 5 @ ~ 9 0 04086273\n"), []string{"ice cream", "button"}},
+		{"words.txt", []byte("# my list\nbattle\n\n  bottle \n"), []string{"battle", "bottle"}},
+	}
+	for _, tc := range cases {
+		got, err := Load(writeFile(t, tc.name, tc.data))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLoadTruncatedStarDict(t *testing.T) {
+	if _, err := Load(writeFile(t, "dict.idx", []byte("apple\x00\x00"))); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestPattern(t *testing.T) {
+	pattern, err := Compile("b?tt??")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	words := []string{"button", "Butter", "better", "bitter", "batter", "bottle", "butt", "b-tter", "button"}
+	got := Search(words, pattern.Match)
+	if !reflect.DeepEqual(got, []string{"batter", "better", "bitter", "bottle", "Butter", "button"}) {
+		t.Fatalf("unexpected matches: %q", got)
+	}
+	pattern, _ = Compile("*ice c*")
+	if got := Search([]string{"ice cream", "rice cake", "iced"}, pattern.Match); !reflect.DeepEqual(got, []string{"ice cream", "rice cake"}) {
+		t.Fatalf("unexpected matches: %q", got)
+	}
+	if _, err := Compile(" "); err == nil {
+		t.Fatalf("expected error for empty pattern")
+	}
+}
+
+func TestAnagram(t *testing.T) {
+	anagram, err := NewAnagram("Listen")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := Search([]string{"silent", "enlist", "tinsel", "listens", "silence", "Inlets"}, anagram.Match)
+	if !reflect.DeepEqual(got, []string{"enlist", "Inlets", "silent", "tinsel"}) {
+		t.Fatalf("unexpected anagrams: %q", got)
+	}
+	anagram, _ = NewAnagram("ca?")
+	if got := Search([]string{"cat", "act", "arc", "dog", "cab"}, anagram.Match); !reflect.DeepEqual(got, []string{"act", "arc", "cab", "cat"}) {
+		t.Fatalf("unexpected anagrams with blank: %q", got)
+	}
+	if _, err := NewAnagram("--"); err == nil {
+		t.Fatalf("expected error for empty letters")
+	}
+}
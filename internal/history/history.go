@@ -0,0 +1,233 @@
+// Package history keeps every query with its answer in a JSONL file, so
+// past lookups can be listed and searched.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Record is one lookup.
+type Record struct {
+	ID             int       `json:"id"`
+	Time           time.Time `json:"time"`
+	Command        string    `json:"command"`
+	Input          string    `json:"input"`
+	InputLanguage  string    `json:"input_language,omitempty"`
+	OutputLanguage string    `json:"output_language,omitempty"`
+	Model          string    `json:"model,omitempty"`
+	Response       string    `json:"response"`
+}
+
+// Filter selects records. Every term must occur in the input or the
+// response, ignoring case; zero times leave that end open.
+type Filter struct {
+	Terms []string
+	Since time.Time
+	Until time.Time
+}
+
+// Match reports whether r passes the filter.
+func (f Filter) Match(r Record) bool {
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !r.Time.Before(f.Until) {
+		return false
+	}
+	text := strings.ToLower(r.Input + "\n" + r.Response)
+	for _, term := range f.Terms {
+		if !strings.Contains(text, strings.ToLower(term)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Store appends records to a JSONL file.
+type Store struct {
+	path string
+	now  func() time.Time
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path, now: time.Now}
+}
+
+// DefaultPath returns ~/.dict-be/history.jsonl.
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir: %w", err)
+	}
+	return filepath.Join(homeDir, ".dict-be", "history.jsonl"), nil
+}
+
+// Load returns the records in the order they were added. A missing file
+// is an empty history.
+func (s *Store) Load() ([]Record, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	defer file.Close()
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("read history %s:%d: %w", s.path, line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	return records, nil
+}
+
+// Find returns the record with the given ID.
+func (s *Store) Find(id int) (Record, error) {
+	records, err := s.Load()
+	if err != nil {
+		return Record{}, err
+	}
+	for _, record := range records {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return Record{}, fmt.Errorf("no history entry %d", id)
+}
+
+// Append stamps record with the next ID and the current time and adds it.
+// The next ID comes from the last line only, and a lock file held around
+// reading it and writing the record keeps concurrent runs from taking the
+// same ID.
+func (s *Store) Append(record Record) (Record, error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return Record{}, fmt.Errorf("create history dir: %w", err)
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return Record{}, err
+	}
+	defer unlock()
+	file, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return Record{}, fmt.Errorf("write history: %w", err)
+	}
+	defer file.Close()
+	last, err := lastRecord(file)
+	if err != nil {
+		return Record{}, fmt.Errorf("read history %s: %w", s.path, err)
+	}
+	record.ID = last.ID + 1
+	record.Time = s.now().UTC().Truncate(time.Second)
+	data, err := json.Marshal(record)
+	if err != nil {
+		return Record{}, fmt.Errorf("encode history: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		return Record{}, fmt.Errorf("write history: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return Record{}, fmt.Errorf("write history: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return Record{}, fmt.Errorf("write history: %w", err)
+	}
+	return record, nil
+}
+
+// Lock files older than staleLock are left over from a run that died
+// while appending, and are taken over.
+const (
+	staleLock    = 10 * time.Second
+	lockAttempts = 500
+	lockInterval = 10 * time.Millisecond
+)
+
+// lock creates the lock file next to the history, waiting while another
+// run holds it, and returns the function that removes it.
+func (s *Store) lock() (func(), error) {
+	path := s.path + ".lock"
+	for attempt := 0; ; attempt++ {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("lock history: %w", err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(path)
+			continue
+		}
+		if attempt == lockAttempts {
+			return nil, fmt.Errorf("lock history: %s is held by another run", path)
+		}
+		time.Sleep(lockInterval)
+	}
+}
+
+// lastRecord decodes the last non-blank line of file, reading backwards
+// from the end in growing blocks. An empty file gives a zero Record.
+func lastRecord(file *os.File) (Record, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return Record{}, err
+	}
+	size := info.Size()
+	for block := int64(4096); ; block *= 2 {
+		start := max(size-block, 0)
+		buf := make([]byte, size-start)
+		if _, err := file.ReadAt(buf, start); err != nil && !errors.Is(err, io.EOF) {
+			return Record{}, err
+		}
+		text := strings.TrimRight(string(buf), " \t\r\n")
+		if text == "" && start > 0 {
+			continue
+		}
+		newline := strings.LastIndexByte(text, '\n')
+		if newline < 0 && start > 0 {
+			continue
+		}
+		line := text[newline+1:]
+		if strings.TrimSpace(line) == "" {
+			return Record{}, nil
+		}
+		var record Record
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return Record{}, err
+		}
+		return record, nil
+	}
+}
+
+// Clear removes the history and returns how many records it held.
+func (s *Store) Clear() (int, error) {
+	records, err := s.Load()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("clear history: %w", err)
+	}
+	return len(records), nil
+}
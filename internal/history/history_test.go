@@ -0,0 +1,96 @@
+package history
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	if records, err := store.Load(); err != nil || len(records) != 0 {
+		t.Fatalf("expected an empty history, got %v, %v", records, err)
+	}
+	for _, input := range []string{"pier", "run"} {
+		if _, err := store.Append(Record{Command: "query", Input: input, Response: "Translation of " + input}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		now = now.Add(24 * time.Hour)
+	}
+	records, err := store.Load()
+	if err != nil || len(records) != 2 || records[1].ID != 2 || !records[1].Time.Equal(time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected records: %+v, %v", records, err)
+	}
+	if record, err := store.Find(1); err != nil || record.Input != "pier" {
+		t.Fatalf("unexpected record 1: %+v, %v", record, err)
+	}
+	if _, err := store.Find(3); err == nil {
+		t.Fatalf("expected an error for a missing record")
+	}
+	if n, err := store.Clear(); err != nil || n != 2 {
+		t.Fatalf("unexpected clear: %d, %v", n, err)
+	}
+	if records, _ := store.Load(); len(records) != 0 {
+		t.Fatalf("expected the history to be cleared, got %+v", records)
+	}
+}
+
+func TestAppendConcurrentIDs(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	if _, err := store.Append(Record{Command: "query", Input: "long", Response: strings.Repeat("x", 10000)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const runs = 20
+	var wg sync.WaitGroup
+	for i := 0; i < runs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// A store per goroutine, as separate runs would have.
+			if _, err := NewStore(store.path).Append(Record{Command: "query", Input: "run"}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	records, err := store.Load()
+	if err != nil || len(records) != runs+1 {
+		t.Fatalf("unexpected records: %d, %v", len(records), err)
+	}
+	seen := make(map[int]bool)
+	for _, record := range records {
+		if seen[record.ID] {
+			t.Fatalf("duplicate ID %d", record.ID)
+		}
+		seen[record.ID] = true
+	}
+	if !seen[runs+1] {
+		t.Fatalf("expected IDs 1 to %d, got %v", runs+1, seen)
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	record := Record{Time: time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC), Input: "Pier", Response: "码头 (wharf)"}
+	cases := []struct {
+		filter Filter
+		want   bool
+	}{
+		{Filter{}, true},
+		{Filter{Terms: []string{"pier", "WHARF"}}, true},
+		{Filter{Terms: []string{"码头"}}, true},
+		{Filter{Terms: []string{"pier", "dock"}}, false},
+		{Filter{Since: record.Time}, true},
+		{Filter{Since: record.Time.Add(time.Second)}, false},
+		{Filter{Until: record.Time}, false},
+		{Filter{Until: record.Time.Add(time.Second)}, true},
+	}
+	for _, tc := range cases {
+		if got := tc.filter.Match(record); got != tc.want {
+			t.Fatalf("%+v: got %v, want %v", tc.filter, got, tc.want)
+		}
+	}
+}
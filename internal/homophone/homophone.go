@@ -0,0 +1,309 @@
+// Package homophone is a small offline table of Chinese and English words
+// that learners confuse by ear, with their pinyin or IPA readings.
+package homophone
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Word is a word and its reading: pinyin with tone marks for Chinese, IPA
+// for English.
+type Word struct {
+	Text    string
+	Reading string
+}
+
+// Result lists the table words that sound like a word. Homophones have
+// the same reading. Near words differ only in tones (Chinese) or in one
+// sound (English).
+type Result struct {
+	Word       Word
+	Homophones []Word
+	Near       []Word
+}
+
+// Lookup finds word in the table for lang ("zh" or "en"). Chinese words
+// may also be given as pinyin, with or without tones; the result then
+// lists every word with that reading as near.
+func Lookup(word, lang string) (Result, bool) {
+	word = strings.TrimSpace(word)
+	switch lang {
+	case "zh":
+		return lookupChinese(word)
+	case "en":
+		return lookupEnglish(strings.ToLower(word))
+	}
+	return Result{}, false
+}
+
+func lookupChinese(word string) (Result, bool) {
+	target, ok := find(chinese, word)
+	if !ok {
+		key := toneless(word)
+		var result Result
+		for _, candidate := range chinese {
+			if toneless(candidate.Reading) == key {
+				result.Near = append(result.Near, candidate)
+			}
+		}
+		return result, len(result.Near) > 0
+	}
+	result := Result{Word: target}
+	for _, candidate := range chinese {
+		switch {
+		case candidate.Text == target.Text:
+		case candidate.Reading == target.Reading:
+			result.Homophones = append(result.Homophones, candidate)
+		case toneless(candidate.Reading) == toneless(target.Reading):
+			result.Near = append(result.Near, candidate)
+		}
+	}
+	return result, true
+}
+
+func lookupEnglish(word string) (Result, bool) {
+	target, ok := find(english, word)
+	if !ok {
+		return Result{}, false
+	}
+	result := Result{Word: target}
+	phonemes := Phonemes(target.Reading)
+	for _, candidate := range english {
+		if candidate.Text == target.Text {
+			continue
+		}
+		switch distance(phonemes, Phonemes(candidate.Reading)) {
+		case 0:
+			result.Homophones = append(result.Homophones, candidate)
+		case 1:
+			result.Near = append(result.Near, candidate)
+		}
+	}
+	return result, true
+}
+
+func find(words []Word, text string) (Word, bool) {
+	for _, word := range words {
+		if word.Text == text {
+			return word, true
+		}
+	}
+	return Word{}, false
+}
+
+// toneMarks maps vowels with pinyin tone marks to the plain vowel.
+var toneMarks = strings.NewReplacer(
+	"ā", "a", "á", "a", "ǎ", "a", "à", "a",
+	"ē", "e", "é", "e", "ě", "e", "è", "e",
+	"ī", "i", "í", "i", "ǐ", "i", "ì", "i",
+	"ō", "o", "ó", "o", "ǒ", "o", "ò", "o",
+	"ū", "u", "ú", "u", "ǔ", "u", "ù", "u",
+	"ǖ", "ü", "ǘ", "ü", "ǚ", "ü", "ǜ", "ü",
+)
+
+// toneless strips tone marks, tone numbers and spaces from pinyin, so
+// readings that differ only in tones compare equal.
+func toneless(pinyin string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsDigit(r) {
+			return -1
+		}
+		return r
+	}, toneMarks.Replace(strings.ToLower(pinyin)))
+}
+
+// diphthongs are read as one sound by Phonemes.
+var diphthongs = []string{"aɪ", "aʊ", "eɪ", "oʊ", "ɔɪ"}
+
+// Phonemes splits an IPA reading into sounds, ignoring stress marks and
+// counting diphthongs and long vowels as one sound.
+func Phonemes(ipa string) []string {
+	ipa = strings.NewReplacer("ˈ", "", "ˌ", "", "/", "", ".", "").Replace(ipa)
+	var phonemes []string
+	for ipa != "" {
+		if ipa[0] == ' ' {
+			ipa = ipa[1:]
+			continue
+		}
+		next := ""
+		for _, diphthong := range diphthongs {
+			if strings.HasPrefix(ipa, diphthong) {
+				next = diphthong
+				break
+			}
+		}
+		if next == "" {
+			r := []rune(ipa)[0]
+			next = string(r)
+		}
+		ipa = ipa[len(next):]
+		if strings.HasPrefix(ipa, "ː") {
+			next += "ː"
+			ipa = ipa[len("ː"):]
+		}
+		phonemes = append(phonemes, next)
+	}
+	return phonemes
+}
+
+// distance is the edit distance between two phoneme sequences.
+func distance(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+var chinese = []Word{
+	{"事实", "shìshí"},
+	{"实事", "shíshì"},
+	{"时事", "shíshì"},
+	{"史诗", "shǐshī"},
+	{"失事", "shīshì"},
+	{"公式", "gōngshì"},
+	{"公事", "gōngshì"},
+	{"攻势", "gōngshì"},
+	{"工事", "gōngshì"},
+	{"期中", "qīzhōng"},
+	{"其中", "qízhōng"},
+	{"权利", "quánlì"},
+	{"权力", "quánlì"},
+	{"必须", "bìxū"},
+	{"必需", "bìxū"},
+	{"反应", "fǎnyìng"},
+	{"反映", "fǎnyìng"},
+	{"意义", "yìyì"},
+	{"异议", "yìyì"},
+	{"形式", "xíngshì"},
+	{"形势", "xíngshì"},
+	{"语言", "yǔyán"},
+	{"预言", "yùyán"},
+	{"寓言", "yùyán"},
+	{"教室", "jiàoshì"},
+	{"教师", "jiàoshī"},
+	{"睡觉", "shuìjiào"},
+	{"水饺", "shuǐjiǎo"},
+	{"厉害", "lìhai"},
+	{"利害", "lìhài"},
+	{"消失", "xiāoshī"},
+	{"小时", "xiǎoshí"},
+	{"做", "zuò"},
+	{"作", "zuò"},
+	{"坐", "zuò"},
+	{"座", "zuò"},
+	{"在", "zài"},
+	{"再", "zài"},
+	{"买", "mǎi"},
+	{"卖", "mài"},
+	{"妈", "mā"},
+	{"麻", "má"},
+	{"马", "mǎ"},
+	{"骂", "mà"},
+	{"汤", "tāng"},
+	{"糖", "táng"},
+	{"躺", "tǎng"},
+	{"烫", "tàng"},
+	{"书", "shū"},
+	{"熟", "shú"},
+	{"数", "shǔ"},
+	{"树", "shù"},
+	{"有", "yǒu"},
+	{"油", "yóu"},
+	{"又", "yòu"},
+	{"右", "yòu"},
+	{"是", "shì"},
+	{"事", "shì"},
+	{"试", "shì"},
+	{"市", "shì"},
+	{"十", "shí"},
+	{"时", "shí"},
+	{"女", "nǚ"},
+	{"绿", "lǜ"},
+	{"旅", "lǚ"},
+}
+
+var english = []Word{
+	{"to", "tuː"},
+	{"too", "tuː"},
+	{"two", "tuː"},
+	{"there", "ðɛr"},
+	{"their", "ðɛr"},
+	{"they're", "ðɛr"},
+	{"right", "raɪt"},
+	{"write", "raɪt"},
+	{"rite", "raɪt"},
+	{"light", "laɪt"},
+	{"rice", "raɪs"},
+	{"lice", "laɪs"},
+	{"know", "noʊ"},
+	{"no", "noʊ"},
+	{"knew", "nuː"},
+	{"new", "nuː"},
+	{"hear", "hɪr"},
+	{"here", "hɪr"},
+	{"see", "siː"},
+	{"sea", "siː"},
+	{"flour", "ˈflaʊər"},
+	{"flower", "ˈflaʊər"},
+	{"weather", "ˈwɛðər"},
+	{"whether", "ˈwɛðər"},
+	{"peace", "piːs"},
+	{"piece", "piːs"},
+	{"break", "breɪk"},
+	{"brake", "breɪk"},
+	{"mail", "meɪl"},
+	{"male", "meɪl"},
+	{"meet", "miːt"},
+	{"meat", "miːt"},
+	{"pair", "pɛr"},
+	{"pear", "pɛr"},
+	{"sun", "sʌn"},
+	{"son", "sʌn"},
+	{"berry", "ˈbɛri"},
+	{"bury", "ˈbɛri"},
+	{"buy", "baɪ"},
+	{"by", "baɪ"},
+	{"bye", "baɪ"},
+	{"eight", "eɪt"},
+	{"ate", "eɪt"},
+	{"wait", "weɪt"},
+	{"weight", "weɪt"},
+	{"hour", "aʊər"},
+	{"our", "aʊər"},
+	{"whole", "hoʊl"},
+	{"hole", "hoʊl"},
+	{"week", "wiːk"},
+	{"weak", "wiːk"},
+	{"wood", "wʊd"},
+	{"would", "wʊd"},
+	{"ship", "ʃɪp"},
+	{"sheep", "ʃiːp"},
+	{"live", "lɪv"},
+	{"leave", "liːv"},
+	{"bad", "bæd"},
+	{"bed", "bɛd"},
+	{"full", "fʊl"},
+	{"fool", "fuːl"},
+	{"thin", "θɪn"},
+	{"tin", "tɪn"},
+	{"pen", "pɛn"},
+	{"pin", "pɪn"},
+	{"accept", "əkˈsɛpt"},
+	{"except", "ɪkˈsɛpt"},
+	{"affect", "əˈfɛkt"},
+	{"effect", "ɪˈfɛkt"},
+}
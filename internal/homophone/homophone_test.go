@@ -0,0 +1,71 @@
+package homophone
+
+import (
+	"reflect"
+	"testing"
+)
+
+func texts(words []Word) []string {
+	var out []string
+	for _, word := range words {
+		out = append(out, word.Text)
+	}
+	return out
+}
+
+func TestLookupChinese(t *testing.T) {
+	result, ok := Lookup("公式", "zh")
+	if !ok || result.Word.Reading != "gōngshì" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if got := texts(result.Homophones); !reflect.DeepEqual(got, []string{"公事", "攻势", "工事"}) {
+		t.Fatalf("unexpected homophones: %q", got)
+	}
+	result, _ = Lookup("事实", "zh")
+	if got := texts(result.Near); !reflect.DeepEqual(got, []string{"实事", "时事", "史诗", "失事"}) {
+		t.Fatalf("unexpected near words: %q", got)
+	}
+}
+
+func TestLookupPinyin(t *testing.T) {
+	result, ok := Lookup("shi shi", "zh")
+	if !ok || result.Word.Text != "" || len(result.Near) != 5 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	result, ok = Lookup("lü4", "zh")
+	if !ok || !reflect.DeepEqual(texts(result.Near), []string{"绿", "旅"}) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if _, ok := Lookup("xyz", "zh"); ok {
+		t.Fatalf("expected miss")
+	}
+}
+
+func TestLookupEnglish(t *testing.T) {
+	result, ok := Lookup(" Right ", "en")
+	if !ok {
+		t.Fatalf("expected entry")
+	}
+	if got := texts(result.Homophones); !reflect.DeepEqual(got, []string{"write", "rite"}) {
+		t.Fatalf("unexpected homophones: %q", got)
+	}
+	if got := texts(result.Near); !reflect.DeepEqual(got, []string{"light", "rice"}) {
+		t.Fatalf("unexpected near words: %q", got)
+	}
+	if _, ok := Lookup("spaceship", "en"); ok {
+		t.Fatalf("expected miss")
+	}
+	if _, ok := Lookup("right", "fr"); ok {
+		t.Fatalf("expected miss for unknown language")
+	}
+}
+
+func TestPhonemes(t *testing.T) {
+	got := Phonemes("ˈflaʊər")
+	if !reflect.DeepEqual(got, []string{"f", "l", "aʊ", "ə", "r"}) {
+		t.Fatalf("unexpected phonemes: %q", got)
+	}
+	if got := Phonemes("ʃiːp"); !reflect.DeepEqual(got, []string{"ʃ", "iː", "p"}) {
+		t.Fatalf("unexpected phonemes: %q", got)
+	}
+}
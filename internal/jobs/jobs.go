@@ -0,0 +1,145 @@
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	StatusPending = "pending"
+	StatusDone    = "done"
+)
+
+// Job records the progress of a long-running command so it can be resumed
+// without repeating completed chunks.
+type Job struct {
+	ID             string    `json:"id"`
+	Command        string    `json:"command"`
+	Input          string    `json:"input"`
+	Output         string    `json:"output,omitempty"`
+	InputLanguage  string    `json:"input_language"`
+	OutputLanguage string    `json:"output_language"`
+	Created        time.Time `json:"created"`
+	Chunks         []Chunk   `json:"chunks"`
+}
+
+// Chunk is one unit of work, such as a paragraph, and its result.
+type Chunk struct {
+	Source string `json:"source"`
+	Target string `json:"target,omitempty"`
+	Status string `json:"status"`
+}
+
+// Done counts completed chunks.
+func (j *Job) Done() int {
+	done := 0
+	for _, chunk := range j.Chunks {
+		if chunk.Status == StatusDone {
+			done++
+		}
+	}
+	return done
+}
+
+// Complete marks every pending chunk with source as done.
+func (j *Job) Complete(source, target string) {
+	for i := range j.Chunks {
+		if j.Chunks[i].Source == source && j.Chunks[i].Status != StatusDone {
+			j.Chunks[i].Target = target
+			j.Chunks[i].Status = StatusDone
+		}
+	}
+}
+
+// Store keeps one JSON file per job in a directory.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultDir returns ~/.dict-be/jobs.
+func DefaultDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir: %w", err)
+	}
+	return filepath.Join(homeDir, ".dict-be", "jobs"), nil
+}
+
+// NewID returns a short random job ID prefixed with the creation date.
+func NewID(now time.Time) string {
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return now.Format("20060102") + "-" + hex.EncodeToString(buf)
+}
+
+func (s *Store) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("invalid job id: %q", id)
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+func (s *Store) Load(id string) (*Job, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("job %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read job: %w", err)
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("decode job %s: %w", id, err)
+	}
+	return &job, nil
+}
+
+// Save writes job atomically, so an interrupted save never leaves a
+// truncated file behind.
+func (s *Store) Save(job *Job) error {
+	path, err := s.path(job.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create job dir: %w", err)
+	}
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode job: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write job: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write job: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) Remove(id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove job: %w", err)
+	}
+	return nil
+}
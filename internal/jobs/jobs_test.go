@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	store := NewStore(t.TempDir())
+	job := &Job{
+		ID:      NewID(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)),
+		Command: "translate",
+		Input:   "doc.md",
+		Chunks: []Chunk{
+			{Source: "a", Status: StatusPending},
+			{Source: "b", Status: StatusPending},
+			{Source: "a", Status: StatusPending},
+		},
+	}
+	if !strings.HasPrefix(job.ID, "20260102-") {
+		t.Fatalf("unexpected id: %s", job.ID)
+	}
+	job.Complete("a", "A")
+	if err := store.Save(job); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := store.Load(job.ID)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.Done() != 2 || loaded.Chunks[2].Target != "A" || loaded.Chunks[1].Status != StatusPending {
+		t.Fatalf("unexpected job: %+v", loaded)
+	}
+	if err := store.Remove(job.ID); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := store.Load(job.ID); err == nil {
+		t.Fatalf("expected missing job error")
+	}
+}
+
+func TestStoreRejectsPathIDs(t *testing.T) {
+	if _, err := NewStore(t.TempDir()).Load("../etc/passwd"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
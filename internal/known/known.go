@@ -0,0 +1,264 @@
+package known
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Store keeps the words a learner already knows in a text file, one
+// normalized word per line.
+type Store struct {
+	path string
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns ~/.dict-be/known.txt.
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir: %w", err)
+	}
+	return filepath.Join(homeDir, ".dict-be", "known.txt"), nil
+}
+
+// Load returns the stored words in the order they were added. A missing
+// file is an empty list.
+func (s *Store) Load() ([]string, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read known words: %w", err)
+	}
+	defer file.Close()
+	words, err := ReadText(file)
+	if err != nil {
+		return nil, fmt.Errorf("read known words: %w", err)
+	}
+	return words, nil
+}
+
+// Add appends the words that are not stored yet and returns how many were
+// added.
+func (s *Store) Add(words []string) (int, error) {
+	stored, err := s.Load()
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[string]bool, len(stored))
+	for _, word := range stored {
+		seen[word] = true
+	}
+	added := 0
+	for _, word := range words {
+		word = Normalize(word)
+		if word == "" || seen[word] {
+			continue
+		}
+		seen[word] = true
+		stored = append(stored, word)
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	if err := s.save(stored); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// save writes words atomically, so an interrupted save never leaves a
+// truncated list behind.
+func (s *Store) save(words []string) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create known words dir: %w", err)
+	}
+	data := strings.Join(words, "\n") + "\n"
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(data), 0o600); err != nil {
+		return fmt.Errorf("write known words: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("write known words: %w", err)
+	}
+	return nil
+}
+
+// Normalize trims and lowercases word and collapses inner whitespace.
+func Normalize(word string) string {
+	return strings.ToLower(strings.Join(strings.Fields(word), " "))
+}
+
+// ReadText reads one word per line, skipping blank lines and lines
+// starting with #.
+func ReadText(r io.Reader) ([]string, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if word := Normalize(line); word != "" {
+			words = append(words, word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// ReadAnki reads the first field of an Anki "Notes in Plain Text" export.
+// Header lines such as #separator:tab are skipped and HTML markup is
+// removed from the field.
+func ReadAnki(r io.Reader) ([]string, error) {
+	var words []string
+	separator := "\t"
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			if value, ok := strings.CutPrefix(line, "#separator:"); ok {
+				separator = ankiSeparator(value)
+			}
+			continue
+		}
+		field, _, _ := strings.Cut(line, separator)
+		field = strings.Trim(field, `"`)
+		field = html.UnescapeString(htmlTag.ReplaceAllString(field, " "))
+		if word := Normalize(field); word != "" {
+			words = append(words, word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+func ankiSeparator(name string) string {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "comma":
+		return ","
+	case "semicolon":
+		return ";"
+	case "space":
+		return " "
+	case "pipe":
+		return "|"
+	case "colon":
+		return ":"
+	default:
+		return "\t"
+	}
+}
+
+// Filter returns the words that occur in text, in their stored order.
+// Words are matched case-insensitively; words ending in letters of
+// space-delimited scripts must also match whole words, so "cat" does not
+// match "category".
+func Filter(words []string, text string) []string {
+	text = strings.ToLower(text)
+	var matched []string
+	for _, word := range words {
+		if containsWord(text, word) {
+			matched = append(matched, word)
+		}
+	}
+	return matched
+}
+
+func containsWord(text, word string) bool {
+	return Index(text, word) >= 0
+}
+
+// Index returns the byte offset of the first occurrence of word in text
+// that Filter would match, or -1. Unlike Filter it is case-sensitive.
+func Index(text, word string) int {
+	if word == "" {
+		return -1
+	}
+	for offset := 0; ; {
+		index := strings.Index(text[offset:], word)
+		if index < 0 {
+			return -1
+		}
+		start := offset + index
+		end := start + len(word)
+		if boundaryBefore(text, start, word) && boundaryAfter(text, end, word) {
+			return start
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+}
+
+func boundaryBefore(text string, start int, word string) bool {
+	first, _ := utf8.DecodeRuneInString(word)
+	if !needsBoundary(first) || start == 0 {
+		return true
+	}
+	previous, _ := utf8.DecodeLastRuneInString(text[:start])
+	return !isWordRune(previous)
+}
+
+func boundaryAfter(text string, end int, word string) bool {
+	last, _ := utf8.DecodeLastRuneInString(word)
+	if !needsBoundary(last) || end == len(text) {
+		return true
+	}
+	next, _ := utf8.DecodeRuneInString(text[end:])
+	return !isWordRune(next)
+}
+
+// needsBoundary reports whether r belongs to a script that separates words
+// with spaces. Chinese and Japanese words are matched as substrings.
+func needsBoundary(r rune) bool {
+	return isWordRune(r) && !unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Coverage counts the running words of text in space-delimited scripts and
+// how many of them are not in words. Chinese and Japanese text is not
+// counted, since it cannot be split into words without a dictionary.
+func Coverage(words []string, text string) (total, unknown int) {
+	knownSet := make(map[string]bool, len(words))
+	for _, word := range words {
+		knownSet[word] = true
+	}
+	tokens := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !needsBoundary(r) && r != '\''
+	})
+	for _, token := range tokens {
+		token = strings.Trim(token, "'")
+		if token == "" || !strings.ContainsFunc(token, unicode.IsLetter) {
+			continue
+		}
+		total++
+		if !knownSet[token] {
+			unknown++
+		}
+	}
+	return total, unknown
+}
@@ -0,0 +1,64 @@
+package known
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStoreAdd(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "known.txt"))
+	words, err := store.Load()
+	if err != nil || len(words) != 0 {
+		t.Fatalf("expected empty list, got %v, %v", words, err)
+	}
+	added, err := store.Add([]string{"Apple", " take  off ", "apple", ""})
+	if err != nil || added != 2 {
+		t.Fatalf("unexpected add: %d, %v", added, err)
+	}
+	added, err = store.Add([]string{"APPLE", "猫"})
+	if err != nil || added != 1 {
+		t.Fatalf("unexpected add: %d, %v", added, err)
+	}
+	words, err = store.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if want := []string{"apple", "take off", "猫"}; !reflect.DeepEqual(words, want) {
+		t.Fatalf("unexpected words: %v", words)
+	}
+}
+
+func TestReadAnki(t *testing.T) {
+	input := "#separator:tab\n#html:true\n<b>Hund</b>\tdog\ncaf&eacute;\tcoffee\n\n"
+	words, err := ReadAnki(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"hund", "café"}; !reflect.DeepEqual(words, want) {
+		t.Fatalf("unexpected words: %v", words)
+	}
+	words, err = ReadAnki(strings.NewReader("#separator:Comma\nrun,laufen\n"))
+	if err != nil || !reflect.DeepEqual(words, []string{"run"}) {
+		t.Fatalf("unexpected words: %v, %v", words, err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	words := []string{"cat", "take off", "学校", "dog"}
+	got := Filter(words, "The Cat's category: planes TAKE OFF near 学校へ.")
+	if want := []string{"cat", "take off", "学校"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected matches: %v", got)
+	}
+	if got := Filter([]string{"cat"}, "category"); len(got) != 0 {
+		t.Fatalf("unexpected matches: %v", got)
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	total, unknown := Coverage([]string{"the", "cat", "don't"}, "The cat don't see 42 dogs; 猫がいる。")
+	if total != 5 || unknown != 2 {
+		t.Fatalf("unexpected coverage: %d total, %d unknown", total, unknown)
+	}
+}
@@ -0,0 +1,177 @@
+// Package langdetect guesses the language of a short text offline, from
+// the scripts it uses and, for Latin script, its diacritics and most
+// common words.
+package langdetect
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Language names as used in prompts and the --in/--out flags.
+const (
+	Chinese    = "Simplified Chinese"
+	Japanese   = "Japanese"
+	Korean     = "Korean"
+	Russian    = "Russian"
+	Ukrainian  = "Ukrainian"
+	Arabic     = "Arabic"
+	Persian    = "Persian"
+	Hebrew     = "Hebrew"
+	Greek      = "Greek"
+	Thai       = "Thai"
+	Hindi      = "Hindi"
+	English    = "English"
+	French     = "French"
+	German     = "German"
+	Spanish    = "Spanish"
+	Italian    = "Italian"
+	Portuguese = "Portuguese"
+	Dutch      = "Dutch"
+)
+
+// scripts maps the non-Latin scripts to the language assumed for them.
+// Han, kana and Hangul are handled before, since they mix.
+var scripts = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Cyrillic, Russian},
+	{unicode.Arabic, Arabic},
+	{unicode.Hebrew, Hebrew},
+	{unicode.Greek, Greek},
+	{unicode.Thai, Thai},
+	{unicode.Devanagari, Hindi},
+}
+
+// variants tells apart languages sharing a script by letters only one of
+// them uses.
+var variants = map[string]struct {
+	letters  string
+	language string
+}{
+	Russian: {"іїєґ", Ukrainian},
+	Arabic:  {"پچژگ", Persian},
+}
+
+// latin holds, per language written in Latin script, the letters that
+// point to it and its most common short words.
+var latin = []struct {
+	language string
+	letters  string
+	words    []string
+}{
+	{English, "", []string{"the", "and", "is", "are", "of", "to", "in", "it", "you", "that", "with", "for", "this", "what", "how", "not"}},
+	{French, "çœéèêëîïôûàâù", []string{"le", "la", "les", "et", "est", "un", "une", "des", "du", "je", "il", "vous", "pas", "que", "qui", "dans", "pour", "avec", "ce", "très", "été"}},
+	{German, "äöüß", []string{"der", "die", "das", "und", "ist", "nicht", "ich", "ein", "eine", "zu", "mit", "sie", "es", "den", "wie", "auf"}},
+	{Spanish, "ñ¿¡áíóú", []string{"el", "la", "los", "las", "y", "es", "un", "una", "que", "de", "en", "no", "por", "con", "para", "está"}},
+	{Italian, "àèìòù", []string{"il", "lo", "la", "gli", "le", "e", "è", "un", "una", "che", "di", "non", "per", "con", "sono", "della"}},
+	{Portuguese, "ãõçáâêéóú", []string{"o", "a", "os", "as", "e", "é", "um", "uma", "que", "de", "não", "em", "para", "com", "você", "do"}},
+	{Dutch, "ĳ", []string{"de", "het", "een", "en", "is", "niet", "ik", "van", "dat", "op", "te", "zijn", "met", "voor", "wat", "je"}},
+}
+
+// Detect returns the name of the language text is most likely written
+// in, or "" when it has no letters. Any kana makes it Japanese, any Hangul
+// Korean and any other Han character Chinese, so a question about a
+// Chinese word counts as Chinese. Otherwise the script used most decides;
+// Latin text is English unless its diacritics and common words point to
+// another language.
+func Detect(text string) string {
+	var han, latinLetters int
+	counts := make([]int, len(scripts))
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			return Japanese
+		case unicode.Is(unicode.Hangul, r):
+			return Korean
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Latin, r):
+			latinLetters++
+		default:
+			for i, script := range scripts {
+				if unicode.Is(script.table, r) {
+					counts[i]++
+					break
+				}
+			}
+		}
+	}
+	if han > 0 {
+		return Chinese
+	}
+	best, most := "", latinLetters
+	for i, count := range counts {
+		if count > most {
+			best, most = scripts[i].language, count
+		}
+	}
+	if best != "" {
+		if variant, ok := variants[best]; ok && strings.ContainsAny(strings.ToLower(text), variant.letters) {
+			return variant.language
+		}
+		return best
+	}
+	if latinLetters == 0 {
+		return ""
+	}
+	return detectLatin(text)
+}
+
+// minCommonWords is how many common words of a language Latin text needs
+// before they count: short words such as "die", "a" or "con" are also
+// English words, so one alone says nothing.
+const minCommonWords = 2
+
+// detectLatin scores each language two points per letter only it uses,
+// one per letter it shares with another language and, from
+// minCommonWords on, one per common word, and falls back to English on a
+// tie with it or when nothing scores. Shared letters count only when at
+// least one common word of the language backs them up: English borrows
+// words such as "café" and "résumé" with their accents.
+func detectLatin(text string) string {
+	text = strings.ToLower(text)
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	best, bestScore := English, 0
+	for _, candidate := range latin {
+		common := 0
+		for _, word := range words {
+			if slices.Contains(candidate.words, word) {
+				common++
+			}
+		}
+		score := 0
+		for _, r := range text {
+			if !strings.ContainsRune(candidate.letters, r) {
+				continue
+			}
+			if !sharedLetter(r) {
+				score += 2
+			} else if common > 0 {
+				score++
+			}
+		}
+		if common >= minCommonWords {
+			score += common
+		}
+		if score > bestScore {
+			best, bestScore = candidate.language, score
+		}
+	}
+	return best
+}
+
+// sharedLetter reports whether more than one language of latin uses r.
+func sharedLetter(r rune) bool {
+	n := 0
+	for _, candidate := range latin {
+		if strings.ContainsRune(candidate.letters, r) {
+			n++
+		}
+	}
+	return n > 1
+}
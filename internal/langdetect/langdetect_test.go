@@ -0,0 +1,41 @@
+package langdetect
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"123 !?":            "",
+		"hello world":       English,
+		"what does 你好 mean": Chinese,
+		"我喜欢读书":             Chinese,
+		"今日はいい天気ですね":        Japanese,
+		"안녕하세요":             Korean,
+		"Как дела?":         Russian,
+		"Як справи? Дякую, все добре, ї":     Ukrainian,
+		"مرحبا بالعالم":                      Arabic,
+		"Καλημέρα":                           Greek,
+		"Je ne sais pas ce que c'est.":       French,
+		"Ich weiß nicht, wie das geht.":      German,
+		"¿Dónde está la biblioteca?":         Spanish,
+		"Il gatto è sul tavolo della cucina": Italian,
+		"Você não sabe o que é isso":         Portuguese,
+		"Ik weet het niet, wat is dat":       Dutch,
+		"The café is closed":                 English,
+		"die":                                English,
+		"a":                                  English,
+		"con":                                English,
+		"die Katze ist klein":                German,
+		"Il a été très gentil":               French,
+		"été":                                French,
+		"résumé":                             English,
+		"café":                               English,
+		"cliché":                             English,
+		"fiancé":                             English,
+	}
+	for text, want := range tests {
+		if got := Detect(text); got != want {
+			t.Errorf("Detect(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -73,10 +73,14 @@ func NewAnthropicClient(cfg AnthropicConfig) (*AnthropicClient, error) {
 func (c *AnthropicClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
 	messages, system := splitAnthropicMessages(req.Messages)
 	payload := anthropicChatRequest{
-		Model:     c.resolveModel(req.Model),
-		Messages:  messages,
-		System:    system,
-		MaxTokens: c.maxTokens,
+		Model:         c.resolveModel(req.Model),
+		Messages:      messages,
+		System:        system,
+		MaxTokens:     firstPositive(req.MaxTokens, c.maxTokens),
+		Temperature:   req.Temperature,
+		TopP:          req.TopP,
+		StopSequences: req.StopSequences,
+		Tools:         toAnthropicTools(req.Tools),
 	}
 	var resp anthropicChatResponse
 	if err := c.do(ctx, payload, &resp); err != nil {
@@ -85,19 +89,25 @@ func (c *AnthropicClient) Chat(ctx context.Context, req ChatRequest) (ChatRespon
 	content := flattenAnthropicContent(resp.Content)
 	return ChatResponse{
 		Content:      content,
+		ToolCalls:    anthropicToolCalls(resp.Content),
 		Model:        resp.Model,
 		FinishReason: resp.StopReason,
+		Usage:        resp.Usage.toUsage(),
 	}, nil
 }
 
 func (c *AnthropicClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
 	messages, system := splitAnthropicMessages(req.Messages)
 	payload := anthropicChatRequest{
-		Model:     c.resolveModel(req.Model),
-		Messages:  messages,
-		System:    system,
-		MaxTokens: c.maxTokens,
-		Stream:    true,
+		Model:         c.resolveModel(req.Model),
+		Messages:      messages,
+		System:        system,
+		MaxTokens:     firstPositive(req.MaxTokens, c.maxTokens),
+		Temperature:   req.Temperature,
+		TopP:          req.TopP,
+		StopSequences: req.StopSequences,
+		Tools:         toAnthropicTools(req.Tools),
+		Stream:        true,
 	}
 	requestBody, err := json.Marshal(payload)
 	if err != nil {
@@ -123,10 +133,7 @@ func (c *AnthropicClient) ChatStream(ctx context.Context, req ChatRequest, handl
 		return ChatResponse{}, readAnthropicError(httpResp.Body, httpResp.StatusCode)
 	}
 
-	var content strings.Builder
-	var finishReason string
-	var model string
-
+	var stream anthropicStream
 	scanner := bufio.NewScanner(httpResp.Body)
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	for scanner.Scan() {
@@ -140,39 +147,109 @@ func (c *AnthropicClient) ChatStream(ctx context.Context, req ChatRequest, handl
 		}
 		var event anthropicStreamEvent
 		if err := json.Unmarshal([]byte(data), &event); err != nil {
-			return ChatResponse{}, fmt.Errorf("decode stream chunk: %w", err)
+			continue
 		}
-		if event.Type == "error" && event.Error != nil {
-			return ChatResponse{}, fmt.Errorf("anthropic error: %s", event.Error.Message)
+		if err := stream.apply(event, handle); err != nil {
+			return ChatResponse{}, err
 		}
-		if event.Type == "message_start" && event.Message != nil && event.Message.Model != "" {
-			model = event.Message.Model
+	}
+	if err := scanner.Err(); err != nil {
+		return ChatResponse{}, fmt.Errorf("%w: %v", ErrStreamInterrupted, err)
+	}
+	resp := stream.response()
+	if resp.FinishReason == "" {
+		return ChatResponse{}, ErrStreamInterrupted
+	}
+	return resp, nil
+}
+
+// anthropicStream accumulates Messages API stream events. Bedrock relays
+// the same events for Claude models.
+type anthropicStream struct {
+	content      strings.Builder
+	finishReason string
+	model        string
+	usage        anthropicUsage
+	// tools holds tool_use blocks in order; toolBlocks maps content block
+	// indexes to them so input_json_delta fragments can be joined.
+	tools      []*anthropicToolStream
+	toolBlocks map[int]*anthropicToolStream
+}
+
+type anthropicToolStream struct {
+	id    string
+	name  string
+	input strings.Builder
+}
+
+func (s *anthropicStream) apply(event anthropicStreamEvent, handle StreamHandler) error {
+	if event.Type == "error" && event.Error != nil {
+		return fmt.Errorf("anthropic error: %s", event.Error.Message)
+	}
+	if event.Type == "message_start" && event.Message != nil {
+		if event.Message.Model != "" {
+			s.model = event.Message.Model
 		}
-		if event.Type == "message_delta" && event.StopReason != "" {
-			finishReason = event.StopReason
+		if event.Message.Usage != nil {
+			s.usage.InputTokens = event.Message.Usage.InputTokens
 		}
-		if event.Type != "content_block_delta" || event.Delta == nil {
-			continue
+	}
+	if event.Type == "message_delta" {
+		if event.StopReason != "" {
+			s.finishReason = event.StopReason
 		}
-		delta := event.Delta.Text
-		if delta == "" {
-			continue
+		if event.Delta != nil && event.Delta.StopReason != "" {
+			s.finishReason = event.Delta.StopReason
 		}
-		content.WriteString(delta)
-		if handle != nil {
-			if err := handle(delta); err != nil {
-				return ChatResponse{}, err
-			}
+		if event.Usage != nil {
+			s.usage.OutputTokens = event.Usage.OutputTokens
 		}
 	}
-	if err := scanner.Err(); err != nil {
-		return ChatResponse{}, fmt.Errorf("read stream: %w", err)
+	if event.Type == "content_block_start" && event.ContentBlock != nil && event.ContentBlock.Type == "tool_use" {
+		tool := &anthropicToolStream{id: event.ContentBlock.ID, name: event.ContentBlock.Name}
+		if s.toolBlocks == nil {
+			s.toolBlocks = make(map[int]*anthropicToolStream)
+		}
+		s.toolBlocks[event.Index] = tool
+		s.tools = append(s.tools, tool)
+		return nil
+	}
+	if event.Type != "content_block_delta" || event.Delta == nil {
+		return nil
+	}
+	if event.Delta.Type == "input_json_delta" {
+		if tool, ok := s.toolBlocks[event.Index]; ok {
+			tool.input.WriteString(event.Delta.PartialJSON)
+		}
+		return nil
+	}
+	delta := event.Delta.Text
+	if delta == "" {
+		return nil
+	}
+	s.content.WriteString(delta)
+	if handle != nil {
+		return handle(delta)
+	}
+	return nil
+}
+
+func (s *anthropicStream) response() ChatResponse {
+	var toolCalls []ToolCall
+	for _, tool := range s.tools {
+		toolCalls = append(toolCalls, ToolCall{
+			ID:        tool.id,
+			Name:      tool.name,
+			Arguments: toolArguments(json.RawMessage(tool.input.String())),
+		})
 	}
 	return ChatResponse{
-		Content:      content.String(),
-		Model:        model,
-		FinishReason: finishReason,
-	}, nil
+		Content:      s.content.String(),
+		ToolCalls:    toolCalls,
+		Model:        s.model,
+		FinishReason: s.finishReason,
+		Usage:        s.usage.toUsage(),
+	}
 }
 
 func (c *AnthropicClient) resolveModel(override string) string {
@@ -231,15 +308,82 @@ func readAnthropicError(body io.Reader, status int) error {
 	return fmt.Errorf("anthropic request failed with status %d", status)
 }
 
-func splitAnthropicMessages(messages []Message) ([]Message, string) {
-	if len(messages) == 0 {
-		return messages, ""
+func firstPositive(values ...int) int {
+	for _, value := range values {
+		if value > 0 {
+			return value
+		}
+	}
+	return 0
+}
+
+// splitAnthropicMessages moves a leading system message to the system
+// field and converts tool calls and results to content blocks. Consecutive
+// tool results share one user message, as the API requires.
+func splitAnthropicMessages(messages []Message) ([]anthropicMessage, string) {
+	system := ""
+	if len(messages) > 0 && messages[0].Role == "system" {
+		system = messages[0].Content
+		messages = messages[1:]
+	}
+	out := make([]anthropicMessage, 0, len(messages))
+	for _, message := range messages {
+		switch {
+		case message.Role == "tool":
+			block := anthropicContent{Type: "tool_result", ToolUseID: message.ToolCallID, Content: message.Content}
+			if last := len(out) - 1; last >= 0 && out[last].Role == "user" && out[last].Content[0].Type == "tool_result" {
+				out[last].Content = append(out[last].Content, block)
+				continue
+			}
+			out = append(out, anthropicMessage{Role: "user", Content: []anthropicContent{block}})
+		case len(message.ToolCalls) > 0:
+			var blocks []anthropicContent
+			if message.Content != "" {
+				blocks = append(blocks, anthropicContent{Type: "text", Text: message.Content})
+			}
+			for _, call := range message.ToolCalls {
+				blocks = append(blocks, anthropicContent{
+					Type:  "tool_use",
+					ID:    call.ID,
+					Name:  call.Name,
+					Input: toolArguments(call.Arguments),
+				})
+			}
+			out = append(out, anthropicMessage{Role: message.Role, Content: blocks})
+		default:
+			out = append(out, anthropicMessage{
+				Role:    message.Role,
+				Content: []anthropicContent{{Type: "text", Text: message.Content}},
+			})
+		}
+	}
+	return out, system
+}
+
+func toAnthropicTools(tools []Tool) []anthropicTool {
+	if len(tools) == 0 {
+		return nil
 	}
-	first := messages[0]
-	if first.Role != "system" {
-		return messages, ""
+	out := make([]anthropicTool, 0, len(tools))
+	for _, tool := range tools {
+		out = append(out, anthropicTool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: toolParameters(tool.Parameters),
+		})
 	}
-	return messages[1:], first.Content
+	return out
+}
+
+func anthropicToolCalls(blocks []anthropicContent) []ToolCall {
+	var calls []ToolCall
+	for _, block := range blocks {
+		if block.Type != "tool_use" {
+			continue
+		}
+		calls = append(calls, ToolCall{ID: block.ID, Name: block.Name, Arguments: toolArguments(block.Input)})
+	}
+	return calls
 }
 
 func flattenAnthropicContent(blocks []anthropicContent) string {
@@ -257,11 +401,58 @@ func flattenAnthropicContent(blocks []anthropicContent) string {
 }
 
 type anthropicChatRequest struct {
-	Model     string    `json:"model"`
-	Messages  []Message `json:"messages"`
-	System    string    `json:"system,omitempty"`
-	MaxTokens int       `json:"max_tokens"`
-	Stream    bool      `json:"stream,omitempty"`
+	Model         string             `json:"model"`
+	Messages      []anthropicMessage `json:"messages"`
+	System        string             `json:"system,omitempty"`
+	MaxTokens     int                `json:"max_tokens"`
+	Temperature   *float64           `json:"temperature,omitempty"`
+	TopP          *float64           `json:"top_p,omitempty"`
+	StopSequences []string           `json:"stop_sequences,omitempty"`
+	Tools         []anthropicTool    `json:"tools,omitempty"`
+	Stream        bool               `json:"stream,omitempty"`
+}
+
+// anthropicMessage is a Messages API message. A single text block is
+// encoded as plain string content.
+type anthropicMessage struct {
+	Role    string
+	Content []anthropicContent
+}
+
+func (m anthropicMessage) MarshalJSON() ([]byte, error) {
+	if len(m.Content) == 1 && m.Content[0].Type == "text" {
+		return json.Marshal(struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		}{m.Role, m.Content[0].Text})
+	}
+	return json.Marshal(struct {
+		Role    string             `json:"role"`
+		Content []anthropicContent `json:"content"`
+	}{m.Role, m.Content})
+}
+
+func (m *anthropicMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	m.Role = raw.Role
+	var text string
+	if err := json.Unmarshal(raw.Content, &text); err == nil {
+		m.Content = []anthropicContent{{Type: "text", Text: text}}
+		return nil
+	}
+	return json.Unmarshal(raw.Content, &m.Content)
+}
+
+type anthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
 }
 
 type anthropicChatResponse struct {
@@ -269,12 +460,36 @@ type anthropicChatResponse struct {
 	Model      string             `json:"model"`
 	Content    []anthropicContent `json:"content"`
 	StopReason string             `json:"stop_reason"`
+	Usage      *anthropicUsage    `json:"usage,omitempty"`
 	Error      *anthropicError    `json:"error,omitempty"`
 }
 
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+func (u *anthropicUsage) toUsage() Usage {
+	if u == nil {
+		return Usage{}
+	}
+	return Usage{
+		PromptTokens:     u.InputTokens,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      u.InputTokens + u.OutputTokens,
+	}
+}
+
+// anthropicContent is a content block: text, tool_use (ID, Name, Input)
+// or tool_result (ToolUseID, Content).
 type anthropicContent struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
 }
 
 type anthropicError struct {
@@ -283,17 +498,24 @@ type anthropicError struct {
 }
 
 type anthropicStreamEvent struct {
-	Type       string          `json:"type"`
-	Message    *anthropicEvent `json:"message,omitempty"`
-	Delta      *anthropicDelta `json:"delta,omitempty"`
-	StopReason string          `json:"stop_reason,omitempty"`
-	Error      *anthropicError `json:"error,omitempty"`
+	Type         string            `json:"type"`
+	Index        int               `json:"index"`
+	ContentBlock *anthropicContent `json:"content_block,omitempty"`
+	Message      *anthropicEvent   `json:"message,omitempty"`
+	Delta        *anthropicDelta   `json:"delta,omitempty"`
+	StopReason   string            `json:"stop_reason,omitempty"`
+	Usage        *anthropicUsage   `json:"usage,omitempty"`
+	Error        *anthropicError   `json:"error,omitempty"`
 }
 
 type anthropicEvent struct {
-	Model string `json:"model"`
+	Model string          `json:"model"`
+	Usage *anthropicUsage `json:"usage,omitempty"`
 }
 
 type anthropicDelta struct {
-	Text string `json:"text"`
+	Type        string `json:"type,omitempty"`
+	Text        string `json:"text"`
+	PartialJSON string `json:"partial_json,omitempty"`
+	StopReason  string `json:"stop_reason,omitempty"`
 }
@@ -73,10 +73,10 @@ func TestAnthropicChatStream(t *testing.T) {
 		w.Header().Set("Content-Type", "text/event-stream")
 		flusher, _ := w.(http.Flusher)
 		chunks := []string{
-			`data: {"type":"message_start","message":{"model":"claude-test"}}` + "\n\n",
+			`data: {"type":"message_start","message":{"model":"claude-test","usage":{"input_tokens":3}}}` + "\n\n",
 			`data: {"type":"content_block_delta","delta":{"text":"he"}}` + "\n\n",
 			`data: {"type":"content_block_delta","delta":{"text":"llo"}}` + "\n\n",
-			`data: {"type":"message_delta","stop_reason":"end_turn"}` + "\n\n",
+			`data: {"type":"message_delta","stop_reason":"end_turn","usage":{"output_tokens":2}}` + "\n\n",
 		}
 		for _, chunk := range chunks {
 			_, _ = w.Write([]byte(chunk))
@@ -118,4 +118,7 @@ func TestAnthropicChatStream(t *testing.T) {
 	if resp.Model != "claude-test" {
 		t.Fatalf("unexpected model: %s", resp.Model)
 	}
+	if resp.Usage.PromptTokens != 3 || resp.Usage.CompletionTokens != 2 || resp.Usage.TotalTokens != 5 {
+		t.Fatalf("unexpected usage: %+v", resp.Usage)
+	}
 }
@@ -0,0 +1,64 @@
+package llm
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSignAWSRequestVanilla uses the get-vanilla case from the AWS
+// Signature Version 4 test suite.
+func TestSignAWSRequestVanilla(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	creds := AWSCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	if err := signAWSRequest(req, nil, creds, "us-east-1", "service", now); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, " +
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Fatalf("unexpected authorization:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCanonicalAWSPathEncodesTwice(t *testing.T) {
+	if got := canonicalAWSPath("/model/anthropic.claude-v2%3A1/invoke"); got != "/model/anthropic.claude-v2%253A1/invoke" {
+		t.Fatalf("unexpected path: %s", got)
+	}
+}
+
+func TestLoadAWSCredentialsProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	data := "[default]\naws_access_key_id = A\naws_secret_access_key = B\n\n[work]\naws_access_key_id = C\naws_secret_access_key = D\naws_session_token = E\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write credentials: %v", err)
+	}
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", path)
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_PROFILE", "")
+
+	creds, err := LoadAWSCredentials("work")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if creds != (AWSCredentials{AccessKeyID: "C", SecretAccessKey: "D", SessionToken: "E"}) {
+		t.Fatalf("unexpected credentials: %+v", creds)
+	}
+	creds, err = LoadAWSCredentials("")
+	if err != nil || creds.AccessKeyID != "A" {
+		t.Fatalf("unexpected default credentials: %+v %v", creds, err)
+	}
+	if _, err := LoadAWSCredentials("missing"); err == nil {
+		t.Fatalf("expected error for missing profile")
+	}
+}
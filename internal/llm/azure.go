@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultAzureOpenAIAPIVersion = "2024-06-01"
+
+type AzureOpenAIConfig struct {
+	// Resource is the Azure OpenAI resource name, used to build
+	// https://<resource>.openai.azure.com when BaseURL is empty.
+	Resource   string
+	BaseURL    string
+	Deployment string
+	APIVersion string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewAzureOpenAIClient returns an OpenAI-compatible client that routes
+// requests to an Azure deployment and authenticates with the api-key
+// header. The deployment decides the model, so it is also used as the
+// model name sent in requests.
+func NewAzureOpenAIClient(cfg AzureOpenAIConfig) (*OpenAIClient, error) {
+	baseURL := strings.TrimSpace(cfg.BaseURL)
+	if baseURL == "" {
+		resource := strings.TrimSpace(cfg.Resource)
+		if resource == "" {
+			return nil, errors.New("azure-openai resource or base url is required")
+		}
+		baseURL = fmt.Sprintf("https://%s.openai.azure.com", resource)
+	}
+	deployment := strings.TrimSpace(cfg.Deployment)
+	if deployment == "" {
+		return nil, errors.New("azure-openai deployment is required")
+	}
+	token := strings.TrimSpace(cfg.Token)
+	if token == "" {
+		return nil, errors.New("azure-openai token is required")
+	}
+	apiVersion := strings.TrimSpace(cfg.APIVersion)
+	if apiVersion == "" {
+		apiVersion = defaultAzureOpenAIAPIVersion
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &OpenAIClient{
+		endpoint:   buildAzureChatEndpoint(baseURL, deployment, apiVersion),
+		authHeader: "api-key",
+		authValue:  token,
+		model:      deployment,
+		provider:   "azure-openai",
+		httpClient: client,
+	}, nil
+}
+
+func buildAzureChatEndpoint(baseURL, deployment, apiVersion string) string {
+	base := strings.TrimRight(baseURL, "/")
+	base = strings.TrimSuffix(base, "/openai")
+	query := url.Values{"api-version": {apiVersion}}
+	return fmt.Sprintf("%s/openai/deployments/%s/chat/completions?%s",
+		base, url.PathEscape(deployment), query.Encode())
+}
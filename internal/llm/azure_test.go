@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAzureOpenAIChatStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/gpt-4o-prod/chat/completions" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("api-version"); got != "2024-10-21" {
+			t.Fatalf("unexpected api-version: %s", got)
+		}
+		if got := r.Header.Get("api-key"); got != "secret" {
+			t.Fatalf("unexpected api-key: %s", got)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Fatalf("unexpected authorization header")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintln(w, `data: {"model":"gpt-4o","choices":[{"delta":{"content":"hel"}}]}`)
+		fmt.Fprintln(w, `data: {"choices":[{"delta":{"content":"lo"},"finish_reason":"stop"}]}`)
+		fmt.Fprintln(w, "data: [DONE]")
+	}))
+	defer server.Close()
+
+	client, err := NewAzureOpenAIClient(AzureOpenAIConfig{
+		BaseURL:    server.URL,
+		Deployment: "gpt-4o-prod",
+		APIVersion: "2024-10-21",
+		Token:      "secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	resp, err := client.ChatStream(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("chat stream: %v", err)
+	}
+	if resp.Content != "hello" || resp.FinishReason != "stop" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAzureOpenAIEndpointFromResource(t *testing.T) {
+	client, err := NewAzureOpenAIClient(AzureOpenAIConfig{
+		Resource:   "contoso",
+		Deployment: "chat",
+		Token:      "secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	want := "https://contoso.openai.azure.com/openai/deployments/chat/chat/completions?api-version=" + defaultAzureOpenAIAPIVersion
+	if client.endpoint != want {
+		t.Fatalf("unexpected endpoint: %s", client.endpoint)
+	}
+}
+
+func TestAzureOpenAIRequiresDeployment(t *testing.T) {
+	if _, err := NewAzureOpenAIClient(AzureOpenAIConfig{Resource: "contoso", Token: "secret"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,343 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	bedrockService          = "bedrock"
+	bedrockAnthropicVersion = "bedrock-2023-05-31"
+	defaultBedrockMaxTokens = 1024
+)
+
+type BedrockConfig struct {
+	Region string
+	// BaseURL overrides https://bedrock-runtime.<region>.amazonaws.com.
+	BaseURL     string
+	Model       string
+	Credentials AWSCredentials
+	MaxTokens   int
+	HTTPClient  *http.Client
+}
+
+// BedrockClient calls Claude and Llama models through the Bedrock
+// InvokeModel and InvokeModelWithResponseStream APIs.
+type BedrockClient struct {
+	baseURL    string
+	region     string
+	model      string
+	creds      AWSCredentials
+	maxTokens  int
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+func NewBedrockClient(cfg BedrockConfig) (*BedrockClient, error) {
+	region := strings.TrimSpace(cfg.Region)
+	if region == "" {
+		return nil, errors.New("bedrock region is required")
+	}
+	model := strings.TrimSpace(cfg.Model)
+	if model == "" {
+		return nil, errors.New("bedrock model is required")
+	}
+	if cfg.Credentials.AccessKeyID == "" || cfg.Credentials.SecretAccessKey == "" {
+		return nil, errors.New("bedrock credentials are required")
+	}
+	baseURL := strings.TrimSpace(cfg.BaseURL)
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", region)
+	}
+	maxTokens := cfg.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultBedrockMaxTokens
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &BedrockClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		region:     region,
+		model:      model,
+		creds:      cfg.Credentials,
+		maxTokens:  maxTokens,
+		httpClient: client,
+		now:        time.Now,
+	}, nil
+}
+
+func (c *BedrockClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	model := c.resolveModel(req.Model)
+	body, err := c.buildBody(model, req)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	httpResp, err := c.send(ctx, model, "invoke", body, "application/json")
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	defer httpResp.Body.Close()
+
+	switch bedrockModelFamily(model) {
+	case "anthropic":
+		var resp anthropicChatResponse
+		if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+			return ChatResponse{}, fmt.Errorf("decode response: %w", err)
+		}
+		return ChatResponse{
+			Content:      flattenAnthropicContent(resp.Content),
+			ToolCalls:    anthropicToolCalls(resp.Content),
+			Model:        firstNonEmptyString(resp.Model, model),
+			FinishReason: resp.StopReason,
+			Usage:        resp.Usage.toUsage(),
+		}, nil
+	default:
+		var resp bedrockLlamaResponse
+		if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+			return ChatResponse{}, fmt.Errorf("decode response: %w", err)
+		}
+		return ChatResponse{
+			Content:      resp.Generation,
+			Model:        model,
+			FinishReason: resp.StopReason,
+			Usage:        resp.toUsage(),
+		}, nil
+	}
+}
+
+func (c *BedrockClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	model := c.resolveModel(req.Model)
+	body, err := c.buildBody(model, req)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	httpResp, err := c.send(ctx, model, "invoke-with-response-stream", body, "application/vnd.amazon.eventstream")
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	defer httpResp.Body.Close()
+
+	family := bedrockModelFamily(model)
+	var claude anthropicStream
+	var llama bedrockLlamaResponse
+	var content strings.Builder
+	for {
+		message, err := readEventStreamMessage(httpResp.Body)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ChatResponse{}, err
+		}
+		if message.Headers[":message-type"] == "exception" {
+			return ChatResponse{}, readBedrockError(message.Headers[":exception-type"], message.Payload)
+		}
+		if message.Headers[":event-type"] != "chunk" {
+			continue
+		}
+		var chunk bedrockChunk
+		if err := json.Unmarshal(message.Payload, &chunk); err != nil {
+			return ChatResponse{}, fmt.Errorf("decode stream chunk: %w", err)
+		}
+		if family == "anthropic" {
+			var event anthropicStreamEvent
+			if err := json.Unmarshal(chunk.Bytes, &event); err != nil {
+				return ChatResponse{}, fmt.Errorf("decode stream chunk: %w", err)
+			}
+			if err := claude.apply(event, handle); err != nil {
+				return ChatResponse{}, err
+			}
+			continue
+		}
+		var part bedrockLlamaResponse
+		if err := json.Unmarshal(chunk.Bytes, &part); err != nil {
+			return ChatResponse{}, fmt.Errorf("decode stream chunk: %w", err)
+		}
+		if part.StopReason != "" {
+			llama.StopReason = part.StopReason
+		}
+		if part.PromptTokenCount > 0 {
+			llama.PromptTokenCount = part.PromptTokenCount
+		}
+		llama.GenerationTokenCount += part.GenerationTokenCount
+		if part.Generation == "" {
+			continue
+		}
+		content.WriteString(part.Generation)
+		if handle != nil {
+			if err := handle(part.Generation); err != nil {
+				return ChatResponse{}, err
+			}
+		}
+	}
+	if family == "anthropic" {
+		resp := claude.response()
+		resp.Model = firstNonEmptyString(resp.Model, model)
+		return resp, nil
+	}
+	return ChatResponse{
+		Content:      content.String(),
+		Model:        model,
+		FinishReason: llama.StopReason,
+		Usage:        llama.toUsage(),
+	}, nil
+}
+
+func (c *BedrockClient) resolveModel(override string) string {
+	if strings.TrimSpace(override) == "" {
+		return c.model
+	}
+	return override
+}
+
+// buildBody encodes req in the model family's native schema. Llama models
+// take no stop sequences.
+func (c *BedrockClient) buildBody(model string, req ChatRequest) ([]byte, error) {
+	var payload any
+	maxTokens := firstPositive(req.MaxTokens, c.maxTokens)
+	switch bedrockModelFamily(model) {
+	case "anthropic":
+		chat, system := splitAnthropicMessages(req.Messages)
+		payload = bedrockAnthropicRequest{
+			AnthropicVersion: bedrockAnthropicVersion,
+			Messages:         chat,
+			System:           system,
+			MaxTokens:        maxTokens,
+			Temperature:      req.Temperature,
+			TopP:             req.TopP,
+			StopSequences:    req.StopSequences,
+			Tools:            toAnthropicTools(req.Tools),
+		}
+	case "llama":
+		payload = bedrockLlamaRequest{
+			Prompt:      buildLlamaPrompt(req.Messages),
+			MaxGenLen:   maxTokens,
+			Temperature: req.Temperature,
+			TopP:        req.TopP,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported bedrock model: %s (expected an anthropic or meta llama model)", model)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	return body, nil
+}
+
+func (c *BedrockClient) send(ctx context.Context, model, action string, body []byte, accept string) (*http.Response, error) {
+	endpoint := fmt.Sprintf("%s/model/%s/%s", c.baseURL, awsURIEncode(model), action)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", accept)
+	if err := signAWSRequest(httpReq, body, c.creds, c.region, bedrockService, c.now()); err != nil {
+		return nil, err
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("bedrock request: %w", err)
+	}
+	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusMultipleChoices {
+		defer httpResp.Body.Close()
+		data, _ := io.ReadAll(httpResp.Body)
+		return nil, fmt.Errorf("%w (status %d)", readBedrockError("", data), httpResp.StatusCode)
+	}
+	return httpResp, nil
+}
+
+// bedrockModelFamily maps a model or inference profile ID, such as
+// "us.anthropic.claude-3-5-haiku-20241022-v1:0", to its request format.
+func bedrockModelFamily(model string) string {
+	switch {
+	case strings.Contains(model, "anthropic."):
+		return "anthropic"
+	case strings.Contains(model, "meta.llama"):
+		return "llama"
+	default:
+		return ""
+	}
+}
+
+// buildLlamaPrompt renders messages in the Llama 3 chat template.
+func buildLlamaPrompt(messages []Message) string {
+	var builder strings.Builder
+	builder.WriteString("<|begin_of_text|>")
+	for _, message := range messages {
+		fmt.Fprintf(&builder, "<|start_header_id|>%s<|end_header_id|>\n\n%s<|eot_id|>", message.Role, message.Content)
+	}
+	builder.WriteString("<|start_header_id|>assistant<|end_header_id|>\n\n")
+	return builder.String()
+}
+
+func readBedrockError(kind string, payload []byte) error {
+	var resp struct {
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(payload, &resp)
+	message := firstNonEmptyString(resp.Message, strings.TrimSpace(string(payload)), "unknown error")
+	if kind != "" {
+		return fmt.Errorf("bedrock %s: %s", kind, message)
+	}
+	return fmt.Errorf("bedrock request failed: %s", message)
+}
+
+func firstNonEmptyString(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+type bedrockAnthropicRequest struct {
+	AnthropicVersion string             `json:"anthropic_version"`
+	Messages         []anthropicMessage `json:"messages"`
+	System           string             `json:"system,omitempty"`
+	MaxTokens        int                `json:"max_tokens"`
+	Temperature      *float64           `json:"temperature,omitempty"`
+	TopP             *float64           `json:"top_p,omitempty"`
+	StopSequences    []string           `json:"stop_sequences,omitempty"`
+	Tools            []anthropicTool    `json:"tools,omitempty"`
+}
+
+type bedrockLlamaRequest struct {
+	Prompt      string   `json:"prompt"`
+	MaxGenLen   int      `json:"max_gen_len"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+}
+
+type bedrockLlamaResponse struct {
+	Generation           string `json:"generation"`
+	PromptTokenCount     int    `json:"prompt_token_count"`
+	GenerationTokenCount int    `json:"generation_token_count"`
+	StopReason           string `json:"stop_reason"`
+}
+
+func (r bedrockLlamaResponse) toUsage() Usage {
+	return Usage{
+		PromptTokens:     r.PromptTokenCount,
+		CompletionTokens: r.GenerationTokenCount,
+		TotalTokens:      r.PromptTokenCount + r.GenerationTokenCount,
+	}
+}
+
+// bedrockChunk is the payload of a chunk event; Bytes holds the
+// base64-decoded model event.
+type bedrockChunk struct {
+	Bytes []byte `json:"bytes"`
+}
@@ -0,0 +1,158 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func encodeEventStreamMessage(headers map[string]string, payload []byte) []byte {
+	var headerBytes bytes.Buffer
+	for name, value := range headers {
+		headerBytes.WriteByte(byte(len(name)))
+		headerBytes.WriteString(name)
+		headerBytes.WriteByte(7)
+		_ = binary.Write(&headerBytes, binary.BigEndian, uint16(len(value)))
+		headerBytes.WriteString(value)
+	}
+	total := uint32(16 + headerBytes.Len() + len(payload))
+	var message bytes.Buffer
+	_ = binary.Write(&message, binary.BigEndian, total)
+	_ = binary.Write(&message, binary.BigEndian, uint32(headerBytes.Len()))
+	_ = binary.Write(&message, binary.BigEndian, crc32.ChecksumIEEE(message.Bytes()))
+	message.Write(headerBytes.Bytes())
+	message.Write(payload)
+	_ = binary.Write(&message, binary.BigEndian, crc32.ChecksumIEEE(message.Bytes()))
+	return message.Bytes()
+}
+
+func bedrockChunkMessage(t *testing.T, event string) []byte {
+	t.Helper()
+	payload, err := json.Marshal(bedrockChunk{Bytes: []byte(event)})
+	if err != nil {
+		t.Fatalf("marshal chunk: %v", err)
+	}
+	return encodeEventStreamMessage(map[string]string{
+		":message-type": "event",
+		":event-type":   "chunk",
+	}, payload)
+}
+
+func newTestBedrockClient(t *testing.T, baseURL, model string) *BedrockClient {
+	t.Helper()
+	client, err := NewBedrockClient(BedrockConfig{
+		Region:      "us-east-1",
+		BaseURL:     baseURL,
+		Model:       model,
+		Credentials: AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return client
+}
+
+func TestBedrockClaudeStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/model/anthropic.claude-3-haiku-20240307-v1%3A0/invoke-with-response-stream" {
+			t.Fatalf("unexpected path: %s", r.URL.EscapedPath())
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			t.Fatalf("unexpected authorization: %s", r.Header.Get("Authorization"))
+		}
+		var req bedrockAnthropicRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.AnthropicVersion != bedrockAnthropicVersion || req.System != "sys" || len(req.Messages) != 1 {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		w.Write(bedrockChunkMessage(t, `{"type":"message_start","message":{"model":"claude-3-haiku","usage":{"input_tokens":4}}}`))
+		w.Write(bedrockChunkMessage(t, `{"type":"content_block_delta","delta":{"text":"hel"}}`))
+		w.Write(bedrockChunkMessage(t, `{"type":"content_block_delta","delta":{"text":"lo"}}`))
+		w.Write(bedrockChunkMessage(t, `{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":2}}`))
+	}))
+	defer server.Close()
+
+	client := newTestBedrockClient(t, server.URL, "anthropic.claude-3-haiku-20240307-v1:0")
+	var deltas []string
+	resp, err := client.ChatStream(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "system", Content: "sys"}, {Role: "user", Content: "hi"}},
+	}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chat stream: %v", err)
+	}
+	if resp.Content != "hello" || strings.Join(deltas, "|") != "hel|lo" {
+		t.Fatalf("unexpected content: %+v %q", resp, deltas)
+	}
+	if resp.FinishReason != "end_turn" || resp.Usage.TotalTokens != 6 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestBedrockLlamaChat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/invoke") {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		var req bedrockLlamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if !strings.Contains(req.Prompt, "<|start_header_id|>user<|end_header_id|>\n\nhi<|eot_id|>") {
+			t.Fatalf("unexpected prompt: %q", req.Prompt)
+		}
+		_ = json.NewEncoder(w).Encode(bedrockLlamaResponse{
+			Generation:           "hello",
+			PromptTokenCount:     5,
+			GenerationTokenCount: 1,
+			StopReason:           "stop",
+		})
+	}))
+	defer server.Close()
+
+	client := newTestBedrockClient(t, server.URL, "meta.llama3-8b-instruct-v1:0")
+	resp, err := client.Chat(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if resp.Content != "hello" || resp.Usage.TotalTokens != 6 || resp.FinishReason != "stop" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestBedrockStreamException(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(encodeEventStreamMessage(map[string]string{
+			":message-type":   "exception",
+			":exception-type": "throttlingException",
+		}, []byte(`{"message":"slow down"}`)))
+	}))
+	defer server.Close()
+
+	client := newTestBedrockClient(t, server.URL, "anthropic.claude-v2")
+	_, err := client.ChatStream(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}, nil)
+	if err == nil || err.Error() != "bedrock throttlingException: slow down" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBedrockRejectsUnknownModel(t *testing.T) {
+	client := newTestBedrockClient(t, "http://127.0.0.1", "amazon.titan-text-express-v1")
+	if _, err := client.Chat(context.Background(), ChatRequest{}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,326 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrNotRecorded is returned in replay mode for a request the cassette
+// has no unused response for.
+var ErrNotRecorded = errors.New("request not recorded in cassette")
+
+// cassette is a JSON file of HTTP exchanges. Credentials are never
+// stored: request headers are dropped, and token-like query parameters
+// and body fields are replaced with "REDACTED".
+type cassette struct {
+	mu           sync.Mutex
+	path         string
+	Interactions []interaction `json:"interactions"`
+	used         []bool
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	recordedBody
+}
+
+type recordedResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	recordedBody
+}
+
+// recordedBody keeps text bodies readable and binary ones, such as
+// Bedrock event streams, intact.
+type recordedBody struct {
+	Body       string `json:"body,omitempty"`
+	BodyBase64 []byte `json:"body_base64,omitempty"`
+}
+
+func newRecordedBody(data []byte) recordedBody {
+	if utf8.Valid(data) {
+		return recordedBody{Body: string(data)}
+	}
+	return recordedBody{BodyBase64: data}
+}
+
+func (b recordedBody) bytes() []byte {
+	if b.BodyBase64 != nil {
+		return b.BodyBase64
+	}
+	return []byte(b.Body)
+}
+
+// recordedHeaders are the response headers kept in a cassette.
+var recordedHeaders = []string{"Content-Type", "Retry-After"}
+
+var (
+	cassettesMu sync.Mutex
+	cassettes   = make(map[cassetteKey]*cassette)
+)
+
+type cassetteKey struct {
+	path   string
+	replay bool
+}
+
+// openCassette returns the cassette at path, shared by every client in the
+// process so their exchanges end up in one file. Recording starts from an
+// empty cassette; replaying loads the file.
+func openCassette(path string, replay bool) (*cassette, error) {
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+	key := cassetteKey{path: path, replay: replay}
+	if c, ok := cassettes[key]; ok {
+		return c, nil
+	}
+	c := &cassette{path: path}
+	if replay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, c); err != nil {
+			return nil, fmt.Errorf("decode cassette %s: %w", path, err)
+		}
+		c.used = make([]bool, len(c.Interactions))
+	}
+	cassettes[key] = c
+	return c, nil
+}
+
+func (c *cassette) add(exchange interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, exchange)
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cassette: %w", err)
+	}
+	if dir := filepath.Dir(c.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create cassette dir: %w", err)
+		}
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	return nil
+}
+
+// take returns the first unused interaction for req, preferring one with
+// the same body. Falling back to any body lets requests that embed
+// timestamps, such as signed token exchanges, replay in order.
+func (c *cassette) take(req recordedRequest) (interaction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fallback := -1
+	for i, exchange := range c.Interactions {
+		if c.used[i] || exchange.Request.Method != req.Method || exchange.Request.URL != req.URL {
+			continue
+		}
+		if bytes.Equal(exchange.Request.bytes(), req.bytes()) {
+			c.used[i] = true
+			return exchange, true
+		}
+		if fallback < 0 {
+			fallback = i
+		}
+	}
+	if fallback < 0 {
+		return interaction{}, false
+	}
+	c.used[fallback] = true
+	return c.Interactions[fallback], true
+}
+
+var (
+	secretParams = map[string]bool{"key": true, "api-key": true, "api_key": true, "access_token": true, "token": true, "sig": true, "signature": true}
+	secretJSON   = regexp.MustCompile(`("(?:access_token|id_token|refresh_token)"\s*:\s*)"[^"]*"`)
+	secretForm   = regexp.MustCompile(`(^|&)(assertion|client_secret|refresh_token)=[^&]*`)
+)
+
+func redactURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+	for name := range query {
+		if secretParams[strings.ToLower(name)] {
+			query.Set(name, "REDACTED")
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	redacted.User = nil
+	return redacted.String()
+}
+
+func redactBody(data []byte) []byte {
+	data = secretJSON.ReplaceAll(data, []byte(`$1"REDACTED"`))
+	return secretForm.ReplaceAll(data, []byte(`${1}${2}=REDACTED`))
+}
+
+// readRequest records req, restoring its body for the next transport.
+func readRequest(req *http.Request) (recordedRequest, error) {
+	recorded := recordedRequest{Method: req.Method, URL: redactURL(req.URL)}
+	if req.Body == nil || req.Body == http.NoBody {
+		return recorded, nil
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return recorded, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	recorded.recordedBody = newRecordedBody(redactBody(data))
+	return recorded, nil
+}
+
+// recordTransport saves every exchange to a cassette once its response
+// body has been read or closed, so streams reach the caller as they
+// arrive.
+type recordTransport struct {
+	base     http.RoundTripper
+	cassette *cassette
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	recorded, err := readRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	response := recordedResponse{Status: resp.StatusCode, Header: make(map[string]string)}
+	for _, name := range recordedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			response.Header[name] = value
+		}
+	}
+	resp.Body = &recordingBody{ReadCloser: resp.Body, done: func(body []byte) error {
+		response.recordedBody = newRecordedBody(redactBody(body))
+		return t.cassette.add(interaction{Request: recorded, Response: response})
+	}}
+	return resp, nil
+}
+
+type recordingBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	once sync.Once
+	done func([]byte) error
+	err  error
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		if saveErr := b.save(); saveErr != nil {
+			return n, saveErr
+		}
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	err := b.ReadCloser.Close()
+	if saveErr := b.save(); saveErr != nil {
+		return saveErr
+	}
+	return err
+}
+
+func (b *recordingBody) save() error {
+	b.once.Do(func() { b.err = b.done(b.buf.Bytes()) })
+	return b.err
+}
+
+// replayTransport answers requests from a cassette without touching the
+// network.
+type replayTransport struct {
+	cassette *cassette
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := readRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	exchange, ok := t.cassette.take(recorded)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, recorded.Method, recorded.URL)
+	}
+	body := exchange.Response.bytes()
+	header := make(http.Header)
+	for name, value := range exchange.Response.Header {
+		header.Set(name, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", exchange.Response.Status, http.StatusText(exchange.Response.Status)),
+		StatusCode:    exchange.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// withRecord returns a copy of client, or of a default client, that saves
+// its exchanges to the cassette at path.
+func withRecord(client *http.Client, path string) (*http.Client, error) {
+	c, err := openCassette(path, false)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := &http.Client{}
+	if client != nil {
+		*wrapped = *client
+	}
+	base := wrapped.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	wrapped.Transport = &recordTransport{base: base, cassette: c}
+	return wrapped, nil
+}
+
+// withReplay returns a copy of client, or of a default client, that
+// answers from the cassette at path instead of the network.
+func withReplay(client *http.Client, path string) (*http.Client, error) {
+	c, err := openCassette(path, true)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := &http.Client{}
+	if client != nil {
+		*wrapped = *client
+	}
+	wrapped.Transport = &replayTransport{cassette: c}
+	return wrapped, nil
+}
@@ -0,0 +1,266 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const defaultDashScopeBaseURL = "https://dashscope.aliyuncs.com"
+
+type DashScopeConfig struct {
+	BaseURL    string
+	Token      string
+	Model      string
+	HTTPClient *http.Client
+}
+
+// DashScopeClient calls Qwen models through the native DashScope
+// text-generation API rather than its OpenAI-compatible mode.
+type DashScopeClient struct {
+	baseURL    string
+	token      string
+	model      string
+	httpClient *http.Client
+}
+
+func NewDashScopeClient(cfg DashScopeConfig) (*DashScopeClient, error) {
+	baseURL := strings.TrimSpace(cfg.BaseURL)
+	if baseURL == "" {
+		baseURL = defaultDashScopeBaseURL
+	}
+	token := strings.TrimSpace(cfg.Token)
+	if token == "" {
+		return nil, errors.New("dashscope token is required")
+	}
+	model := strings.TrimSpace(cfg.Model)
+	if model == "" {
+		return nil, errors.New("dashscope model is required")
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &DashScopeClient{
+		baseURL:    baseURL,
+		token:      token,
+		model:      model,
+		httpClient: client,
+	}, nil
+}
+
+func (c *DashScopeClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	model := c.resolveModel(req.Model)
+	httpResp, err := c.send(ctx, model, req, false)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	defer httpResp.Body.Close()
+
+	var resp dashScopeResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return ChatResponse{}, fmt.Errorf("decode response: %w", err)
+	}
+	if resp.Code != "" {
+		return ChatResponse{}, fmt.Errorf("dashscope error: %s", resp.errorMessage())
+	}
+	if len(resp.Output.Choices) == 0 {
+		return ChatResponse{}, errors.New("dashscope response has no choices")
+	}
+	choice := resp.Output.Choices[0]
+	return ChatResponse{
+		Content:      choice.Message.Content,
+		Model:        model,
+		FinishReason: choice.finishReason(),
+		Usage:        resp.Usage.toUsage(),
+	}, nil
+}
+
+func (c *DashScopeClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	model := c.resolveModel(req.Model)
+	httpResp, err := c.send(ctx, model, req, true)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	defer httpResp.Body.Close()
+
+	var content strings.Builder
+	var finishReason string
+	var usage Usage
+
+	scanner := bufio.NewScanner(httpResp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		var chunk dashScopeResponse
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &chunk); err != nil {
+			continue
+		}
+		if chunk.Code != "" {
+			return ChatResponse{}, fmt.Errorf("dashscope error: %s", chunk.errorMessage())
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage.toUsage()
+		}
+		if len(chunk.Output.Choices) == 0 {
+			continue
+		}
+		choice := chunk.Output.Choices[0]
+		if reason := choice.finishReason(); reason != "" {
+			finishReason = reason
+		}
+		delta := choice.Message.Content
+		if delta == "" {
+			continue
+		}
+		content.WriteString(delta)
+		if handle != nil {
+			if err := handle(delta); err != nil {
+				return ChatResponse{}, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ChatResponse{}, fmt.Errorf("%w: %v", ErrStreamInterrupted, err)
+	}
+	if finishReason == "" {
+		return ChatResponse{}, ErrStreamInterrupted
+	}
+	return ChatResponse{
+		Content:      content.String(),
+		Model:        model,
+		FinishReason: finishReason,
+		Usage:        usage,
+	}, nil
+}
+
+func (c *DashScopeClient) resolveModel(override string) string {
+	if strings.TrimSpace(override) == "" {
+		return c.model
+	}
+	return override
+}
+
+// send posts a generation request. Streaming uses the X-DashScope-SSE
+// protocol with incremental output, so each event carries only new text.
+func (c *DashScopeClient) send(ctx context.Context, model string, req ChatRequest, stream bool) (*http.Response, error) {
+	payload := dashScopeRequest{Model: model}
+	payload.Input.Messages = req.Messages
+	payload.Parameters.ResultFormat = "message"
+	payload.Parameters.IncrementalOutput = stream
+	payload.Parameters.Temperature = req.Temperature
+	payload.Parameters.TopP = req.TopP
+	payload.Parameters.MaxTokens = req.MaxTokens
+	payload.Parameters.Stop = req.StopSequences
+	requestBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	endpoint := strings.TrimRight(c.baseURL, "/") + "/api/v1/services/aigc/text-generation/generation"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+		httpReq.Header.Set("X-DashScope-SSE", "enable")
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("dashscope request: %w", err)
+	}
+	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusMultipleChoices {
+		defer httpResp.Body.Close()
+		return nil, readDashScopeError(httpResp.Body, httpResp.StatusCode)
+	}
+	return httpResp, nil
+}
+
+func readDashScopeError(body io.Reader, status int) error {
+	var resp dashScopeResponse
+	_ = json.NewDecoder(body).Decode(&resp)
+	statusErr := &StatusError{Provider: "dashscope", StatusCode: status}
+	if resp.Code != "" || resp.Message != "" {
+		statusErr.Message = resp.errorMessage()
+	}
+	return statusErr
+}
+
+type dashScopeRequest struct {
+	Model string `json:"model"`
+	Input struct {
+		Messages []Message `json:"messages"`
+	} `json:"input"`
+	Parameters struct {
+		ResultFormat      string   `json:"result_format"`
+		IncrementalOutput bool     `json:"incremental_output,omitempty"`
+		Temperature       *float64 `json:"temperature,omitempty"`
+		TopP              *float64 `json:"top_p,omitempty"`
+		MaxTokens         int      `json:"max_tokens,omitempty"`
+		Stop              []string `json:"stop,omitempty"`
+	} `json:"parameters"`
+}
+
+type dashScopeResponse struct {
+	Output struct {
+		Choices []dashScopeChoice `json:"choices"`
+	} `json:"output"`
+	Usage     *dashScopeUsage `json:"usage,omitempty"`
+	RequestID string          `json:"request_id"`
+	Code      string          `json:"code,omitempty"`
+	Message   string          `json:"message,omitempty"`
+}
+
+func (r dashScopeResponse) errorMessage() string {
+	if r.Code == "" {
+		return r.Message
+	}
+	return fmt.Sprintf("%s: %s", r.Code, r.Message)
+}
+
+type dashScopeChoice struct {
+	Message      Message `json:"message"`
+	FinishReason string  `json:"finish_reason"`
+}
+
+// finishReason maps the "null" placeholder DashScope sends on
+// intermediate chunks to an empty reason.
+func (c dashScopeChoice) finishReason() string {
+	if c.FinishReason == "null" {
+		return ""
+	}
+	return c.FinishReason
+}
+
+type dashScopeUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+func (u *dashScopeUsage) toUsage() Usage {
+	if u == nil {
+		return Usage{}
+	}
+	total := u.TotalTokens
+	if total == 0 {
+		total = u.InputTokens + u.OutputTokens
+	}
+	return Usage{
+		PromptTokens:     u.InputTokens,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      total,
+	}
+}
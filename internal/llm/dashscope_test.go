@@ -0,0 +1,72 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDashScopeChatStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/services/aigc/text-generation/generation" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("X-DashScope-SSE") != "enable" {
+			t.Fatalf("missing X-DashScope-SSE header")
+		}
+		var req dashScopeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Model != "qwen-plus" || !req.Parameters.IncrementalOutput || len(req.Input.Messages) != 1 {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		fmt.Fprint(w, "id:1\nevent:result\n:HTTP_STATUS/200\n")
+		fmt.Fprint(w, `data:{"output":{"choices":[{"message":{"role":"assistant","content":"你"},"finish_reason":"null"}]},"usage":{"input_tokens":3,"output_tokens":1,"total_tokens":4}}`+"\n\n")
+		fmt.Fprint(w, "id:2\nevent:result\n:HTTP_STATUS/200\n")
+		fmt.Fprint(w, `data:{"output":{"choices":[{"message":{"role":"assistant","content":"好"},"finish_reason":"stop"}]},"usage":{"input_tokens":3,"output_tokens":2,"total_tokens":5}}`+"\n\n")
+	}))
+	defer server.Close()
+
+	client, err := NewDashScopeClient(DashScopeConfig{BaseURL: server.URL, Token: "sk", Model: "qwen-plus"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	var deltas []string
+	resp, err := client.ChatStream(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chat stream: %v", err)
+	}
+	if resp.Content != "你好" || strings.Join(deltas, "|") != "你|好" {
+		t.Fatalf("unexpected content: %+v", resp)
+	}
+	if resp.FinishReason != "stop" || resp.Usage.TotalTokens != 5 || resp.Model != "qwen-plus" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestDashScopeChatError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"code":"InvalidApiKey","message":"Invalid API-key provided.","request_id":"x"}`)
+	}))
+	defer server.Close()
+
+	client, err := NewDashScopeClient(DashScopeConfig{BaseURL: server.URL, Token: "sk", Model: "qwen-plus"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	_, err = client.Chat(context.Background(), ChatRequest{})
+	if err == nil || err.Error() != "dashscope request failed: InvalidApiKey: Invalid API-key provided. (status 401)" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
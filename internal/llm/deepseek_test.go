@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDeepSeekStreamReasoning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Fatalf("unexpected authorization: %s", r.Header.Get("Authorization"))
+		}
+		fmt.Fprintln(w, `data: {"model":"deepseek-reasoner","choices":[{"delta":{"reasoning_content":"think "}}]}`)
+		fmt.Fprintln(w, `data: {"choices":[{"delta":{"reasoning_content":"more"}}]}`)
+		fmt.Fprintln(w, `data: {"choices":[{"delta":{"content":"answer"},"finish_reason":"stop"}]}`)
+		fmt.Fprintln(w, "data: [DONE]")
+	}))
+	defer server.Close()
+
+	client, err := NewDeepSeekClient(OpenAIConfig{BaseURL: server.URL, Token: "token", Model: "deepseek-reasoner"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	var thoughts []string
+	resp, err := client.ChatStream(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		ReasoningHandler: func(delta string) error {
+			thoughts = append(thoughts, delta)
+			return nil
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("chat stream: %v", err)
+	}
+	if resp.Content != "answer" || resp.Reasoning != "think more" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if strings.Join(thoughts, "|") != "think |more" {
+		t.Fatalf("unexpected reasoning deltas: %q", thoughts)
+	}
+}
+
+func TestDeepSeekChatReasoning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"model":"deepseek-reasoner","choices":[{"message":{"role":"assistant","content":"answer","reasoning_content":"because"},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewDeepSeekClient(OpenAIConfig{BaseURL: server.URL, Token: "token", Model: "deepseek-reasoner"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	resp, err := client.Chat(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if resp.Content != "answer" || resp.Reasoning != "because" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestDeepSeekDefaultBaseURL(t *testing.T) {
+	client, err := NewDeepSeekClient(OpenAIConfig{Token: "token", Model: "deepseek-chat"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if client.endpoint != "https://api.deepseek.com/v1/chat/completions" {
+		t.Fatalf("unexpected endpoint: %s", client.endpoint)
+	}
+}
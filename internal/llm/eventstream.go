@@ -0,0 +1,100 @@
+package llm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// eventStreamMessage is one frame of the AWS event stream encoding
+// (application/vnd.amazon.eventstream) used by Bedrock streaming.
+type eventStreamMessage struct {
+	Headers map[string]string
+	Payload []byte
+}
+
+const maxEventStreamMessage = 16 * 1024 * 1024
+
+// readEventStreamMessage reads the next frame from r. It returns io.EOF
+// when the stream ends cleanly between frames. Only string headers are
+// kept; other header types are skipped.
+func readEventStreamMessage(r io.Reader) (eventStreamMessage, error) {
+	prelude := make([]byte, 12)
+	if _, err := io.ReadFull(r, prelude); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return eventStreamMessage{}, fmt.Errorf("read event stream prelude: %w", err)
+		}
+		return eventStreamMessage{}, err
+	}
+	totalLength := binary.BigEndian.Uint32(prelude[0:4])
+	headersLength := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[0:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return eventStreamMessage{}, errors.New("event stream prelude checksum mismatch")
+	}
+	if totalLength < 16+headersLength || totalLength > maxEventStreamMessage {
+		return eventStreamMessage{}, fmt.Errorf("invalid event stream message length %d", totalLength)
+	}
+	rest := make([]byte, totalLength-12)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return eventStreamMessage{}, fmt.Errorf("read event stream message: %w", err)
+	}
+	body := rest[:len(rest)-4]
+	checksum := crc32.NewIEEE()
+	checksum.Write(prelude)
+	checksum.Write(body)
+	if checksum.Sum32() != binary.BigEndian.Uint32(rest[len(rest)-4:]) {
+		return eventStreamMessage{}, errors.New("event stream message checksum mismatch")
+	}
+	headers, err := parseEventStreamHeaders(body[:headersLength])
+	if err != nil {
+		return eventStreamMessage{}, err
+	}
+	return eventStreamMessage{Headers: headers, Payload: body[headersLength:]}, nil
+}
+
+func parseEventStreamHeaders(data []byte) (map[string]string, error) {
+	headers := make(map[string]string)
+	for len(data) > 0 {
+		nameLength := int(data[0])
+		if len(data) < 1+nameLength+1 {
+			return nil, errors.New("truncated event stream header")
+		}
+		name := string(data[1 : 1+nameLength])
+		valueType := data[1+nameLength]
+		data = data[2+nameLength:]
+
+		size := 0
+		switch valueType {
+		case 0, 1:
+			size = 0
+		case 2:
+			size = 1
+		case 3:
+			size = 2
+		case 4:
+			size = 4
+		case 5, 8:
+			size = 8
+		case 9:
+			size = 16
+		case 6, 7:
+			if len(data) < 2 {
+				return nil, errors.New("truncated event stream header")
+			}
+			size = int(binary.BigEndian.Uint16(data[0:2]))
+			data = data[2:]
+		default:
+			return nil, fmt.Errorf("unknown event stream header type %d", valueType)
+		}
+		if len(data) < size {
+			return nil, errors.New("truncated event stream header")
+		}
+		if valueType == 7 {
+			headers[name] = string(data[:size])
+		}
+		data = data[size:]
+	}
+	return headers, nil
+}
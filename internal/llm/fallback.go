@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+type streamFallback struct {
+	client Client
+}
+
+// WithStreamFallback retries a stream that fails with ErrStreamInterrupted
+// as a non-streaming request. Text already passed to the handler is not
+// repeated: if the full answer continues it, only the rest is passed on,
+// otherwise the full answer follows on a new line.
+func WithStreamFallback(client Client) Client {
+	return &streamFallback{client: client}
+}
+
+func (c *streamFallback) Unwrap() Client { return c.client }
+
+func (c *streamFallback) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return c.client.Chat(ctx, req)
+}
+
+func (c *streamFallback) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	var streamed strings.Builder
+	resp, err := c.client.ChatStream(ctx, req, func(delta string) error {
+		streamed.WriteString(delta)
+		if handle == nil {
+			return nil
+		}
+		return handle(delta)
+	})
+	if !errors.Is(err, ErrStreamInterrupted) || ctx.Err() != nil {
+		return resp, err
+	}
+
+	// Reasoning already shown while streaming would be repeated in full.
+	req.ReasoningHandler = nil
+	resp, err = c.client.Chat(ctx, req)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	rest := resp.Content
+	if prefix := streamed.String(); prefix != "" {
+		if strings.HasPrefix(rest, prefix) {
+			rest = strings.TrimPrefix(rest, prefix)
+		} else {
+			rest = "\n" + rest
+		}
+	}
+	if rest != "" && handle != nil {
+		if err := handle(rest); err != nil {
+			return ChatResponse{}, err
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStreamFallbackAfterInterruption(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			fmt.Fprintln(w, `data: {"model":"gpt-test","choices":[{"delta":{"content":"hel"}}]}`)
+			fmt.Fprintln(w, `data: {not json`)
+			fmt.Fprintln(w, `: keep-alive`)
+			fmt.Fprintln(w, `data: {"choices":[{"delta":{"content":"lo "}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"model":"gpt-test","choices":[{"message":{"content":"hello world"},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+
+	inner, err := NewOpenAIClient(OpenAIConfig{BaseURL: server.URL, Token: "token", Model: "gpt-test"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	var deltas []string
+	resp, err := WithStreamFallback(inner).ChatStream(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chat stream: %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected a non-streaming retry, got %d requests", requests)
+	}
+	if got := strings.Join(deltas, "|"); got != "hel|lo |world" {
+		t.Fatalf("unexpected deltas: %s", got)
+	}
+	if resp.Content != "hello world" || resp.FinishReason != "stop" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestStreamFallbackDifferentAnswer(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			fmt.Fprintln(w, `data: {"choices":[{"delta":{"content":"hi"}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"hello"},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+
+	inner, _ := NewOpenAIClient(OpenAIConfig{BaseURL: server.URL, Token: "token", Model: "gpt-test"})
+	var out strings.Builder
+	_, err := WithStreamFallback(inner).ChatStream(context.Background(), ChatRequest{}, func(delta string) error {
+		out.WriteString(delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chat stream: %v", err)
+	}
+	if out.String() != "hi\nhello" {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
@@ -15,36 +15,71 @@ import (
 )
 
 type GeminiConfig struct {
-	BaseURL    string
-	Token      string
-	Model      string
+	BaseURL string
+	Token   string
+	Model   string
+	// Vertex switches from API-key auth to Vertex AI: OAuth bearer tokens
+	// and the projects/{p}/locations/{l}/publishers/google/models/{m}
+	// endpoint layout.
+	Vertex     *VertexConfig
 	HTTPClient *http.Client
 }
 
+type VertexConfig struct {
+	Project  string
+	Location string
+	// CredentialsFile is a service account key or authorized user file.
+	// When empty, application default credentials are used. A static
+	// GeminiConfig.Token takes precedence over both.
+	CredentialsFile string
+}
+
 type GeminiClient struct {
 	baseURL    string
 	token      string
 	model      string
+	vertex     *geminiVertex
 	httpClient *http.Client
 }
 
+type geminiVertex struct {
+	project  string
+	location string
+	token    func(ctx context.Context) (string, error)
+}
+
 func NewGeminiClient(cfg GeminiConfig) (*GeminiClient, error) {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	model := strings.TrimSpace(cfg.Model)
+	if model == "" {
+		return nil, errors.New("gemini model is required")
+	}
 	baseURL := strings.TrimSpace(cfg.BaseURL)
+	token := strings.TrimSpace(cfg.Token)
+	if cfg.Vertex != nil {
+		vertex, err := newGeminiVertex(*cfg.Vertex, token, client)
+		if err != nil {
+			return nil, err
+		}
+		if baseURL == "" {
+			baseURL = vertexBaseURL(vertex.location)
+		}
+		return &GeminiClient{
+			baseURL:    baseURL,
+			model:      model,
+			vertex:     vertex,
+			httpClient: client,
+		}, nil
+	}
 	if baseURL == "" {
 		return nil, errors.New("gemini base url is required")
 	}
-	token := strings.TrimSpace(cfg.Token)
 	if token == "" {
 		return nil, errors.New("gemini token is required")
 	}
-	model := strings.TrimSpace(cfg.Model)
-	if model == "" {
-		return nil, errors.New("gemini model is required")
-	}
-	client := cfg.HTTPClient
-	if client == nil {
-		client = &http.Client{}
-	}
 	return &GeminiClient{
 		baseURL:    baseURL,
 		token:      token,
@@ -53,11 +88,42 @@ func NewGeminiClient(cfg GeminiConfig) (*GeminiClient, error) {
 	}, nil
 }
 
+func newGeminiVertex(cfg VertexConfig, token string, httpClient *http.Client) (*geminiVertex, error) {
+	project := strings.TrimSpace(cfg.Project)
+	if project == "" {
+		return nil, errors.New("vertex project is required")
+	}
+	location := strings.TrimSpace(cfg.Location)
+	if location == "" {
+		location = "us-central1"
+	}
+	vertex := &geminiVertex{project: project, location: location}
+	if token != "" {
+		vertex.token = func(context.Context) (string, error) { return token, nil }
+		return vertex, nil
+	}
+	creds, err := loadGoogleCredentials(strings.TrimSpace(cfg.CredentialsFile))
+	if err != nil {
+		return nil, err
+	}
+	vertex.token = newGoogleTokenSource(creds, httpClient).Token
+	return vertex, nil
+}
+
+func vertexBaseURL(location string) string {
+	if location == "global" {
+		return "https://aiplatform.googleapis.com"
+	}
+	return fmt.Sprintf("https://%s-aiplatform.googleapis.com", location)
+}
+
 func (c *GeminiClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
 	contents, system := buildGeminiContents(req.Messages)
 	payload := geminiGenerateContentRequest{
 		Contents:          contents,
 		SystemInstruction: system,
+		GenerationConfig:  newGeminiGenerationConfig(req),
+		Tools:             toGeminiTools(req.Tools),
 	}
 	var resp geminiGenerateContentResponse
 	if err := c.do(ctx, payload, c.resolveModel(req.Model), false, &resp); err != nil {
@@ -69,8 +135,10 @@ func (c *GeminiClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse,
 	content := flattenGeminiContent(resp.Candidates[0].Content)
 	return ChatResponse{
 		Content:      content,
+		ToolCalls:    geminiToolCalls(resp.Candidates[0].Content),
 		Model:        resp.ModelVersion,
 		FinishReason: resp.Candidates[0].FinishReason,
+		Usage:        resp.UsageMetadata.toUsage(),
 	}, nil
 }
 
@@ -79,21 +147,17 @@ func (c *GeminiClient) ChatStream(ctx context.Context, req ChatRequest, handle S
 	payload := geminiGenerateContentRequest{
 		Contents:          contents,
 		SystemInstruction: system,
+		GenerationConfig:  newGeminiGenerationConfig(req),
+		Tools:             toGeminiTools(req.Tools),
 	}
 	requestBody, err := json.Marshal(payload)
 	if err != nil {
 		return ChatResponse{}, fmt.Errorf("marshal request: %w", err)
 	}
-	model := c.resolveModel(req.Model)
-	endpoint, err := buildGeminiEndpoint(c.baseURL, model, true, c.token)
+	httpReq, err := c.newRequest(ctx, c.resolveModel(req.Model), true, requestBody)
 	if err != nil {
 		return ChatResponse{}, err
 	}
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(requestBody))
-	if err != nil {
-		return ChatResponse{}, fmt.Errorf("create request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "text/event-stream")
 
 	httpResp, err := c.httpClient.Do(httpReq)
@@ -107,8 +171,10 @@ func (c *GeminiClient) ChatStream(ctx context.Context, req ChatRequest, handle S
 	}
 
 	var content strings.Builder
+	var toolCalls []ToolCall
 	var finishReason string
 	var modelVersion string
+	var usage Usage
 
 	scanner := bufio.NewScanner(httpResp.Body)
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
@@ -129,7 +195,7 @@ func (c *GeminiClient) ChatStream(ctx context.Context, req ChatRequest, handle S
 		}
 		var chunk geminiGenerateContentResponse
 		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
-			return ChatResponse{}, fmt.Errorf("decode stream chunk: %w", err)
+			continue
 		}
 		if chunk.Error != nil {
 			return ChatResponse{}, fmt.Errorf("gemini error: %s", chunk.Error.Message)
@@ -137,12 +203,17 @@ func (c *GeminiClient) ChatStream(ctx context.Context, req ChatRequest, handle S
 		if chunk.ModelVersion != "" {
 			modelVersion = chunk.ModelVersion
 		}
+		if chunk.UsageMetadata != nil {
+			usage = chunk.UsageMetadata.toUsage()
+		}
 		if len(chunk.Candidates) == 0 {
 			continue
 		}
 		if chunk.Candidates[0].FinishReason != "" {
 			finishReason = chunk.Candidates[0].FinishReason
 		}
+		// Function calls arrive whole, never split across chunks.
+		toolCalls = append(toolCalls, geminiToolCalls(chunk.Candidates[0].Content)...)
 		delta := flattenGeminiContent(chunk.Candidates[0].Content)
 		if delta == "" {
 			continue
@@ -155,12 +226,17 @@ func (c *GeminiClient) ChatStream(ctx context.Context, req ChatRequest, handle S
 		}
 	}
 	if err := scanner.Err(); err != nil {
-		return ChatResponse{}, fmt.Errorf("read stream: %w", err)
+		return ChatResponse{}, fmt.Errorf("%w: %v", ErrStreamInterrupted, err)
+	}
+	if finishReason == "" {
+		return ChatResponse{}, ErrStreamInterrupted
 	}
 	return ChatResponse{
 		Content:      content.String(),
+		ToolCalls:    toolCalls,
 		Model:        modelVersion,
 		FinishReason: finishReason,
+		Usage:        usage,
 	}, nil
 }
 
@@ -176,15 +252,10 @@ func (c *GeminiClient) do(ctx context.Context, payload geminiGenerateContentRequ
 	if err != nil {
 		return fmt.Errorf("marshal request: %w", err)
 	}
-	endpoint, err := buildGeminiEndpoint(c.baseURL, model, stream, c.token)
+	httpReq, err := c.newRequest(ctx, model, stream, requestBody)
 	if err != nil {
 		return err
 	}
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(requestBody))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
 
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -204,6 +275,49 @@ func (c *GeminiClient) do(ctx context.Context, payload geminiGenerateContentRequ
 	return nil
 }
 
+// newRequest builds a generateContent request, authenticated with the API
+// key query parameter or, in Vertex mode, an OAuth bearer token.
+func (c *GeminiClient) newRequest(ctx context.Context, model string, stream bool, body []byte) (*http.Request, error) {
+	var endpoint string
+	var err error
+	if c.vertex != nil {
+		endpoint, err = buildVertexEndpoint(c.baseURL, c.vertex.project, c.vertex.location, model, stream)
+	} else {
+		endpoint, err = buildGeminiEndpoint(c.baseURL, model, stream, c.token)
+	}
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.vertex != nil {
+		token, err := c.vertex.token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	return httpReq, nil
+}
+
+func buildVertexEndpoint(baseURL, project, location, model string, stream bool) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil {
+		return "", fmt.Errorf("invalid base url: %w", err)
+	}
+	verb := "generateContent"
+	if stream {
+		verb = "streamGenerateContent"
+		u.RawQuery = url.Values{"alt": {"sse"}}.Encode()
+	}
+	u.Path = path.Join(u.Path, "/v1/projects", project, "locations", location,
+		"publishers/google/models", fmt.Sprintf("%s:%s", model, verb))
+	return u.String(), nil
+}
+
 func buildGeminiEndpoint(baseURL, model string, stream bool, token string) (string, error) {
 	baseURL = strings.TrimSpace(baseURL)
 	if baseURL == "" {
@@ -258,18 +372,76 @@ func buildGeminiContents(messages []Message) ([]geminiContent, *geminiSystemInst
 	}
 	contents := make([]geminiContent, 0, len(messages)-start)
 	for _, message := range messages[start:] {
+		if message.Role == "tool" {
+			// Consecutive tool results share one user turn.
+			part := geminiPart{FunctionResponse: &geminiFunctionResponse{
+				ID:       message.ToolCallID,
+				Name:     message.Name,
+				Response: toolResult(message.Content),
+			}}
+			if last := len(contents) - 1; last >= 0 && contents[last].Role == "user" && contents[last].Parts[0].FunctionResponse != nil {
+				contents[last].Parts = append(contents[last].Parts, part)
+				continue
+			}
+			contents = append(contents, geminiContent{Role: "user", Parts: []geminiPart{part}})
+			continue
+		}
 		role := message.Role
 		if role == "assistant" {
 			role = "model"
 		}
-		contents = append(contents, geminiContent{
-			Role:  role,
-			Parts: []geminiPart{{Text: message.Content}},
-		})
+		parts := []geminiPart{{Text: message.Content}}
+		if len(message.ToolCalls) > 0 {
+			parts = parts[:0]
+			if message.Content != "" {
+				parts = append(parts, geminiPart{Text: message.Content})
+			}
+			for _, call := range message.ToolCalls {
+				parts = append(parts, geminiPart{FunctionCall: &geminiFunctionCall{
+					ID:   call.ID,
+					Name: call.Name,
+					Args: toolArguments(call.Arguments),
+				}})
+			}
+		}
+		contents = append(contents, geminiContent{Role: role, Parts: parts})
 	}
 	return contents, system
 }
 
+// toGeminiTools declares all tools in one function declarations entry.
+// Parameters are omitted when unset, since Gemini rejects object schemas
+// without properties.
+func toGeminiTools(tools []Tool) []geminiTool {
+	if len(tools) == 0 {
+		return nil
+	}
+	declarations := make([]geminiFunctionDeclaration, 0, len(tools))
+	for _, tool := range tools {
+		declarations = append(declarations, geminiFunctionDeclaration{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Parameters:  tool.Parameters,
+		})
+	}
+	return []geminiTool{{FunctionDeclarations: declarations}}
+}
+
+func geminiToolCalls(content geminiContent) []ToolCall {
+	var calls []ToolCall
+	for _, part := range content.Parts {
+		if part.FunctionCall == nil {
+			continue
+		}
+		calls = append(calls, ToolCall{
+			ID:        part.FunctionCall.ID,
+			Name:      part.FunctionCall.Name,
+			Arguments: toolArguments(part.FunctionCall.Args),
+		})
+	}
+	return calls
+}
+
 func flattenGeminiContent(content geminiContent) string {
 	if len(content.Parts) == 0 {
 		return ""
@@ -287,12 +459,63 @@ func flattenGeminiContent(content geminiContent) string {
 type geminiGenerateContentRequest struct {
 	Contents          []geminiContent          `json:"contents"`
 	SystemInstruction *geminiSystemInstruction `json:"systemInstruction,omitempty"`
+	GenerationConfig  *geminiGenerationConfig  `json:"generationConfig,omitempty"`
+	Tools             []geminiTool             `json:"tools,omitempty"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature     *float64 `json:"temperature,omitempty"`
+	TopP            *float64 `json:"topP,omitempty"`
+	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
+	StopSequences   []string `json:"stopSequences,omitempty"`
+}
+
+// newGeminiGenerationConfig returns nil when req sets no sampling
+// parameters, so the request keeps the model defaults.
+func newGeminiGenerationConfig(req ChatRequest) *geminiGenerationConfig {
+	if req.Temperature == nil && req.TopP == nil && req.MaxTokens <= 0 && len(req.StopSequences) == 0 {
+		return nil
+	}
+	return &geminiGenerationConfig{
+		Temperature:     req.Temperature,
+		TopP:            req.TopP,
+		MaxOutputTokens: req.MaxTokens,
+		StopSequences:   req.StopSequences,
+	}
 }
 
 type geminiGenerateContentResponse struct {
-	Candidates   []geminiCandidate `json:"candidates"`
-	ModelVersion string            `json:"modelVersion,omitempty"`
-	Error        *geminiError      `json:"error,omitempty"`
+	Candidates    []geminiCandidate    `json:"candidates"`
+	ModelVersion  string               `json:"modelVersion,omitempty"`
+	UsageMetadata *geminiUsageMetadata `json:"usageMetadata,omitempty"`
+	Error         *geminiError         `json:"error,omitempty"`
+}
+
+type geminiUsageMetadata struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
+}
+
+func (u *geminiUsageMetadata) toUsage() Usage {
+	if u == nil {
+		return Usage{}
+	}
+	return Usage{
+		PromptTokens:     u.PromptTokenCount,
+		CompletionTokens: u.CandidatesTokenCount,
+		TotalTokens:      u.TotalTokenCount,
+	}
 }
 
 type geminiCandidate struct {
@@ -306,7 +529,21 @@ type geminiContent struct {
 }
 
 type geminiPart struct {
-	Text string `json:"text,omitempty"`
+	Text             string                  `json:"text,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type geminiFunctionCall struct {
+	ID   string          `json:"id,omitempty"`
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type geminiFunctionResponse struct {
+	ID       string          `json:"id,omitempty"`
+	Name     string          `json:"name"`
+	Response json.RawMessage `json:"response"`
 }
 
 type geminiSystemInstruction struct {
@@ -0,0 +1,180 @@
+package llm
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	googleCloudScope    = "https://www.googleapis.com/auth/cloud-platform"
+	defaultGoogleTokens = "https://oauth2.googleapis.com/token"
+)
+
+// googleCredentials is a service account key or an application default
+// credentials file written by `gcloud auth application-default login`.
+type googleCredentials struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// loadGoogleCredentials reads path, or the application default
+// credentials (GOOGLE_APPLICATION_CREDENTIALS, then the gcloud default
+// location) when path is empty.
+func loadGoogleCredentials(path string) (googleCredentials, error) {
+	if path == "" {
+		path = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	if path == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return googleCredentials{}, fmt.Errorf("resolve config dir: %w", err)
+		}
+		path = filepath.Join(configDir, "gcloud", "application_default_credentials.json")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return googleCredentials{}, fmt.Errorf("read google credentials: %w", err)
+	}
+	var creds googleCredentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return googleCredentials{}, fmt.Errorf("decode google credentials: %w", err)
+	}
+	switch creds.Type {
+	case "service_account", "authorized_user":
+		return creds, nil
+	default:
+		return googleCredentials{}, fmt.Errorf("unsupported google credentials type: %q", creds.Type)
+	}
+}
+
+// googleTokenSource exchanges credentials for OAuth access tokens and
+// caches each token until shortly before it expires.
+type googleTokenSource struct {
+	creds      googleCredentials
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newGoogleTokenSource(creds googleCredentials, httpClient *http.Client) *googleTokenSource {
+	return &googleTokenSource{creds: creds, httpClient: httpClient, now: time.Now}
+}
+
+func (s *googleTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && s.now().Before(s.expires.Add(-time.Minute)) {
+		return s.token, nil
+	}
+
+	form := url.Values{}
+	tokenURI := s.creds.TokenURI
+	if tokenURI == "" {
+		tokenURI = defaultGoogleTokens
+	}
+	switch s.creds.Type {
+	case "service_account":
+		assertion, err := signGoogleJWT(s.creds, tokenURI, s.now())
+		if err != nil {
+			return "", err
+		}
+		form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+		form.Set("assertion", assertion)
+	default:
+		form.Set("grant_type", "refresh_token")
+		form.Set("client_id", s.creds.ClientID)
+		form.Set("client_secret", s.creds.ClientSecret)
+		form.Set("refresh_token", s.creds.RefreshToken)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("create token request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpResp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("google token request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	var resp struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return "", fmt.Errorf("decode token response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK || resp.AccessToken == "" {
+		message := firstNonEmptyString(resp.ErrorDescription, resp.Error, "no access token")
+		return "", fmt.Errorf("google token request failed: %s (status %d)", message, httpResp.StatusCode)
+	}
+	s.token = resp.AccessToken
+	s.expires = s.now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// signGoogleJWT builds the RS256-signed assertion for the service account
+// JWT bearer grant.
+func signGoogleJWT(creds googleCredentials, audience string, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(creds.PrivateKey))
+	if block == nil {
+		return "", errors.New("google service account private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("parse google private key: %w", err)
+		}
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", errors.New("google service account key is not an RSA key")
+	}
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iss":   creds.ClientEmail,
+		"scope": googleCloudScope,
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("encode jwt claims: %w", err)
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign jwt: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
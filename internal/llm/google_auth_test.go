@@ -0,0 +1,132 @@
+package llm
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGeminiVertexServiceAccount(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	tokenRequests := 0
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		if got := r.PostForm.Get("grant_type"); got != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			t.Fatalf("unexpected grant type: %s", got)
+		}
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		if len(parts) != 3 {
+			t.Fatalf("unexpected assertion: %v", parts)
+		}
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			t.Fatalf("decode signature: %v", err)
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			t.Fatalf("verify signature: %v", err)
+		}
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		var claims map[string]any
+		_ = json.Unmarshal(payload, &claims)
+		if claims["iss"] != "bot@proj.iam.gserviceaccount.com" || claims["aud"] != server.URL+"/token" {
+			t.Fatalf("unexpected claims: %v", claims)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "ya29.test", "expires_in": 3600})
+	})
+	mux.HandleFunc("/v1/projects/proj/locations/europe-west4/publishers/google/models/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/projects/proj/locations/europe-west4/publishers/google/models/gemini-test:generateContent" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Has("key") {
+			t.Fatalf("unexpected api key query")
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer ya29.test" {
+			t.Fatalf("unexpected authorization: %s", got)
+		}
+		_ = json.NewEncoder(w).Encode(geminiGenerateContentResponse{
+			Candidates: []geminiCandidate{{
+				Content:      geminiContent{Role: "model", Parts: []geminiPart{{Text: "hello"}}},
+				FinishReason: "STOP",
+			}},
+		})
+	})
+
+	credentials, _ := json.Marshal(googleCredentials{
+		Type:        "service_account",
+		ClientEmail: "bot@proj.iam.gserviceaccount.com",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		TokenURI:    server.URL + "/token",
+	})
+	path := filepath.Join(t.TempDir(), "sa.json")
+	if err := os.WriteFile(path, credentials, 0o600); err != nil {
+		t.Fatalf("write credentials: %v", err)
+	}
+
+	client, err := NewClient("gemini", Config{
+		BaseURL: server.URL,
+		Model:   "gemini-test",
+		Options: map[string]string{
+			"project":     "proj",
+			"location":    "europe-west4",
+			"credentials": path,
+		},
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Chat(context.Background(), ChatRequest{
+			Messages: []Message{{Role: "user", Content: "hi"}},
+		})
+		if err != nil {
+			t.Fatalf("chat: %v", err)
+		}
+		if resp.Content != "hello" {
+			t.Fatalf("unexpected content: %s", resp.Content)
+		}
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected cached token, got %d token requests", tokenRequests)
+	}
+}
+
+func TestBuildVertexEndpoint(t *testing.T) {
+	endpoint, err := buildVertexEndpoint(vertexBaseURL("us-central1"), "proj", "us-central1", "gemini-1.5-pro", true)
+	if err != nil {
+		t.Fatalf("build endpoint: %v", err)
+	}
+	want := "https://us-central1-aiplatform.googleapis.com/v1/projects/proj/locations/us-central1/publishers/google/models/gemini-1.5-pro:streamGenerateContent?alt=sse"
+	if endpoint != want {
+		t.Fatalf("unexpected endpoint: %s", endpoint)
+	}
+	if got := vertexBaseURL("global"); got != "https://aiplatform.googleapis.com" {
+		t.Fatalf("unexpected global base url: %s", got)
+	}
+}
@@ -0,0 +1,23 @@
+package llm
+
+import (
+	"context"
+	"time"
+)
+
+// Health is the outcome of a HealthCheck.
+type Health struct {
+	Model   string
+	Latency time.Duration
+	Err     error
+}
+
+// HealthCheck sends a minimal ping chat through client and reports the
+// answering model and round-trip latency.
+func HealthCheck(ctx context.Context, client Client) Health {
+	start := time.Now()
+	resp, err := client.Chat(ctx, ChatRequest{
+		Messages: []Message{{Role: "user", Content: "ping"}},
+	})
+	return Health{Model: resp.Model, Latency: time.Since(start), Err: err}
+}
@@ -0,0 +1,32 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type healthStub struct {
+	err error
+}
+
+func (s healthStub) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	if len(req.Messages) != 1 || req.Messages[0].Content != "ping" {
+		return ChatResponse{}, errors.New("unexpected request")
+	}
+	return ChatResponse{Model: "gpt-test"}, s.err
+}
+
+func (s healthStub) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	return s.Chat(ctx, req)
+}
+
+func TestHealthCheck(t *testing.T) {
+	health := HealthCheck(context.Background(), healthStub{})
+	if health.Err != nil || health.Model != "gpt-test" || health.Latency <= 0 {
+		t.Fatalf("unexpected health: %+v", health)
+	}
+	if health := HealthCheck(context.Background(), healthStub{err: errors.New("down")}); health.Err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,260 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultLlamaCppBaseURL  = "http://127.0.0.1:8080"
+	defaultLlamaCppTemplate = "chatml"
+)
+
+type LlamaCppConfig struct {
+	BaseURL string
+	// Token is sent as a bearer token for servers started with --api-key.
+	Token string
+	// Model is informational; the server answers with the model it loaded.
+	Model string
+	// Template is the chat format used to build the prompt: chatml
+	// (default), llama3 or plain.
+	Template   string
+	HTTPClient *http.Client
+}
+
+// LlamaCppClient talks to the native /completion endpoint of a llama.cpp
+// server or llamafile, which takes a single prompt string instead of chat
+// messages.
+type LlamaCppClient struct {
+	baseURL    string
+	token      string
+	model      string
+	template   llamaCppTemplate
+	httpClient *http.Client
+}
+
+func NewLlamaCppClient(cfg LlamaCppConfig) (*LlamaCppClient, error) {
+	baseURL := strings.TrimSpace(cfg.BaseURL)
+	if baseURL == "" {
+		baseURL = defaultLlamaCppBaseURL
+	}
+	name := strings.ToLower(strings.TrimSpace(cfg.Template))
+	if name == "" {
+		name = defaultLlamaCppTemplate
+	}
+	template, ok := llamaCppTemplates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown llamacpp template: %s (expected chatml, llama3 or plain)", name)
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &LlamaCppClient{
+		baseURL:    baseURL,
+		token:      strings.TrimSpace(cfg.Token),
+		model:      strings.TrimSpace(cfg.Model),
+		template:   template,
+		httpClient: client,
+	}, nil
+}
+
+func (c *LlamaCppClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	httpResp, err := c.send(ctx, req, false)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	defer httpResp.Body.Close()
+
+	var resp llamaCppResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return ChatResponse{}, fmt.Errorf("decode response: %w", err)
+	}
+	return ChatResponse{
+		Content:      resp.Content,
+		Model:        firstNonEmptyString(resp.Model, c.model),
+		FinishReason: resp.finishReason(),
+		Usage:        resp.usage(),
+	}, nil
+}
+
+func (c *LlamaCppClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	httpResp, err := c.send(ctx, req, true)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	defer httpResp.Body.Close()
+
+	var content strings.Builder
+	var last llamaCppResponse
+
+	scanner := bufio.NewScanner(httpResp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		var chunk llamaCppResponse
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &chunk); err != nil {
+			continue
+		}
+		if chunk.Error != nil {
+			return ChatResponse{}, fmt.Errorf("llamacpp error: %s", chunk.Error.Message)
+		}
+		if chunk.Content != "" {
+			content.WriteString(chunk.Content)
+			if handle != nil {
+				if err := handle(chunk.Content); err != nil {
+					return ChatResponse{}, err
+				}
+			}
+		}
+		if chunk.Stop {
+			last = chunk
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ChatResponse{}, fmt.Errorf("%w: %v", ErrStreamInterrupted, err)
+	}
+	if !last.Stop {
+		return ChatResponse{}, ErrStreamInterrupted
+	}
+	return ChatResponse{
+		Content:      content.String(),
+		Model:        firstNonEmptyString(last.Model, c.model),
+		FinishReason: last.finishReason(),
+		Usage:        last.usage(),
+	}, nil
+}
+
+func (c *LlamaCppClient) send(ctx context.Context, req ChatRequest, stream bool) (*http.Response, error) {
+	payload := llamaCppRequest{
+		Prompt:      c.template.render(req.Messages),
+		Stop:        append(append([]string(nil), c.template.stop...), req.StopSequences...),
+		Stream:      stream,
+		CachePrompt: true,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		NPredict:    req.MaxTokens,
+	}
+	requestBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	endpoint := strings.TrimRight(c.baseURL, "/") + "/completion"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("llamacpp request: %w", err)
+	}
+	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusMultipleChoices {
+		defer httpResp.Body.Close()
+		return nil, readLlamaCppError(httpResp.Body, httpResp.StatusCode)
+	}
+	return httpResp, nil
+}
+
+func readLlamaCppError(body io.Reader, status int) error {
+	var resp llamaCppResponse
+	_ = json.NewDecoder(body).Decode(&resp)
+	statusErr := &StatusError{Provider: "llamacpp", StatusCode: status}
+	if resp.Error != nil {
+		statusErr.Message = resp.Error.Message
+	}
+	return statusErr
+}
+
+// llamaCppTemplate renders chat messages into the prompt format a model
+// was trained on, ending with an open assistant turn.
+type llamaCppTemplate struct {
+	turn      string
+	assistant string
+	stop      []string
+}
+
+var llamaCppTemplates = map[string]llamaCppTemplate{
+	"chatml": {
+		turn:      "<|im_start|>%s\n%s<|im_end|>\n",
+		assistant: "<|im_start|>assistant\n",
+		stop:      []string{"<|im_end|>"},
+	},
+	"llama3": {
+		turn:      "<|start_header_id|>%s<|end_header_id|>\n\n%s<|eot_id|>",
+		assistant: "<|start_header_id|>assistant<|end_header_id|>\n\n",
+		stop:      []string{"<|eot_id|>"},
+	},
+	"plain": {
+		turn:      "%s: %s\n\n",
+		assistant: "assistant:",
+		stop:      []string{"\nuser:"},
+	},
+}
+
+func (t llamaCppTemplate) render(messages []Message) string {
+	var prompt strings.Builder
+	for _, message := range messages {
+		fmt.Fprintf(&prompt, t.turn, message.Role, message.Content)
+	}
+	prompt.WriteString(t.assistant)
+	return prompt.String()
+}
+
+type llamaCppRequest struct {
+	Prompt      string   `json:"prompt"`
+	Stop        []string `json:"stop,omitempty"`
+	Stream      bool     `json:"stream"`
+	CachePrompt bool     `json:"cache_prompt"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	NPredict    int      `json:"n_predict,omitempty"`
+}
+
+type llamaCppResponse struct {
+	Content         string `json:"content"`
+	Stop            bool   `json:"stop"`
+	Model           string `json:"model"`
+	StoppedLimit    bool   `json:"stopped_limit"`
+	TokensPredicted int    `json:"tokens_predicted"`
+	TokensEvaluated int    `json:"tokens_evaluated"`
+	Error           *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (r llamaCppResponse) finishReason() string {
+	switch {
+	case !r.Stop:
+		return ""
+	case r.StoppedLimit:
+		return "length"
+	default:
+		return "stop"
+	}
+}
+
+func (r llamaCppResponse) usage() Usage {
+	return Usage{
+		PromptTokens:     r.TokensEvaluated,
+		CompletionTokens: r.TokensPredicted,
+		TotalTokens:      r.TokensEvaluated + r.TokensPredicted,
+	}
+}
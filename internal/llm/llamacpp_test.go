@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLlamaCppChat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/completion" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		var req llamaCppRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		want := "<|im_start|>system\nbe brief<|im_end|>\n<|im_start|>user\nhi<|im_end|>\n<|im_start|>assistant\n"
+		if req.Prompt != want || req.Stream || len(req.Stop) != 1 {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		_, _ = w.Write([]byte(`{"content":"hello","stop":true,"model":"qwen2.5-7b.gguf","tokens_evaluated":12,"tokens_predicted":3}`))
+	}))
+	defer server.Close()
+
+	client, err := NewLlamaCppClient(LlamaCppConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	resp, err := client.Chat(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "system", Content: "be brief"}, {Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if resp.Content != "hello" || resp.Model != "qwen2.5-7b.gguf" || resp.FinishReason != "stop" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.Usage.TotalTokens != 15 {
+		t.Fatalf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestLlamaCppChatStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Fatalf("missing auth header")
+		}
+		var req llamaCppRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream || !strings.HasSuffix(req.Prompt, "<|start_header_id|>assistant<|end_header_id|>\n\n") {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"content\":\"hel\",\"stop\":false}\n\n" +
+			"data: {\"content\":\"lo\",\"stop\":false}\n\n" +
+			"data: {\"content\":\"\",\"stop\":true,\"stopped_limit\":true,\"tokens_evaluated\":5,\"tokens_predicted\":2}\n\n"))
+	}))
+	defer server.Close()
+
+	client, err := NewLlamaCppClient(LlamaCppConfig{BaseURL: server.URL, Token: "secret", Model: "local", Template: "llama3"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	var deltas []string
+	resp, err := client.ChatStream(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chat stream: %v", err)
+	}
+	if strings.Join(deltas, "|") != "hel|lo" || resp.Content != "hello" {
+		t.Fatalf("unexpected stream: %v %+v", deltas, resp)
+	}
+	if resp.FinishReason != "length" || resp.Model != "local" || resp.Usage.CompletionTokens != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestLlamaCppUnknownTemplate(t *testing.T) {
+	if _, err := NewLlamaCppClient(LlamaCppConfig{Template: "alpaca"}); err == nil {
+		t.Fatalf("expected template error")
+	}
+}
@@ -1,23 +1,98 @@
 package llm
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
 
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	// ToolCalls are the calls requested by an assistant message.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolCallID and Name identify the call answered by a "tool" message.
+	// Gemini matches results by Name, the other providers by ToolCallID.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	Name       string `json:"name,omitempty"`
+}
+
+// Tool declares a function the model may call. Parameters is a JSON Schema
+// object describing the arguments.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// ToolCall is a function call requested by the model. Arguments is a JSON
+// object; streamed arguments are only complete once the stream ends.
+type ToolCall struct {
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
 }
 
 type ChatRequest struct {
 	Model    string    `json:"model"`
 	Messages []Message `json:"messages"`
+	// ReasoningHandler, when set, receives the model's reasoning from
+	// providers that expose it: as deltas while streaming, or once before
+	// Chat returns.
+	ReasoningHandler StreamHandler `json:"-"`
+	// Sampling parameters. Nil, zero and empty values leave the
+	// provider's default in place.
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopP          *float64 `json:"top_p,omitempty"`
+	MaxTokens     int      `json:"max_tokens,omitempty"`
+	StopSequences []string `json:"stop,omitempty"`
+	// Tools are declared to providers that support function calling;
+	// requested calls are returned in ChatResponse.ToolCalls.
+	Tools []Tool `json:"tools,omitempty"`
 }
 
 type ChatResponse struct {
-	Content      string
+	Content string
+	// Reasoning is the model's reasoning text, for providers such as
+	// DeepSeek that return it separately from the answer.
+	Reasoning string
+	// ToolCalls are the functions the model asked to call, in order.
+	ToolCalls    []ToolCall
 	Model        string
 	FinishReason string
+	Usage        Usage
 }
 
+// Usage reports token counts when the provider returns them.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func (u Usage) IsZero() bool {
+	return u.PromptTokens == 0 && u.CompletionTokens == 0 && u.TotalTokens == 0
+}
+
+// StatusError reports a non-2xx HTTP response from a provider.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s request failed: %s (status %d)", e.Provider, e.Message, e.StatusCode)
+	}
+	return fmt.Sprintf("%s request failed with status %d", e.Provider, e.StatusCode)
+}
+
+// ErrStreamInterrupted is returned by ChatStream when the connection fails
+// or the stream ends before the provider reports a finish reason.
+var ErrStreamInterrupted = errors.New("stream interrupted")
+
 type StreamHandler func(delta string) error
 
 type Client interface {
@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"unicode/utf8"
+)
+
+// logContentLimit is the number of characters of a prompt or response
+// kept in a log record.
+const logContentLimit = 200
+
+type loggingClient struct {
+	client Client
+	logger *slog.Logger
+	redact func(string) string
+	now    func() time.Time
+}
+
+// WithLogging logs every request of client at debug level: method, model,
+// latency, token usage and the truncated last message and response.
+// redact is applied to all logged text, including errors, so credentials
+// do not end up in logs.
+func WithLogging(client Client, logger *slog.Logger, redact func(string) string) Client {
+	if redact == nil {
+		redact = func(text string) string { return text }
+	}
+	return &loggingClient{client: client, logger: logger, redact: redact, now: time.Now}
+}
+
+func (c *loggingClient) Unwrap() Client { return c.client }
+
+func (c *loggingClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	start := c.now()
+	resp, err := c.client.Chat(ctx, req)
+	c.log(ctx, "chat", start, req, resp, err)
+	return resp, err
+}
+
+func (c *loggingClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	start := c.now()
+	resp, err := c.client.ChatStream(ctx, req, handle)
+	c.log(ctx, "chat_stream", start, req, resp, err)
+	return resp, err
+}
+
+func (c *loggingClient) log(ctx context.Context, method string, start time.Time, req ChatRequest, resp ChatResponse, err error) {
+	if !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("model", model),
+		slog.Duration("latency", c.now().Sub(start).Round(time.Millisecond)),
+		slog.Int("messages", len(req.Messages)),
+	}
+	if n := len(req.Messages); n > 0 {
+		attrs = append(attrs, slog.String("prompt", truncate(c.redact(req.Messages[n-1].Content), logContentLimit)))
+	}
+	if !resp.Usage.IsZero() {
+		attrs = append(attrs,
+			slog.Int("prompt_tokens", resp.Usage.PromptTokens),
+			slog.Int("completion_tokens", resp.Usage.CompletionTokens))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", c.redact(err.Error())))
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "llm request failed", attrs...)
+		return
+	}
+	attrs = append(attrs,
+		slog.String("finish_reason", resp.FinishReason),
+		slog.String("response", truncate(c.redact(resp.Content), logContentLimit)))
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "llm request", attrs...)
+}
+
+// truncate shortens text to limit characters, marking the cut with "…".
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return string([]rune(text)[:limit]) + "…"
+}
@@ -0,0 +1,55 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type failingClient struct{}
+
+func (failingClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return ChatResponse{}, errors.New("unauthorized: key sk-secret")
+}
+
+func (c failingClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	return c.Chat(ctx, req)
+}
+
+func TestWithLogging(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	redact := func(text string) string { return strings.ReplaceAll(text, "sk-secret", "[REDACTED]") }
+
+	inner := &usageClient{usage: Usage{PromptTokens: 12, CompletionTokens: 3, TotalTokens: 15}}
+	req := ChatRequest{Model: "gpt-test", Messages: []Message{{Role: "user", Content: "token sk-secret " + strings.Repeat("x", 300)}}}
+	if _, err := WithLogging(inner, logger, redact).ChatStream(context.Background(), req, nil); err != nil {
+		t.Fatalf("chat stream: %v", err)
+	}
+	line := out.String()
+	for _, want := range []string{"method=chat_stream", "model=gpt-test", "prompt_tokens=12", "[REDACTED]", "x…", "response=ok"} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("log missing %s: %s", want, line)
+		}
+	}
+	if strings.Contains(line, "sk-secret") || strings.Contains(line, strings.Repeat("x", 200)) {
+		t.Fatalf("log not redacted or truncated: %s", line)
+	}
+
+	out.Reset()
+	if _, err := WithLogging(failingClient{}, logger, redact).Chat(context.Background(), req); err == nil {
+		t.Fatalf("expected error")
+	}
+	if line := out.String(); !strings.Contains(line, `error="unauthorized: key [REDACTED]"`) {
+		t.Fatalf("unexpected error log: %s", line)
+	}
+
+	out.Reset()
+	quiet := slog.New(slog.NewTextHandler(&out, nil))
+	if _, err := WithLogging(inner, quiet, nil).Chat(context.Background(), req); err != nil || out.Len() != 0 {
+		t.Fatalf("expected no log below debug level: %q, %v", out.String(), err)
+	}
+}
@@ -0,0 +1,86 @@
+package llm
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Middleware wraps a Client to add behaviour around its requests. A
+// middleware that has nothing to do returns the client unchanged.
+type Middleware func(Client) Client
+
+// Chain combines middlewares into one. The first middleware is the
+// outermost: it sees a request first and its response last. Nil entries
+// are skipped.
+func Chain(middlewares ...Middleware) Middleware {
+	return func(client Client) Client {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			if middlewares[i] != nil {
+				client = middlewares[i](client)
+			}
+		}
+		return client
+	}
+}
+
+// Provider returns the first client in client's middleware chain that
+// implements T, following Unwrap methods. It reaches optional provider
+// interfaces such as ModelLister through the wrappers NewClient adds.
+func Provider[T any](client Client) (T, bool) {
+	for {
+		if found, ok := client.(T); ok {
+			return found, true
+		}
+		wrapper, ok := client.(interface{ Unwrap() Client })
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		client = wrapper.Unwrap()
+	}
+}
+
+// StreamFallbackMiddleware applies WithStreamFallback.
+func StreamFallbackMiddleware() Middleware {
+	return WithStreamFallback
+}
+
+// StreamResumeMiddleware applies WithStreamResume with attempts.
+func StreamResumeMiddleware(attempts int) Middleware {
+	return func(client Client) Client {
+		return WithStreamResume(client, attempts)
+	}
+}
+
+// TimeoutMiddleware applies WithTimeout with policy.
+func TimeoutMiddleware(policy TimeoutPolicy) Middleware {
+	return func(client Client) Client {
+		return WithTimeout(client, policy)
+	}
+}
+
+// RateLimitMiddleware applies WithRateLimit with limit.
+func RateLimitMiddleware(limit RateLimit) Middleware {
+	return func(client Client) Client {
+		return WithRateLimit(client, limit)
+	}
+}
+
+// LoggingMiddleware applies WithLogging with logger and redact.
+func LoggingMiddleware(logger *slog.Logger, redact func(string) string) Middleware {
+	return func(client Client) Client {
+		return WithLogging(client, logger, redact)
+	}
+}
+
+// PollMiddleware applies WithPolling.
+func PollMiddleware() Middleware {
+	return WithPolling
+}
+
+// BufferingMiddleware applies DetectBuffering with threshold and warn.
+func BufferingMiddleware(threshold time.Duration, warn func(silence time.Duration)) Middleware {
+	return func(client Client) Client {
+		return DetectBuffering(client, threshold, warn)
+	}
+}
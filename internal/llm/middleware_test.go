@@ -0,0 +1,70 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// tagClient appends its tag to the request's last message on the way in
+// and to the response on the way out.
+type tagClient struct {
+	client Client
+	tag    string
+}
+
+func (c *tagClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	req.Messages = append(req.Messages, Message{Role: "user", Content: c.tag})
+	resp, err := c.client.Chat(ctx, req)
+	resp.Content += c.tag
+	return resp, err
+}
+
+func (c *tagClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	return c.Chat(ctx, req)
+}
+
+type echoClient struct{}
+
+func (echoClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	var tags []string
+	for _, message := range req.Messages {
+		tags = append(tags, message.Content)
+	}
+	return ChatResponse{Content: strings.Join(tags, "") + "|"}, nil
+}
+
+func (c echoClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	return c.Chat(ctx, req)
+}
+
+func tag(name string) Middleware {
+	return func(client Client) Client {
+		return &tagClient{client: client, tag: name}
+	}
+}
+
+func TestChain(t *testing.T) {
+	client := Chain(tag("a"), nil, tag("b"))(echoClient{})
+	resp, err := client.Chat(context.Background(), ChatRequest{})
+	if err != nil || resp.Content != "ab|ba" {
+		t.Fatalf("unexpected order: %q, %v", resp.Content, err)
+	}
+	if client := Chain()(echoClient{}); client != (echoClient{}) {
+		t.Fatalf("empty chain should return the client")
+	}
+}
+
+func TestNewClientMiddleware(t *testing.T) {
+	Register("test-echo", func(cfg Config) (Client, error) {
+		return echoClient{}, nil
+	})
+	client, err := NewClient("test-echo", Config{Middleware: []Middleware{tag("a"), tag("b")}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	resp, _ := client.Chat(context.Background(), ChatRequest{})
+	if resp.Content != "ab|ba" {
+		t.Fatalf("unexpected response: %q", resp.Content)
+	}
+}
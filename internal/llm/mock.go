@@ -0,0 +1,153 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	defaultMockModel    = "mock"
+	defaultMockResponse = "mock response to: {{.Prompt}}"
+)
+
+type MockConfig struct {
+	Model string
+	// Response is a text/template for the answer to requests no fixture
+	// matches. It sees .Prompt (the last user message), .System and
+	// .Model.
+	Response string
+	// Fixtures is a directory of *.json files, each holding a "match"
+	// regular expression tested against the last user message and the
+	// "response" template used when it matches. Files are tried in name
+	// order; an empty match matches every request.
+	Fixtures string
+}
+
+// MockClient answers requests offline from templates, so commands,
+// prompts and output formats can be exercised without a provider.
+type MockClient struct {
+	model    string
+	fallback *template.Template
+	fixtures []mockFixture
+}
+
+type mockFixture struct {
+	name     string
+	match    *regexp.Regexp
+	response *template.Template
+}
+
+// mockData is what response templates see.
+type mockData struct {
+	Prompt string
+	System string
+	Model  string
+}
+
+func NewMockClient(cfg MockConfig) (*MockClient, error) {
+	fallback, err := template.New("response").Parse(firstNonEmptyString(cfg.Response, defaultMockResponse))
+	if err != nil {
+		return nil, fmt.Errorf("parse mock response: %w", err)
+	}
+	client := &MockClient{
+		model:    firstNonEmptyString(strings.TrimSpace(cfg.Model), defaultMockModel),
+		fallback: fallback,
+	}
+	if dir := strings.TrimSpace(cfg.Fixtures); dir != "" {
+		if client.fixtures, err = loadMockFixtures(dir); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+func loadMockFixtures(dir string) ([]mockFixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list mock fixtures: %w", err)
+	}
+	if len(paths) == 0 {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("read mock fixtures: %w", err)
+		}
+	}
+	sort.Strings(paths)
+	fixtures := make([]mockFixture, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read mock fixture: %w", err)
+		}
+		var raw struct {
+			Match    string `json:"match"`
+			Response string `json:"response"`
+		}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("decode mock fixture %s: %w", path, err)
+		}
+		fixture := mockFixture{name: filepath.Base(path)}
+		if fixture.match, err = regexp.Compile(raw.Match); err != nil {
+			return nil, fmt.Errorf("mock fixture %s: %w", fixture.name, err)
+		}
+		if fixture.response, err = template.New(fixture.name).Parse(raw.Response); err != nil {
+			return nil, fmt.Errorf("mock fixture %s: %w", fixture.name, err)
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+func (c *MockClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return ChatResponse{}, err
+	}
+	data := mockData{Model: firstNonEmptyString(strings.TrimSpace(req.Model), c.model)}
+	for _, message := range req.Messages {
+		switch message.Role {
+		case "system":
+			data.System = message.Content
+		case "user":
+			data.Prompt = message.Content
+		}
+	}
+	response := c.fallback
+	for _, fixture := range c.fixtures {
+		if fixture.match.MatchString(data.Prompt) {
+			response = fixture.response
+			break
+		}
+	}
+	var buf bytes.Buffer
+	if err := response.Execute(&buf, data); err != nil {
+		return ChatResponse{}, fmt.Errorf("mock response: %w", err)
+	}
+	content := buf.String()
+	prompt := estimateTokens(ChatRequest{Messages: req.Messages})
+	completion := estimateTokens(ChatRequest{Messages: []Message{{Content: content}}})
+	return ChatResponse{
+		Content:      content,
+		Model:        data.Model,
+		FinishReason: "stop",
+		Usage:        Usage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion},
+	}, nil
+}
+
+// ChatStream passes the response to handle word by word.
+func (c *MockClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	resp, err := c.Chat(ctx, req)
+	if err != nil || handle == nil {
+		return resp, err
+	}
+	if err := streamWords(resp.Content, handle); err != nil {
+		return ChatResponse{}, err
+	}
+	return resp, nil
+}
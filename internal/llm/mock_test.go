@@ -0,0 +1,70 @@
+package llm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMockClientTemplate(t *testing.T) {
+	client, err := NewClient("mock", Config{Options: map[string]string{"response": "{{.Model}}: {{.Prompt | printf \"%q\"}}"}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	var deltas []string
+	resp, err := client.ChatStream(context.Background(), ChatRequest{
+		Messages: []Message{{Role: "system", Content: "be brief"}, {Role: "user", Content: "hello world"}},
+	}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chat stream: %v", err)
+	}
+	if resp.Content != `mock: "hello world"` || resp.FinishReason != "stop" || resp.Usage.TotalTokens == 0 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if len(deltas) != 3 || strings.Join(deltas, "") != resp.Content {
+		t.Fatalf("unexpected deltas: %q", deltas)
+	}
+}
+
+func TestMockClientFixtures(t *testing.T) {
+	dir := t.TempDir()
+	fixtures := map[string]string{
+		"10-greeting.json": `{"match": "(?i)^hello", "response": "Translation: 你好 ({{.System}})"}`,
+		"20-default.json":  `{"match": "", "response": "no fixture for {{.Prompt}}"}`,
+	}
+	for name, content := range fixtures {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+	client, err := NewMockClient(MockConfig{Fixtures: dir})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	for prompt, want := range map[string]string{
+		"Hello there": "Translation: 你好 (translate)",
+		"goodbye":     "no fixture for goodbye",
+	} {
+		resp, err := client.Chat(context.Background(), ChatRequest{
+			Messages: []Message{{Role: "system", Content: "translate"}, {Role: "user", Content: prompt}},
+		})
+		if err != nil || resp.Content != want {
+			t.Fatalf("prompt %q: unexpected response %q, %v", prompt, resp.Content, err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "30-bad.json"), []byte(`{"match": "("}`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if _, err := NewMockClient(MockConfig{Fixtures: dir}); err == nil || !strings.Contains(err.Error(), "30-bad.json") {
+		t.Fatalf("expected fixture error, got %v", err)
+	}
+	if _, err := NewMockClient(MockConfig{Fixtures: filepath.Join(dir, "missing")}); err == nil {
+		t.Fatalf("expected missing directory error")
+	}
+}
@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ModelLister is implemented by clients that can list the models available
+// to the configured account.
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]string, error)
+}
+
+// ListModels returns the sorted model IDs offered by client's provider.
+func ListModels(ctx context.Context, client Client) ([]string, error) {
+	lister, ok := Provider[ModelLister](client)
+	if !ok {
+		return nil, fmt.Errorf("provider does not support listing models")
+	}
+	models, err := lister.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(models)
+	return models, nil
+}
+
+func (c *OpenAIClient) ListModels(ctx context.Context) ([]string, error) {
+	if c.provider == "azure-openai" {
+		return nil, fmt.Errorf("azure-openai does not support listing models; set llm.azure.deployment instead")
+	}
+	endpoint := strings.TrimSuffix(c.endpoint, "/chat/completions") + "/models"
+	var resp struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	err := getJSON(ctx, c.httpClient, endpoint, map[string]string{c.authHeader: c.authValue}, &resp, func(body io.Reader, status int) error {
+		return readOpenAIError(c.provider, body, status)
+	})
+	if err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(resp.Data))
+	for _, model := range resp.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
+
+func (c *OpenRouterClient) ListModels(ctx context.Context) ([]string, error) {
+	return c.client.ListModels(ctx)
+}
+
+func (c *AnthropicClient) ListModels(ctx context.Context) ([]string, error) {
+	endpoint := strings.TrimSuffix(buildAnthropicEndpoint(c.baseURL), "/messages") + "/models?limit=1000"
+	var resp struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	headers := map[string]string{"x-api-key": c.token, "anthropic-version": c.version}
+	if err := getJSON(ctx, c.httpClient, endpoint, headers, &resp, readAnthropicError); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(resp.Data))
+	for _, model := range resp.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
+
+// ListModels returns the Gemini API models that support generateContent.
+// Vertex AI publisher models are not listed.
+func (c *GeminiClient) ListModels(ctx context.Context) ([]string, error) {
+	if c.vertex != nil {
+		return nil, fmt.Errorf("listing models is not supported in vertex ai mode")
+	}
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	apiPath := strings.TrimSuffix(u.Path, "/")
+	if !strings.HasSuffix(apiPath, "/v1") && !strings.HasSuffix(apiPath, "/v1beta") {
+		apiPath = path.Join(apiPath, "/v1beta")
+	}
+	u.Path = path.Join(apiPath, "models")
+	u.RawQuery = url.Values{"key": {c.token}, "pageSize": {"1000"}}.Encode()
+	var resp struct {
+		Models []struct {
+			Name    string   `json:"name"`
+			Methods []string `json:"supportedGenerationMethods"`
+		} `json:"models"`
+	}
+	if err := getJSON(ctx, c.httpClient, u.String(), nil, &resp, readGeminiError); err != nil {
+		return nil, err
+	}
+	var models []string
+	for _, model := range resp.Models {
+		for _, method := range model.Methods {
+			if method == "generateContent" {
+				models = append(models, strings.TrimPrefix(model.Name, "models/"))
+				break
+			}
+		}
+	}
+	return models, nil
+}
+
+func getJSON(ctx context.Context, httpClient *http.Client, endpoint string, headers map[string]string, out any, readError func(io.Reader, int) error) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	for key, value := range headers {
+		httpReq.Header.Set(key, value)
+	}
+	httpResp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("list models: %w", err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusMultipleChoices {
+		return readError(httpResp.Body, httpResp.StatusCode)
+	}
+	if err := json.NewDecoder(httpResp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenAIListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/models" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Fatalf("missing auth header")
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"gpt-4o-mini"},{"id":"gpt-4o"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewOpenAIClient(OpenAIConfig{BaseURL: server.URL + "/v1", Token: "token", Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	models, err := ListModels(context.Background(), client)
+	if err != nil {
+		t.Fatalf("list models: %v", err)
+	}
+	if strings.Join(models, ",") != "gpt-4o,gpt-4o-mini" {
+		t.Fatalf("unexpected models: %v", models)
+	}
+	wrapped := WithStreamResume(WithTimeout(client, TimeoutPolicy{Timeout: time.Minute}), 1)
+	if models, err := ListModels(context.Background(), wrapped); err != nil || len(models) != 2 {
+		t.Fatalf("list models through middleware: %v, %v", models, err)
+	}
+}
+
+func TestGeminiListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1beta/models" || r.URL.Query().Get("key") != "token" {
+			t.Fatalf("unexpected request: %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"models":[
+			{"name":"models/gemini-1.5-pro","supportedGenerationMethods":["generateContent","countTokens"]},
+			{"name":"models/text-embedding-004","supportedGenerationMethods":["embedContent"]}
+		]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewGeminiClient(GeminiConfig{BaseURL: server.URL, Token: "token", Model: "gemini-1.5-pro"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	models, err := ListModels(context.Background(), client)
+	if err != nil {
+		t.Fatalf("list models: %v", err)
+	}
+	if strings.Join(models, ",") != "gemini-1.5-pro" {
+		t.Fatalf("unexpected models: %v", models)
+	}
+}
+
+func TestListModelsUnsupported(t *testing.T) {
+	client, err := NewBedrockClient(BedrockConfig{Region: "us-east-1", Model: "meta.llama3", Credentials: AWSCredentials{AccessKeyID: "a", SecretAccessKey: "b"}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if _, err := ListModels(context.Background(), client); err == nil {
+		t.Fatalf("expected unsupported error")
+	}
+}
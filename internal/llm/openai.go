@@ -20,9 +20,11 @@ type OpenAIConfig struct {
 }
 
 type OpenAIClient struct {
-	baseURL    string
-	token      string
+	endpoint   string
+	authHeader string
+	authValue  string
 	model      string
+	provider   string
 	httpClient *http.Client
 }
 
@@ -44,64 +46,71 @@ func NewOpenAIClient(cfg OpenAIConfig) (*OpenAIClient, error) {
 		client = &http.Client{}
 	}
 	return &OpenAIClient{
-		baseURL:    baseURL,
-		token:      token,
+		endpoint:   buildChatEndpoint(baseURL),
+		authHeader: "Authorization",
+		authValue:  "Bearer " + token,
 		model:      model,
+		provider:   "openai",
 		httpClient: client,
 	}, nil
 }
 
 func (c *OpenAIClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
-	payload := openAIChatRequest{
-		Model:    c.resolveModel(req.Model),
-		Messages: req.Messages,
-	}
+	payload := c.newRequest(req, false)
 	var resp openAIChatResponse
 	if err := c.do(ctx, payload, &resp); err != nil {
 		return ChatResponse{}, err
 	}
 	if len(resp.Choices) == 0 {
-		return ChatResponse{}, errors.New("openai response has no choices")
+		return ChatResponse{}, fmt.Errorf("%s response has no choices", c.provider)
+	}
+	reasoning := resp.Choices[0].Message.ReasoningContent
+	if reasoning != "" && req.ReasoningHandler != nil {
+		if err := req.ReasoningHandler(reasoning); err != nil {
+			return ChatResponse{}, err
+		}
 	}
 	return ChatResponse{
 		Content:      resp.Choices[0].Message.Content,
+		Reasoning:    reasoning,
+		ToolCalls:    fromOpenAIToolCalls(resp.Choices[0].Message.ToolCalls),
 		Model:        resp.Model,
 		FinishReason: resp.Choices[0].FinishReason,
+		Usage:        resp.Usage.toUsage(),
 	}, nil
 }
 
 func (c *OpenAIClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
-	payload := openAIChatRequest{
-		Model:    c.resolveModel(req.Model),
-		Messages: req.Messages,
-		Stream:   true,
-	}
+	payload := c.newRequest(req, true)
 	requestBody, err := json.Marshal(payload)
 	if err != nil {
 		return ChatResponse{}, fmt.Errorf("marshal request: %w", err)
 	}
-	endpoint := buildChatEndpoint(c.baseURL)
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(requestBody))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(requestBody))
 	if err != nil {
 		return ChatResponse{}, fmt.Errorf("create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	httpReq.Header.Set(c.authHeader, c.authValue)
 	httpReq.Header.Set("Accept", "text/event-stream")
 
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return ChatResponse{}, fmt.Errorf("openai request: %w", err)
+		return ChatResponse{}, fmt.Errorf("%s request: %w", c.provider, err)
 	}
 	defer httpResp.Body.Close()
 
 	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusMultipleChoices {
-		return ChatResponse{}, readOpenAIError(httpResp.Body, httpResp.StatusCode)
+		return ChatResponse{}, readOpenAIError(c.provider, httpResp.Body, httpResp.StatusCode)
 	}
 
 	var content strings.Builder
+	var reasoning strings.Builder
+	var toolCalls openAIToolCallStream
 	var finishReason string
 	var model string
+	var usage Usage
+	var done bool
 
 	scanner := bufio.NewScanner(httpResp.Body)
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
@@ -112,24 +121,39 @@ func (c *OpenAIClient) ChatStream(ctx context.Context, req ChatRequest, handle S
 		}
 		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
 		if data == "[DONE]" {
+			done = true
 			break
 		}
+		// Skip chunks that do not parse, such as proxy keep-alives,
+		// instead of failing a stream that is otherwise healthy.
 		var chunk openAIChatResponse
 		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
-			return ChatResponse{}, fmt.Errorf("decode stream chunk: %w", err)
+			continue
 		}
 		if chunk.Error != nil {
-			return ChatResponse{}, fmt.Errorf("openai error: %s", chunk.Error.Message)
+			return ChatResponse{}, fmt.Errorf("%s error: %s", c.provider, chunk.Error.Message)
 		}
 		if chunk.Model != "" {
 			model = chunk.Model
 		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage.toUsage()
+		}
 		if len(chunk.Choices) == 0 {
 			continue
 		}
 		if chunk.Choices[0].FinishReason != "" {
 			finishReason = chunk.Choices[0].FinishReason
 		}
+		if thought := chunk.Choices[0].Delta.ReasoningContent; thought != "" {
+			reasoning.WriteString(thought)
+			if req.ReasoningHandler != nil {
+				if err := req.ReasoningHandler(thought); err != nil {
+					return ChatResponse{}, err
+				}
+			}
+		}
+		toolCalls.apply(chunk.Choices[0].Delta.ToolCalls)
 		delta := chunk.Choices[0].Delta.Content
 		if delta == "" {
 			continue
@@ -142,15 +166,38 @@ func (c *OpenAIClient) ChatStream(ctx context.Context, req ChatRequest, handle S
 		}
 	}
 	if err := scanner.Err(); err != nil {
-		return ChatResponse{}, fmt.Errorf("read stream: %w", err)
+		return ChatResponse{}, fmt.Errorf("%w: %v", ErrStreamInterrupted, err)
+	}
+	if !done && finishReason == "" {
+		return ChatResponse{}, ErrStreamInterrupted
 	}
 	return ChatResponse{
 		Content:      content.String(),
+		Reasoning:    reasoning.String(),
+		ToolCalls:    toolCalls.calls(),
 		Model:        model,
 		FinishReason: finishReason,
+		Usage:        usage,
 	}, nil
 }
 
+const defaultDeepSeekBaseURL = "https://api.deepseek.com"
+
+// NewDeepSeekClient returns an OpenAI-compatible client for DeepSeek,
+// defaulting the base URL to the public API. Reasoning models return
+// their reasoning in ChatResponse.Reasoning.
+func NewDeepSeekClient(cfg OpenAIConfig) (*OpenAIClient, error) {
+	if strings.TrimSpace(cfg.BaseURL) == "" {
+		cfg.BaseURL = defaultDeepSeekBaseURL
+	}
+	client, err := NewOpenAIClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client.provider = "deepseek"
+	return client, nil
+}
+
 func (c *OpenAIClient) resolveModel(override string) string {
 	if strings.TrimSpace(override) == "" {
 		return c.model
@@ -158,33 +205,140 @@ func (c *OpenAIClient) resolveModel(override string) string {
 	return override
 }
 
+func (c *OpenAIClient) newRequest(req ChatRequest, stream bool) openAIChatRequest {
+	body := openAIChatRequest{
+		Model:       c.resolveModel(req.Model),
+		Messages:    toOpenAIMessages(req.Messages),
+		Stream:      stream,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		MaxTokens:   req.MaxTokens,
+		Stop:        req.StopSequences,
+		Tools:       toOpenAITools(req.Tools),
+	}
+	if stream {
+		// Streams only report token usage, in a last chunk without
+		// choices, when asked to.
+		body.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+	}
+	return body
+}
+
+func toOpenAIMessages(messages []Message) []openAIRequestMessage {
+	out := make([]openAIRequestMessage, 0, len(messages))
+	for _, message := range messages {
+		converted := openAIRequestMessage{
+			Role:       message.Role,
+			Content:    message.Content,
+			ToolCallID: message.ToolCallID,
+		}
+		for _, call := range message.ToolCalls {
+			toolCall := openAIToolCall{ID: call.ID, Type: "function"}
+			toolCall.Function.Name = call.Name
+			toolCall.Function.Arguments = string(toolArguments(call.Arguments))
+			converted.ToolCalls = append(converted.ToolCalls, toolCall)
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+func toOpenAITools(tools []Tool) []openAITool {
+	if len(tools) == 0 {
+		return nil
+	}
+	out := make([]openAITool, 0, len(tools))
+	for _, tool := range tools {
+		converted := openAITool{Type: "function"}
+		converted.Function.Name = tool.Name
+		converted.Function.Description = tool.Description
+		converted.Function.Parameters = toolParameters(tool.Parameters)
+		out = append(out, converted)
+	}
+	return out
+}
+
+func fromOpenAIToolCalls(calls []openAIToolCall) []ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	out := make([]ToolCall, 0, len(calls))
+	for _, call := range calls {
+		out = append(out, ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: toolArguments(json.RawMessage(call.Function.Arguments)),
+		})
+	}
+	return out
+}
+
+// openAIToolCallStream joins streamed tool call deltas by index: the first
+// delta of a call carries its ID and name, later ones argument fragments.
+type openAIToolCallStream struct {
+	order   []int
+	pending map[int]*openAIToolCall
+}
+
+func (s *openAIToolCallStream) apply(deltas []openAIToolCall) {
+	for _, delta := range deltas {
+		index := len(s.order)
+		if delta.Index != nil {
+			index = *delta.Index
+		}
+		if s.pending == nil {
+			s.pending = make(map[int]*openAIToolCall)
+		}
+		call, ok := s.pending[index]
+		if !ok {
+			call = &openAIToolCall{}
+			s.pending[index] = call
+			s.order = append(s.order, index)
+		}
+		if delta.ID != "" {
+			call.ID = delta.ID
+		}
+		if delta.Function.Name != "" {
+			call.Function.Name = delta.Function.Name
+		}
+		call.Function.Arguments += delta.Function.Arguments
+	}
+}
+
+func (s *openAIToolCallStream) calls() []ToolCall {
+	calls := make([]openAIToolCall, 0, len(s.order))
+	for _, index := range s.order {
+		calls = append(calls, *s.pending[index])
+	}
+	return fromOpenAIToolCalls(calls)
+}
+
 func (c *OpenAIClient) do(ctx context.Context, payload openAIChatRequest, out *openAIChatResponse) error {
 	requestBody, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal request: %w", err)
 	}
-	endpoint := buildChatEndpoint(c.baseURL)
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(requestBody))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(requestBody))
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	httpReq.Header.Set(c.authHeader, c.authValue)
 
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("openai request: %w", err)
+		return fmt.Errorf("%s request: %w", c.provider, err)
 	}
 	defer httpResp.Body.Close()
 
 	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusMultipleChoices {
-		return readOpenAIError(httpResp.Body, httpResp.StatusCode)
+		return readOpenAIError(c.provider, httpResp.Body, httpResp.StatusCode)
 	}
 	if err := json.NewDecoder(httpResp.Body).Decode(out); err != nil {
 		return fmt.Errorf("decode response: %w", err)
 	}
 	if out.Error != nil {
-		return fmt.Errorf("openai error: %s", out.Error.Message)
+		return fmt.Errorf("%s error: %s", c.provider, out.Error.Message)
 	}
 	return nil
 }
@@ -197,31 +351,97 @@ func buildChatEndpoint(baseURL string) string {
 	return base + "/v1/chat/completions"
 }
 
-func readOpenAIError(body io.Reader, status int) error {
+func readOpenAIError(provider string, body io.Reader, status int) error {
 	var resp openAIChatResponse
 	_ = json.NewDecoder(body).Decode(&resp)
-	if resp.Error != nil && resp.Error.Message != "" {
-		return fmt.Errorf("openai request failed: %s (status %d)", resp.Error.Message, status)
+	statusErr := &StatusError{Provider: provider, StatusCode: status}
+	if resp.Error != nil {
+		statusErr.Message = resp.Error.Message
 	}
-	return fmt.Errorf("openai request failed with status %d", status)
+	return statusErr
 }
 
 type openAIChatRequest struct {
-	Model    string    `json:"model"`
-	Messages []Message `json:"messages"`
-	Stream   bool      `json:"stream,omitempty"`
+	Model         string                 `json:"model"`
+	Messages      []openAIRequestMessage `json:"messages"`
+	Stream        bool                   `json:"stream,omitempty"`
+	StreamOptions *openAIStreamOptions   `json:"stream_options,omitempty"`
+	Temperature   *float64               `json:"temperature,omitempty"`
+	TopP          *float64               `json:"top_p,omitempty"`
+	MaxTokens     int                    `json:"max_tokens,omitempty"`
+	Stop          []string               `json:"stop,omitempty"`
+	Tools         []openAITool           `json:"tools,omitempty"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIRequestMessage struct {
+	Role       string           `json:"role"`
+	Content    string           `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAITool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description,omitempty"`
+		Parameters  json.RawMessage `json:"parameters"`
+	} `json:"function"`
+}
+
+// openAIToolCall is a tool call in a message or, with Index set, a
+// fragment of one in a stream delta. Arguments is a JSON-encoded string.
+type openAIToolCall struct {
+	Index    *int   `json:"index,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
 }
 
 type openAIChatResponse struct {
 	ID      string `json:"id"`
 	Model   string `json:"model"`
 	Choices []struct {
-		Message      Message `json:"message"`
-		Delta        Message `json:"delta"`
-		FinishReason string  `json:"finish_reason"`
+		Message      openAIMessage `json:"message"`
+		Delta        openAIMessage `json:"delta"`
+		FinishReason string        `json:"finish_reason"`
 	} `json:"choices"`
+	Usage *openAIUsage `json:"usage,omitempty"`
 	Error *struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
 	} `json:"error"`
 }
+
+// openAIMessage is a response message. reasoning_content is returned by
+// reasoning models such as deepseek-reasoner.
+type openAIMessage struct {
+	Role             string           `json:"role"`
+	Content          string           `json:"content"`
+	ReasoningContent string           `json:"reasoning_content,omitempty"`
+	ToolCalls        []openAIToolCall `json:"tool_calls,omitempty"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func (u *openAIUsage) toUsage() Usage {
+	if u == nil {
+		return Usage{}
+	}
+	return Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+}
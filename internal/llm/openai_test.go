@@ -24,12 +24,12 @@ func TestOpenAIChat(t *testing.T) {
 		resp := openAIChatResponse{
 			Model: "gpt-test",
 			Choices: []struct {
-				Message      Message `json:"message"`
-				Delta        Message `json:"delta"`
-				FinishReason string  `json:"finish_reason"`
+				Message      openAIMessage `json:"message"`
+				Delta        openAIMessage `json:"delta"`
+				FinishReason string        `json:"finish_reason"`
 			}{
 				{
-					Message: Message{
+					Message: openAIMessage{
 						Role:    "assistant",
 						Content: "hello",
 					},
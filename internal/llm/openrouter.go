@@ -0,0 +1,110 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const defaultOpenRouterBaseURL = "https://openrouter.ai/api/v1"
+
+type OpenRouterConfig struct {
+	BaseURL string
+	Token   string
+	// Models is the ordered fallback list. A request model, when set, is
+	// tried first.
+	Models     []string
+	HTTPClient *http.Client
+}
+
+// OpenRouterClient sends OpenAI-compatible requests to OpenRouter and
+// moves on to the next model when one is rate limited or failing.
+type OpenRouterClient struct {
+	client *OpenAIClient
+	models []string
+}
+
+func NewOpenRouterClient(cfg OpenRouterConfig) (*OpenRouterClient, error) {
+	var models []string
+	seen := make(map[string]bool)
+	for _, model := range cfg.Models {
+		if model = strings.TrimSpace(model); model != "" && !seen[model] {
+			seen[model] = true
+			models = append(models, model)
+		}
+	}
+	if len(models) == 0 {
+		return nil, errors.New("openrouter model is required")
+	}
+	baseURL := strings.TrimSpace(cfg.BaseURL)
+	if baseURL == "" {
+		baseURL = defaultOpenRouterBaseURL
+	}
+	client, err := NewOpenAIClient(OpenAIConfig{
+		BaseURL:    baseURL,
+		Token:      cfg.Token,
+		Model:      models[0],
+		HTTPClient: cfg.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	client.provider = "openrouter"
+	return &OpenRouterClient{client: client, models: models}, nil
+}
+
+func (c *OpenRouterClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return c.fallback(req, func(req ChatRequest) (ChatResponse, error) {
+		return c.client.Chat(ctx, req)
+	})
+}
+
+// ChatStream falls back only on errors returned before the response body
+// is read, so no deltas from a failed model reach handle.
+func (c *OpenRouterClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	return c.fallback(req, func(req ChatRequest) (ChatResponse, error) {
+		return c.client.ChatStream(ctx, req, handle)
+	})
+}
+
+func (c *OpenRouterClient) fallback(req ChatRequest, send func(ChatRequest) (ChatResponse, error)) (ChatResponse, error) {
+	var err error
+	for _, model := range c.candidates(req.Model) {
+		req.Model = model
+		var resp ChatResponse
+		resp, err = send(req)
+		if err == nil {
+			if resp.Model == "" {
+				resp.Model = model
+			}
+			return resp, nil
+		}
+		if !isFallbackError(err) {
+			return ChatResponse{}, err
+		}
+	}
+	return ChatResponse{}, err
+}
+
+func (c *OpenRouterClient) candidates(requested string) []string {
+	requested = strings.TrimSpace(requested)
+	if requested == "" {
+		return c.models
+	}
+	models := []string{requested}
+	for _, model := range c.models {
+		if model != requested {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+func isFallbackError(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenRouterFallsBack(t *testing.T) {
+	var tried []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		tried = append(tried, req.Model)
+		switch req.Model {
+		case "a/first":
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error":{"message":"rate limited"}}`)
+		case "b/second":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewOpenRouterClient(OpenRouterConfig{
+		BaseURL: server.URL,
+		Token:   "token",
+		Models:  []string{"a/first", "b/second", "c/third"},
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	resp, err := client.Chat(context.Background(), ChatRequest{Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if resp.Model != "c/third" || resp.Content != "ok" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if strings.Join(tried, ",") != "a/first,b/second,c/third" {
+		t.Fatalf("unexpected attempts: %v", tried)
+	}
+}
+
+func TestOpenRouterStopsOnClientError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"bad key"}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewOpenRouterClient(OpenRouterConfig{
+		BaseURL: server.URL,
+		Token:   "token",
+		Models:  []string{"a", "b"},
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	_, err = client.ChatStream(context.Background(), ChatRequest{Model: "b"}, nil)
+	if err == nil || err.Error() != "openrouter request failed: bad key (status 401)" || calls != 1 {
+		t.Fatalf("unexpected result: %v (calls %d)", err, calls)
+	}
+}
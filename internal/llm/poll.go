@@ -0,0 +1,130 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBufferingThreshold is how long a stream may stay silent
+	// before arriving all at once, or timing out, counts as buffered.
+	DefaultBufferingThreshold = 10 * time.Second
+
+	// bufferedBurst is the longest a buffered stream takes to deliver its
+	// deltas once they arrive, and bufferedDeltas the fewest deltas
+	// needed to tell a burst from a short answer.
+	bufferedBurst  = 250 * time.Millisecond
+	bufferedDeltas = 5
+)
+
+type pollClient struct {
+	client Client
+}
+
+// WithPolling turns every stream into a non-streaming request whose
+// answer is then passed to the handler word by word. It is for gateways
+// that buffer server-sent events until the response is complete.
+func WithPolling(client Client) Client {
+	return &pollClient{client: client}
+}
+
+func (c *pollClient) Unwrap() Client { return c.client }
+
+func (c *pollClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return c.client.Chat(ctx, req)
+}
+
+func (c *pollClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	reasoning := req.ReasoningHandler
+	req.ReasoningHandler = nil
+	resp, err := c.client.Chat(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	if reasoning != nil && resp.Reasoning != "" {
+		if err := streamWords(resp.Reasoning, reasoning); err != nil {
+			return ChatResponse{}, err
+		}
+	}
+	if handle != nil {
+		if err := streamWords(resp.Content, handle); err != nil {
+			return ChatResponse{}, err
+		}
+	}
+	return resp, nil
+}
+
+// streamWords passes text to handle one word at a time, keeping the
+// whitespace before each word.
+func streamWords(text string, handle StreamHandler) error {
+	for text != "" {
+		end := strings.IndexAny(text[1:], " \n")
+		chunk := text
+		if end >= 0 {
+			chunk = text[:end+1]
+		}
+		text = text[len(chunk):]
+		if err := handle(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type bufferingDetector struct {
+	client    Client
+	threshold time.Duration
+	warn      func(silence time.Duration)
+	now       func() time.Time
+}
+
+// DetectBuffering calls warn when a stream looks buffered by a proxy or
+// gateway: it sent nothing for at least threshold and then delivered its
+// whole answer at once, or it timed out without sending anything. warn
+// receives how long the stream was silent.
+func DetectBuffering(client Client, threshold time.Duration, warn func(silence time.Duration)) Client {
+	if threshold <= 0 || warn == nil {
+		return client
+	}
+	return &bufferingDetector{client: client, threshold: threshold, warn: warn, now: time.Now}
+}
+
+func (c *bufferingDetector) Unwrap() Client { return c.client }
+
+func (c *bufferingDetector) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return c.client.Chat(ctx, req)
+}
+
+func (c *bufferingDetector) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	start := c.now()
+	var first, last time.Time
+	deltas := 0
+	observe := func(handle StreamHandler) StreamHandler {
+		return func(delta string) error {
+			last = c.now()
+			if deltas == 0 {
+				first = last
+			}
+			deltas++
+			if handle == nil {
+				return nil
+			}
+			return handle(delta)
+		}
+	}
+	if req.ReasoningHandler != nil {
+		req.ReasoningHandler = observe(req.ReasoningHandler)
+	}
+	resp, err := c.client.ChatStream(ctx, req, observe(handle))
+	switch {
+	case err == nil && deltas >= bufferedDeltas && first.Sub(start) >= c.threshold && last.Sub(first) <= bufferedBurst:
+		c.warn(first.Sub(start))
+	case errors.Is(err, ErrTimeout) && errors.Is(err, ErrStreamInterrupted) && deltas == 0:
+		if silence := c.now().Sub(start); silence >= c.threshold {
+			c.warn(silence)
+		}
+	}
+	return resp, err
+}
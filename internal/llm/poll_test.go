@@ -0,0 +1,98 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// pollStub answers Chat and fails if asked to stream.
+type pollStub struct{}
+
+func (pollStub) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return ChatResponse{Content: "one two\nthree", Reasoning: "think it"}, nil
+}
+
+func (pollStub) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	return ChatResponse{}, fmt.Errorf("unexpected stream")
+}
+
+func TestWithPolling(t *testing.T) {
+	var deltas, reasoning []string
+	req := ChatRequest{ReasoningHandler: func(delta string) error {
+		reasoning = append(reasoning, delta)
+		return nil
+	}}
+	resp, err := WithPolling(pollStub{}).ChatStream(context.Background(), req, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil || resp.Content != "one two\nthree" {
+		t.Fatalf("unexpected response: %+v, %v", resp, err)
+	}
+	if !reflect.DeepEqual(deltas, []string{"one", " two", "\nthree"}) {
+		t.Fatalf("unexpected deltas: %q", deltas)
+	}
+	if !reflect.DeepEqual(reasoning, []string{"think", " it"}) {
+		t.Fatalf("unexpected reasoning: %q", reasoning)
+	}
+}
+
+// clockStream sends deltas at the given offsets of a fake clock, then
+// returns err at end, if set.
+type clockStream struct {
+	clock   *time.Time
+	offsets []time.Duration
+	end     time.Duration
+	err     error
+}
+
+func (c *clockStream) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return ChatResponse{}, nil
+}
+
+func (c *clockStream) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	start := *c.clock
+	for _, offset := range c.offsets {
+		*c.clock = start.Add(offset)
+		if err := handle("x"); err != nil {
+			return ChatResponse{}, err
+		}
+	}
+	if c.end > 0 {
+		*c.clock = start.Add(c.end)
+	}
+	return ChatResponse{}, c.err
+}
+
+func TestDetectBuffering(t *testing.T) {
+	burst := []time.Duration{12 * time.Second, 12*time.Second + 10*time.Millisecond, 12*time.Second + 20*time.Millisecond,
+		12*time.Second + 30*time.Millisecond, 12*time.Second + 40*time.Millisecond}
+	steady := []time.Duration{12 * time.Second, 13 * time.Second, 14 * time.Second, 15 * time.Second, 16 * time.Second}
+	idle := fmt.Errorf("%w: %w: no data", ErrTimeout, ErrStreamInterrupted)
+	cases := []struct {
+		name    string
+		offsets []time.Duration
+		end     time.Duration
+		err     error
+		warned  time.Duration
+	}{
+		{name: "burst after silence", offsets: burst, warned: 12 * time.Second},
+		{name: "slow first token, then streaming", offsets: steady},
+		{name: "fast burst", offsets: []time.Duration{0, 0, 0, 0, 0}},
+		{name: "idle timeout", end: time.Minute, err: idle, warned: time.Minute},
+	}
+	for _, tc := range cases {
+		clock := time.Unix(0, 0)
+		stream := &clockStream{clock: &clock, offsets: tc.offsets, end: tc.end, err: tc.err}
+		var warned time.Duration
+		client := DetectBuffering(stream, DefaultBufferingThreshold, func(silence time.Duration) { warned = silence })
+		client.(*bufferingDetector).now = func() time.Time { return clock }
+		_, _ = client.ChatStream(context.Background(), ChatRequest{}, nil)
+		if warned != tc.warned {
+			t.Fatalf("%s: warned after %s, want %s", tc.name, warned, tc.warned)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// withProxy returns a copy of client, or of a default client, that sends
+// every request through the proxy at rawURL. http, https, socks5 and
+// socks5h proxies are supported; the URL may carry credentials.
+func withProxy(client *http.Client, rawURL string) (*http.Client, error) {
+	proxy, err := url.Parse(rawURL)
+	if err != nil || proxy.Host == "" {
+		// The parse error would repeat the URL and any password in it.
+		return nil, fmt.Errorf("invalid proxy url")
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme: %s (expected http, https, socks5 or socks5h)", proxy.Scheme)
+	}
+
+	wrapped := &http.Client{}
+	if client != nil {
+		*wrapped = *client
+	}
+	var transport *http.Transport
+	switch base := wrapped.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = base.Clone()
+	default:
+		return nil, fmt.Errorf("proxy requires an *http.Transport, got %T", base)
+	}
+	transport.Proxy = http.ProxyURL(proxy)
+	wrapped.Transport = transport
+	return wrapped, nil
+}
@@ -0,0 +1,154 @@
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// RateLimit caps the requests and tokens a client sends per minute. Zero
+// leaves either limit off.
+type RateLimit struct {
+	RPM int
+	TPM int
+}
+
+type rateLimitClient struct {
+	client   Client
+	requests *tokenBucket
+	tokens   *tokenBucket
+}
+
+// WithRateLimit delays requests of client so they stay within limit. The
+// token count of a request is estimated before it is sent, from the
+// prompt length and MaxTokens, and corrected once the response reports
+// its usage.
+func WithRateLimit(client Client, limit RateLimit) Client {
+	if limit.RPM <= 0 && limit.TPM <= 0 {
+		return client
+	}
+	return &rateLimitClient{
+		client:   client,
+		requests: newTokenBucket(limit.RPM),
+		tokens:   newTokenBucket(limit.TPM),
+	}
+}
+
+func (c *rateLimitClient) Unwrap() Client { return c.client }
+
+func (c *rateLimitClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	estimate, err := c.wait(ctx, req)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	resp, err := c.client.Chat(ctx, req)
+	c.settle(estimate, resp.Usage)
+	return resp, err
+}
+
+func (c *rateLimitClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	estimate, err := c.wait(ctx, req)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	resp, err := c.client.ChatStream(ctx, req, handle)
+	c.settle(estimate, resp.Usage)
+	return resp, err
+}
+
+func (c *rateLimitClient) wait(ctx context.Context, req ChatRequest) (int, error) {
+	if err := c.requests.take(ctx, 1); err != nil {
+		return 0, err
+	}
+	estimate := estimateTokens(req)
+	if err := c.tokens.take(ctx, estimate); err != nil {
+		return 0, err
+	}
+	return estimate, nil
+}
+
+// settle charges the difference between the reported and the estimated
+// token count, so later requests wait for tokens that were underestimated.
+func (c *rateLimitClient) settle(estimate int, usage Usage) {
+	if usage.TotalTokens > 0 {
+		c.tokens.charge(usage.TotalTokens - estimate)
+	}
+}
+
+// estimateTokens approximates the tokens a request uses at four
+// characters per token, plus the completion budget when one is set.
+func estimateTokens(req ChatRequest) int {
+	chars := 0
+	for _, message := range req.Messages {
+		chars += utf8.RuneCountInString(message.Content)
+	}
+	return (chars+3)/4 + req.MaxTokens
+}
+
+// tokenBucket refills at perMinute tokens per minute up to a burst of one
+// minute's worth. A nil bucket never waits.
+type tokenBucket struct {
+	mu        sync.Mutex
+	perMinute int
+	available float64
+	updated   time.Time
+	now       func() time.Time
+	sleep     func(ctx context.Context, d time.Duration) error
+}
+
+func newTokenBucket(perMinute int) *tokenBucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &tokenBucket{
+		perMinute: perMinute,
+		available: float64(perMinute),
+		updated:   time.Now(),
+		now:       time.Now,
+		sleep:     sleepContext,
+	}
+}
+
+// take waits until n tokens are available and removes them. A request
+// larger than the burst waits for a full bucket and leaves it in debt.
+func (b *tokenBucket) take(ctx context.Context, n int) error {
+	if b == nil {
+		return nil
+	}
+	for {
+		b.mu.Lock()
+		b.refill()
+		need := min(float64(n), float64(b.perMinute))
+		if b.available >= need {
+			b.available -= float64(n)
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((need - b.available) / float64(b.perMinute) * float64(time.Minute))
+		b.mu.Unlock()
+		if err := b.sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// charge removes n further tokens, or returns them when n is negative.
+func (b *tokenBucket) charge(n int) {
+	if b == nil || n == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	b.available = min(b.available-float64(n), float64(b.perMinute))
+}
+
+func (b *tokenBucket) refill() {
+	now := b.now()
+	elapsed := now.Sub(b.updated)
+	b.updated = now
+	if elapsed > 0 {
+		b.available = min(b.available+elapsed.Minutes()*float64(b.perMinute), float64(b.perMinute))
+	}
+}
@@ -0,0 +1,102 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock makes a bucket sleep by advancing time and records the delays.
+func fakeClock(b *tokenBucket) *[]time.Duration {
+	now := time.Unix(0, 0)
+	b.updated = now
+	b.now = func() time.Time { return now }
+	var delays []time.Duration
+	b.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		now = now.Add(d)
+		return ctx.Err()
+	}
+	return &delays
+}
+
+func TestTokenBucket(t *testing.T) {
+	bucket := newTokenBucket(60)
+	delays := fakeClock(bucket)
+	ctx := context.Background()
+	for i := 0; i < 60; i++ {
+		if err := bucket.take(ctx, 1); err != nil {
+			t.Fatalf("take: %v", err)
+		}
+	}
+	if len(*delays) != 0 {
+		t.Fatalf("burst should not wait: %v", *delays)
+	}
+	if err := bucket.take(ctx, 2); err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	if len(*delays) != 1 || (*delays)[0] != 2*time.Second {
+		t.Fatalf("unexpected delays: %v", *delays)
+	}
+
+	// A request over the burst waits for a full bucket and leaves debt.
+	if err := bucket.take(ctx, 90); err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	if got := (*delays)[1]; got != time.Minute {
+		t.Fatalf("unexpected delay: %v", got)
+	}
+	if bucket.available != -30 {
+		t.Fatalf("unexpected balance: %v", bucket.available)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := bucket.take(cancelled, 1); err == nil {
+		t.Fatalf("expected cancellation")
+	}
+}
+
+type usageClient struct {
+	usage Usage
+}
+
+func (c *usageClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return ChatResponse{Content: "ok", Usage: c.usage}, nil
+}
+
+func (c *usageClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	return c.Chat(ctx, req)
+}
+
+func TestRateLimitSettlesUsage(t *testing.T) {
+	if client := WithRateLimit(&usageClient{}, RateLimit{}); client == nil {
+		t.Fatalf("expected client")
+	} else if _, ok := client.(*rateLimitClient); ok {
+		t.Fatalf("zero limit should not wrap the client")
+	}
+
+	inner := &usageClient{usage: Usage{TotalTokens: 400}}
+	client := WithRateLimit(inner, RateLimit{RPM: 10, TPM: 1000}).(*rateLimitClient)
+	fakeClock(client.requests)
+	delays := fakeClock(client.tokens)
+	req := ChatRequest{Messages: []Message{{Role: "user", Content: strings.Repeat("a", 400)}}, MaxTokens: 100}
+	if estimateTokens(req) != 200 {
+		t.Fatalf("unexpected estimate: %d", estimateTokens(req))
+	}
+	if _, err := client.Chat(context.Background(), req); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if client.tokens.available != 600 {
+		t.Fatalf("usage not settled: %v", client.tokens.available)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := client.ChatStream(context.Background(), req, nil); err != nil {
+			t.Fatalf("chat stream: %v", err)
+		}
+	}
+	if len(*delays) != 1 || (*delays)[0] != 24*time.Second {
+		t.Fatalf("unexpected delays: %v", *delays)
+	}
+}
@@ -0,0 +1,271 @@
+package llm
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Config is the provider-independent client configuration passed to a
+// Factory. Options carries provider-specific settings, such as the Azure
+// deployment or the Bedrock region.
+type Config struct {
+	BaseURL string
+	Token   string
+	Model   string
+	Options map[string]string
+	// UserAgent, when set, is sent as the User-Agent header of every
+	// request the client makes, including credential exchanges.
+	UserAgent string
+	// Proxy, when set, is the URL of an http, https or socks5 proxy that
+	// every request goes through instead of the environment's proxy.
+	Proxy string
+	// Record, when set, is the path of a cassette file that every HTTP
+	// exchange is saved to. Replay, when set, is a cassette whose
+	// responses are returned instead of sending requests.
+	Record string
+	Replay string
+	// Retry retries transient failures of every request when Retries is
+	// positive.
+	Retry RetryPolicy
+	// Timeout bounds every request the client makes through its context.
+	Timeout TimeoutPolicy
+	// StreamReconnects is how often a broken stream is re-issued to
+	// continue from the text received so far.
+	StreamReconnects int
+	// Poll sends streams as non-streaming requests and replays the
+	// answer word by word, for gateways that buffer server-sent events.
+	Poll bool
+	// RateLimit delays requests to stay within the provider's limits;
+	// waiting for it does not count towards Timeout.
+	RateLimit RateLimit
+	// Middleware wraps the client, outermost first, around the rate
+	// limit and timeout.
+	Middleware []Middleware
+	HTTPClient *http.Client
+}
+
+// Factory builds a client for one provider type.
+type Factory func(cfg Config) (Client, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a provider available to NewClient under name. It panics
+// if name is empty or already registered, so conflicts surface at startup.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if name == "" || factory == nil {
+		panic("llm: Register requires a name and factory")
+	}
+	if _, exists := registry[name]; exists {
+		panic("llm: provider registered twice: " + name)
+	}
+	registry[name] = factory
+}
+
+// Providers returns the registered provider types in sorted order.
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewClient builds a client for the registered provider type.
+func NewClient(providerType string, cfg Config) (Client, error) {
+	registryMu.RLock()
+	factory, ok := registry[providerType]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported llm.type: %s (expected one of %s)",
+			providerType, strings.Join(Providers(), ", "))
+	}
+	if cfg.Record != "" && cfg.Replay != "" {
+		return nil, fmt.Errorf("record and replay cannot be combined")
+	}
+	if cfg.Proxy != "" && cfg.Replay == "" {
+		client, err := withProxy(cfg.HTTPClient, cfg.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		cfg.HTTPClient = client
+	}
+	if cfg.Record != "" {
+		client, err := withRecord(cfg.HTTPClient, cfg.Record)
+		if err != nil {
+			return nil, err
+		}
+		cfg.HTTPClient = client
+	}
+	if cfg.Replay != "" {
+		client, err := withReplay(cfg.HTTPClient, cfg.Replay)
+		if err != nil {
+			return nil, err
+		}
+		cfg.HTTPClient = client
+	}
+	if cfg.UserAgent != "" {
+		cfg.HTTPClient = withUserAgent(cfg.HTTPClient, cfg.UserAgent)
+	}
+	if cfg.Retry.Retries > 0 {
+		cfg.HTTPClient = withRetry(cfg.HTTPClient, cfg.Retry)
+	}
+	client, err := factory(cfg)
+	if err != nil {
+		return nil, err
+	}
+	middleware := make([]Middleware, 0, len(cfg.Middleware)+4)
+	middleware = append(middleware, cfg.Middleware...)
+	middleware = append(middleware, StreamResumeMiddleware(cfg.StreamReconnects))
+	if cfg.Poll {
+		// Outside the timeout, so the wait for the whole answer is not
+		// mistaken for an idle stream.
+		middleware = append(middleware, PollMiddleware())
+	}
+	middleware = append(middleware, RateLimitMiddleware(cfg.RateLimit), TimeoutMiddleware(cfg.Timeout))
+	return Chain(middleware...)(client), nil
+}
+
+// userAgentTransport sets the User-Agent header on outgoing requests.
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(req)
+}
+
+// withUserAgent returns a copy of client, or of a default client, whose
+// requests carry userAgent.
+func withUserAgent(client *http.Client, userAgent string) *http.Client {
+	wrapped := &http.Client{}
+	if client != nil {
+		*wrapped = *client
+	}
+	base := wrapped.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	wrapped.Transport = &userAgentTransport{base: base, userAgent: userAgent}
+	return wrapped
+}
+
+func init() {
+	Register("openai", func(cfg Config) (Client, error) {
+		return NewOpenAIClient(OpenAIConfig{
+			BaseURL:    cfg.BaseURL,
+			Token:      cfg.Token,
+			Model:      cfg.Model,
+			HTTPClient: cfg.HTTPClient,
+		})
+	})
+	Register("azure-openai", func(cfg Config) (Client, error) {
+		deployment := cfg.Options["deployment"]
+		if deployment == "" {
+			deployment = cfg.Model
+		}
+		return NewAzureOpenAIClient(AzureOpenAIConfig{
+			Resource:   cfg.Options["resource"],
+			BaseURL:    cfg.BaseURL,
+			Deployment: deployment,
+			APIVersion: cfg.Options["api_version"],
+			Token:      cfg.Token,
+			HTTPClient: cfg.HTTPClient,
+		})
+	})
+	Register("deepseek", func(cfg Config) (Client, error) {
+		return NewDeepSeekClient(OpenAIConfig{
+			BaseURL:    cfg.BaseURL,
+			Token:      cfg.Token,
+			Model:      cfg.Model,
+			HTTPClient: cfg.HTTPClient,
+		})
+	})
+	Register("openrouter", func(cfg Config) (Client, error) {
+		models := []string{cfg.Model}
+		if list := cfg.Options["models"]; list != "" {
+			models = append(models, strings.Split(list, ",")...)
+		}
+		return NewOpenRouterClient(OpenRouterConfig{
+			BaseURL:    cfg.BaseURL,
+			Token:      cfg.Token,
+			Models:     models,
+			HTTPClient: cfg.HTTPClient,
+		})
+	})
+	Register("dashscope", func(cfg Config) (Client, error) {
+		return NewDashScopeClient(DashScopeConfig{
+			BaseURL:    cfg.BaseURL,
+			Token:      cfg.Token,
+			Model:      cfg.Model,
+			HTTPClient: cfg.HTTPClient,
+		})
+	})
+	Register("llamacpp", func(cfg Config) (Client, error) {
+		return NewLlamaCppClient(LlamaCppConfig{
+			BaseURL:    cfg.BaseURL,
+			Token:      cfg.Token,
+			Model:      cfg.Model,
+			Template:   cfg.Options["template"],
+			HTTPClient: cfg.HTTPClient,
+		})
+	})
+	Register("mock", func(cfg Config) (Client, error) {
+		return NewMockClient(MockConfig{
+			Model:    cfg.Model,
+			Response: cfg.Options["response"],
+			Fixtures: cfg.Options["fixtures"],
+		})
+	})
+	Register("anthropics", func(cfg Config) (Client, error) {
+		return NewAnthropicClient(AnthropicConfig{
+			BaseURL:    cfg.BaseURL,
+			Token:      cfg.Token,
+			Model:      cfg.Model,
+			HTTPClient: cfg.HTTPClient,
+		})
+	})
+	Register("gemini", func(cfg Config) (Client, error) {
+		geminiCfg := GeminiConfig{
+			BaseURL:    cfg.BaseURL,
+			Token:      cfg.Token,
+			Model:      cfg.Model,
+			HTTPClient: cfg.HTTPClient,
+		}
+		if project := cfg.Options["project"]; project != "" {
+			geminiCfg.Vertex = &VertexConfig{
+				Project:         project,
+				Location:        cfg.Options["location"],
+				CredentialsFile: cfg.Options["credentials"],
+			}
+		}
+		return NewGeminiClient(geminiCfg)
+	})
+	Register("bedrock", func(cfg Config) (Client, error) {
+		creds, err := LoadAWSCredentials(cfg.Options["profile"])
+		if err != nil {
+			return nil, err
+		}
+		return NewBedrockClient(BedrockConfig{
+			Region:      firstNonEmptyString(cfg.Options["region"], os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")),
+			BaseURL:     cfg.BaseURL,
+			Model:       cfg.Model,
+			Credentials: creds,
+			HTTPClient:  cfg.HTTPClient,
+		})
+	})
+}
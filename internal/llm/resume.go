@@ -0,0 +1,127 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// DefaultStreamReconnects applies when the config does not set
+// llm.stream_reconnects.
+const DefaultStreamReconnects = 2
+
+// continuePrompt asks the model to continue an answer whose stream broke
+// off after the assistant message holding what was received.
+const continuePrompt = "Your previous answer was cut off. Continue it exactly where it stopped, without repeating any of it and without any preamble."
+
+// minOverlap is the shortest repeated text that is trimmed where a
+// continuation starts, so a coincidental shared word is kept.
+const minOverlap = 8
+
+type streamResume struct {
+	client   Client
+	attempts int
+}
+
+// WithStreamResume reconnects a stream that fails with
+// ErrStreamInterrupted up to attempts times. Each attempt re-issues the
+// request with the text received so far as an assistant message and asks
+// the model to continue it; text the continuation repeats is trimmed, so
+// the handler sees one answer. A cancelled context is not resumed.
+func WithStreamResume(client Client, attempts int) Client {
+	if attempts <= 0 {
+		return client
+	}
+	return &streamResume{client: client, attempts: attempts}
+}
+
+func (c *streamResume) Unwrap() Client { return c.client }
+
+func (c *streamResume) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return c.client.Chat(ctx, req)
+}
+
+func (c *streamResume) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	var received strings.Builder
+	emit := func(delta string) error {
+		received.WriteString(delta)
+		if handle == nil {
+			return nil
+		}
+		return handle(delta)
+	}
+	reasoned := false
+	first := req
+	if req.ReasoningHandler != nil {
+		first.ReasoningHandler = func(delta string) error {
+			reasoned = true
+			return req.ReasoningHandler(delta)
+		}
+	}
+	resp, err := c.client.ChatStream(ctx, first, emit)
+	for attempt := 0; attempt < c.attempts && errors.Is(err, ErrStreamInterrupted) && ctx.Err() == nil; attempt++ {
+		next := req
+		if reasoned {
+			// Reasoning already shown would be repeated in full.
+			next.ReasoningHandler = nil
+		}
+		prefix := received.String()
+		if prefix != "" {
+			next.Messages = append(slices.Clone(req.Messages),
+				Message{Role: "assistant", Content: prefix},
+				Message{Role: "user", Content: continuePrompt},
+			)
+		}
+		joint := &stitcher{prefix: prefix, emit: emit}
+		resp, err = c.client.ChatStream(ctx, next, joint.delta)
+		if err == nil {
+			err = joint.flush()
+		}
+	}
+	if err != nil {
+		return resp, err
+	}
+	resp.Content = received.String()
+	return resp, nil
+}
+
+// stitcher holds back the start of a continuation while it may still
+// repeat the end of prefix, then passes on the rest.
+type stitcher struct {
+	prefix string
+	buf    strings.Builder
+	passed bool
+	emit   StreamHandler
+}
+
+func (s *stitcher) delta(delta string) error {
+	if s.passed || s.prefix == "" {
+		return s.emit(delta)
+	}
+	s.buf.WriteString(delta)
+	if strings.Contains(s.prefix, s.buf.String()) {
+		return nil
+	}
+	return s.flush()
+}
+
+// flush passes on the held back text without the part that repeats the
+// end of prefix.
+func (s *stitcher) flush() error {
+	if s.passed {
+		return nil
+	}
+	s.passed = true
+	held := s.buf.String()
+	for k := min(len(held), len(s.prefix)); k >= minOverlap; k-- {
+		if strings.HasSuffix(s.prefix, held[:k]) {
+			held = held[k:]
+			break
+		}
+	}
+	if held == "" {
+		return nil
+	}
+	return s.emit(held)
+}
@@ -0,0 +1,89 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// dropStub streams the deltas of one script per call; a script ending in
+// "!drop" breaks off there.
+type dropStub struct {
+	scripts  [][]string
+	requests []ChatRequest
+}
+
+func (s *dropStub) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	return ChatResponse{}, fmt.Errorf("unexpected chat")
+}
+
+func (s *dropStub) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	script := s.scripts[len(s.requests)]
+	s.requests = append(s.requests, req)
+	var content strings.Builder
+	for _, delta := range script {
+		if delta == "!drop" {
+			return ChatResponse{}, fmt.Errorf("%w: connection reset", ErrStreamInterrupted)
+		}
+		content.WriteString(delta)
+		if err := handle(delta); err != nil {
+			return ChatResponse{}, err
+		}
+	}
+	return ChatResponse{Content: content.String(), FinishReason: "stop"}, nil
+}
+
+func TestStreamResume(t *testing.T) {
+	cases := []struct {
+		name    string
+		scripts [][]string
+		want    string
+	}{
+		{"continues", [][]string{{"Hello wor", "!drop"}, {"ld", ", again."}}, "Hello world, again."},
+		{"trims a repeated start", [][]string{{"Hello wor", "!drop"}, {"Hello ", "world, ", "again."}}, "Hello world, again."},
+		{"restarts in full", [][]string{{"Once upon a time", "!drop"}, {"Once upon", " a time there was"}}, "Once upon a time there was"},
+		{"drops twice", [][]string{{"one two ", "!drop"}, {"three ", "!drop"}, {"four"}}, "one two three four"},
+		{"drops before any text", [][]string{{"!drop"}, {"fresh answer"}}, "fresh answer"},
+	}
+	for _, tc := range cases {
+		stub := &dropStub{scripts: tc.scripts}
+		var shown strings.Builder
+		resp, err := WithStreamResume(stub, 2).ChatStream(context.Background(),
+			ChatRequest{Messages: []Message{{Role: "user", Content: "tell me"}}},
+			func(delta string) error {
+				shown.WriteString(delta)
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if shown.String() != tc.want || resp.Content != tc.want {
+			t.Fatalf("%s: shown %q, content %q, want %q", tc.name, shown.String(), resp.Content, tc.want)
+		}
+	}
+}
+
+func TestStreamResumeRequest(t *testing.T) {
+	stub := &dropStub{scripts: [][]string{{"partial", "!drop"}, {" rest"}}}
+	_, err := WithStreamResume(stub, 1).ChatStream(context.Background(),
+		ChatRequest{Messages: []Message{{Role: "user", Content: "tell me"}}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	messages := stub.requests[1].Messages
+	if len(messages) != 3 || messages[1].Role != "assistant" || messages[1].Content != "partial" || messages[2].Content != continuePrompt {
+		t.Fatalf("unexpected continuation request: %+v", messages)
+	}
+	if len(stub.requests[0].Messages) != 1 {
+		t.Fatalf("original request was modified: %+v", stub.requests[0].Messages)
+	}
+}
+
+func TestStreamResumeGivesUp(t *testing.T) {
+	stub := &dropStub{scripts: [][]string{{"a", "!drop"}, {"!drop"}, {"never"}}}
+	_, err := WithStreamResume(stub, 1).ChatStream(context.Background(), ChatRequest{}, func(string) error { return nil })
+	if err == nil || len(stub.requests) != 2 {
+		t.Fatalf("expected failure after one reconnect, got %v after %d requests", err, len(stub.requests))
+	}
+}
@@ -0,0 +1,134 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultRetries and DefaultRetryBackoff apply when the config does
+	// not set llm.retries or llm.retry_backoff.
+	DefaultRetries      = 2
+	DefaultRetryBackoff = 500 * time.Millisecond
+
+	// maxRetryDelay caps the backoff and the Retry-After delay a request
+	// waits for; a longer Retry-After is returned to the caller instead.
+	maxRetryDelay = time.Minute
+)
+
+// RetryPolicy retries requests that fail with a network error, 429 or a
+// 5xx status. The delay before retry n is a random duration between half
+// and all of Backoff*2^n, unless the response sets Retry-After.
+type RetryPolicy struct {
+	Retries int
+	Backoff time.Duration
+}
+
+// retryTransport applies a RetryPolicy below the provider clients, so a
+// stream is only retried before any of it has been read.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt >= t.policy.Retries || !t.canRetry(req) || !retryable(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if after > maxRetryDelay {
+					return resp, nil
+				}
+				delay = after
+			}
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+		}
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// canRetry reports whether the request body can be sent again.
+func (t *retryTransport) canRetry(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := t.policy.Backoff << attempt
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, ErrNotRecorded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP
+// date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// withRetry returns a copy of client, or of a default client, whose
+// requests are retried according to policy.
+func withRetry(client *http.Client, policy RetryPolicy) *http.Client {
+	wrapped := &http.Client{}
+	if client != nil {
+		*wrapped = *client
+	}
+	base := wrapped.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	wrapped.Transport = &retryTransport{base: base, policy: policy, sleep: sleepContext}
+	return wrapped
+}
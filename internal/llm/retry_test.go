@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRetryClient(policy RetryPolicy, delays *[]time.Duration) *http.Client {
+	client := withRetry(nil, policy)
+	client.Transport.(*retryTransport).sleep = func(ctx context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+	return client
+}
+
+func TestRetryTransportRetriesTransientErrors(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		switch len(bodies) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = io.WriteString(w, "ok")
+		}
+	}))
+	defer server.Close()
+
+	var delays []time.Duration
+	client := newTestRetryClient(RetryPolicy{Retries: 2, Backoff: 100 * time.Millisecond}, &delays)
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(bodies) != 3 || bodies[2] != `{"a":1}` {
+		t.Fatalf("unexpected result: status %d, bodies %q", resp.StatusCode, bodies)
+	}
+	if len(delays) != 2 || delays[0] < 50*time.Millisecond || delays[0] > 100*time.Millisecond || delays[1] != 3*time.Second {
+		t.Fatalf("unexpected delays: %v", delays)
+	}
+}
+
+func TestRetryTransportStopsOnClientErrorsAndLongRetryAfter(t *testing.T) {
+	for _, tc := range []struct {
+		status     int
+		retryAfter string
+	}{
+		{status: http.StatusBadRequest},
+		{status: http.StatusTooManyRequests, retryAfter: "3600"},
+	} {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if tc.retryAfter != "" {
+				w.Header().Set("Retry-After", tc.retryAfter)
+			}
+			w.WriteHeader(tc.status)
+		}))
+		var delays []time.Duration
+		client := newTestRetryClient(RetryPolicy{Retries: 3, Backoff: time.Millisecond}, &delays)
+		resp, err := client.Get(server.URL)
+		server.Close()
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.status || calls != 1 || len(delays) != 0 {
+			t.Fatalf("status %d: unexpected %d calls, delays %v", tc.status, calls, delays)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if d, ok := retryAfter("7", now); !ok || d != 7*time.Second {
+		t.Fatalf("unexpected seconds delay: %v %v", d, ok)
+	}
+	if d, ok := retryAfter(now.Add(time.Minute).Format(http.TimeFormat), now); !ok || d != time.Minute {
+		t.Fatalf("unexpected date delay: %v %v", d, ok)
+	}
+	if _, ok := retryAfter("soon", now); ok {
+		t.Fatalf("expected invalid header to be ignored")
+	}
+}
@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func samplingRequest() ChatRequest {
+	temperature, topP := 0.2, 0.9
+	return ChatRequest{
+		Messages:      []Message{{Role: "user", Content: "hi"}},
+		Temperature:   &temperature,
+		TopP:          &topP,
+		MaxTokens:     64,
+		StopSequences: []string{"END"},
+	}
+}
+
+// captureBody returns a server that records the JSON request body and
+// answers with reply.
+func captureBody(t *testing.T, body *map[string]any, reply string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		_, _ = w.Write([]byte(reply))
+	}))
+}
+
+func TestSamplingParametersOpenAI(t *testing.T) {
+	var body map[string]any
+	server := captureBody(t, &body, `{"choices":[{"message":{"content":"ok"}}]}`)
+	defer server.Close()
+	client, _ := NewOpenAIClient(OpenAIConfig{BaseURL: server.URL, Token: "t", Model: "m"})
+	if _, err := client.Chat(context.Background(), samplingRequest()); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if body["temperature"] != 0.2 || body["top_p"] != 0.9 || body["max_tokens"] != 64.0 || body["stop"].([]any)[0] != "END" {
+		t.Fatalf("unexpected body: %v", body)
+	}
+}
+
+func TestSamplingParametersAnthropic(t *testing.T) {
+	var body map[string]any
+	server := captureBody(t, &body, `{"content":[{"type":"text","text":"ok"}]}`)
+	defer server.Close()
+	client, _ := NewAnthropicClient(AnthropicConfig{BaseURL: server.URL, Token: "t", Model: "m"})
+	if _, err := client.Chat(context.Background(), samplingRequest()); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if body["max_tokens"] != 64.0 || body["temperature"] != 0.2 || body["stop_sequences"].([]any)[0] != "END" {
+		t.Fatalf("unexpected body: %v", body)
+	}
+
+	body = nil
+	if _, err := client.Chat(context.Background(), ChatRequest{Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if body["max_tokens"] != float64(defaultAnthropicMaxTokens) || body["temperature"] != nil {
+		t.Fatalf("expected defaults: %v", body)
+	}
+}
+
+func TestSamplingParametersGemini(t *testing.T) {
+	var body map[string]any
+	server := captureBody(t, &body, `{"candidates":[{"content":{"parts":[{"text":"ok"}]}}]}`)
+	defer server.Close()
+	client, _ := NewGeminiClient(GeminiConfig{BaseURL: server.URL, Token: "t", Model: "m"})
+	if _, err := client.Chat(context.Background(), samplingRequest()); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	config, ok := body["generationConfig"].(map[string]any)
+	if !ok || config["maxOutputTokens"] != 64.0 || config["topP"] != 0.9 || config["stopSequences"].([]any)[0] != "END" {
+		t.Fatalf("unexpected body: %v", body)
+	}
+
+	body = nil
+	if _, err := client.Chat(context.Background(), ChatRequest{Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if _, ok := body["generationConfig"]; ok {
+		t.Fatalf("expected no generationConfig without sampling parameters: %v", body)
+	}
+}
@@ -0,0 +1,89 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Defaults for SpeechRequest fields left empty.
+const (
+	DefaultSpeechModel = "gpt-4o-mini-tts"
+	DefaultSpeechVoice = "alloy"
+)
+
+// SpeechRequest asks for Input to be read aloud. Format is an audio format
+// such as "mp3", the default.
+type SpeechRequest struct {
+	Model  string
+	Voice  string
+	Input  string
+	Format string
+}
+
+// SpeechSynthesizer is implemented by clients whose provider can turn
+// text into speech.
+type SpeechSynthesizer interface {
+	Synthesize(ctx context.Context, req SpeechRequest) ([]byte, error)
+}
+
+// Synthesize returns the audio for req from client's provider.
+func Synthesize(ctx context.Context, client Client, req SpeechRequest) ([]byte, error) {
+	synthesizer, ok := Provider[SpeechSynthesizer](client)
+	if !ok {
+		return nil, fmt.Errorf("provider does not support speech synthesis")
+	}
+	if strings.TrimSpace(req.Input) == "" {
+		return nil, fmt.Errorf("speech input is required")
+	}
+	if req.Model == "" {
+		req.Model = DefaultSpeechModel
+	}
+	if req.Voice == "" {
+		req.Voice = DefaultSpeechVoice
+	}
+	if req.Format == "" {
+		req.Format = "mp3"
+	}
+	return synthesizer.Synthesize(ctx, req)
+}
+
+// Synthesize calls the OpenAI-compatible /audio/speech endpoint.
+func (c *OpenAIClient) Synthesize(ctx context.Context, req SpeechRequest) ([]byte, error) {
+	if c.provider == "azure-openai" {
+		return nil, fmt.Errorf("azure-openai speech synthesis is not supported; its deployment serves chat only")
+	}
+	body, err := json.Marshal(map[string]string{
+		"model":           req.Model,
+		"voice":           req.Voice,
+		"input":           req.Input,
+		"response_format": req.Format,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+	endpoint := strings.TrimSuffix(c.endpoint, "/chat/completions") + "/audio/speech"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(c.authHeader, c.authValue)
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("synthesize speech: %w", err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusMultipleChoices {
+		return nil, readOpenAIError(c.provider, httpResp.Body, httpResp.StatusCode)
+	}
+	audio, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read audio: %w", err)
+	}
+	return audio, nil
+}
@@ -0,0 +1,50 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOpenAISynthesize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/audio/speech" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Fatalf("missing auth header")
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if body["input"] != "colonel" || body["model"] != DefaultSpeechModel || body["voice"] != "nova" || body["response_format"] != "mp3" {
+			t.Fatalf("unexpected body: %v", body)
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		_, _ = w.Write([]byte("ID3audio"))
+	}))
+	defer server.Close()
+
+	client, err := NewOpenAIClient(OpenAIConfig{BaseURL: server.URL + "/v1", Token: "token", Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	wrapped := WithTimeout(client, TimeoutPolicy{Timeout: time.Minute})
+	audio, err := Synthesize(context.Background(), wrapped, SpeechRequest{Input: "colonel", Voice: "nova"})
+	if err != nil {
+		t.Fatalf("synthesize: %v", err)
+	}
+	if string(audio) != "ID3audio" {
+		t.Fatalf("unexpected audio: %q", audio)
+	}
+}
+
+func TestSynthesizeUnsupported(t *testing.T) {
+	if _, err := Synthesize(context.Background(), WithStreamFallback(&MockClient{}), SpeechRequest{Input: "a"}); err == nil {
+		t.Fatalf("expected an error for a provider without speech")
+	}
+}
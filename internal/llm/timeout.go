@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultTimeout and DefaultStreamIdleTimeout apply when the config
+	// does not set llm.timeout or llm.stream_idle_timeout.
+	DefaultTimeout           = 5 * time.Minute
+	DefaultStreamIdleTimeout = time.Minute
+)
+
+// ErrTimeout is returned when a request exceeds its Timeout or a stream
+// sends nothing for StreamIdle.
+var ErrTimeout = errors.New("llm request timed out")
+
+// TimeoutPolicy bounds a request. Timeout limits the whole request,
+// including retries; StreamIdle limits the wait for each stream delta,
+// counted from the start of the request. Zero disables either limit.
+type TimeoutPolicy struct {
+	Timeout    time.Duration
+	StreamIdle time.Duration
+}
+
+type timeoutClient struct {
+	client Client
+	policy TimeoutPolicy
+}
+
+// WithTimeout applies policy to every request of client through its
+// context. An idle stream fails with an error wrapping both ErrTimeout
+// and ErrStreamInterrupted, so WithStreamFallback retries it.
+func WithTimeout(client Client, policy TimeoutPolicy) Client {
+	if policy.Timeout <= 0 && policy.StreamIdle <= 0 {
+		return client
+	}
+	return &timeoutClient{client: client, policy: policy}
+}
+
+func (c *timeoutClient) Unwrap() Client { return c.client }
+
+func (c *timeoutClient) Chat(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.client.Chat(ctx, req)
+	return resp, timeoutCause(ctx, err)
+}
+
+func (c *timeoutClient) ChatStream(ctx context.Context, req ChatRequest, handle StreamHandler) (ChatResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	if c.policy.StreamIdle <= 0 {
+		resp, err := c.client.ChatStream(ctx, req, handle)
+		return resp, timeoutCause(ctx, err)
+	}
+
+	idle := c.policy.StreamIdle
+	ctx, cancelIdle := context.WithCancelCause(ctx)
+	defer cancelIdle(nil)
+	timer := time.AfterFunc(idle, func() {
+		cancelIdle(fmt.Errorf("%w: %w: no data for %s", ErrTimeout, ErrStreamInterrupted, idle))
+	})
+	defer timer.Stop()
+	reset := func(handle StreamHandler) StreamHandler {
+		return func(delta string) error {
+			timer.Reset(idle)
+			if handle == nil {
+				return nil
+			}
+			return handle(delta)
+		}
+	}
+	if req.ReasoningHandler != nil {
+		req.ReasoningHandler = reset(req.ReasoningHandler)
+	}
+	resp, err := c.client.ChatStream(ctx, req, reset(handle))
+	return resp, timeoutCause(ctx, err)
+}
+
+func (c *timeoutClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.policy.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, c.policy.Timeout,
+		fmt.Errorf("%w after %s", ErrTimeout, c.policy.Timeout))
+}
+
+// timeoutCause replaces err with the reason ctx was cancelled when that
+// reason is one of our timeouts, since providers only report the bare
+// context error or a failed read.
+func timeoutCause(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	if cause := context.Cause(ctx); errors.Is(cause, ErrTimeout) {
+		return cause
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeoutChat(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	inner, err := NewOpenAIClient(OpenAIConfig{BaseURL: server.URL, Token: "token", Model: "gpt-test"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	client := WithTimeout(inner, TimeoutPolicy{Timeout: 50 * time.Millisecond})
+	_, err = client.Chat(context.Background(), ChatRequest{Messages: []Message{{Role: "user", Content: "hi"}}})
+	if !errors.Is(err, ErrTimeout) || errors.Is(err, ErrStreamInterrupted) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+}
+
+func TestTimeoutStreamIdle(t *testing.T) {
+	requests := 0
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			fmt.Fprint(w, `{"model":"gpt-test","choices":[{"message":{"content":"hello world"},"finish_reason":"stop"}]}`)
+			return
+		}
+		// Each delta arrives within the idle limit, then the stream hangs.
+		for _, delta := range []string{"hel", "lo "} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", delta)
+			w.(http.Flusher).Flush()
+			time.Sleep(30 * time.Millisecond)
+		}
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	inner, err := NewOpenAIClient(OpenAIConfig{BaseURL: server.URL, Token: "token", Model: "gpt-test"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	req := ChatRequest{Messages: []Message{{Role: "user", Content: "hi"}}}
+	policy := TimeoutPolicy{StreamIdle: 100 * time.Millisecond}
+	_, err = WithTimeout(inner, policy).ChatStream(context.Background(), req, nil)
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, ErrStreamInterrupted) {
+		t.Fatalf("expected idle timeout, got %v", err)
+	}
+
+	requests = 0
+	var streamed string
+	resp, err := WithStreamFallback(WithTimeout(inner, policy)).ChatStream(context.Background(), req, func(delta string) error {
+		streamed += delta
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chat stream: %v", err)
+	}
+	if streamed != "hello world" || resp.Content != "hello world" {
+		t.Fatalf("unexpected output: %q, %+v", streamed, resp)
+	}
+}
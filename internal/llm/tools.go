@@ -0,0 +1,35 @@
+package llm
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// toolArguments returns raw, or an empty JSON object when the model sent
+// no arguments, so every ToolCall carries a decodable object.
+func toolArguments(raw json.RawMessage) json.RawMessage {
+	if strings.TrimSpace(string(raw)) == "" {
+		return json.RawMessage("{}")
+	}
+	return raw
+}
+
+// toolParameters returns the JSON Schema of a tool, defaulting to an
+// object without properties, which all providers accept.
+func toolParameters(raw json.RawMessage) json.RawMessage {
+	if strings.TrimSpace(string(raw)) == "" {
+		return json.RawMessage(`{"type":"object","properties":{}}`)
+	}
+	return raw
+}
+
+// toolResult decodes the content of a tool message as a JSON object, or
+// wraps it as {"result": content} for providers that require an object.
+func toolResult(content string) json.RawMessage {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content), &object); err == nil && object != nil {
+		return json.RawMessage(content)
+	}
+	wrapped, _ := json.Marshal(map[string]string{"result": content})
+	return wrapped
+}
@@ -0,0 +1,140 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var lookupTool = Tool{
+	Name:        "lookup",
+	Description: "Look up a word",
+	Parameters:  json.RawMessage(`{"type":"object","properties":{"word":{"type":"string"}}}`),
+}
+
+// toolConversation is a request answering an earlier lookup call.
+var toolConversation = []Message{
+	{Role: "user", Content: "define cat"},
+	{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1", Name: "lookup", Arguments: json.RawMessage(`{"word":"cat"}`)}}},
+	{Role: "tool", ToolCallID: "call_1", Name: "lookup", Content: "a small feline"},
+}
+
+func writeEvents(w http.ResponseWriter, events ...string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	for _, event := range events {
+		_, _ = io.WriteString(w, "data: "+event+"\n\n")
+	}
+}
+
+func TestOpenAIToolCallStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		for _, want := range []string{
+			`"tools":[{"type":"function","function":{"name":"lookup"`,
+			`"tool_calls":[{"id":"call_1","type":"function","function":{"name":"lookup","arguments":"{\"word\":\"cat\"}"}}]`,
+			`"tool_call_id":"call_1"`,
+		} {
+			if !strings.Contains(string(body), want) {
+				t.Fatalf("request missing %s: %s", want, body)
+			}
+		}
+		writeEvents(w,
+			`{"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_2","type":"function","function":{"name":"lookup","arguments":""}}]}}]}`,
+			`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"word\":"}}]}}]}`,
+			`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"dog\"}"}}]},"finish_reason":"tool_calls"}]}`,
+			"[DONE]",
+		)
+	}))
+	defer server.Close()
+
+	client, err := NewOpenAIClient(OpenAIConfig{BaseURL: server.URL, Token: "token", Model: "gpt-test"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	resp, err := client.ChatStream(context.Background(), ChatRequest{Messages: toolConversation, Tools: []Tool{lookupTool}}, nil)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "call_2" || string(resp.ToolCalls[0].Arguments) != `{"word":"dog"}` {
+		t.Fatalf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+}
+
+func TestAnthropicToolUseStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req anthropicChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if len(req.Tools) != 1 || req.Tools[0].Name != "lookup" || len(req.Messages) != 3 {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		if block := req.Messages[1].Content[0]; block.Type != "tool_use" || string(block.Input) != `{"word":"cat"}` {
+			t.Fatalf("unexpected tool use: %+v", block)
+		}
+		if block := req.Messages[2].Content[0]; req.Messages[2].Role != "user" || block.Type != "tool_result" || block.ToolUseID != "call_1" {
+			t.Fatalf("unexpected tool result: %+v", req.Messages[2])
+		}
+		writeEvents(w,
+			`{"type":"message_start","message":{"model":"claude-test"}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Checking."}}`,
+			`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"lookup","input":{}}}`,
+			`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"word\":"}}`,
+			`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"dog\"}"}}`,
+			`{"type":"message_delta","delta":{"stop_reason":"tool_use"}}`,
+		)
+	}))
+	defer server.Close()
+
+	client, err := NewAnthropicClient(AnthropicConfig{BaseURL: server.URL, Token: "token", Model: "claude-test"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	resp, err := client.ChatStream(context.Background(), ChatRequest{Messages: toolConversation, Tools: []Tool{lookupTool}}, nil)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if resp.Content != "Checking." || resp.FinishReason != "tool_use" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "toolu_1" || string(resp.ToolCalls[0].Arguments) != `{"word":"dog"}` {
+		t.Fatalf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+}
+
+func TestGeminiFunctionCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req geminiGenerateContentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if len(req.Tools) != 1 || req.Tools[0].FunctionDeclarations[0].Name != "lookup" || len(req.Contents) != 3 {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		if call := req.Contents[1].Parts[0].FunctionCall; req.Contents[1].Role != "model" || call == nil || call.Name != "lookup" {
+			t.Fatalf("unexpected function call: %+v", req.Contents[1])
+		}
+		if result := req.Contents[2].Parts[0].FunctionResponse; result == nil || string(result.Response) != `{"result":"a small feline"}` {
+			t.Fatalf("unexpected function response: %+v", req.Contents[2])
+		}
+		_, _ = io.WriteString(w, `{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"lookup","args":{"word":"dog"}}}]},"finishReason":"STOP"}]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewGeminiClient(GeminiConfig{BaseURL: server.URL, Token: "token", Model: "gemini-test"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	resp, err := client.Chat(context.Background(), ChatRequest{Messages: toolConversation, Tools: []Tool{lookupTool}})
+	if err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Name != "lookup" || string(resp.ToolCalls[0].Arguments) != `{"word":"dog"}` {
+		t.Fatalf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+}
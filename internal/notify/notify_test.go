@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandDarwinQuotes(t *testing.T) {
+	name, args, err := command("darwin", `say "hi"`, `done \ ok`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "osascript" || len(args) != 2 {
+		t.Fatalf("unexpected command: %s %q", name, args)
+	}
+	if args[1] != `display notification "done \\ ok" with title "say \"hi\""` {
+		t.Fatalf("unexpected script: %s", args[1])
+	}
+}
+
+func TestCommandLinux(t *testing.T) {
+	name, args, err := command("linux", "title", "message")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "notify-send" || args[len(args)-1] != "message" {
+		t.Fatalf("unexpected command: %s %q", name, args)
+	}
+}
+
+func TestCommandWindowsQuotes(t *testing.T) {
+	_, args, err := command("windows", "it's", "done")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(args[len(args)-1], "'it''s'") {
+		t.Fatalf("unexpected script: %s", args[len(args)-1])
+	}
+}
+
+func TestCommandUnsupported(t *testing.T) {
+	if _, _, err := command("plan9", "t", "m"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,139 @@
+package postprocess
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Spec configures one post-processing step: either a built-in by name or
+// an external shell command that reads the text on stdin.
+type Spec struct {
+	Builtin string
+	Command string
+}
+
+// Processor transforms output text.
+type Processor interface {
+	Process(ctx context.Context, text string) (string, error)
+}
+
+// Pipeline applies processors in order.
+type Pipeline []Processor
+
+func New(specs []Spec) (Pipeline, error) {
+	pipeline := make(Pipeline, 0, len(specs))
+	for i, spec := range specs {
+		builtin := strings.TrimSpace(spec.Builtin)
+		command := strings.TrimSpace(spec.Command)
+		switch {
+		case builtin != "" && command != "":
+			return nil, fmt.Errorf("postprocess[%d]: only one of builtin or command can be set", i)
+		case builtin != "":
+			processor, ok := builtins[builtin]
+			if !ok {
+				return nil, fmt.Errorf("postprocess[%d]: unknown builtin %q", i, builtin)
+			}
+			pipeline = append(pipeline, processor)
+		case command != "":
+			pipeline = append(pipeline, commandProcessor(command))
+		default:
+			return nil, fmt.Errorf("postprocess[%d]: builtin or command is required", i)
+		}
+	}
+	return pipeline, nil
+}
+
+func (p Pipeline) Apply(ctx context.Context, text string) (string, error) {
+	for _, processor := range p {
+		var err error
+		text, err = processor.Process(ctx, text)
+		if err != nil {
+			return "", err
+		}
+	}
+	return text, nil
+}
+
+// Builtins lists the names accepted in Spec.Builtin.
+func Builtins() []string {
+	return []string{"strip-markdown", "uppercase-headwords", "trim"}
+}
+
+type processorFunc func(text string) string
+
+func (f processorFunc) Process(ctx context.Context, text string) (string, error) {
+	return f(text), nil
+}
+
+var builtins = map[string]Processor{
+	"strip-markdown":      processorFunc(StripMarkdown),
+	"uppercase-headwords": processorFunc(UppercaseHeadwords),
+	"trim":                processorFunc(strings.TrimSpace),
+}
+
+var (
+	headingPattern    = regexp.MustCompile(`(?m)^#{1,6}\s+`)
+	linkPattern       = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	emphasisPattern   = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	italicPattern     = regexp.MustCompile(`(^|[^*\w])[*_]([^*_\n]+)[*_]`)
+	inlineCodePattern = regexp.MustCompile("`([^`\n]+)`")
+	fencePattern      = regexp.MustCompile("(?m)^```.*\n?")
+	headwordPattern   = regexp.MustCompile(`(?m)^(#{1,6}\s+)(.+)$`)
+	boldLinePattern   = regexp.MustCompile(`(?m)^(\*\*|__)(.+?)(\*\*|__)`)
+)
+
+// StripMarkdown removes common markdown markup and keeps the text.
+func StripMarkdown(text string) string {
+	text = fencePattern.ReplaceAllString(text, "")
+	text = headingPattern.ReplaceAllString(text, "")
+	text = linkPattern.ReplaceAllString(text, "$1")
+	text = emphasisPattern.ReplaceAllString(text, "$2")
+	text = italicPattern.ReplaceAllString(text, "$1$2")
+	text = inlineCodePattern.ReplaceAllString(text, "$1")
+	return text
+}
+
+// UppercaseHeadwords upper-cases markdown headings and bold text that
+// starts a line, which is how entries mark their headword.
+func UppercaseHeadwords(text string) string {
+	text = headwordPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := headwordPattern.FindStringSubmatch(match)
+		return parts[1] + strings.ToUpper(parts[2])
+	})
+	return boldLinePattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := boldLinePattern.FindStringSubmatch(match)
+		return parts[1] + strings.ToUpper(parts[2]) + parts[3]
+	})
+}
+
+type commandProcessor string
+
+func (c commandProcessor) Process(ctx context.Context, text string) (string, error) {
+	name, args := shellCommand(string(c))
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(text)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("postprocess command %q: %w: %s", string(c), err, strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("postprocess command %q: %w", string(c), err)
+	}
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+func shellCommand(command string) (string, []string) {
+	if runtime.GOOS == "windows" {
+		return "cmd", []string{"/C", command}
+	}
+	return "sh", []string{"-c", command}
+}
@@ -0,0 +1,70 @@
+package postprocess
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+func TestStripMarkdown(t *testing.T) {
+	input := "## Word\n**bold** and *italic* with `code` and [link](https://example.com)\n```go\nx := 1\n```\n- item"
+	expected := "Word\nbold and italic with code and link\nx := 1\n- item"
+	if got := StripMarkdown(input); got != expected {
+		t.Fatalf("unexpected output:\n%q\nwant\n%q", got, expected)
+	}
+}
+
+func TestUppercaseHeadwords(t *testing.T) {
+	input := "# serendipity\n**noun** luck\nplain **bold**"
+	expected := "# SERENDIPITY\n**NOUN** luck\nplain **bold**"
+	if got := UppercaseHeadwords(input); got != expected {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestNewValidatesSpecs(t *testing.T) {
+	if _, err := New([]Spec{{Builtin: "nope"}}); err == nil {
+		t.Fatalf("expected unknown builtin error")
+	}
+	if _, err := New([]Spec{{Builtin: "trim", Command: "cat"}}); err == nil {
+		t.Fatalf("expected conflict error")
+	}
+	if _, err := New([]Spec{{}}); err == nil {
+		t.Fatalf("expected empty spec error")
+	}
+}
+
+func TestPipelineApply(t *testing.T) {
+	specs := []Spec{{Builtin: "strip-markdown"}, {Builtin: "trim"}}
+	if runtime.GOOS != "windows" {
+		specs = append(specs, Spec{Command: "tr a-z A-Z"})
+	}
+	pipeline, err := New(specs)
+	if err != nil {
+		t.Fatalf("new pipeline: %v", err)
+	}
+	got, err := pipeline.Apply(context.Background(), "  **hello**  \n")
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	expected := "HELLO"
+	if runtime.GOOS == "windows" {
+		expected = "hello"
+	}
+	if got != expected {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestCommandFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	pipeline, err := New([]Spec{{Command: "echo bad >&2; exit 3"}})
+	if err != nil {
+		t.Fatalf("new pipeline: %v", err)
+	}
+	if _, err := pipeline.Apply(context.Background(), "x"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,128 @@
+// Package prefs remembers the flag values a user passes most often, so
+// commands can offer them as defaults.
+package prefs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// MinUses is how often a value must have been passed before it becomes a
+// default.
+const MinUses = 3
+
+// Prefs counts the values passed to each flag of each command.
+type Prefs struct {
+	Commands map[string]map[string]map[string]int `json:"commands"`
+}
+
+// Record counts one use of value for flag of command.
+func (p *Prefs) Record(command, flag, value string) {
+	if p.Commands == nil {
+		p.Commands = make(map[string]map[string]map[string]int)
+	}
+	flags := p.Commands[command]
+	if flags == nil {
+		flags = make(map[string]map[string]int)
+		p.Commands[command] = flags
+	}
+	if flags[flag] == nil {
+		flags[flag] = make(map[string]int)
+	}
+	flags[flag][value]++
+}
+
+// Default returns the value passed most often to flag of command, if it
+// was passed at least MinUses times. Ties go to the value that sorts
+// first.
+func (p *Prefs) Default(command, flag string) (string, bool) {
+	best, uses := "", 0
+	for _, use := range p.Uses(command, flag) {
+		if use.Count > uses {
+			best, uses = use.Value, use.Count
+		}
+	}
+	return best, uses >= MinUses
+}
+
+// Use is a value and how often it was passed.
+type Use struct {
+	Value string
+	Count int
+}
+
+// Uses lists the values passed to flag of command in value order.
+func (p *Prefs) Uses(command, flag string) []Use {
+	counts := p.Commands[command][flag]
+	uses := make([]Use, 0, len(counts))
+	for value, count := range counts {
+		uses = append(uses, Use{Value: value, Count: count})
+	}
+	sort.Slice(uses, func(i, j int) bool { return uses[i].Value < uses[j].Value })
+	return uses
+}
+
+// Store keeps Prefs in a JSON file.
+type Store struct {
+	path string
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns ~/.dict-be/prefs.json.
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir: %w", err)
+	}
+	return filepath.Join(homeDir, ".dict-be", "prefs.json"), nil
+}
+
+// Load reads the stored preferences. A missing file is empty.
+func (s *Store) Load() (*Prefs, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Prefs{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read preferences: %w", err)
+	}
+	var prefs Prefs
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return nil, fmt.Errorf("decode preferences %s: %w", s.path, err)
+	}
+	return &prefs, nil
+}
+
+// Save writes prefs atomically.
+func (s *Store) Save(prefs *Prefs) error {
+	data, err := json.MarshalIndent(prefs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode preferences: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create preferences dir: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write preferences: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("write preferences: %w", err)
+	}
+	return nil
+}
+
+// Reset removes the stored preferences.
+func (s *Store) Reset() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reset preferences: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package prefs
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	var prefs Prefs
+	for _, value := range []string{"ja", "zh", "zh", "ja", "de"} {
+		prefs.Record("query", "out", value)
+	}
+	if _, ok := prefs.Default("query", "out"); ok {
+		t.Fatalf("default before %d uses", MinUses)
+	}
+	prefs.Record("query", "out", "zh")
+	if value, ok := prefs.Default("query", "out"); !ok || value != "zh" {
+		t.Fatalf("unexpected default: %q, %v", value, ok)
+	}
+	if _, ok := prefs.Default("translate", "out"); ok {
+		t.Fatalf("defaults must be per command")
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "prefs.json"))
+	prefs, err := store.Load()
+	if err != nil || len(prefs.Commands) != 0 {
+		t.Fatalf("expected empty preferences, got %+v, %v", prefs, err)
+	}
+	prefs.Record("read", "level", "B2")
+	if err := store.Save(prefs); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := store.Load()
+	if err != nil || loaded.Commands["read"]["level"]["B2"] != 1 {
+		t.Fatalf("unexpected preferences: %+v, %v", loaded, err)
+	}
+	if err := store.Reset(); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if err := store.Reset(); err != nil {
+		t.Fatalf("reset without file: %v", err)
+	}
+	if loaded, _ := store.Load(); len(loaded.Commands) != 0 {
+		t.Fatalf("preferences not reset: %+v", loaded)
+	}
+}
@@ -0,0 +1,155 @@
+// Package progress reports the progress of batch commands on a terminal
+// line or as periodic plain-text lines.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Display modes accepted by --progress. Auto picks Bar on a terminal and
+// Plain otherwise.
+const (
+	Auto  = "auto"
+	None  = "none"
+	Plain = "plain"
+	Bar   = "bar"
+)
+
+const (
+	// plainInterval is the minimum time between two plain progress lines.
+	plainInterval = 10 * time.Second
+	barWidth      = 20
+)
+
+// ParseMode validates a --progress value.
+func ParseMode(mode string) error {
+	switch mode {
+	case Auto, None, Plain, Bar:
+		return nil
+	}
+	return fmt.Errorf("invalid --progress: %s (expected none, plain or bar)", mode)
+}
+
+// Reporter tracks the segments of one batch run. A nil Reporter reports
+// nothing, so callers need not check the mode.
+type Reporter struct {
+	mu      sync.Mutex
+	out     io.Writer
+	bar     bool
+	unit    string
+	total   int
+	done    int
+	failed  int
+	cost    float64
+	costSet bool
+	start   time.Time
+	printed time.Time
+	drawn   bool
+	now     func() time.Time
+}
+
+// New returns a Reporter for total segments named unit, such as
+// "paragraphs", or nil when mode is None or there is nothing to do.
+// terminal tells whether out is a terminal: Auto draws a bar only there,
+// and Bar degrades to plain lines elsewhere.
+func New(out io.Writer, mode string, terminal bool, total int, unit string) *Reporter {
+	if mode == None || total <= 0 {
+		return nil
+	}
+	now := time.Now()
+	return &Reporter{
+		out:     out,
+		bar:     terminal && (mode == Auto || mode == Bar),
+		unit:    unit,
+		total:   total,
+		start:   now,
+		printed: now,
+		now:     time.Now,
+	}
+}
+
+// Done records a finished segment.
+func (r *Reporter) Done() {
+	r.update(func() { r.done++ })
+}
+
+// Fail records a failed segment.
+func (r *Reporter) Fail() {
+	r.update(func() { r.failed++ })
+}
+
+// AddCost adds the USD cost of a request to the running total.
+func (r *Reporter) AddCost(usd float64) {
+	r.update(func() {
+		r.cost += usd
+		r.costSet = true
+	})
+}
+
+// Clear removes the bar so other output can be written, until the next
+// update redraws it.
+func (r *Reporter) Clear() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clear()
+}
+
+// Finish removes the bar at the end of the run.
+func (r *Reporter) Finish() {
+	r.Clear()
+}
+
+func (r *Reporter) update(change func()) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	change()
+	if r.bar {
+		fmt.Fprintf(r.out, "\r\033[K%s", r.line(true))
+		r.drawn = true
+		return
+	}
+	if now := r.now(); now.Sub(r.printed) >= plainInterval {
+		r.printed = now
+		fmt.Fprintf(r.out, "progress: %s\n", r.line(false))
+	}
+}
+
+func (r *Reporter) clear() {
+	if r.drawn {
+		fmt.Fprint(r.out, "\r\033[K")
+		r.drawn = false
+	}
+}
+
+// line formats the counts, ETA, cost and failures, with a bar in front
+// when drawing one.
+func (r *Reporter) line(bar bool) string {
+	finished := r.done + r.failed
+	parts := []string{fmt.Sprintf("%d/%d %s", finished, r.total, r.unit)}
+	if bar {
+		filled := barWidth * min(finished, r.total) / r.total
+		parts[0] = "[" + strings.Repeat("#", filled) + strings.Repeat("-", barWidth-filled) + "] " + parts[0]
+	}
+	if finished > 0 && finished < r.total {
+		elapsed := r.now().Sub(r.start)
+		eta := elapsed / time.Duration(finished) * time.Duration(r.total-finished)
+		parts = append(parts, "ETA "+eta.Round(time.Second).String())
+	}
+	if r.costSet {
+		parts = append(parts, fmt.Sprintf("$%.4f", r.cost))
+	}
+	if r.failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", r.failed))
+	}
+	return strings.Join(parts, ", ")
+}
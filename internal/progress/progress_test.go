@@ -0,0 +1,69 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReporterBar(t *testing.T) {
+	var out bytes.Buffer
+	r := New(&out, Auto, true, 4, "paragraphs")
+	now := r.start
+	r.now = func() time.Time { return now }
+	now = now.Add(10 * time.Second)
+	r.Done()
+	r.AddCost(0.0125)
+	now = now.Add(10 * time.Second)
+	r.Fail()
+	if got := out.String(); !strings.HasSuffix(got, "\r\033[K[##########----------] 2/4 paragraphs, ETA 20s, $0.0125, 1 failed") {
+		t.Fatalf("unexpected bar: %q", got)
+	}
+	out.Reset()
+	r.Finish()
+	if out.String() != "\r\033[K" {
+		t.Fatalf("bar not cleared: %q", out.String())
+	}
+}
+
+func TestReporterPlain(t *testing.T) {
+	var out bytes.Buffer
+	// Bar degrades to plain lines when out is not a terminal.
+	r := New(&out, Bar, false, 3, "inputs")
+	now := r.start
+	r.now = func() time.Time { return now }
+	r.Done()
+	if out.Len() != 0 {
+		t.Fatalf("plain line printed before the interval: %q", out.String())
+	}
+	now = now.Add(plainInterval)
+	r.Done()
+	r.Done()
+	if got := out.String(); got != "progress: 2/3 inputs, ETA 5s\n" {
+		t.Fatalf("unexpected lines: %q", got)
+	}
+	r.Finish()
+	if got := out.String(); got != "progress: 2/3 inputs, ETA 5s\n" {
+		t.Fatalf("finish changed plain output: %q", got)
+	}
+}
+
+func TestReporterNone(t *testing.T) {
+	var out bytes.Buffer
+	for _, r := range []*Reporter{New(&out, None, true, 3, "inputs"), New(&out, Auto, true, 0, "inputs")} {
+		if r != nil {
+			t.Fatalf("expected no reporter")
+		}
+		r.Done()
+		r.Fail()
+		r.AddCost(1)
+		r.Finish()
+	}
+	if out.Len() != 0 {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	if err := ParseMode("fancy"); err == nil {
+		t.Fatalf("expected invalid mode error")
+	}
+}
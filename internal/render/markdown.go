@@ -0,0 +1,248 @@
+package render
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// The markdown support below covers what models typically emit for
+// dictionary and translation answers: headings, lists, fenced code,
+// paragraphs and bold/italic/code spans. Anything else is kept as text.
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockBullet
+	blockOrdered
+	blockCode
+	blockBlank
+)
+
+type block struct {
+	kind   blockKind
+	level  int
+	marker string
+	text   string
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	bulletPattern  = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedPattern = regexp.MustCompile(`^\s*(\d+)[.)]\s+(.*)$`)
+)
+
+func parseBlocks(text string) []block {
+	var blocks []block
+	var code []string
+	inCode := false
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			if inCode {
+				blocks = append(blocks, block{kind: blockCode, text: strings.Join(code, "\n")})
+				code = nil
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			code = append(code, line)
+			continue
+		}
+		if trimmed == "" {
+			blocks = append(blocks, block{kind: blockBlank})
+			continue
+		}
+		if match := headingPattern.FindStringSubmatch(trimmed); match != nil {
+			blocks = append(blocks, block{kind: blockHeading, level: len(match[1]), text: match[2]})
+			continue
+		}
+		if match := bulletPattern.FindStringSubmatch(line); match != nil {
+			blocks = append(blocks, block{kind: blockBullet, text: match[1]})
+			continue
+		}
+		if match := orderedPattern.FindStringSubmatch(line); match != nil {
+			blocks = append(blocks, block{kind: blockOrdered, marker: match[1], text: match[2]})
+			continue
+		}
+		if n := len(blocks); n > 0 && blocks[n-1].kind == blockParagraph {
+			blocks[n-1].text += "\n" + trimmed
+			continue
+		}
+		blocks = append(blocks, block{kind: blockParagraph, text: trimmed})
+	}
+	if inCode {
+		blocks = append(blocks, block{kind: blockCode, text: strings.Join(code, "\n")})
+	}
+	return blocks
+}
+
+type spanStyle int
+
+const (
+	spanPlain spanStyle = iota
+	spanBold
+	spanItalic
+	spanCode
+)
+
+type span struct {
+	style spanStyle
+	text  string
+}
+
+// parseInline splits text into plain, bold, italic and code spans. Spans
+// do not nest; an unmatched delimiter is kept as plain text.
+func parseInline(text string) []span {
+	var spans []span
+	var plain strings.Builder
+	flush := func() {
+		if plain.Len() > 0 {
+			spans = append(spans, span{style: spanPlain, text: plain.String()})
+			plain.Reset()
+		}
+	}
+	for i := 0; i < len(text); {
+		style, delim := spanPlain, ""
+		switch {
+		case text[i] == '`':
+			style, delim = spanCode, "`"
+		case strings.HasPrefix(text[i:], "**") || strings.HasPrefix(text[i:], "__"):
+			style, delim = spanBold, text[i:i+2]
+		case text[i] == '*' || (text[i] == '_' && (i == 0 || !isWordByte(text[i-1]))):
+			style, delim = spanItalic, text[i:i+1]
+		}
+		if delim != "" {
+			rest := text[i+len(delim):]
+			end := strings.Index(rest, delim)
+			if end > 0 && rest[0] != ' ' {
+				flush()
+				spans = append(spans, span{style: style, text: rest[:end]})
+				i += len(delim)*2 + end
+				continue
+			}
+		}
+		plain.WriteByte(text[i])
+		i++
+	}
+	flush()
+	return spans
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiDim       = "\x1b[2m"
+	ansiItalic    = "\x1b[3m"
+	ansiHeading   = "\x1b[1;4m"
+	ansiCodeColor = "\x1b[36m"
+)
+
+// MarkdownToANSI renders markdown for a terminal using ANSI styles.
+func MarkdownToANSI(text string) string {
+	var builder strings.Builder
+	for _, b := range parseBlocks(text) {
+		switch b.kind {
+		case blockBlank:
+			builder.WriteString("\n")
+		case blockHeading:
+			builder.WriteString(ansiHeading + b.text + ansiReset + "\n")
+		case blockBullet:
+			builder.WriteString("  • " + inlineANSI(b.text) + "\n")
+		case blockOrdered:
+			builder.WriteString("  " + b.marker + ". " + inlineANSI(b.text) + "\n")
+		case blockCode:
+			for _, line := range strings.Split(b.text, "\n") {
+				builder.WriteString("    " + ansiDim + line + ansiReset + "\n")
+			}
+		default:
+			builder.WriteString(inlineANSI(b.text) + "\n")
+		}
+	}
+	return builder.String()
+}
+
+func inlineANSI(text string) string {
+	var builder strings.Builder
+	for _, s := range parseInline(text) {
+		switch s.style {
+		case spanBold:
+			builder.WriteString(ansiBold + s.text + ansiReset)
+		case spanItalic:
+			builder.WriteString(ansiItalic + s.text + ansiReset)
+		case spanCode:
+			builder.WriteString(ansiCodeColor + s.text + ansiReset)
+		default:
+			builder.WriteString(s.text)
+		}
+	}
+	return builder.String()
+}
+
+// MarkdownToHTML renders markdown as an HTML fragment with escaped text.
+func MarkdownToHTML(text string) string {
+	var builder strings.Builder
+	list := ""
+	closeList := func() {
+		if list != "" {
+			builder.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+	openList := func(tag string) {
+		if list != tag {
+			closeList()
+			builder.WriteString("<" + tag + ">\n")
+			list = tag
+		}
+	}
+	for _, b := range parseBlocks(text) {
+		switch b.kind {
+		case blockBullet:
+			openList("ul")
+			builder.WriteString("<li>" + inlineHTML(b.text) + "</li>\n")
+			continue
+		case blockOrdered:
+			openList("ol")
+			builder.WriteString("<li>" + inlineHTML(b.text) + "</li>\n")
+			continue
+		}
+		closeList()
+		switch b.kind {
+		case blockHeading:
+			tag := "h" + string(rune('0'+b.level))
+			builder.WriteString("<" + tag + ">" + inlineHTML(b.text) + "</" + tag + ">\n")
+		case blockCode:
+			builder.WriteString("<pre><code>" + html.EscapeString(b.text) + "</code></pre>\n")
+		case blockParagraph:
+			builder.WriteString("<p>" + strings.ReplaceAll(inlineHTML(b.text), "\n", "<br>\n") + "</p>\n")
+		}
+	}
+	closeList()
+	return builder.String()
+}
+
+func inlineHTML(text string) string {
+	var builder strings.Builder
+	for _, s := range parseInline(text) {
+		escaped := html.EscapeString(s.text)
+		switch s.style {
+		case spanBold:
+			builder.WriteString("<strong>" + escaped + "</strong>")
+		case spanItalic:
+			builder.WriteString("<em>" + escaped + "</em>")
+		case spanCode:
+			builder.WriteString("<code>" + escaped + "</code>")
+		default:
+			builder.WriteString(escaped)
+		}
+	}
+	return builder.String()
+}
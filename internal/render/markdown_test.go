@@ -0,0 +1,32 @@
+package render
+
+import "testing"
+
+func TestMarkdownToHTML(t *testing.T) {
+	input := "## Word\n**run** means *to move* fast, see `run_fast`.\n\n- one\n- two <b>\n1. first\n```\na < b\n```"
+	want := "<h2>Word</h2>\n" +
+		"<p><strong>run</strong> means <em>to move</em> fast, see <code>run_fast</code>.</p>\n" +
+		"<ul>\n<li>one</li>\n<li>two &lt;b&gt;</li>\n</ul>\n" +
+		"<ol>\n<li>first</li>\n</ol>\n" +
+		"<pre><code>a &lt; b</code></pre>\n"
+	if got := MarkdownToHTML(input); got != want {
+		t.Fatalf("unexpected html:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMarkdownToANSI(t *testing.T) {
+	input := "# Title\n- **bold** item\nsnake_case_word"
+	want := "\x1b[1;4mTitle\x1b[0m\n" +
+		"  • \x1b[1mbold\x1b[0m item\n" +
+		"snake_case_word\n"
+	if got := MarkdownToANSI(input); got != want {
+		t.Fatalf("unexpected ansi: %q", got)
+	}
+}
+
+func TestParseInlineUnmatched(t *testing.T) {
+	spans := parseInline("2 * 3 and a*")
+	if len(spans) != 1 || spans[0].text != "2 * 3 and a*" {
+		t.Fatalf("unexpected spans: %+v", spans)
+	}
+}
@@ -0,0 +1,197 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"dict-be/internal/llm"
+)
+
+const (
+	Text   = "text"
+	ANSI   = "ansi"
+	JSON   = "json"
+	NDJSON = "ndjson"
+	HTML   = "html"
+)
+
+// Formats lists the supported renderer names in help order.
+var Formats = []string{Text, ANSI, JSON, NDJSON, HTML}
+
+// Renderer prints one LLM response. Start is called before the request is
+// sent, Delta for each streamed chunk when Incremental reports true, and
+// then exactly one of Done or Fail.
+type Renderer interface {
+	// Incremental reports whether the renderer prints deltas as they
+	// arrive. Other renderers only see the final response.
+	Incremental() bool
+	Start(model string) error
+	Delta(text string) error
+	// Done receives the final response. streamed reports whether its
+	// content was already passed to Delta.
+	Done(resp llm.ChatResponse, streamed bool) error
+	Fail(err error) error
+}
+
+// New returns the renderer for format writing to out.
+func New(format string, out io.Writer) (Renderer, error) {
+	switch format {
+	case Text:
+		return &textRenderer{out: out}, nil
+	case ANSI:
+		return &ansiRenderer{out: out}, nil
+	case JSON:
+		return &jsonRenderer{out: out}, nil
+	case NDJSON:
+		return &ndjsonRenderer{encoder: json.NewEncoder(out)}, nil
+	case HTML:
+		return &htmlRenderer{out: out}, nil
+	default:
+		return nil, fmt.Errorf("invalid format: %s (expected %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+type textRenderer struct {
+	out io.Writer
+}
+
+func (r *textRenderer) Incremental() bool { return true }
+
+func (r *textRenderer) Start(model string) error { return nil }
+
+func (r *textRenderer) Delta(text string) error {
+	_, err := fmt.Fprint(r.out, text)
+	return err
+}
+
+func (r *textRenderer) Done(resp llm.ChatResponse, streamed bool) error {
+	if streamed {
+		_, err := fmt.Fprintln(r.out)
+		return err
+	}
+	_, err := fmt.Fprintln(r.out, resp.Content)
+	return err
+}
+
+func (r *textRenderer) Fail(err error) error { return nil }
+
+// Event is one line of NDJSON output.
+type Event struct {
+	Type         string     `json:"type"`
+	Model        string     `json:"model,omitempty"`
+	Content      string     `json:"content,omitempty"`
+	FinishReason string     `json:"finish_reason,omitempty"`
+	Usage        *llm.Usage `json:"usage,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+type ndjsonRenderer struct {
+	encoder *json.Encoder
+}
+
+func (r *ndjsonRenderer) Incremental() bool { return true }
+
+func (r *ndjsonRenderer) Start(model string) error {
+	return r.encoder.Encode(Event{Type: "start", Model: model})
+}
+
+func (r *ndjsonRenderer) Delta(text string) error {
+	return r.encoder.Encode(Event{Type: "delta", Content: text})
+}
+
+func (r *ndjsonRenderer) Done(resp llm.ChatResponse, streamed bool) error {
+	if !streamed && resp.Content != "" {
+		if err := r.Delta(resp.Content); err != nil {
+			return err
+		}
+	}
+	if !resp.Usage.IsZero() {
+		usage := resp.Usage
+		if err := r.encoder.Encode(Event{Type: "usage", Usage: &usage}); err != nil {
+			return err
+		}
+	}
+	return r.encoder.Encode(Event{
+		Type:         "done",
+		Model:        resp.Model,
+		FinishReason: resp.FinishReason,
+	})
+}
+
+func (r *ndjsonRenderer) Fail(err error) error {
+	return r.encoder.Encode(Event{Type: "error", Error: err.Error()})
+}
+
+type jsonRenderer struct {
+	out io.Writer
+}
+
+type jsonResponse struct {
+	Model        string     `json:"model,omitempty"`
+	Content      string     `json:"content"`
+	Reasoning    string     `json:"reasoning,omitempty"`
+	FinishReason string     `json:"finish_reason,omitempty"`
+	Usage        *llm.Usage `json:"usage,omitempty"`
+}
+
+func (r *jsonRenderer) Incremental() bool { return false }
+
+func (r *jsonRenderer) Start(model string) error { return nil }
+
+func (r *jsonRenderer) Delta(text string) error { return nil }
+
+func (r *jsonRenderer) Done(resp llm.ChatResponse, streamed bool) error {
+	payload := jsonResponse{
+		Model:        resp.Model,
+		Content:      resp.Content,
+		Reasoning:    resp.Reasoning,
+		FinishReason: resp.FinishReason,
+	}
+	if !resp.Usage.IsZero() {
+		usage := resp.Usage
+		payload.Usage = &usage
+	}
+	encoder := json.NewEncoder(r.out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(payload)
+}
+
+func (r *jsonRenderer) Fail(err error) error { return nil }
+
+type ansiRenderer struct {
+	out io.Writer
+}
+
+func (r *ansiRenderer) Incremental() bool { return false }
+
+func (r *ansiRenderer) Start(model string) error { return nil }
+
+func (r *ansiRenderer) Delta(text string) error { return nil }
+
+func (r *ansiRenderer) Done(resp llm.ChatResponse, streamed bool) error {
+	_, err := io.WriteString(r.out, MarkdownToANSI(resp.Content))
+	return err
+}
+
+func (r *ansiRenderer) Fail(err error) error { return nil }
+
+type htmlRenderer struct {
+	out io.Writer
+}
+
+func (r *htmlRenderer) Incremental() bool { return false }
+
+func (r *htmlRenderer) Start(model string) error { return nil }
+
+func (r *htmlRenderer) Delta(text string) error { return nil }
+
+func (r *htmlRenderer) Done(resp llm.ChatResponse, streamed bool) error {
+	_, err := fmt.Fprintf(r.out, "<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"></head>\n<body>\n%s</body>\n</html>\n",
+		MarkdownToHTML(resp.Content))
+	return err
+}
+
+func (r *htmlRenderer) Fail(err error) error { return nil }
@@ -0,0 +1,68 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"dict-be/internal/llm"
+)
+
+func TestNewRejectsUnknownFormat(t *testing.T) {
+	if _, err := New("yaml", &bytes.Buffer{}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestTextRenderer(t *testing.T) {
+	var out bytes.Buffer
+	r, _ := New(Text, &out)
+	_ = r.Delta("he")
+	_ = r.Delta("llo")
+	if err := r.Done(llm.ChatResponse{Content: "hello"}, true); err != nil {
+		t.Fatalf("done: %v", err)
+	}
+	if out.String() != "hello\n" {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestNDJSONRendererFail(t *testing.T) {
+	var out bytes.Buffer
+	r, _ := New(NDJSON, &out)
+	_ = r.Start("m")
+	_ = r.Fail(errors.New("boom"))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"error":"boom"`) {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestJSONRenderer(t *testing.T) {
+	var out bytes.Buffer
+	r, _ := New(JSON, &out)
+	resp := llm.ChatResponse{Content: "hi", Model: "m", Usage: llm.Usage{TotalTokens: 3}}
+	if err := r.Done(resp, false); err != nil {
+		t.Fatalf("done: %v", err)
+	}
+	var decoded jsonResponse
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded.Content != "hi" || decoded.Usage == nil || decoded.Usage.TotalTokens != 3 {
+		t.Fatalf("unexpected payload: %+v", decoded)
+	}
+}
+
+func TestHTMLRendererWrapsDocument(t *testing.T) {
+	var out bytes.Buffer
+	r, _ := New(HTML, &out)
+	if err := r.Done(llm.ChatResponse{Content: "# Hi"}, false); err != nil {
+		t.Fatalf("done: %v", err)
+	}
+	if !strings.Contains(out.String(), "<body>\n<h1>Hi</h1>\n</body>") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
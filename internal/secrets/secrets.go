@@ -0,0 +1,128 @@
+package secrets
+
+import (
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Finding is a suspected secret in scanned text.
+type Finding struct {
+	Kind  string
+	Match string
+}
+
+type pattern struct {
+	kind  string
+	regex *regexp.Regexp
+	group int
+}
+
+var patterns = []pattern{
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`), 0},
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), 0},
+	{"Anthropic API key", regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`), 0},
+	{"OpenAI API key", regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_-]{20,}`), 0},
+	{"GitHub token", regexp.MustCompile(`\b(?:ghp|gho|ghu|ghs|ghr|github_pat)_[A-Za-z0-9_]{20,}`), 0},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`), 0},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`), 0},
+	{"JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`), 0},
+	{"password", regexp.MustCompile(`(?i)\b(?:password|passwd|pwd|secret|api[_-]?key|access[_-]?token)\s*[:=]\s*["']?([^\s"'<>]{6,})`), 1},
+}
+
+var privateKeyBlock = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)
+
+var hostnamePattern = regexp.MustCompile(`\b(?:[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.)+[A-Za-z]{2,}\b`)
+
+var ipPattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+
+var internalSuffixes = []string{".internal", ".local", ".corp", ".lan", ".intranet", ".localdomain"}
+
+// Scan looks for credentials, private IP addresses and internal host names.
+// Hosts under any of internalDomains are reported in addition to the
+// built-in internal suffixes.
+func Scan(text string, internalDomains []string) []Finding {
+	var findings []Finding
+	seen := make(map[string]bool)
+	add := func(kind, match string) {
+		key := kind + "\x00" + match
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		findings = append(findings, Finding{Kind: kind, Match: match})
+	}
+
+	// A credential is reported once, by the first pattern that matches it:
+	// an Anthropic key also looks like an OpenAI key.
+	var spans [][2]int
+	overlaps := func(start, end int) bool {
+		for _, span := range spans {
+			if start < span[1] && span[0] < end {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range patterns {
+		for _, match := range p.regex.FindAllStringSubmatchIndex(text, -1) {
+			start, end := match[2*p.group], match[2*p.group+1]
+			if overlaps(start, end) {
+				continue
+			}
+			spans = append(spans, [2]int{start, end})
+			add(p.kind, text[start:end])
+		}
+	}
+	suffixes := append([]string(nil), internalSuffixes...)
+	for _, domain := range internalDomains {
+		domain = strings.ToLower(strings.Trim(strings.TrimSpace(domain), "."))
+		if domain != "" {
+			suffixes = append(suffixes, "."+domain)
+		}
+	}
+	for _, host := range hostnamePattern.FindAllString(text, -1) {
+		lower := "." + strings.ToLower(host)
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(lower, suffix) {
+				add("internal hostname", host)
+				break
+			}
+		}
+	}
+	for _, candidate := range ipPattern.FindAllString(text, -1) {
+		if ip := net.ParseIP(candidate); ip != nil && ip.IsPrivate() {
+			add("private IP address", candidate)
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Kind < findings[j].Kind
+	})
+	return findings
+}
+
+// Redact shortens a match so warnings do not echo the full secret.
+func Redact(match string) string {
+	runes := []rune(match)
+	if len(runes) <= 8 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:4]) + "…" + string(runes[len(runes)-2:])
+}
+
+// Mask replaces every finding in text with a [REDACTED <kind>] marker, for
+// copies of the text that are stored rather than shown once.
+func Mask(text string, internalDomains []string) string {
+	text = privateKeyBlock.ReplaceAllString(text, "[REDACTED private key]")
+	findings := Scan(text, internalDomains)
+	// Replace longer matches first so a match contained in another one
+	// does not leave part of the longer secret behind.
+	sort.SliceStable(findings, func(i, j int) bool {
+		return len(findings[i].Match) > len(findings[j].Match)
+	})
+	for _, finding := range findings {
+		text = strings.ReplaceAll(text, finding.Match, "[REDACTED "+finding.Kind+"]")
+	}
+	return text
+}
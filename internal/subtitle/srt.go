@@ -0,0 +1,75 @@
+// Package subtitle reads and writes SubRip (.srt) subtitles.
+package subtitle
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Cue is one subtitle block. Index and Timing hold the block's first two
+// lines exactly as read, so writing the cues back leaves them unchanged.
+type Cue struct {
+	Index  string
+	Timing string
+	Lines  []string
+}
+
+// Text returns the cue's text lines joined with newlines.
+func (c Cue) Text() string {
+	return strings.Join(c.Lines, "\n")
+}
+
+// ParseSRT splits text into cues. Blocks are separated by blank lines; a
+// block needs an index line and a timing line containing "-->", and may
+// have no text lines. A byte order mark and CRLF line endings are
+// accepted.
+func ParseSRT(text string) ([]Cue, error) {
+	text = strings.TrimPrefix(text, "\uFEFF")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var cues []Cue
+	var block []string
+	line := 0
+	flush := func() error {
+		if len(block) == 0 {
+			return nil
+		}
+		start := line - len(block)
+		if len(block) < 2 || !strings.Contains(block[1], "-->") {
+			return fmt.Errorf("line %d: expected a subtitle index followed by a timing line", start+1)
+		}
+		cues = append(cues, Cue{Index: block[0], Timing: block[1], Lines: block[2:]})
+		block = nil
+		return nil
+	}
+	for _, raw := range strings.Split(text, "\n") {
+		if strings.TrimSpace(raw) == "" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		} else {
+			block = append(block, raw)
+		}
+		line++
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return cues, nil
+}
+
+// WriteSRT writes cues as SubRip blocks separated by blank lines.
+func WriteSRT(w io.Writer, cues []Cue) error {
+	for i, cue := range cues {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		lines := append([]string{cue.Index, cue.Timing}, cue.Lines...)
+		if _, err := io.WriteString(w, strings.Join(lines, "\n")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
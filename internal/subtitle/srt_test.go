@@ -0,0 +1,45 @@
+package subtitle
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testSRT = "\uFEFF1\r\n00:00:01,000 --> 00:00:03,500\r\nHello there.\r\nHow are you?\r\n\r\n" +
+	"2\r\n00:00:04,000 --> 00:00:05,000  X1:10\r\n\r\n\r\n" +
+	"3\r\n00:00:06,000 --> 00:00:07,250\r\n<i>Fine.</i>\r\n"
+
+func TestParseSRT(t *testing.T) {
+	cues, err := ParseSRT(testSRT)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cues) != 3 {
+		t.Fatalf("unexpected cues: %+v", cues)
+	}
+	if cues[0].Index != "1" || cues[0].Text() != "Hello there.\nHow are you?" {
+		t.Fatalf("unexpected first cue: %+v", cues[0])
+	}
+	if cues[1].Timing != "00:00:04,000 --> 00:00:05,000  X1:10" || len(cues[1].Lines) != 0 {
+		t.Fatalf("unexpected second cue: %+v", cues[1])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSRT(&buf, cues); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "1\n00:00:01,000 --> 00:00:03,500\nHello there.\nHow are you?\n\n" +
+		"2\n00:00:04,000 --> 00:00:05,000  X1:10\n\n" +
+		"3\n00:00:06,000 --> 00:00:07,250\n<i>Fine.</i>\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%q", buf.String())
+	}
+}
+
+func TestParseSRTInvalid(t *testing.T) {
+	_, err := ParseSRT("1\n00:00:01,000 --> 00:00:02,000\nok\n\nno timing here\nsecond line\n")
+	if err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
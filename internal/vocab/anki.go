@@ -0,0 +1,59 @@
+package vocab
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteAnki writes entries as an Anki "Notes in Plain Text" file with the
+// word on the front and the answer on the back. Each note has a GUID
+// derived from the entry ID, so importing a later export updates the
+// notes instead of adding duplicates. The word stays the first field, so
+// known import can read the file back.
+func WriteAnki(w io.Writer, entries []Entry) error {
+	if _, err := io.WriteString(w, "#separator:tab\n#html:true\n#tags column:3\n#guid column:4\n"); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		fields := []string{
+			ankiField(entry.Word),
+			entry.ankiBack(),
+			"dict-be " + entry.Command,
+			"dict-be-" + strconv.Itoa(entry.ID),
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ankiBack is the translation, mnemonics and examples when the entry has
+// them, and the whole answer otherwise.
+func (e Entry) ankiBack() string {
+	if e.Translation == "" && len(e.Mnemonics) == 0 && len(e.Examples) == 0 {
+		return ankiField(e.Content)
+	}
+	var parts []string
+	if e.Translation != "" {
+		parts = append(parts, ankiField(e.Translation))
+	}
+	for _, mnemonic := range e.Mnemonics {
+		parts = append(parts, ankiField(mnemonic))
+	}
+	for _, example := range e.Examples {
+		parts = append(parts, ankiField(example.Sentence)+"<br><i>"+ankiField(example.Translation)+"</i>")
+	}
+	return strings.Join(parts, "<br><br>")
+}
+
+// ankiField escapes text for an HTML field on a single line.
+func ankiField(text string) string {
+	text = html.EscapeString(strings.TrimSpace(text))
+	text = strings.ReplaceAll(text, "\t", " ")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
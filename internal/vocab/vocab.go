@@ -0,0 +1,421 @@
+// Package vocab keeps the learner's vocabulary notebook: words saved from
+// query and define together with the answer they got, in a SQLite
+// database.
+package vocab
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"dict-be/internal/known"
+
+	_ "modernc.org/sqlite"
+)
+
+// migrations[i] upgrades the database from schema version i to i+1; the
+// version is kept in PRAGMA user_version, 0 being an empty database.
+// Append new steps, never edit released ones.
+var migrations = []string{
+	`CREATE TABLE entries (
+		id              INTEGER PRIMARY KEY AUTOINCREMENT,
+		word            TEXT NOT NULL,
+		word_key        TEXT NOT NULL,
+		command         TEXT NOT NULL,
+		input_language  TEXT NOT NULL DEFAULT '',
+		output_language TEXT NOT NULL DEFAULT '',
+		translation     TEXT NOT NULL DEFAULT '',
+		mnemonics       TEXT NOT NULL DEFAULT '[]',
+		examples        TEXT NOT NULL DEFAULT '[]',
+		content         TEXT NOT NULL,
+		added           TEXT NOT NULL
+	);
+	CREATE INDEX entries_word_key ON entries (word_key, command);`,
+	// Version 2 adds quiz statistics; saved words start with none.
+	`ALTER TABLE entries ADD COLUMN quizzed INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE entries ADD COLUMN correct INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE entries ADD COLUMN last_quizzed TEXT;`,
+}
+
+// schemaVersion is the database schema this build writes.
+func schemaVersion() int {
+	return len(migrations)
+}
+
+// Entry is a saved word.
+type Entry struct {
+	ID             int
+	Word           string
+	Command        string
+	InputLanguage  string
+	OutputLanguage string
+	Translation    string
+	Mnemonics      []string
+	Examples       []Example
+	// Content is the model's answer; structured query answers are kept as
+	// markdown.
+	Content string
+	Added   time.Time
+	// Quizzed and Correct count quiz answers about the word.
+	Quizzed     int
+	Correct     int
+	LastQuizzed *time.Time
+}
+
+// Accuracy is the share of quiz answers that were right, 0 before the
+// first quiz.
+func (e Entry) Accuracy() float64 {
+	if e.Quizzed == 0 {
+		return 0
+	}
+	return float64(e.Correct) / float64(e.Quizzed)
+}
+
+// Answer is the result of one quiz question.
+type Answer struct {
+	ID      int
+	Correct bool
+}
+
+// Example is an example sentence with its translation.
+type Example struct {
+	Sentence    string `json:"sentence"`
+	Translation string `json:"translation"`
+}
+
+// Notebook is the list of saved entries, oldest first.
+type Notebook struct {
+	Entries []Entry
+}
+
+// Find returns the entry with the given ID, or the latest one saved for a
+// word.
+func (n *Notebook) Find(ref string) (Entry, bool) {
+	if i := n.index(ref); i >= 0 {
+		return n.Entries[i], true
+	}
+	return Entry{}, false
+}
+
+func (n *Notebook) index(ref string) int {
+	if id, err := strconv.Atoi(ref); err == nil {
+		for i, entry := range n.Entries {
+			if entry.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+	word := known.Normalize(ref)
+	for i := len(n.Entries) - 1; i >= 0; i-- {
+		if known.Normalize(n.Entries[i].Word) == word {
+			return i
+		}
+	}
+	return -1
+}
+
+// Store keeps the notebook in a SQLite database.
+type Store struct {
+	path string
+	now  func() time.Time
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path, now: time.Now}
+}
+
+// DefaultPath returns ~/.dict-be/dict.db.
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir: %w", err)
+	}
+	return filepath.Join(homeDir, ".dict-be", "dict.db"), nil
+}
+
+// busyTimeout is how long a run waits for another one writing the
+// database before failing.
+const busyTimeout = 5 * time.Second
+
+// open opens the database, creating it when missing, and upgrades its
+// schema. A database written by a newer build is refused.
+func (s *Store) open() (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return nil, fmt.Errorf("create vocabulary dir: %w", err)
+	}
+	// Transactions take the write lock when they begin, so two runs
+	// cannot both read and then wait on each other to write.
+	db, err := sql.Open("sqlite", fmt.Sprintf("%s?_pragma=busy_timeout(%d)&_txlock=immediate", s.path, busyTimeout.Milliseconds()))
+	if err != nil {
+		return nil, fmt.Errorf("open vocabulary: %w", err)
+	}
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("vocabulary %s: %w", s.path, err)
+	}
+	return db, nil
+}
+
+func migrate(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var version int
+	if err := tx.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version > schemaVersion() {
+		return fmt.Errorf("schema version %d was written by a newer dict-be", version)
+	}
+	if version == schemaVersion() {
+		return nil
+	}
+	for v := version; v < schemaVersion(); v++ {
+		if _, err := tx.Exec(migrations[v]); err != nil {
+			return fmt.Errorf("upgrade from version %d: %w", v, err)
+		}
+	}
+	// PRAGMA takes no parameters; the version is a number this build chose.
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion())); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const entryColumns = `id, word, command, input_language, output_language, translation,
+	mnemonics, examples, content, added, quizzed, correct, last_quizzed`
+
+// Load returns every saved entry. A missing database is an empty
+// notebook.
+func (s *Store) Load() (*Notebook, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	entries, err := loadEntries(db)
+	if err != nil {
+		return nil, err
+	}
+	return &Notebook{Entries: entries}, nil
+}
+
+// loadEntries reads the entries in ID order through a database or a
+// transaction.
+func loadEntries(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}) ([]Entry, error) {
+	rows, err := q.Query("SELECT " + entryColumns + " FROM entries ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("read vocabulary: %w", err)
+	}
+	defer rows.Close()
+	var entries []Entry
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("read vocabulary: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read vocabulary: %w", err)
+	}
+	return entries, nil
+}
+
+func scanEntry(rows *sql.Rows) (Entry, error) {
+	var (
+		entry               Entry
+		mnemonics, examples string
+		added               string
+		lastQuizzed         sql.NullString
+	)
+	err := rows.Scan(&entry.ID, &entry.Word, &entry.Command, &entry.InputLanguage, &entry.OutputLanguage,
+		&entry.Translation, &mnemonics, &examples, &entry.Content, &added, &entry.Quizzed, &entry.Correct, &lastQuizzed)
+	if err != nil {
+		return Entry{}, err
+	}
+	if err := json.Unmarshal([]byte(mnemonics), &entry.Mnemonics); err != nil {
+		return Entry{}, fmt.Errorf("entry %d mnemonics: %w", entry.ID, err)
+	}
+	if err := json.Unmarshal([]byte(examples), &entry.Examples); err != nil {
+		return Entry{}, fmt.Errorf("entry %d examples: %w", entry.ID, err)
+	}
+	if entry.Added, err = time.Parse(time.RFC3339, added); err != nil {
+		return Entry{}, fmt.Errorf("entry %d: %w", entry.ID, err)
+	}
+	if lastQuizzed.Valid {
+		t, err := time.Parse(time.RFC3339, lastQuizzed.String)
+		if err != nil {
+			return Entry{}, fmt.Errorf("entry %d: %w", entry.ID, err)
+		}
+		entry.LastQuizzed = &t
+	}
+	return entry, nil
+}
+
+// Save adds entry, or replaces the answer of the entry already saved for
+// the same word by the same command, keeping its ID and quiz statistics.
+// It returns the stored entry.
+func (s *Store) Save(entry Entry) (Entry, error) {
+	saved, err := s.SaveAll([]Entry{entry})
+	if err != nil {
+		return Entry{}, err
+	}
+	return saved[0], nil
+}
+
+// SaveAll saves entries as Save does, in one transaction, and returns
+// them as stored.
+func (s *Store) SaveAll(entries []Entry) ([]Entry, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("write vocabulary: %w", err)
+	}
+	defer tx.Rollback()
+	added := s.now().UTC().Truncate(time.Second)
+	saved := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		entry.Added = added
+		if err := saveEntry(tx, &entry); err != nil {
+			return nil, err
+		}
+		saved = append(saved, entry)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("write vocabulary: %w", err)
+	}
+	return saved, nil
+}
+
+// saveEntry inserts entry or updates the entry saved for its word and
+// command, and fills in its ID and quiz statistics.
+func saveEntry(tx *sql.Tx, entry *Entry) error {
+	mnemonics, err := json.Marshal(nonNil(entry.Mnemonics))
+	if err != nil {
+		return fmt.Errorf("encode vocabulary: %w", err)
+	}
+	examples, err := json.Marshal(nonNil(entry.Examples))
+	if err != nil {
+		return fmt.Errorf("encode vocabulary: %w", err)
+	}
+	key := known.Normalize(entry.Word)
+	err = tx.QueryRow("SELECT id, quizzed, correct FROM entries WHERE word_key = ? AND command = ? ORDER BY id LIMIT 1", key, entry.Command).
+		Scan(&entry.ID, &entry.Quizzed, &entry.Correct)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		result, err := tx.Exec(`INSERT INTO entries (word, word_key, command, input_language, output_language,
+			translation, mnemonics, examples, content, added) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			entry.Word, key, entry.Command, entry.InputLanguage, entry.OutputLanguage,
+			entry.Translation, string(mnemonics), string(examples), entry.Content, entry.Added.Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("write vocabulary: %w", err)
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("write vocabulary: %w", err)
+		}
+		entry.ID = int(id)
+	case err != nil:
+		return fmt.Errorf("read vocabulary: %w", err)
+	default:
+		_, err := tx.Exec(`UPDATE entries SET word = ?, input_language = ?, output_language = ?, translation = ?,
+			mnemonics = ?, examples = ?, content = ?, added = ? WHERE id = ?`,
+			entry.Word, entry.InputLanguage, entry.OutputLanguage, entry.Translation,
+			string(mnemonics), string(examples), entry.Content, entry.Added.Format(time.RFC3339), entry.ID)
+		if err != nil {
+			return fmt.Errorf("write vocabulary: %w", err)
+		}
+	}
+	return nil
+}
+
+// nonNil stores a missing list as [] rather than null.
+func nonNil[T any](list []T) []T {
+	if list == nil {
+		return []T{}
+	}
+	return list
+}
+
+// Delete removes the entries refs name, by ID or word, and returns them.
+// Nothing is removed when a ref matches no entry.
+func (s *Store) Delete(refs []string) ([]Entry, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("write vocabulary: %w", err)
+	}
+	defer tx.Rollback()
+	entries, err := loadEntries(tx)
+	if err != nil {
+		return nil, err
+	}
+	notebook := &Notebook{Entries: entries}
+	var deleted []Entry
+	for _, ref := range refs {
+		i := notebook.index(ref)
+		if i < 0 {
+			return nil, fmt.Errorf("no saved word %q", ref)
+		}
+		entry := notebook.Entries[i]
+		if _, err := tx.Exec("DELETE FROM entries WHERE id = ?", entry.ID); err != nil {
+			return nil, fmt.Errorf("write vocabulary: %w", err)
+		}
+		deleted = append(deleted, entry)
+		notebook.Entries = append(notebook.Entries[:i], notebook.Entries[i+1:]...)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("write vocabulary: %w", err)
+	}
+	return deleted, nil
+}
+
+// RecordAnswers adds quiz answers to the statistics of their entries.
+// Answers about entries deleted in the meantime are ignored.
+func (s *Store) RecordAnswers(answers []Answer) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	now := s.now().UTC().Truncate(time.Second).Format(time.RFC3339)
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("write vocabulary: %w", err)
+	}
+	defer tx.Rollback()
+	for _, answer := range answers {
+		correct := 0
+		if answer.Correct {
+			correct = 1
+		}
+		_, err := tx.Exec("UPDATE entries SET quizzed = quizzed + 1, correct = correct + ?, last_quizzed = ? WHERE id = ?",
+			correct, now, answer.ID)
+		if err != nil {
+			return fmt.Errorf("write vocabulary: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("write vocabulary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package vocab
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dict-be/internal/known"
+)
+
+func newTestStore(t *testing.T) *Store {
+	store := NewStore(filepath.Join(t.TempDir(), "dict.db"))
+	store.now = func() time.Time { return time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC) }
+	return store
+}
+
+func TestStoreSaveFindDelete(t *testing.T) {
+	store := newTestStore(t)
+	notebook, err := store.Load()
+	if err != nil || len(notebook.Entries) != 0 {
+		t.Fatalf("expected an empty notebook, got %+v, %v", notebook, err)
+	}
+	pier, err := store.Save(Entry{Word: "pier", Command: "query", Translation: "码头", Content: "码头"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := store.Save(Entry{Word: "run", Command: "define", Content: "run (v.)"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := store.Save(Entry{Word: " Pier", Command: "query", Translation: "栈桥", Content: "栈桥"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pier.ID != 1 || again.ID != 1 || !again.Added.Equal(store.now()) {
+		t.Fatalf("expected the query entry to be replaced in place, got %+v then %+v", pier, again)
+	}
+
+	notebook, err = store.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notebook.Entries) != 2 {
+		t.Fatalf("unexpected notebook: %+v", notebook)
+	}
+	if entry, ok := notebook.Find("PIER"); !ok || entry.Translation != "栈桥" {
+		t.Fatalf("unexpected entry for pier: %+v", entry)
+	}
+	if entry, ok := notebook.Find("2"); !ok || entry.Word != "run" {
+		t.Fatalf("unexpected entry 2: %+v", entry)
+	}
+
+	if _, err := store.Delete([]string{"run", "missing"}); err == nil {
+		t.Fatalf("expected an error for an unknown word")
+	}
+	deleted, err := store.Delete([]string{"2"})
+	if err != nil || len(deleted) != 1 || deleted[0].Word != "run" {
+		t.Fatalf("unexpected delete: %+v, %v", deleted, err)
+	}
+	if entry, _ := store.Save(Entry{Word: "tide", Command: "query"}); entry.ID != 3 {
+		t.Fatalf("expected IDs not to be reused, got %d", entry.ID)
+	}
+}
+
+func TestStoreSaveAll(t *testing.T) {
+	store := newTestStore(t)
+	if _, err := store.Save(Entry{Word: "pier", Command: "read", Translation: "dock"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	saved, err := store.SaveAll([]Entry{{Word: "tide", Command: "read"}, {Word: "Pier", Command: "read", Translation: "码头"}})
+	if err != nil || len(saved) != 2 || saved[0].ID != 2 || saved[1].ID != 1 {
+		t.Fatalf("unexpected save: %+v, %v", saved, err)
+	}
+	notebook, err := store.Load()
+	if err != nil || len(notebook.Entries) != 2 || notebook.Entries[0].Translation != "码头" {
+		t.Fatalf("unexpected notebook: %+v, %v", notebook, err)
+	}
+}
+
+func TestStoreRecordAnswers(t *testing.T) {
+	store := newTestStore(t)
+	for _, word := range []string{"pier", "run"} {
+		if _, err := store.Save(Entry{Word: word, Command: "query"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	answers := []Answer{{ID: 1, Correct: true}, {ID: 2}, {ID: 1}, {ID: 1, Correct: true}, {ID: 7, Correct: true}}
+	if err := store.RecordAnswers(answers); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notebook, err := store.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pier, run := notebook.Entries[0], notebook.Entries[1]
+	if pier.Quizzed != 3 || pier.Correct != 2 || run.Accuracy() != 0 || run.Quizzed != 1 {
+		t.Fatalf("unexpected statistics: %+v, %+v", pier, run)
+	}
+	if pier.LastQuizzed == nil || !pier.LastQuizzed.Equal(store.now()) {
+		t.Fatalf("unexpected last quizzed time: %v", pier.LastQuizzed)
+	}
+}
+
+func TestStoreMigrations(t *testing.T) {
+	store := newTestStore(t)
+	db, err := sql.Open("sqlite", store.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A version 1 database, from before quiz statistics.
+	for _, statement := range []string{
+		migrations[0],
+		"PRAGMA user_version = 1",
+		`INSERT INTO entries (word, word_key, command, content, added) VALUES ('pier', 'pier', 'query', '码头', '2026-02-01T08:00:00Z')`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+	db.Close()
+
+	if err := store.RecordAnswers([]Answer{{ID: 1, Correct: true}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notebook, err := store.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notebook.Entries) != 1 || notebook.Entries[0].Content != "码头" || notebook.Entries[0].Correct != 1 {
+		t.Fatalf("expected the notebook to be upgraded, got %+v", notebook)
+	}
+
+	db, err = sql.Open("sqlite", store.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("PRAGMA user_version = 9"); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	if _, err := store.Load(); err == nil || !strings.Contains(err.Error(), "newer dict-be") {
+		t.Fatalf("expected a newer-version error, got %v", err)
+	}
+}
+
+func TestStoreConcurrentSaves(t *testing.T) {
+	store := newTestStore(t)
+	const runs = 10
+	var wg sync.WaitGroup
+	for i := 0; i < runs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// A store per goroutine, as separate runs would have.
+			if _, err := NewStore(store.path).Save(Entry{Word: fmt.Sprintf("word%d", i), Command: "query"}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	notebook, err := store.Load()
+	if err != nil || len(notebook.Entries) != runs {
+		t.Fatalf("unexpected notebook: %+v, %v", notebook, err)
+	}
+}
+
+func TestWriteAnki(t *testing.T) {
+	entries := []Entry{
+		{ID: 1, Word: "pier", Command: "query", Translation: "码头", Mnemonics: []string{"Pier <peer>."},
+			Examples: []Example{{Sentence: "We met on the pier.", Translation: "我们在码头见面。"}}},
+		{ID: 4, Word: "run", Command: "define", Content: "run /rʌn/\n1.\t(v.) move fast\n"},
+	}
+	var out strings.Builder
+	if err := WriteAnki(&out, entries); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "#separator:tab\n#html:true\n#tags column:3\n#guid column:4\n" +
+		"pier\t码头<br><br>Pier &lt;peer&gt;.<br><br>We met on the pier.<br><i>我们在码头见面。</i>\tdict-be query\tdict-be-1\n" +
+		"run\trun /rʌn/<br>1. (v.) move fast\tdict-be define\tdict-be-4\n"
+	if out.String() != want {
+		t.Fatalf("unexpected export:\n%s", out.String())
+	}
+	words, err := known.ReadAnki(strings.NewReader(out.String()))
+	if err != nil || len(words) != 2 || words[0] != "pier" || words[1] != "run" {
+		t.Fatalf("expected known import to read the words back, got %v, %v", words, err)
+	}
+}
@@ -0,0 +1,101 @@
+// Package wordfreq ranks English words by how common they are, from a
+// bundled list of about a thousand of the most frequent headwords in
+// general English, most frequent first.
+package wordfreq
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed en.txt
+var englishList string
+
+var english = rankWords(englishList)
+
+func rankWords(list string) map[string]int {
+	ranks := make(map[string]int)
+	for _, word := range strings.Fields(list) {
+		if _, ok := ranks[word]; !ok {
+			ranks[word] = len(ranks) + 1
+		}
+	}
+	return ranks
+}
+
+// Size is the number of words in the list.
+func Size() int {
+	return len(english)
+}
+
+// irregular maps common inflected forms to their headword.
+var irregular = map[string]string{
+	"am": "be", "is": "be", "are": "be", "was": "be", "were": "be", "been": "be", "being": "be",
+	"has": "have", "had": "have", "does": "do", "did": "do", "done": "do",
+	"said": "say", "went": "go", "gone": "go", "made": "make", "knew": "know", "known": "know",
+	"took": "take", "taken": "take", "saw": "see", "seen": "see", "came": "come", "got": "get",
+	"gave": "give", "given": "give", "found": "find", "thought": "think", "told": "tell",
+	"became": "become", "left": "leave", "felt": "feel", "brought": "bring", "began": "begin",
+	"begun": "begin", "kept": "keep", "held": "hold", "wrote": "write", "written": "write",
+	"stood": "stand", "heard": "hear", "meant": "mean", "met": "meet", "ran": "run", "paid": "pay",
+	"sat": "sit", "spoke": "speak", "spoken": "speak", "lost": "lose", "led": "lead", "grew": "grow",
+	"grown": "grow", "fell": "fall", "fallen": "fall", "built": "build", "sent": "send", "spent": "spend",
+	"sold": "sell", "understood": "understand", "children": "child", "men": "man", "women": "woman",
+	"people": "people", "feet": "foot", "better": "good", "best": "good", "worse": "bad", "worst": "bad",
+	"him": "he", "her": "she", "them": "they", "us": "we", "me": "i",
+}
+
+// Rank returns the 1-based rank of word, or of its headword when word is
+// an inflected form, and whether the list has it.
+func Rank(word string) (int, bool) {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if rank, ok := english[word]; ok {
+		return rank, true
+	}
+	if base, ok := irregular[word]; ok {
+		rank, ok := english[base]
+		return rank, ok
+	}
+	for _, base := range baseForms(word) {
+		if rank, ok := english[base]; ok {
+			return rank, true
+		}
+	}
+	return 0, false
+}
+
+// baseForms guesses the headwords of a regularly inflected word: plurals
+// and third person (-s, -es, -ies), past tense (-ed, -ied, doubled
+// consonant) and -ing forms.
+func baseForms(word string) []string {
+	var forms []string
+	add := func(suffix, replacement string) {
+		if stem, ok := strings.CutSuffix(word, suffix); ok && len(stem) > 1 {
+			forms = append(forms, stem+replacement)
+			if n := len(stem); replacement == "" && n > 2 && stem[n-1] == stem[n-2] {
+				forms = append(forms, stem[:n-1])
+			}
+		}
+	}
+	add("ies", "y")
+	add("ied", "y")
+	add("es", "")
+	add("s", "")
+	add("ed", "")
+	add("ed", "e")
+	add("ing", "")
+	add("ing", "e")
+	return forms
+}
+
+// Band names the frequency band of a rank.
+func Band(rank int) string {
+	switch {
+	case rank <= 100:
+		return "top 100"
+	case rank <= 500:
+		return "top 500"
+	default:
+		return "top 1000"
+	}
+}
@@ -0,0 +1,40 @@
+package wordfreq
+
+import "testing"
+
+func TestRank(t *testing.T) {
+	if rank, ok := Rank("the"); !ok || rank != 1 {
+		t.Fatalf("expected the at rank 1, got %d, %v", rank, ok)
+	}
+	for _, form := range []string{"Take", "took", "takes", "taking"} {
+		if rank, ok := Rank(form); !ok || rank != mustRank(t, "take") {
+			t.Errorf("Rank(%q) = %d, %v", form, rank, ok)
+		}
+	}
+	for form, base := range map[string]string{"stopped": "stop", "studies": "study", "running": "run", "children": "child", "is": "be"} {
+		if rank, ok := Rank(form); !ok || rank != mustRank(t, base) {
+			t.Errorf("Rank(%q) = %d, %v, want the rank of %q", form, rank, ok, base)
+		}
+	}
+	if _, ok := Rank("serendipity"); ok {
+		t.Fatalf("expected serendipity to be missing")
+	}
+}
+
+func mustRank(t *testing.T, word string) int {
+	t.Helper()
+	rank, ok := english[word]
+	if !ok {
+		t.Fatalf("%q is not in the list", word)
+	}
+	return rank
+}
+
+func TestBand(t *testing.T) {
+	if Band(1) != "top 100" || Band(101) != "top 500" || Band(501) != "top 1000" {
+		t.Fatalf("unexpected bands")
+	}
+	if Size() < 900 {
+		t.Fatalf("unexpected list size: %d", Size())
+	}
+}